@@ -2,24 +2,57 @@ package commands
 
 import (
 	"context"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
 	"log/slog"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
-	"time"
 
 	"github.com/bogatyr285/auth-go/config"
+	"github.com/bogatyr285/auth-go/internal/auth/account"
+	"github.com/bogatyr285/auth-go/internal/auth/authz"
+	"github.com/bogatyr285/auth-go/internal/auth/entity"
+	"github.com/bogatyr285/auth-go/internal/auth/events"
+	"github.com/bogatyr285/auth-go/internal/auth/ldap"
+	"github.com/bogatyr285/auth-go/internal/auth/login"
+	"github.com/bogatyr285/auth-go/internal/auth/oauth"
 	"github.com/bogatyr285/auth-go/internal/auth/repository"
 	"github.com/bogatyr285/auth-go/internal/auth/usecase"
 	"github.com/bogatyr285/auth-go/internal/buildinfo"
 	"github.com/bogatyr285/auth-go/internal/gateway/grpc/auth"
 	"github.com/bogatyr285/auth-go/internal/gateway/http/gen"
+	"github.com/bogatyr285/auth-go/internal/gateway/http/validation"
+	"github.com/bogatyr285/auth-go/internal/gateway/mux"
+	"github.com/bogatyr285/auth-go/pkg/certs"
 	"github.com/bogatyr285/auth-go/pkg/crypto"
+	"github.com/bogatyr285/auth-go/pkg/health"
 	"github.com/bogatyr285/auth-go/pkg/jwt"
+	"github.com/bogatyr285/auth-go/pkg/notifier"
+	"github.com/bogatyr285/auth-go/pkg/oidc"
+	"github.com/bogatyr285/auth-go/pkg/saml"
+	authpb "github.com/bogatyr285/auth-go/pkg/server/grpc/auth"
+	"github.com/bogatyr285/auth-go/pkg/service"
+	"github.com/bogatyr285/auth-go/pkg/sms"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-webauthn/webauthn/webauthn"
 	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+)
+
+// probes of the health checker
+const (
+	probeDatabase   = "database"
+	probeSigningKey = "signing_key"
+	probeNotifier   = "notifier"
 )
 
 func NewServeCmd() *cobra.Command {
@@ -55,67 +88,531 @@ func NewServeCmd() *cobra.Command {
 				return err
 			}
 
-			useCase := usecase.NewUseCase(&storage,
+			wa, err := webauthn.New(&webauthn.Config{
+				RPID:          cfg.WebAuthn.RPID,
+				RPDisplayName: cfg.WebAuthn.RPDisplayName,
+				RPOrigins:     cfg.WebAuthn.RPOrigins,
+			})
+			if err != nil {
+				return err
+			}
+
+			checker := health.NewChecker(health.Config{
+				Interval: cfg.Health.Interval,
+				Timeout:  cfg.Health.Timeout,
+			}, log)
+			checker.AddProbe(probeDatabase, storage.Ping)
+			checker.AddProbe(probeSigningKey, func(context.Context) error {
+				return jwtManager.CheckSigningKey()
+			})
+			authProbes := []string{probeDatabase, probeSigningKey}
+
+			var notify usecase.Notifier = notifier.NewLogNotifier(log)
+			if cfg.Notifier.SMTPAddress != "" {
+				smtpNotifier, err := notifier.NewSMTPNotifier(
+					cfg.Notifier.SMTPAddress,
+					cfg.Notifier.From,
+					cfg.Notifier.SMTPUsername,
+					cfg.Notifier.SMTPPassword)
+				if err != nil {
+					return err
+				}
+				notify = smtpNotifier
+
+				checker.AddProbe(probeNotifier, smtpNotifier.Ping)
+				authProbes = append(authProbes, probeNotifier)
+			}
+			checker.AddService(authpb.AuthService_ServiceDesc.ServiceName, authProbes...)
+			checker.AddService(authpb.RelationService_ServiceDesc.ServiceName, probeDatabase)
+
+			relations, err := authz.NewEngine(&storage, namespaceConfigs(cfg.Relations))
+			if err != nil {
+				return err
+			}
+
+			if err = identityNamespace(cfg); err != nil {
+				return err
+			}
+
+			directories, err := loginDirectories(cfg.Directories)
+			if err != nil {
+				return err
+			}
+
+			samlSettings, err := samlConnections(cfg.SAML)
+			if err != nil {
+				return err
+			}
+
+			idps, err := identityProviders(cfg.Federation)
+			if err != nil {
+				return err
+			}
+
+			eventBus := events.NewBus(cfg.Events.Buffer)
+
+			accounts := account.New(&storage,
 				passwordHasher,
 				jwtManager,
 				buildinfo.New(),
+				account.WithDirectories(directories...),
+				account.WithEvents(eventBus),
 			)
 
+			useCase := usecase.NewUseCase(&storage,
+				accounts,
+				jwtManager,
+				usecase.WithFederation(idps),
+				usecase.WithSAML(samlSettings),
+				usecase.WithWebAuthn(wa),
+				usecase.WithMagicLink(usecase.MagicLinkSettings{
+					Notifier: notify,
+					LinkURL:  cfg.MagicLink.LinkURL,
+					TTL:      cfg.MagicLink.TTL,
+					Limit:    cfg.MagicLink.Limit,
+					Window:   cfg.MagicLink.Window,
+				}),
+				usecase.WithRelations(relations),
+				usecase.WithEvents(eventBus),
+			)
+
+			if err = useCase.GrantAdmins(ctx, cfg.RBAC.Admins); err != nil {
+				return err
+			}
+
+			spec, err := gen.GetSwagger()
+			if err != nil {
+				return fmt.Errorf("failed to load openapi spec: %s", err)
+			}
+
+			var validationOpts []validation.Option
+			if cfg.HTTPServer.ValidateResponses {
+				validationOpts = append(validationOpts, validation.WithResponseValidation())
+			}
+			validator, err := validation.New(spec, log, validationOpts...)
+			if err != nil {
+				return err
+			}
+
 			router := chi.NewRouter()
 			router.Use(middleware.Logger)
 			router.Use(middleware.RequestID)
 			router.Use(middleware.Recoverer)
 			router.Use(useCase.AuthMiddleware)
+			router.Use(validator.Middleware)
+
+			providerOpts := []oauth.Option{oauth.WithEvents(eventBus)}
+			if len(cfg.TokenExchange.Policies) > 0 {
+				policies, err := exchangePolicies(cfg.TokenExchange)
+				if err != nil {
+					return err
+				}
+
+				providerOpts = append(providerOpts, oauth.WithTokenExchange(policies, log.With(slog.String("log", "audit"))))
+			}
+			oauth.NewProvider(&storage, passwordHasher, jwtManager, cfg.JWT.ExpiresIn, providerOpts...).Routes(router)
+
+			httpTLS, err := serverTLS(cfg.HTTPServer.TLS, log)
+			if err != nil {
+				return err
+			}
+
+			authGRPCHandlers := auth.NewAuthHandlers(
+				&storage,
+				passwordHasher,
+				accounts,
+				auth.WithSMSSender(sms.NewLogSender(log)),
+				auth.WithEventBus(eventBus),
+			)
+
+			var connectOpts []auth.ConnectOption
+			if len(cfg.Connect.AllowedOrigins) > 0 {
+				connectOpts = append(connectOpts, auth.WithConnectCORS(cfg.Connect.AllowedOrigins, cfg.Connect.CORSMaxAge))
+			}
+			connectPath, connectHandler := auth.NewConnectHandler(authGRPCHandlers, jwtManager, &storage, log, connectOpts...)
+
+			// gRPC-Web and Connect calls are authorized by method policies, not the REST
+			// middleware, health checks are public
+			httpMux := http.NewServeMux()
+			httpMux.Handle(health.LivenessPath, checker.LivenessHandler())
+			httpMux.Handle(health.ReadinessPath, checker.ReadinessHandler())
+			httpMux.Handle(connectPath, connectHandler)
+			httpMux.Handle("/", gen.HandlerFromMux(gen.NewStrictHandler(useCase, nil), router))
 
 			httpServer := http.Server{
 				Addr:         cfg.HTTPServer.Address,
 				ReadTimeout:  cfg.HTTPServer.Timeout,
 				WriteTimeout: cfg.HTTPServer.Timeout,
-				Handler:      gen.HandlerFromMux(gen.NewStrictHandler(useCase, nil), router),
+				Handler:      httpMux,
+				TLSConfig:    httpTLS,
 			}
 
-			authGRPCHandlers := auth.NewAuthHandlers(&storage, passwordHasher, jwtManager, buildinfo.New())
-			grpcServer, err := auth.NewGRPCServer(cfg.GRPCServer.Address, authGRPCHandlers, log)
-			if err != nil {
-				return err
+			// with a shared port TLS is terminated by the HTTP server in front of gRPC
+			grpcAddr, grpcTLSConfig := cfg.GRPCServer.Address, cfg.GRPCServer.TLS
+			if cfg.Multiplex.Address != "" {
+				grpcAddr, grpcTLSConfig = cfg.Multiplex.Address, cfg.Multiplex.TLS
 			}
 
-			grpcCloser, err := grpcServer.Run()
+			var grpcOpts []grpc.ServerOption
+			if cfg.Multiplex.Address == "" {
+				grpcTLS, err := serverTLS(cfg.GRPCServer.TLS, log)
+				if err != nil {
+					return err
+				}
+				if grpcTLS != nil {
+					grpcOpts = append(grpcOpts, grpc.Creds(credentials.NewTLS(grpcTLS)))
+				}
+			}
+
+			grpcServer, err := auth.NewGRPCServer(
+				cfg.GRPCServer.Address,
+				authGRPCHandlers,
+				auth.NewRelationHandlers(relations),
+				checker,
+				jwtManager,
+				&storage,
+				log,
+				grpcOpts...,
+			)
 			if err != nil {
 				return err
 			}
-			grpcGw, err := auth.NewGateway(ctx, cfg.GRPCServer.Address, ":9091", log)
+
+			gwOpts, err := gatewayOptions(cfg, grpcAddr, grpcTLSConfig, log)
 			if err != nil {
 				return err
 			}
-			grpcGwCloser, err := grpcGw.Start()
+
+			grpcGw, err := auth.NewGateway(ctx, grpcAddr, cfg.Gateway.Address, log, gwOpts...)
 			if err != nil {
 				return err
 			}
 
-			go func() {
-				if err = httpServer.ListenAndServe(); err != nil {
-					log.Error("ListenAndServe", slog.Any("err", err))
+			manager := service.NewManager(log)
+			if cfg.Multiplex.Address != "" {
+				muxTLS, err := serverTLS(cfg.Multiplex.TLS, log)
+				if err != nil {
+					return err
 				}
-			}()
-			log.Info("server listening:", slog.Any("port", cfg.HTTPServer.Address))
-			<-ctx.Done()
 
-			closeCtx, _ := context.WithTimeout(context.Background(), time.Second*5)
-			if err = httpServer.Shutdown(closeCtx); err != nil {
-				log.Error("httpServer.Shutdown", slog.Any("err", err))
-			}
+				muxServer, err := mux.NewServer(mux.Config{
+					Address:           cfg.Multiplex.Address,
+					TLS:               muxTLS,
+					ReadHeaderTimeout: cfg.Multiplex.ReadHeaderTimeout,
+					IdleTimeout:       cfg.Multiplex.IdleTimeout,
+					ShutdownTimeout:   cfg.Multiplex.ShutdownTimeout,
+				},
+					mux.Handler(grpcServer, grpcGw, auth.GatewayPathPrefix, httpServer.Handler),
+					log,
+					// the gateway's connection is to the shared port, closed once it's drained
+					grpcGw, grpcServer,
+				)
+				if err != nil {
+					return err
+				}
 
-			if err = storage.Close(); err != nil {
-				log.Error("storage.Close", slog.Any("err", err))
+				manager.AddService(muxServer, eventBus, checker)
+			} else {
+				// stopped in reverse order: services report NOT_SERVING and watch streams end
+				// first, then the gateway drains before the gRPC server behind it
+				manager.AddService(
+					service.NewHTTPServer(&httpServer, cfg.HTTPServer.ShutdownTimeout, log),
+					grpcServer,
+					grpcGw,
+					eventBus,
+					checker,
+				)
 			}
+			err = manager.Run(ctx)
 
-			grpcCloser()
-			grpcGwCloser()
+			if closeErr := storage.Close(); closeErr != nil {
+				log.Error("storage.Close", slog.Any("err", closeErr))
+			}
 
-			return nil
+			return err
 		},
 	}
 	c.Flags().StringVar(&configPath, "config", "", "path to config")
 	return c
 }
+
+// serverTLS - TLS of a listener with certificates reloaded from disk, nil when it
+// serves plaintext
+func serverTLS(cfg config.TLS, log *slog.Logger) (*tls.Config, error) {
+	if cfg.CertFile == "" {
+		return nil, nil
+	}
+
+	store, err := certs.New(certs.Files{
+		CertFile: cfg.CertFile,
+		KeyFile:  cfg.KeyFile,
+		CAFile:   cfg.ClientCAFile,
+	}, cfg.ReloadInterval, log)
+	if err != nil {
+		return nil, err
+	}
+
+	return store.ServerConfig(), nil
+}
+
+// gatewayOptions - timeouts, headers, error statuses and TLS of the gateway listener
+// and of its connections to the gRPC server at grpcAddr, which has grpcTLS
+func gatewayOptions(cfg *config.Config, grpcAddr string, grpcTLS config.TLS, log *slog.Logger) ([]auth.GatewayOption, error) {
+	statuses := make(map[codes.Code]int, len(cfg.Gateway.ErrorStatuses))
+	for name, httpStatus := range cfg.Gateway.ErrorStatuses {
+		var code codes.Code
+		if err := code.UnmarshalJSON([]byte(strconv.Quote(name))); err != nil {
+			return nil, fmt.Errorf("gateway error status of %s: %s", name, err)
+		}
+
+		if http.StatusText(httpStatus) == "" {
+			return nil, fmt.Errorf("gateway error status of %s: unknown http status %d", name, httpStatus)
+		}
+
+		statuses[code] = httpStatus
+	}
+
+	opts := []auth.GatewayOption{
+		auth.WithForwardedHeaders(cfg.Gateway.ForwardHeaders...),
+		auth.WithErrorStatuses(statuses),
+		auth.WithGatewayTimeouts(auth.GatewayTimeouts{
+			Read:     cfg.Gateway.ReadTimeout,
+			Write:    cfg.Gateway.WriteTimeout,
+			Idle:     cfg.Gateway.IdleTimeout,
+			Shutdown: cfg.Gateway.ShutdownTimeout,
+		}),
+	}
+
+	gwTLS, err := serverTLS(cfg.Gateway.TLS, log)
+	if err != nil {
+		return nil, err
+	}
+	if gwTLS != nil {
+		opts = append(opts, auth.WithGatewayTLS(gwTLS))
+	}
+
+	if grpcTLS.CertFile == "" {
+		return opts, nil
+	}
+
+	client := cfg.Gateway.GRPCTLS
+	store, err := certs.New(certs.Files{
+		CertFile: client.CertFile,
+		KeyFile:  client.KeyFile,
+		CAFile:   client.CAFile,
+	}, client.ReloadInterval, log)
+	if err != nil {
+		return nil, err
+	}
+
+	serverName := client.ServerName
+	if serverName == "" {
+		serverName, _, _ = net.SplitHostPort(grpcAddr)
+	}
+	if serverName == "" {
+		serverName = "localhost"
+	}
+
+	return append(opts, auth.WithGRPCCredentials(credentials.NewTLS(store.ClientConfig(serverName)))), nil
+}
+
+func namespaceConfigs(cfg config.Relations) []entity.NamespaceConfig {
+	namespaces := make([]entity.NamespaceConfig, 0, len(cfg.Namespaces))
+	for _, ns := range cfg.Namespaces {
+		namespace := entity.NamespaceConfig{
+			Name:      ns.Name,
+			Relations: make(map[string]entity.RelationConfig, len(ns.Relations)),
+		}
+
+		for _, rel := range ns.Relations {
+			relation := entity.RelationConfig{Computed: rel.Computed}
+			for _, ttu := range rel.TupleToUserset {
+				relation.TupleToUserset = append(relation.TupleToUserset, entity.TupleToUserset{
+					Tupleset: ttu.Tupleset,
+					Computed: ttu.Computed,
+				})
+			}
+
+			namespace.Relations[rel.Name] = relation
+		}
+
+		namespaces = append(namespaces, namespace)
+	}
+
+	return namespaces
+}
+
+func identityProviders(cfg config.Federation) (map[string]usecase.IdentityProvider, error) {
+	providers := make(map[string]usecase.IdentityProvider, len(cfg.Providers))
+	for _, p := range cfg.Providers {
+		if entity.IsLocalLoginMethod(p.Name) {
+			return nil, fmt.Errorf("provider name %s is reserved", p.Name)
+		}
+
+		providers[p.Name] = oidc.NewProvider(oidc.Config{
+			Issuer:       p.Issuer,
+			ClientID:     p.ClientID,
+			ClientSecret: p.ClientSecret,
+			RedirectURL:  cfg.CallbackURL,
+			Scopes:       p.Scopes,
+		}, nil)
+	}
+
+	return providers, nil
+}
+
+// identityNamespace - identities of federation and SAML providers and directories are
+// kept by the name of their source, so the names must differ
+func identityNamespace(cfg *config.Config) error {
+	names := make(map[string]bool)
+	add := func(name string) error {
+		if entity.IsLocalLoginMethod(name) {
+			return fmt.Errorf("provider name %s is reserved", name)
+		}
+
+		if names[name] {
+			return fmt.Errorf("provider name %s is used more than once", name)
+		}
+		names[name] = true
+
+		return nil
+	}
+
+	for _, p := range cfg.Federation.Providers {
+		if err := add(p.Name); err != nil {
+			return err
+		}
+	}
+
+	for _, p := range cfg.SAML.Providers {
+		if err := add(p.Name); err != nil {
+			return fmt.Errorf("saml: %s", err)
+		}
+	}
+
+	for _, d := range cfg.Directories {
+		if err := add(d.Name); err != nil {
+			return fmt.Errorf("directory: %s", err)
+		}
+	}
+
+	return nil
+}
+
+func loginDirectories(cfg []config.Directory) ([]login.Directory, error) {
+	directories := make([]login.Directory, 0, len(cfg))
+	for _, d := range cfg {
+		for group, role := range d.GroupRoles {
+			if !entity.IsRole(role) {
+				return nil, fmt.Errorf("unknown role %q of group %s of directory %s", role, group, d.Name)
+			}
+		}
+
+		backend, err := ldap.New(ldap.Config{
+			URL:          d.URL,
+			StartTLS:     d.StartTLS,
+			BindDN:       d.BindDN,
+			BindPassword: d.BindPassword,
+			BaseDN:       d.BaseDN,
+			UserFilter:   d.UserFilter,
+			GroupRoles:   d.GroupRoles,
+			Timeout:      d.Timeout,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("directory %s: %s", d.Name, err)
+		}
+
+		directories = append(directories, login.Directory{
+			Name:     d.Name,
+			Backend:  backend,
+			Domains:  d.Domains,
+			Fallback: d.Fallback,
+		})
+	}
+
+	return directories, nil
+}
+
+func samlConnections(cfg config.SAML) (usecase.SAMLSettings, error) {
+	if len(cfg.Providers) == 0 {
+		return usecase.SAMLSettings{}, nil
+	}
+
+	spConfig := saml.Config{EntityID: cfg.EntityID, ACSURL: cfg.ACSURL}
+	if cfg.Certificate != "" {
+		keyPair, err := tls.LoadX509KeyPair(cfg.Certificate, cfg.PrivateKey)
+		if err != nil {
+			return usecase.SAMLSettings{}, fmt.Errorf("failed to load saml key pair: %s", err)
+		}
+
+		key, ok := keyPair.PrivateKey.(*rsa.PrivateKey)
+		if !ok {
+			return usecase.SAMLSettings{}, fmt.Errorf("saml private key must be RSA")
+		}
+
+		cert, err := x509.ParseCertificate(keyPair.Certificate[0])
+		if err != nil {
+			return usecase.SAMLSettings{}, fmt.Errorf("failed to parse saml certificate: %s", err)
+		}
+
+		spConfig.Key = key
+		spConfig.Certificate = cert
+	}
+
+	sp, err := saml.New(spConfig)
+	if err != nil {
+		return usecase.SAMLSettings{}, err
+	}
+
+	settings := usecase.SAMLSettings{
+		ServiceProvider: sp,
+		Connections:     make(map[string]usecase.SAMLConnection, len(cfg.Providers)),
+	}
+	for _, p := range cfg.Providers {
+		for group, role := range p.GroupRoles {
+			if !entity.IsRole(role) {
+				return usecase.SAMLSettings{}, fmt.Errorf("unknown role %q of group %s of saml provider %s", role, group, p.Name)
+			}
+		}
+
+		metadata, err := os.ReadFile(p.Metadata)
+		if err != nil {
+			return usecase.SAMLSettings{}, err
+		}
+
+		idp, err := sp.Connect(metadata)
+		if err != nil {
+			return usecase.SAMLSettings{}, fmt.Errorf("saml provider %s: %s", p.Name, err)
+		}
+
+		settings.Connections[p.Name] = usecase.SAMLConnection{
+			Provider:       idp,
+			EmailAttribute: p.EmailAttribute,
+			NameAttribute:  p.NameAttribute,
+			RolesAttribute: p.RolesAttribute,
+			GroupRoles:     p.GroupRoles,
+		}
+	}
+
+	return settings, nil
+}
+
+func exchangePolicies(cfg config.TokenExchange) ([]oauth.ExchangePolicy, error) {
+	policies := make([]oauth.ExchangePolicy, 0, len(cfg.Policies))
+	for _, p := range cfg.Policies {
+		for _, role := range p.ActorRoles {
+			if !entity.IsRole(role) {
+				return nil, fmt.Errorf("unknown actor role %q of token exchange policy of %s", role, p.ClientID)
+			}
+		}
+
+		policies = append(policies, oauth.ExchangePolicy{
+			ClientID:   p.ClientID,
+			Audiences:  p.Audiences,
+			Scopes:     p.Scopes,
+			ActorRoles: p.ActorRoles,
+		})
+	}
+
+	return policies, nil
+}
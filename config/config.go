@@ -10,17 +10,116 @@ import (
 type Config struct {
 	HTTPServer HTTPServer `yaml:"http_server"`
 	GRPCServer GRPCServer `yaml:"grpc_server"`
+	Gateway    Gateway    `yaml:"gateway"`
+	Multiplex  Multiplex  `yaml:"multiplex"`
+	Connect    Connect    `yaml:"connect"`
+	Events     Events     `yaml:"events"`
+	Health     Health     `yaml:"health"`
 	Storage    Storage    `yaml:"storage"`
 	JWT        JWT        `yaml:"jwt"`
+	WebAuthn   WebAuthn   `yaml:"webauthn"`
+	Notifier   Notifier   `yaml:"notifier"`
+	MagicLink  MagicLink  `yaml:"magic_link"`
+	RBAC       RBAC       `yaml:"rbac"`
+	Relations  Relations  `yaml:"relations"`
+
+	TokenExchange TokenExchange `yaml:"token_exchange"`
+	Federation    Federation    `yaml:"federation"`
+	Directories   []Directory   `yaml:"directories"`
+	SAML          SAML          `yaml:"saml"`
 }
 
 type HTTPServer struct {
 	Address string        `yaml:"address" env-default:":8080"`
 	Timeout time.Duration `yaml:"timeout" env-default:"5s"`
+	// ShutdownTimeout - how long requests in flight are waited for on shutdown
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" env-default:"5s"`
+	TLS             TLS           `yaml:"tls"`
+	// ValidateResponses - responses are checked against the OpenAPI spec like requests
+	// are, and answered with 500 when they don't match. Meant for development
+	ValidateResponses bool `yaml:"validate_responses"`
 }
 
 type GRPCServer struct {
 	Address string `yaml:"address" env-default:":9090"`
+	TLS     TLS    `yaml:"tls"`
+}
+
+// Gateway - grpc-gateway proxying HTTP/JSON to the gRPC server
+type Gateway struct {
+	Address         string        `yaml:"address" env-default:":9091"`
+	ReadTimeout     time.Duration `yaml:"read_timeout" env-default:"5s"`
+	WriteTimeout    time.Duration `yaml:"write_timeout" env-default:"10s"`
+	IdleTimeout     time.Duration `yaml:"idle_timeout" env-default:"1m"`
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" env-default:"5s"`
+	// ForwardHeaders - HTTP headers passed to the gRPC server as metadata,
+	// Authorization is forwarded always
+	ForwardHeaders []string `yaml:"forward_headers" env-default:"Authorization,X-Request-ID"`
+	// ErrorStatuses - HTTP status by gRPC code name, e.g. NOT_FOUND: 410, overriding
+	// the standard mapping
+	ErrorStatuses map[string]int `yaml:"error_statuses"`
+	TLS           TLS            `yaml:"tls"`
+	// GRPCTLS - connection to the gRPC server, used when the gRPC server has TLS
+	GRPCTLS ClientTLS `yaml:"grpc_tls"`
+}
+
+// Multiplex - serves REST, the gateway and gRPC on Address instead of the separate
+// listeners when it is set. gRPC calls are told apart by content type, over TLS
+// HTTP/2 is negotiated with ALPN and plaintext gRPC uses h2c. Timeouts of the REST
+// server and the gateway don't apply, as gRPC streams may be long
+type Multiplex struct {
+	Address           string        `yaml:"address"`
+	ReadHeaderTimeout time.Duration `yaml:"read_header_timeout" env-default:"5s"`
+	IdleTimeout       time.Duration `yaml:"idle_timeout" env-default:"1m"`
+	ShutdownTimeout   time.Duration `yaml:"shutdown_timeout" env-default:"5s"`
+	// TLS - client certificates of ClientCAFile are required from REST clients too
+	TLS TLS `yaml:"tls"`
+}
+
+// Connect - gRPC-Web and Connect protocol handlers of AuthService for browsers, served
+// by the HTTP server under /auth.v1.AuthService/
+type Connect struct {
+	// AllowedOrigins - origins of frontends calling the handlers cross-origin, e.g.
+	// https://app.example.com, "*" allows any. Only same-origin calls work when empty
+	AllowedOrigins []string `yaml:"allowed_origins"`
+	// CORSMaxAge - how long browsers cache preflight responses
+	CORSMaxAge time.Duration `yaml:"cors_max_age" env-default:"2h"`
+}
+
+// Events - security events watched with the WatchSecurityEvents RPCs
+type Events struct {
+	// Buffer - events a watcher may lag behind, its stream is ended when it's exceeded
+	Buffer int `yaml:"buffer" env-default:"64"`
+}
+
+// Health - probes of the database, the signing key and the SMTP server, reported by
+// the gRPC health service and /healthz, /readyz of the http_server
+type Health struct {
+	Interval time.Duration `yaml:"interval" env-default:"10s"`
+	// Timeout - a probe taking longer fails
+	Timeout time.Duration `yaml:"timeout" env-default:"2s"`
+}
+
+// TLS - certificate of a listener, it serves plaintext when CertFile is empty.
+// Files are reloaded when they change, so certificates rotate without a restart
+type TLS struct {
+	CertFile string `yaml:"cert_file"`
+	KeyFile  string `yaml:"key_file"`
+	// ClientCAFile - CA bundle of service callers, client certificates are required when set
+	ClientCAFile   string        `yaml:"client_ca_file"`
+	ReloadInterval time.Duration `yaml:"reload_interval" env-default:"10s"`
+}
+
+// ClientTLS - TLS of outgoing connections
+type ClientTLS struct {
+	// CAFile - CA bundle of the server certificate, system roots are used when empty
+	CAFile string `yaml:"ca_file"`
+	// CertFile, KeyFile - client certificate for servers requiring mutual TLS
+	CertFile string `yaml:"cert_file"`
+	KeyFile  string `yaml:"key_file"`
+	// ServerName - name verified in the server certificate, host of the address when empty
+	ServerName     string        `yaml:"server_name"`
+	ReloadInterval time.Duration `yaml:"reload_interval" env-default:"10s"`
 }
 
 type Storage struct {
@@ -34,6 +133,130 @@ type JWT struct {
 	PrivateKey string        `yaml:"private_key"`
 }
 
+type WebAuthn struct {
+	RPID          string   `yaml:"rp_id" env-default:"localhost"`
+	RPDisplayName string   `yaml:"rp_display_name" env-default:"auth-go"`
+	RPOrigins     []string `yaml:"rp_origins" env-default:"http://localhost:8080"`
+}
+
+// Notifier - messages are only logged when smtp address is empty
+type Notifier struct {
+	SMTPAddress  string `yaml:"smtp_address"`
+	From         string `yaml:"from" env-default:"no-reply@localhost"`
+	SMTPUsername string `yaml:"smtp_username"`
+	SMTPPassword string `yaml:"smtp_password"`
+}
+
+type MagicLink struct {
+	// LinkURL - page which posts the token from its query to /magic-link/consume
+	LinkURL string        `yaml:"link_url" env-default:"http://localhost:8080/magic-link"`
+	TTL     time.Duration `yaml:"ttl" env-default:"15m"`
+	// Limit - links per address within Window
+	Limit  int           `yaml:"limit" env-default:"3"`
+	Window time.Duration `yaml:"window" env-default:"15m"`
+}
+
+type RBAC struct {
+	// Admins - usernames granted admin role on startup, the users must be registered
+	Admins []string `yaml:"admins"`
+}
+
+// Relations - namespace configs of relation tuples
+type Relations struct {
+	Namespaces []Namespace `yaml:"namespaces"`
+}
+
+type Namespace struct {
+	Name      string     `yaml:"name"`
+	Relations []Relation `yaml:"relations"`
+}
+
+type Relation struct {
+	Name string `yaml:"name"`
+	// Computed - relations of the same object implying this one
+	Computed       []string         `yaml:"computed"`
+	TupleToUserset []TupleToUserset `yaml:"tuple_to_userset"`
+}
+
+// TupleToUserset - e.g. tupleset "parent" and computed "viewer" for viewers of the parent folder
+type TupleToUserset struct {
+	Tupleset string `yaml:"tupleset"`
+	Computed string `yaml:"computed"`
+}
+
+// TokenExchange - RFC 8693 grant is enabled when there is at least one policy
+type TokenExchange struct {
+	Policies []ExchangePolicy `yaml:"policies"`
+}
+
+type ExchangePolicy struct {
+	ClientID  string   `yaml:"client_id"`
+	Audiences []string `yaml:"audiences"`
+	// Scopes - upper bound of scopes of issued tokens
+	Scopes []string `yaml:"scopes"`
+	// ActorRoles - roles of actors allowed to impersonate users through the client, e.g. moderator
+	ActorRoles []string `yaml:"actor_roles"`
+}
+
+// Federation - external OpenID Connect providers users may sign in with
+type Federation struct {
+	// CallbackURL - public URL of /federation/callback, registered at every provider
+	CallbackURL string               `yaml:"callback_url" env-default:"http://localhost:8080/federation/callback"`
+	Providers   []FederationProvider `yaml:"providers"`
+}
+
+type FederationProvider struct {
+	// Name - value of provider param of /federation/login
+	Name         string   `yaml:"name"`
+	Issuer       string   `yaml:"issuer"`
+	ClientID     string   `yaml:"client_id"`
+	ClientSecret string   `yaml:"client_secret"`
+	Scopes       []string `yaml:"scopes"`
+}
+
+// Directory - LDAP or Active Directory checking passwords of login
+type Directory struct {
+	Name string `yaml:"name"`
+	// URL - ldap:// or ldaps:// address
+	URL          string `yaml:"url"`
+	StartTLS     bool   `yaml:"start_tls"`
+	BindDN       string `yaml:"bind_dn"`
+	BindPassword string `yaml:"bind_password"`
+	BaseDN       string `yaml:"base_dn"`
+	// UserFilter - %s is replaced with the username
+	UserFilter string `yaml:"user_filter" env-default:"(userPrincipalName=%s)"`
+	// GroupRoles - role by group DN, roles are granted and revoked on every login
+	GroupRoles map[string]string `yaml:"group_roles"`
+	// Domains - usernames of the domains are checked by the directory only and can't be
+	// registered locally
+	Domains []string `yaml:"domains"`
+	// Fallback - the directory is asked when the local password check fails
+	Fallback bool          `yaml:"fallback"`
+	Timeout  time.Duration `yaml:"timeout" env-default:"5s"`
+}
+
+// SAML - service provider of enterprise SSO, enabled when there is at least one provider
+type SAML struct {
+	EntityID string `yaml:"entity_id" env-default:"http://localhost:8080/saml/metadata"`
+	ACSURL   string `yaml:"acs_url" env-default:"http://localhost:8080/saml/acs"`
+	// Certificate, PrivateKey - optional PEM files, for identity providers encrypting assertions
+	Certificate string         `yaml:"certificate"`
+	PrivateKey  string         `yaml:"private_key"`
+	Providers   []SAMLProvider `yaml:"providers"`
+}
+
+type SAMLProvider struct {
+	// Name - value of provider param of /saml/login
+	Name string `yaml:"name"`
+	// Metadata - XML metadata file of the identity provider
+	Metadata       string `yaml:"metadata"`
+	EmailAttribute string `yaml:"email_attribute"`
+	NameAttribute  string `yaml:"name_attribute"`
+	// RolesAttribute - attribute of groups, mapped to roles by GroupRoles
+	RolesAttribute string            `yaml:"roles_attribute"`
+	GroupRoles     map[string]string `yaml:"group_roles"`
+}
+
 func Parse(s string) (*Config, error) {
 	c := &Config{}
 	if err := cleanenv.ReadConfig(s, c); err != nil {
@@ -0,0 +1,288 @@
+// Package account - registration, login and tokens of user accounts, shared by REST and
+// gRPC so both APIs behave the same
+package account
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/bogatyr285/auth-go/internal/auth/entity"
+	"github.com/bogatyr285/auth-go/internal/auth/login"
+	"github.com/bogatyr285/auth-go/internal/buildinfo"
+	pkgjwt "github.com/bogatyr285/auth-go/pkg/jwt"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+const (
+	// TokenUseMFA - token_use of tokens proving the first factor, they are exchanged
+	// for the token pair once the second factor is passed
+	TokenUseMFA = "mfa"
+	mfaTokenTTL = 5 * time.Minute
+)
+
+var (
+	ErrInvalidCredentials  = login.ErrInvalidCredentials
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+	ErrUsernameRequired    = errors.New("username is required")
+	ErrUsernameTaken       = errors.New("username is taken")
+)
+
+//go:generate mockgen -source=account.go -destination=../../mocks/account_mock.go -package mocks -exclude_interfaces=CryptoPassword,JWTManager,Publisher -mock_names=Repository=MockAccountRepository
+type Repository interface {
+	login.Repository
+	RegisterUser(ctx context.Context, u entity.UserAccount) error
+	GenerateUserToken(ctx context.Context, userID int) (uuid.UUID, error)
+	ExistsTokenByUserID(ctx context.Context, userID int) (string, error)
+	SelectUserByToken(ctx context.Context, token string) (entity.UserAccount, error)
+	ListWebAuthnCredentials(ctx context.Context, userID int) ([]entity.WebAuthnCredential, error)
+}
+
+type CryptoPassword interface {
+	HashPassword(password string) ([]byte, error)
+	ComparePasswords(fromUser, fromDB string) bool
+}
+
+type JWTManager interface {
+	IssueTokenWithClaims(userID string, extra jwt.MapClaims) (string, error)
+}
+
+// Publisher - receives security events of accounts, e.g. events.Bus
+type Publisher interface {
+	Publish(e entity.SecurityEvent)
+}
+
+// Session - outcome of a login, either the token pair or the mfa challenge
+type Session struct {
+	AccessToken  string
+	RefreshToken string
+	// MFAToken - set instead of the token pair when a second factor is required
+	MFAToken string
+	// MFAMethods - second factors the mfa token can be exchanged with
+	MFAMethods []string
+}
+
+// MFARequired - the first factor is not enough
+func (s Session) MFARequired() bool {
+	return s.MFAToken != ""
+}
+
+type Service struct {
+	repo        Repository
+	cp          CryptoPassword
+	jm          JWTManager
+	bi          buildinfo.BuildInfo
+	directories []login.Directory
+	events      Publisher
+}
+
+type Option func(*Service)
+
+// WithDirectories - checked by Login along with local passwords
+func WithDirectories(directories ...login.Directory) Option {
+	return func(s *Service) {
+		s.directories = directories
+	}
+}
+
+// WithEvents - logins are published, events are dropped when unset
+func WithEvents(p Publisher) Option {
+	return func(s *Service) {
+		s.events = p
+	}
+}
+
+func New(repo Repository, cp CryptoPassword, jm JWTManager, bi buildinfo.BuildInfo, opts ...Option) *Service {
+	s := &Service{
+		repo: repo,
+		cp:   cp,
+		jm:   jm,
+		bi:   bi,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// Register - creates the account with the password, usernames which are emails become
+// the (unverified) email of the account. Usernames of directory domains are taken by
+// the directory's users
+func (s *Service) Register(ctx context.Context, user entity.UserAccount, password string) (entity.UserAccount, error) {
+	user.Username = strings.TrimSpace(user.Username)
+	if user.Username == "" {
+		return entity.UserAccount{}, ErrUsernameRequired
+	}
+
+	if _, ok := login.DomainDirectory(s.directories, user.Username); ok {
+		return entity.UserAccount{}, ErrUsernameTaken
+	}
+
+	exists, err := s.repo.ExistsUserByUsername(ctx, user.Username)
+	if err != nil {
+		return entity.UserAccount{}, err
+	}
+
+	if exists {
+		return entity.UserAccount{}, ErrUsernameTaken
+	}
+
+	hashedPassword, err := s.cp.HashPassword(password)
+	if err != nil {
+		return entity.UserAccount{}, fmt.Errorf("failed to hash password: %s", err)
+	}
+	user.Password = string(hashedPassword)
+
+	if addr, err := mail.ParseAddress(user.Username); user.Email == "" && err == nil && addr.Address == user.Username {
+		user.Email = user.Username
+	}
+
+	if err = s.repo.RegisterUser(ctx, user); err != nil {
+		return entity.UserAccount{}, err
+	}
+
+	return user, nil
+}
+
+// Login - checks the password with local accounts and directories, ErrInvalidCredentials
+// is returned when it doesn't match
+func (s *Service) Login(ctx context.Context, username, password string) (Session, error) {
+	user, err := login.NewAuthenticator(s.repo, s.cp, s.directories...).Authenticate(ctx, username, password)
+	if err != nil {
+		return Session{}, err
+	}
+
+	return s.SignIn(ctx, user)
+}
+
+// SignIn - session of the user who passed the first factor, by password or any other
+// login method. The token pair is issued when the user has no second factor enrolled
+func (s *Service) SignIn(ctx context.Context, user entity.UserAccount) (Session, error) {
+	methods, err := s.secondFactorMethods(ctx, user)
+	if err != nil {
+		return Session{}, err
+	}
+
+	if len(methods) == 0 {
+		return s.IssueTokens(ctx, user)
+	}
+
+	mfaToken, err := s.jm.IssueTokenWithClaims(user.Username, jwt.MapClaims{
+		pkgjwt.TokenUseClaim: TokenUseMFA,
+		"exp":                time.Now().Add(mfaTokenTTL).Unix(),
+	})
+	if err != nil {
+		return Session{}, err
+	}
+
+	return Session{MFAToken: mfaToken, MFAMethods: methods}, nil
+}
+
+// IssueTokens - new access token and the user's refresh token (created on first login)
+// for the user who passed all factors
+func (s *Service) IssueTokens(ctx context.Context, user entity.UserAccount) (Session, error) {
+	token, err := s.AccessToken(ctx, user, time.Now())
+	if err != nil {
+		return Session{}, err
+	}
+
+	refreshToken, err := s.repo.ExistsTokenByUserID(ctx, user.ID)
+	if err != nil {
+		return Session{}, err
+	}
+
+	if refreshToken == "" {
+		var rt uuid.UUID
+		rt, err = s.repo.GenerateUserToken(ctx, user.ID)
+		if err != nil {
+			return Session{}, err
+		}
+
+		refreshToken = rt.String()
+	}
+
+	if s.events != nil {
+		s.events.Publish(entity.SecurityEvent{
+			Type:     entity.SecurityEventLogin,
+			UserID:   user.ID,
+			Username: user.Username,
+		})
+	}
+
+	return Session{AccessToken: token, RefreshToken: refreshToken}, nil
+}
+
+// AccessToken - access token with the user's roles embedded, authTime is omitted when zero
+func (s *Service) AccessToken(ctx context.Context, user entity.UserAccount, authTime time.Time) (string, error) {
+	roles, err := s.repo.ListUserRoles(ctx, user.ID)
+	if err != nil {
+		return "", err
+	}
+
+	claims := jwt.MapClaims{
+		pkgjwt.RolesClaim: roles,
+	}
+	if !authTime.IsZero() {
+		claims[pkgjwt.AuthTimeClaim] = authTime.Unix()
+	}
+
+	return s.jm.IssueTokenWithClaims(user.Username, claims)
+}
+
+// Refresh - new access token for the refresh token. Refreshed tokens don't prove the
+// user signed in recently, so they carry no auth time
+func (s *Service) Refresh(ctx context.Context, refreshToken string) (Session, error) {
+	if refreshToken == "" {
+		return Session{}, ErrInvalidRefreshToken
+	}
+
+	user, err := s.repo.SelectUserByToken(ctx, refreshToken)
+	if err != nil {
+		if errors.Is(err, entity.ErrUserNotFound) {
+			return Session{}, ErrInvalidRefreshToken
+		}
+
+		return Session{}, err
+	}
+
+	token, err := s.AccessToken(ctx, user, time.Time{})
+	if err != nil {
+		return Session{}, err
+	}
+
+	return Session{AccessToken: token, RefreshToken: refreshToken}, nil
+}
+
+// GetUser - entity.ErrUserNotFound is returned for unknown IDs
+func (s *Service) GetUser(ctx context.Context, ID int) (entity.UserAccount, error) {
+	if ID <= 0 {
+		return entity.UserAccount{}, entity.ErrUserNotFound
+	}
+
+	return s.repo.GetUserById(ctx, ID)
+}
+
+func (s *Service) BuildInfo() buildinfo.BuildInfo {
+	return s.bi
+}
+
+// secondFactorMethods - second factors enrolled by the user, password alone is enough when empty
+func (s *Service) secondFactorMethods(ctx context.Context, user entity.UserAccount) ([]string, error) {
+	var methods []string
+
+	credentials, err := s.repo.ListWebAuthnCredentials(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(credentials) > 0 {
+		methods = append(methods, "webauthn")
+	}
+
+	return methods, nil
+}
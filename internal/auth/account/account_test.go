@@ -0,0 +1,49 @@
+package account_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/bogatyr285/auth-go/internal/auth/account"
+	"github.com/bogatyr285/auth-go/internal/auth/entity"
+	"github.com/bogatyr285/auth-go/internal/auth/login"
+	"github.com/bogatyr285/auth-go/internal/auth/repository"
+	"github.com/bogatyr285/auth-go/internal/buildinfo"
+	"github.com/bogatyr285/auth-go/pkg/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+// noDirectory - directory rejecting every password
+type noDirectory struct{}
+
+func (noDirectory) Authenticate(context.Context, string, string) (entity.DirectoryUser, error) {
+	return entity.DirectoryUser{}, login.ErrInvalidCredentials
+}
+
+func (noDirectory) Roles() []string {
+	return nil
+}
+
+func TestRegister(t *testing.T) {
+	storage, err := repository.New(filepath.Join(t.TempDir(), "db.sqlite"))
+	require.NoError(t, err)
+	t.Cleanup(func() { storage.Close() })
+
+	accounts := account.New(&storage, crypto.NewPasswordHasher(), newTestJWTManager(t), buildinfo.New(),
+		account.WithDirectories(login.Directory{Name: "corp", Backend: noDirectory{}, Domains: []string{"corp.example.com"}}),
+	)
+
+	t.Run("usernames of directory domains are taken", func(t *testing.T) {
+		for _, username := range []string{"ceo@corp.example.com", "CEO@Corp.Example.com"} {
+			_, err := accounts.Register(context.Background(), entity.UserAccount{Username: username}, "rLy_5tr0nG!")
+			require.ErrorIs(t, err, account.ErrUsernameTaken)
+		}
+	})
+
+	t.Run("other usernames", func(t *testing.T) {
+		user, err := accounts.Register(context.Background(), entity.UserAccount{Username: "ceo@example.com"}, "rLy_5tr0nG!")
+		require.NoError(t, err)
+		require.Equal(t, "ceo@example.com", user.Email)
+	})
+}
@@ -0,0 +1,323 @@
+package account_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/bogatyr285/auth-go/internal/auth/account"
+	"github.com/bogatyr285/auth-go/internal/auth/entity"
+	"github.com/bogatyr285/auth-go/internal/auth/repository"
+	"github.com/bogatyr285/auth-go/internal/auth/usecase"
+	"github.com/bogatyr285/auth-go/internal/buildinfo"
+	"github.com/bogatyr285/auth-go/internal/gateway/grpc/auth"
+	"github.com/bogatyr285/auth-go/internal/gateway/http/gen"
+	"github.com/bogatyr285/auth-go/pkg/crypto"
+	"github.com/bogatyr285/auth-go/pkg/jwt"
+	authpb "github.com/bogatyr285/auth-go/pkg/server/grpc/auth"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// outcome - result of a call, the same for both transports
+type outcome string
+
+const (
+	ok              outcome = "ok"
+	invalid         outcome = "invalid"
+	unauthenticated outcome = "unauthenticated"
+	forbidden       outcome = "forbidden"
+	notFound        outcome = "not found"
+	conflict        outcome = "conflict"
+)
+
+// transport - the account API as served by REST or gRPC
+type transport interface {
+	register(t *testing.T, username, password string) outcome
+	login(t *testing.T, username, password string) (account.Session, outcome)
+	refresh(t *testing.T, refreshToken string) (account.Session, outcome)
+	getUser(t *testing.T, token string, ID int) (string, outcome)
+	buildInfo(t *testing.T) (buildinfo.BuildInfo, outcome)
+}
+
+// TestContract - REST and gRPC APIs behave the same
+func TestContract(t *testing.T) {
+	for name, newTransport := range map[string]func(*testing.T, *repository.SQLLiteStorage) transport{
+		"rest": newRESTTransport,
+		"grpc": newGRPCTransport,
+	} {
+		t.Run(name, func(t *testing.T) {
+			storage, err := repository.New(filepath.Join(t.TempDir(), "db.sqlite"))
+			require.NoError(t, err)
+			t.Cleanup(func() { storage.Close() })
+
+			testContract(t, newTransport(t, &storage), &storage)
+		})
+	}
+}
+
+func testContract(t *testing.T, tr transport, storage *repository.SQLLiteStorage) {
+	const (
+		alice    = "alice@example.com"
+		password = "rLy_5tr0nG!"
+	)
+
+	t.Run("register", func(t *testing.T) {
+		require.Equal(t, ok, tr.register(t, alice, password))
+		require.Equal(t, conflict, tr.register(t, alice, password))
+		require.Equal(t, invalid, tr.register(t, "", password))
+	})
+
+	var session account.Session
+	t.Run("login", func(t *testing.T) {
+		_, res := tr.login(t, alice, "wrong")
+		require.Equal(t, unauthenticated, res)
+
+		_, res = tr.login(t, "bob@example.com", password)
+		require.Equal(t, unauthenticated, res)
+
+		session, res = tr.login(t, alice, password)
+		require.Equal(t, ok, res)
+		require.False(t, session.MFARequired())
+		require.NotEmpty(t, session.AccessToken)
+		require.NotEmpty(t, session.RefreshToken)
+	})
+
+	t.Run("refresh", func(t *testing.T) {
+		refreshed, res := tr.refresh(t, session.RefreshToken)
+		require.Equal(t, ok, res)
+		require.NotEmpty(t, refreshed.AccessToken)
+		require.Equal(t, session.RefreshToken, refreshed.RefreshToken)
+
+		_, res = tr.refresh(t, "bogus")
+		require.Equal(t, unauthenticated, res)
+	})
+
+	t.Run("get user", func(t *testing.T) {
+		_, res := tr.getUser(t, "", 1)
+		require.Equal(t, unauthenticated, res)
+
+		_, res = tr.getUser(t, session.AccessToken, 1)
+		require.Equal(t, forbidden, res)
+
+		require.NoError(t, storage.GrantUserRole(context.Background(), 1, entity.RoleAdmin))
+
+		// roles are embedded into access tokens, refreshed ones have the new role
+		refreshed, res := tr.refresh(t, session.RefreshToken)
+		require.Equal(t, ok, res)
+
+		username, res := tr.getUser(t, refreshed.AccessToken, 1)
+		require.Equal(t, ok, res)
+		require.Equal(t, alice, username)
+
+		_, res = tr.getUser(t, refreshed.AccessToken, 42)
+		require.Equal(t, notFound, res)
+	})
+
+	t.Run("build info", func(t *testing.T) {
+		bi, res := tr.buildInfo(t)
+		require.Equal(t, ok, res)
+		require.Equal(t, runtime.Version(), bi.GoVersion)
+	})
+}
+
+type restTransport struct {
+	srv *httptest.Server
+}
+
+func newRESTTransport(t *testing.T, storage *repository.SQLLiteStorage) transport {
+	jm := newTestJWTManager(t)
+	accounts := account.New(storage, crypto.NewPasswordHasher(), jm, buildinfo.New())
+	useCase := usecase.NewUseCase(storage, accounts, jm)
+
+	router := chi.NewRouter()
+	router.Use(useCase.AuthMiddleware)
+
+	srv := httptest.NewServer(gen.HandlerFromMux(gen.NewStrictHandler(useCase, nil), router))
+	t.Cleanup(srv.Close)
+
+	return restTransport{srv: srv}
+}
+
+func (r restTransport) do(t *testing.T, method, path, token string, body, out any) outcome {
+	var reader bytes.Buffer
+	if body != nil {
+		require.NoError(t, json.NewEncoder(&reader).Encode(body))
+	}
+
+	req, err := http.NewRequest(method, r.srv.URL+path, &reader)
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	res, err := r.srv.Client().Do(req)
+	require.NoError(t, err)
+	defer res.Body.Close()
+
+	switch res.StatusCode {
+	case http.StatusOK, http.StatusCreated:
+		if out != nil {
+			require.NoError(t, json.NewDecoder(res.Body).Decode(out))
+		}
+		return ok
+	case http.StatusBadRequest:
+		return invalid
+	case http.StatusUnauthorized:
+		return unauthenticated
+	case http.StatusForbidden:
+		return forbidden
+	case http.StatusNotFound:
+		return notFound
+	case http.StatusConflict:
+		return conflict
+	}
+
+	return outcome(res.Status)
+}
+
+func (r restTransport) register(t *testing.T, username, password string) outcome {
+	return r.do(t, http.MethodPost, "/register", "", gen.RegisterUserRequest{Username: username, Password: password}, nil)
+}
+
+func (r restTransport) login(t *testing.T, username, password string) (account.Session, outcome) {
+	var res gen.LoginUserResponse
+	out := r.do(t, http.MethodPost, "/login", "", gen.LoginUserRequest{Username: username, Password: password}, &res)
+	return account.Session{AccessToken: res.AccessToken, RefreshToken: res.RefreshToken}, out
+}
+
+func (r restTransport) refresh(t *testing.T, refreshToken string) (account.Session, outcome) {
+	var res gen.TokenResponse
+	out := r.do(t, http.MethodPost, "/refresh", "", gen.TokenRequest{RefreshToken: refreshToken}, &res)
+	return account.Session{AccessToken: res.AccessToken, RefreshToken: res.RefreshToken}, out
+}
+
+func (r restTransport) getUser(t *testing.T, token string, ID int) (string, outcome) {
+	var res gen.UserInfo
+	out := r.do(t, http.MethodGet, fmt.Sprintf("/users/%d", ID), token, nil, &res)
+	return res.Username, out
+}
+
+func (r restTransport) buildInfo(t *testing.T) (buildinfo.BuildInfo, outcome) {
+	var res gen.BuildInfo
+	out := r.do(t, http.MethodGet, "/buildinfo", "", nil, &res)
+	return buildinfo.BuildInfo{Version: res.Version, GoVersion: res.GoVersion}, out
+}
+
+type grpcTransport struct {
+	client authpb.AuthServiceClient
+}
+
+func newGRPCTransport(t *testing.T, storage *repository.SQLLiteStorage) transport {
+	jm := newTestJWTManager(t)
+
+	srv := grpc.NewServer(grpc.UnaryInterceptor(auth.AuthUnaryInterceptor(jm, storage)))
+	hasher := crypto.NewPasswordHasher()
+	authpb.RegisterAuthServiceServer(srv, auth.NewAuthHandlers(storage, hasher, account.New(storage, hasher, jm, buildinfo.New())))
+
+	lis := bufconn.Listen(1 << 20)
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return grpcTransport{client: authpb.NewAuthServiceClient(conn)}
+}
+
+func grpcOutcome(t *testing.T, err error) outcome {
+	switch status.Code(err) {
+	case codes.OK:
+		return ok
+	case codes.InvalidArgument:
+		return invalid
+	case codes.Unauthenticated:
+		return unauthenticated
+	case codes.PermissionDenied:
+		return forbidden
+	case codes.NotFound:
+		return notFound
+	case codes.AlreadyExists:
+		return conflict
+	}
+
+	t.Logf("unexpected error: %s", err)
+	return outcome(status.Code(err).String())
+}
+
+func (g grpcTransport) register(t *testing.T, username, password string) outcome {
+	_, err := g.client.RegisterUser(context.Background(), &authpb.RegisterUserRequest{
+		User:     &authpb.User{Name: username},
+		Password: password,
+	})
+	return grpcOutcome(t, err)
+}
+
+func (g grpcTransport) login(t *testing.T, username, password string) (account.Session, outcome) {
+	res, err := g.client.LoginUser(context.Background(), &authpb.LoginUserRequest{
+		LoginMethod: &authpb.LoginUserRequest_Email{Email: username},
+		Password:    password,
+	})
+	return account.Session{AccessToken: res.GetToken(), RefreshToken: res.GetRefreshToken()}, grpcOutcome(t, err)
+}
+
+func (g grpcTransport) refresh(t *testing.T, refreshToken string) (account.Session, outcome) {
+	res, err := g.client.Refresh(context.Background(), &authpb.RefreshRequest{RefreshToken: refreshToken})
+	return account.Session{AccessToken: res.GetToken(), RefreshToken: res.GetRefreshToken()}, grpcOutcome(t, err)
+}
+
+func (g grpcTransport) getUser(t *testing.T, token string, ID int) (string, outcome) {
+	ctx := context.Background()
+	if token != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token)
+	}
+
+	res, err := g.client.GetUser(ctx, &authpb.GetUserRequest{Id: int64(ID)})
+	return res.GetUsername(), grpcOutcome(t, err)
+}
+
+func (g grpcTransport) buildInfo(t *testing.T) (buildinfo.BuildInfo, outcome) {
+	res, err := g.client.BuildInfo(context.Background(), &authpb.BuildInfoRequest{})
+	return buildinfo.BuildInfo{Version: res.GetVersion(), GoVersion: res.GetGoVersion()}, grpcOutcome(t, err)
+}
+
+func newTestJWTManager(t *testing.T) *jwt.JWTManager {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	privDER, err := x509.MarshalPKCS8PrivateKey(priv)
+	require.NoError(t, err)
+	pubDER, err := x509.MarshalPKIXPublicKey(pub)
+	require.NoError(t, err)
+
+	jm, err := jwt.NewJWTManager("auth-service", time.Hour,
+		pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER}),
+		pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privDER}),
+	)
+	require.NoError(t, err)
+
+	return jm
+}
@@ -0,0 +1,346 @@
+// Package authz - relationship based authorization in the manner of Zanzibar:
+// relation tuples "object#relation@subject" are evaluated against namespace configs
+package authz
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/bogatyr285/auth-go/internal/auth/entity"
+)
+
+// maxDepth - limit of nested usersets a check or expand follows
+const maxDepth = 25
+
+var (
+	ErrInvalidRequest          = errors.New("invalid request")
+	ErrInvalidConsistencyToken = errors.New("invalid consistency token")
+	ErrDepthExceeded           = errors.New("max depth exceeded")
+)
+
+type TupleRepository interface {
+	WriteTuples(ctx context.Context, updates []entity.TupleUpdate) (int64, error)
+	LatestRevision(ctx context.Context) (int64, error)
+	ReadTuples(ctx context.Context, object entity.Object, relation string, rev int64) ([]entity.RelationTuple, error)
+	ListObjectIDs(ctx context.Context, namespace string, rev int64) ([]string, error)
+}
+
+type Engine struct {
+	tr         TupleRepository
+	namespaces map[string]entity.NamespaceConfig
+}
+
+// NewEngine - namespace configs are validated, relations may refer only to relations
+// of the same namespace config
+func NewEngine(tr TupleRepository, namespaces []entity.NamespaceConfig) (*Engine, error) {
+	e := &Engine{
+		tr:         tr,
+		namespaces: make(map[string]entity.NamespaceConfig, len(namespaces)),
+	}
+
+	for _, ns := range namespaces {
+		if _, ok := e.namespaces[ns.Name]; ok {
+			return nil, fmt.Errorf("namespace %s: duplicated", ns.Name)
+		}
+
+		for name, rel := range ns.Relations {
+			for _, c := range rel.Computed {
+				if _, ok := ns.Relations[c]; !ok || c == name {
+					return nil, fmt.Errorf("namespace %s: relation %s: invalid computed relation %q", ns.Name, name, c)
+				}
+			}
+
+			for _, ttu := range rel.TupleToUserset {
+				if _, ok := ns.Relations[ttu.Tupleset]; !ok {
+					return nil, fmt.Errorf("namespace %s: relation %s: unknown tupleset relation %q", ns.Name, name, ttu.Tupleset)
+				}
+				if ttu.Computed == "" {
+					return nil, fmt.Errorf("namespace %s: relation %s: computed relation of %s is required", ns.Name, name, ttu.Tupleset)
+				}
+			}
+		}
+
+		e.namespaces[ns.Name] = ns
+	}
+
+	return e, nil
+}
+
+// Write - applies updates atomically, the returned token makes later reads see them
+func (e *Engine) Write(ctx context.Context, updates []entity.TupleUpdate) (string, error) {
+	if len(updates) == 0 {
+		return "", fmt.Errorf("%w: no updates", ErrInvalidRequest)
+	}
+
+	for _, u := range updates {
+		if u.Operation != entity.TupleTouch && u.Operation != entity.TupleDelete {
+			return "", fmt.Errorf("%w: unknown operation %q", ErrInvalidRequest, u.Operation)
+		}
+
+		if _, err := e.relation(u.Tuple.Object.Namespace, u.Tuple.Relation); err != nil {
+			return "", err
+		}
+	}
+
+	rev, err := e.tr.WriteTuples(ctx, updates)
+	if err != nil {
+		return "", err
+	}
+
+	return encodeToken(rev), nil
+}
+
+// Check - whether the subject has the relation with the object. Reads are done at the latest
+// revision, which is at least the one of token; the returned token is the revision checked at
+func (e *Engine) Check(ctx context.Context, tuple entity.RelationTuple, token string) (bool, string, error) {
+	if _, err := e.relation(tuple.Object.Namespace, tuple.Relation); err != nil {
+		return false, "", err
+	}
+
+	rev, err := e.snapshot(ctx, token)
+	if err != nil {
+		return false, "", err
+	}
+
+	allowed, err := e.check(ctx, tuple.Object, tuple.Relation, tuple.Subject, rev, 0, map[string]bool{})
+	if err != nil {
+		return false, "", err
+	}
+
+	return allowed, encodeToken(rev), nil
+}
+
+// Expand - tree of usersets having the relation with the object
+func (e *Engine) Expand(ctx context.Context, object entity.Object, relation, token string) (entity.UsersetTree, string, error) {
+	if _, err := e.relation(object.Namespace, relation); err != nil {
+		return entity.UsersetTree{}, "", err
+	}
+
+	rev, err := e.snapshot(ctx, token)
+	if err != nil {
+		return entity.UsersetTree{}, "", err
+	}
+
+	tree, err := e.expand(ctx, object, relation, rev, 0, map[string]bool{})
+	if err != nil {
+		return entity.UsersetTree{}, "", err
+	}
+
+	return tree, encodeToken(rev), nil
+}
+
+// ListObjects - IDs of objects of the namespace the subject has the relation with.
+// Every object having tuples is checked, which is fine for moderate amounts of objects
+func (e *Engine) ListObjects(ctx context.Context, namespace, relation string, subject entity.Subject, token string) ([]string, string, error) {
+	if _, err := e.relation(namespace, relation); err != nil {
+		return nil, "", err
+	}
+
+	rev, err := e.snapshot(ctx, token)
+	if err != nil {
+		return nil, "", err
+	}
+
+	IDs, err := e.tr.ListObjectIDs(ctx, namespace, rev)
+	if err != nil {
+		return nil, "", err
+	}
+
+	objects := []string{}
+	for _, ID := range IDs {
+		object := entity.Object{Namespace: namespace, ID: ID}
+		allowed, err := e.check(ctx, object, relation, subject, rev, 0, map[string]bool{})
+		if err != nil {
+			return nil, "", err
+		}
+
+		if allowed {
+			objects = append(objects, ID)
+		}
+	}
+
+	return objects, encodeToken(rev), nil
+}
+
+func (e *Engine) relation(namespace, relation string) (entity.RelationConfig, error) {
+	ns, ok := e.namespaces[namespace]
+	if !ok {
+		return entity.RelationConfig{}, fmt.Errorf("%w: unknown namespace %q", ErrInvalidRequest, namespace)
+	}
+
+	rel, ok := ns.Relations[relation]
+	if !ok {
+		return entity.RelationConfig{}, fmt.Errorf("%w: unknown relation %q of namespace %q", ErrInvalidRequest, relation, namespace)
+	}
+
+	return rel, nil
+}
+
+// snapshot - revision to read at, token of a revision newer than the latest one
+// wasn't issued by this store
+func (e *Engine) snapshot(ctx context.Context, token string) (int64, error) {
+	latest, err := e.tr.LatestRevision(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	if token == "" {
+		return latest, nil
+	}
+
+	rev, err := decodeToken(token)
+	if err != nil || rev > latest {
+		return 0, ErrInvalidConsistencyToken
+	}
+
+	return latest, nil
+}
+
+// check - visited usersets are skipped, so cyclic usersets (e.g. nested groups) terminate
+func (e *Engine) check(ctx context.Context, object entity.Object, relation string, subject entity.Subject, rev int64, depth int, visited map[string]bool) (bool, error) {
+	if depth > maxDepth {
+		return false, ErrDepthExceeded
+	}
+
+	userset := entity.Subject{Object: object, Relation: relation}
+	if visited[userset.String()] {
+		return false, nil
+	}
+	visited[userset.String()] = true
+
+	// the subject may be the userset itself, e.g. group:eng#member is a member of group:eng
+	if userset == subject {
+		return true, nil
+	}
+
+	rel, err := e.relation(object.Namespace, relation)
+	if err != nil {
+		// tuples may point to usersets of relations which are not configured
+		return false, nil
+	}
+
+	tuples, err := e.tr.ReadTuples(ctx, object, relation, rev)
+	if err != nil {
+		return false, err
+	}
+
+	for _, t := range tuples {
+		if t.Subject == subject {
+			return true, nil
+		}
+
+		if t.Subject.Relation != "" {
+			ok, err := e.check(ctx, t.Subject.Object, t.Subject.Relation, subject, rev, depth+1, visited)
+			if err != nil || ok {
+				return ok, err
+			}
+		}
+	}
+
+	for _, c := range rel.Computed {
+		ok, err := e.check(ctx, object, c, subject, rev, depth+1, visited)
+		if err != nil || ok {
+			return ok, err
+		}
+	}
+
+	for _, ttu := range rel.TupleToUserset {
+		tuples, err := e.tr.ReadTuples(ctx, object, ttu.Tupleset, rev)
+		if err != nil {
+			return false, err
+		}
+
+		for _, t := range tuples {
+			ok, err := e.check(ctx, t.Subject.Object, ttu.Computed, subject, rev, depth+1, visited)
+			if err != nil || ok {
+				return ok, err
+			}
+		}
+	}
+
+	return false, nil
+}
+
+func (e *Engine) expand(ctx context.Context, object entity.Object, relation string, rev int64, depth int, visited map[string]bool) (entity.UsersetTree, error) {
+	tree := entity.UsersetTree{Object: object, Relation: relation}
+	if depth > maxDepth {
+		return tree, ErrDepthExceeded
+	}
+
+	userset := entity.Subject{Object: object, Relation: relation}
+	if visited[userset.String()] {
+		return tree, nil
+	}
+	visited[userset.String()] = true
+
+	rel, err := e.relation(object.Namespace, relation)
+	if err != nil {
+		return tree, nil
+	}
+
+	tuples, err := e.tr.ReadTuples(ctx, object, relation, rev)
+	if err != nil {
+		return tree, err
+	}
+
+	for _, t := range tuples {
+		tree.Subjects = append(tree.Subjects, t.Subject)
+
+		if t.Subject.Relation != "" {
+			child, err := e.expand(ctx, t.Subject.Object, t.Subject.Relation, rev, depth+1, visited)
+			if err != nil {
+				return tree, err
+			}
+			tree.Children = append(tree.Children, child)
+		}
+	}
+
+	for _, c := range rel.Computed {
+		child, err := e.expand(ctx, object, c, rev, depth+1, visited)
+		if err != nil {
+			return tree, err
+		}
+		tree.Children = append(tree.Children, child)
+	}
+
+	for _, ttu := range rel.TupleToUserset {
+		tuples, err := e.tr.ReadTuples(ctx, object, ttu.Tupleset, rev)
+		if err != nil {
+			return tree, err
+		}
+
+		for _, t := range tuples {
+			child, err := e.expand(ctx, t.Subject.Object, ttu.Computed, rev, depth+1, visited)
+			if err != nil {
+				return tree, err
+			}
+			tree.Children = append(tree.Children, child)
+		}
+	}
+
+	return tree, nil
+}
+
+const tokenPrefix = "rev:"
+
+func encodeToken(rev int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(tokenPrefix + strconv.FormatInt(rev, 10)))
+}
+
+func decodeToken(token string) (int64, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, err
+	}
+
+	rev, ok := strings.CutPrefix(string(raw), tokenPrefix)
+	if !ok {
+		return 0, ErrInvalidConsistencyToken
+	}
+
+	return strconv.ParseInt(rev, 10, 64)
+}
@@ -0,0 +1,164 @@
+package authz_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/bogatyr285/auth-go/internal/auth/authz"
+	"github.com/bogatyr285/auth-go/internal/auth/entity"
+	"github.com/bogatyr285/auth-go/internal/auth/repository"
+	"github.com/stretchr/testify/require"
+)
+
+var namespaces = []entity.NamespaceConfig{
+	{Name: "group", Relations: map[string]entity.RelationConfig{
+		"member": {},
+	}},
+	{Name: "folder", Relations: map[string]entity.RelationConfig{
+		"owner":  {},
+		"viewer": {Computed: []string{"owner"}},
+	}},
+	{Name: "document", Relations: map[string]entity.RelationConfig{
+		"parent": {},
+		"owner":  {},
+		"editor": {Computed: []string{"owner"}},
+		"viewer": {
+			Computed:       []string{"editor"},
+			TupleToUserset: []entity.TupleToUserset{{Tupleset: "parent", Computed: "viewer"}},
+		},
+	}},
+}
+
+func tuple(t *testing.T, object, relation, subject string) entity.RelationTuple {
+	tuple, err := entity.ParseTuple(object, relation, subject)
+	require.NoError(t, err)
+
+	return tuple
+}
+
+func newTestEngine(t *testing.T) *authz.Engine {
+	storage, err := repository.New(filepath.Join(t.TempDir(), "db.sqlite"))
+	require.NoError(t, err)
+	t.Cleanup(func() { storage.Close() })
+
+	engine, err := authz.NewEngine(&storage, namespaces)
+	require.NoError(t, err)
+
+	return engine
+}
+
+func TestEngine(t *testing.T) {
+	ctx := context.Background()
+	engine := newTestEngine(t)
+
+	var updates []entity.TupleUpdate
+	for _, tt := range [][3]string{
+		{"group:eng", "member", "user:alice"},
+		{"group:eng", "member", "group:oncall#member"},
+		{"group:oncall", "member", "user:carol"},
+		// cyclic nesting must not loop forever
+		{"group:oncall", "member", "group:eng#member"},
+		{"folder:docs", "viewer", "group:eng#member"},
+		{"document:readme", "parent", "folder:docs"},
+		{"document:readme", "owner", "user:bob"},
+		{"document:spec", "editor", "user:alice"},
+	} {
+		updates = append(updates, entity.TupleUpdate{Operation: entity.TupleTouch, Tuple: tuple(t, tt[0], tt[1], tt[2])})
+	}
+
+	token, err := engine.Write(ctx, updates)
+	require.NoError(t, err)
+
+	t.Run("check", func(t *testing.T) {
+		for _, tt := range []struct {
+			tuple   [3]string
+			allowed bool
+		}{
+			{[3]string{"document:readme", "owner", "user:bob"}, true},
+			{[3]string{"document:readme", "viewer", "user:bob"}, true},
+			{[3]string{"document:readme", "editor", "user:alice"}, false},
+			{[3]string{"document:readme", "viewer", "user:alice"}, true},
+			{[3]string{"document:readme", "viewer", "user:carol"}, true},
+			{[3]string{"document:readme", "viewer", "user:dave"}, false},
+			{[3]string{"document:readme", "viewer", "group:eng#member"}, true},
+			{[3]string{"document:spec", "viewer", "user:alice"}, true},
+			{[3]string{"document:spec", "viewer", "user:bob"}, false},
+		} {
+			allowed, checkedAt, err := engine.Check(ctx, tuple(t, tt.tuple[0], tt.tuple[1], tt.tuple[2]), token)
+			require.NoError(t, err)
+			require.Equal(t, tt.allowed, allowed, "%v", tt.tuple)
+			require.Equal(t, token, checkedAt)
+		}
+	})
+
+	t.Run("list objects", func(t *testing.T) {
+		objects, _, err := engine.ListObjects(ctx, "document", "viewer", entity.Subject{Object: entity.Object{Namespace: "user", ID: "alice"}}, "")
+		require.NoError(t, err)
+		require.Equal(t, []string{"readme", "spec"}, objects)
+
+		objects, _, err = engine.ListObjects(ctx, "document", "editor", entity.Subject{Object: entity.Object{Namespace: "user", ID: "bob"}}, "")
+		require.NoError(t, err)
+		require.Equal(t, []string{"readme"}, objects)
+	})
+
+	t.Run("expand", func(t *testing.T) {
+		tree, _, err := engine.Expand(ctx, entity.Object{Namespace: "document", ID: "readme"}, "viewer", token)
+		require.NoError(t, err)
+
+		require.Empty(t, tree.Subjects)
+		require.Len(t, tree.Children, 2)
+
+		editor := tree.Children[0]
+		require.Equal(t, "editor", editor.Relation)
+		require.Len(t, editor.Children, 1)
+		require.Equal(t, "user:bob", editor.Children[0].Subjects[0].String())
+
+		folder := tree.Children[1]
+		require.Equal(t, "folder:docs", folder.Object.String())
+		require.Equal(t, "group:eng#member", folder.Subjects[0].String())
+	})
+
+	t.Run("consistency tokens", func(t *testing.T) {
+		readme := tuple(t, "document:readme", "viewer", "user:bob")
+
+		deleted, err := engine.Write(ctx, []entity.TupleUpdate{
+			{Operation: entity.TupleDelete, Tuple: tuple(t, "document:readme", "owner", "user:bob")},
+		})
+		require.NoError(t, err)
+		require.NotEqual(t, token, deleted)
+
+		allowed, _, err := engine.Check(ctx, readme, deleted)
+		require.NoError(t, err)
+		require.False(t, allowed)
+
+		_, _, err = engine.Check(ctx, readme, "garbage")
+		require.ErrorIs(t, err, authz.ErrInvalidConsistencyToken)
+
+		// token of a revision this store hasn't reached
+		_, _, err = newTestEngine(t).Check(ctx, readme, deleted)
+		require.ErrorIs(t, err, authz.ErrInvalidConsistencyToken)
+	})
+
+	t.Run("invalid requests", func(t *testing.T) {
+		_, _, err := engine.Check(ctx, tuple(t, "spreadsheet:q1", "viewer", "user:bob"), "")
+		require.ErrorIs(t, err, authz.ErrInvalidRequest)
+
+		_, err = engine.Write(ctx, []entity.TupleUpdate{
+			{Operation: entity.TupleTouch, Tuple: tuple(t, "document:readme", "commenter", "user:bob")},
+		})
+		require.ErrorIs(t, err, authz.ErrInvalidRequest)
+	})
+}
+
+func TestNewEngine(t *testing.T) {
+	_, err := authz.NewEngine(nil, []entity.NamespaceConfig{{Name: "document", Relations: map[string]entity.RelationConfig{
+		"viewer": {Computed: []string{"editor"}},
+	}}})
+	require.Error(t, err)
+
+	_, err = authz.NewEngine(nil, []entity.NamespaceConfig{{Name: "document", Relations: map[string]entity.RelationConfig{
+		"viewer": {TupleToUserset: []entity.TupleToUserset{{Tupleset: "parent", Computed: "viewer"}}},
+	}}})
+	require.Error(t, err)
+}
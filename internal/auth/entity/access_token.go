@@ -0,0 +1,22 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+)
+
+var ErrAccessTokenNotFound = fmt.Errorf("access token not found")
+
+// PersonalAccessToken - db schema, long-lived token a user creates for scripts and CI.
+// Only the hash of the token is stored. Scopes limit the permissions the user's roles grant
+type PersonalAccessToken struct {
+	ID        string
+	UserID    int
+	Name      string
+	TokenHash string
+	Scopes    []Permission
+	ExpiresAt time.Time
+	// LastUsedAt - nil until the token is used
+	LastUsedAt *time.Time
+	CreatedAt  time.Time
+}
@@ -0,0 +1,10 @@
+package entity
+
+// DirectoryUser - user authenticated by an external directory, e.g. LDAP
+type DirectoryUser struct {
+	Username string
+	Name     string
+	Email    string
+	// Roles - roles the directory groups of the user are mapped to
+	Roles []string
+}
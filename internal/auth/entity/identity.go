@@ -0,0 +1,45 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+)
+
+// Login methods of the account itself, names of external providers must differ
+const (
+	IdentityPassword = "password"
+	IdentityPhone    = "phone"
+	IdentityWebAuthn = "webauthn"
+)
+
+var (
+	ErrIdentityNotFound = fmt.Errorf("login method not found")
+	ErrLastLoginMethod  = fmt.Errorf("the last login method of the account can't be unlinked")
+)
+
+// IsLocalLoginMethod - the name is reserved for login methods of the account itself
+func IsLocalLoginMethod(provider string) bool {
+	return provider == IdentityPassword || provider == IdentityPhone || provider == IdentityWebAuthn
+}
+
+// Identity - login method of a user: identity at an external provider, where subject is
+// the provider's ID of the user, or one of the account itself, e.g. its phone
+type Identity struct {
+	Provider  string
+	Subject   string
+	UserID    int
+	Email     string
+	CreatedAt time.Time
+}
+
+// FederatedLogin - state of a login redirected to an external provider, kept until
+// the provider redirects back
+type FederatedLogin struct {
+	StateHash    string
+	Provider     string
+	Nonce        string
+	CodeVerifier string
+	// UserID - set when the identity is being linked to the account instead of logging in
+	UserID    int
+	ExpiresAt time.Time
+}
@@ -0,0 +1,11 @@
+package entity
+
+import "time"
+
+// MagicLink - db schema, ID is the jti of the signed link token
+type MagicLink struct {
+	ID        string
+	Email     string
+	CreatedAt time.Time
+	ExpiresAt time.Time
+}
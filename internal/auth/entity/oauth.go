@@ -0,0 +1,70 @@
+package entity
+
+import "time"
+
+// OAuthClient - db schema, application allowed to request tokens on behalf of users
+// within Scopes. Confidential clients authenticate with secrets and may request tokens
+// on their own behalf within Scopes as well
+type OAuthClient struct {
+	ID           string
+	Name         string
+	RedirectURIs []string
+	Confidential bool
+	Scopes       []string
+	CreatedAt    time.Time
+}
+
+// ClientSecret - db schema, only the hash of the secret is stored. Secrets replaced by
+// rotation stay valid until ExpiresAt, nil means the secret doesn't expire
+type ClientSecret struct {
+	ID         int
+	ClientID   string
+	SecretHash string
+	CreatedAt  time.Time
+	ExpiresAt  *time.Time
+}
+
+// AuthorizationCode - db schema, only the hash of the code is stored
+type AuthorizationCode struct {
+	CodeHash      string
+	ClientID      string
+	UserID        int
+	RedirectURI   string
+	Scope         string
+	CodeChallenge string
+	ExpiresAt     time.Time
+	// Nonce and AuthTime - for ID tokens of OpenID Connect requests
+	Nonce    string
+	AuthTime time.Time
+}
+
+// OAuthGrant - db schema, scope the user consented to give the client
+type OAuthGrant struct {
+	UserID   int
+	ClientID string
+	Scope    string
+}
+
+// statuses of DeviceAuthorization
+const (
+	DeviceAuthorizationPending  = "pending"
+	DeviceAuthorizationApproved = "approved"
+	DeviceAuthorizationDenied   = "denied"
+	DeviceAuthorizationConsumed = "consumed"
+)
+
+// DeviceAuthorization - db schema, RFC 8628 request of a device waiting for the user
+// to approve it. Only the hash of the device code is stored
+type DeviceAuthorization struct {
+	DeviceCodeHash string
+	UserCode       string
+	ClientID       string
+	Scope          string
+	Status         string
+	UserID         int
+	// Interval - minimal time between polls of the token endpoint
+	Interval     time.Duration
+	LastPolledAt time.Time
+	ApprovedAt   time.Time
+	ExpiresAt    time.Time
+}
@@ -0,0 +1,19 @@
+package entity
+
+import "time"
+
+const (
+	OTPPurposeVerify = "verify"
+	OTPPurposeLogin  = "login"
+)
+
+// OTP - db schema, one-time code sent by SMS
+type OTP struct {
+	ID        int
+	Phone     string
+	Purpose   string
+	CodeHash  string
+	Attempts  int
+	ExpiresAt time.Time
+	CreatedAt time.Time
+}
@@ -0,0 +1,57 @@
+package entity
+
+import "slices"
+
+// Role names match UserRole values of auth.proto without the USER_ROLE_ prefix
+const (
+	RoleAdmin     = "admin"
+	RoleUser      = "user"
+	RoleModerator = "moderator"
+)
+
+type Permission string
+
+const (
+	PermissionProfileRead Permission = "profile:read"
+	PermissionUsersRead   Permission = "users:read"
+	PermissionRolesManage Permission = "roles:manage"
+	// relation tuples of RelationService, used by product services
+	PermissionRelationsRead  Permission = "relations:read"
+	PermissionRelationsWrite Permission = "relations:write"
+	// OAuth clients of the authorization server
+	PermissionClientsManage Permission = "clients:manage"
+	// security events of all users, every user watches their own with profile:read
+	PermissionSecurityEventsRead Permission = "security_events:read"
+)
+
+// RolePermissions - permissions granted by every role
+var RolePermissions = map[string][]Permission{
+	RoleUser:      {PermissionProfileRead},
+	RoleModerator: {PermissionProfileRead, PermissionUsersRead, PermissionRelationsRead},
+	RoleAdmin: {
+		PermissionProfileRead,
+		PermissionUsersRead,
+		PermissionRolesManage,
+		PermissionRelationsRead,
+		PermissionRelationsWrite,
+		PermissionClientsManage,
+		PermissionSecurityEventsRead,
+	},
+}
+
+// IsRole - checks the name is a known role
+func IsRole(role string) bool {
+	_, ok := RolePermissions[role]
+	return ok
+}
+
+// HasPermission - checks any of the roles grants the permission
+func HasPermission(roles []string, p Permission) bool {
+	for _, role := range roles {
+		if slices.Contains(RolePermissions[role], p) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,124 @@
+package entity
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	TupleTouch  = "touch"
+	TupleDelete = "delete"
+)
+
+// Object - "namespace:id", e.g. "document:readme"
+type Object struct {
+	Namespace string
+	ID        string
+}
+
+func (o Object) String() string {
+	return o.Namespace + ":" + o.ID
+}
+
+// Subject - a user ("user:alice") or a userset, i.e. everyone having
+// the relation with the object ("group:eng#member")
+type Subject struct {
+	Object
+	Relation string
+}
+
+func (s Subject) String() string {
+	if s.Relation == "" {
+		return s.Object.String()
+	}
+
+	return s.Object.String() + "#" + s.Relation
+}
+
+// RelationTuple - db schema, "object#relation@subject"
+type RelationTuple struct {
+	Object   Object
+	Relation string
+	Subject  Subject
+}
+
+func (t RelationTuple) String() string {
+	return t.Object.String() + "#" + t.Relation + "@" + t.Subject.String()
+}
+
+// TupleUpdate - TupleTouch writes the tuple if it doesn't exist yet, TupleDelete removes it
+type TupleUpdate struct {
+	Operation string
+	Tuple     RelationTuple
+}
+
+// UsersetTree - subjects having the relation with the object, children are
+// usersets the relation is derived from
+type UsersetTree struct {
+	Object   Object
+	Relation string
+	Subjects []Subject
+	Children []UsersetTree
+}
+
+// NamespaceConfig - relations objects of the namespace may have. Every relation
+// includes subjects of its own tuples and subjects of Computed and TupleToUserset
+type NamespaceConfig struct {
+	Name      string
+	Relations map[string]RelationConfig
+}
+
+type RelationConfig struct {
+	// Computed - relations of the same object implying this one, e.g. owner for editor
+	Computed []string
+	// TupleToUserset - e.g. {parent, viewer} for viewers of the parent folder
+	TupleToUserset []TupleToUserset
+}
+
+// TupleToUserset - subjects having Computed relation with objects which are
+// subjects of the Tupleset relation
+type TupleToUserset struct {
+	Tupleset string
+	Computed string
+}
+
+func ParseObject(s string) (Object, error) {
+	namespace, ID, ok := strings.Cut(s, ":")
+	if !ok || namespace == "" || ID == "" || strings.ContainsAny(s, "#@") {
+		return Object{}, fmt.Errorf("invalid object %q, expected namespace:id", s)
+	}
+
+	return Object{Namespace: namespace, ID: ID}, nil
+}
+
+func ParseSubject(s string) (Subject, error) {
+	object, relation, hasRelation := strings.Cut(s, "#")
+	if hasRelation && relation == "" {
+		return Subject{}, fmt.Errorf("invalid subject %q, expected namespace:id or namespace:id#relation", s)
+	}
+
+	o, err := ParseObject(object)
+	if err != nil {
+		return Subject{}, fmt.Errorf("invalid subject %q, expected namespace:id or namespace:id#relation", s)
+	}
+
+	return Subject{Object: o, Relation: relation}, nil
+}
+
+func ParseTuple(object, relation, subject string) (RelationTuple, error) {
+	o, err := ParseObject(object)
+	if err != nil {
+		return RelationTuple{}, err
+	}
+
+	if relation == "" {
+		return RelationTuple{}, fmt.Errorf("relation is required")
+	}
+
+	s, err := ParseSubject(subject)
+	if err != nil {
+		return RelationTuple{}, err
+	}
+
+	return RelationTuple{Object: o, Relation: relation, Subject: s}, nil
+}
@@ -0,0 +1,36 @@
+package entity
+
+import "time"
+
+type SecurityEventType string
+
+const (
+	// SecurityEventLogin - the user passed all factors and got the token pair
+	SecurityEventLogin              SecurityEventType = "login"
+	SecurityEventAccessTokenCreated SecurityEventType = "access_token_created"
+	SecurityEventAccessTokenRevoked SecurityEventType = "access_token_revoked"
+	// SecurityEventPasswordRemoved - the password was unlinked, the user signs in with other methods
+	SecurityEventPasswordRemoved SecurityEventType = "password_removed"
+	SecurityEventIdentityLinked  SecurityEventType = "identity_linked"
+	// SecurityEventIdentityUnlinked - a login method other than the password was unlinked
+	SecurityEventIdentityUnlinked  SecurityEventType = "identity_unlinked"
+	SecurityEventPasskeyRegistered SecurityEventType = "passkey_registered"
+	// SecurityEventOAuthSignIn - the user signed in to an OAuth client, refreshes aren't reported
+	SecurityEventOAuthSignIn SecurityEventType = "oauth_sign_in"
+)
+
+// SecurityEvent - change of a user account its owner should know about, e.g. to spot
+// logins they didn't make
+type SecurityEvent struct {
+	ID       string
+	Type     SecurityEventType
+	UserID   int
+	Username string
+	// TokenID - personal access token of the access token events
+	TokenID string
+	// Provider - login method of the identity and passkey events
+	Provider string
+	// ClientID - OAuth client of the sign in events
+	ClientID string
+	Time     time.Time
+}
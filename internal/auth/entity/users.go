@@ -1,9 +1,36 @@
 package entity
 
+import "fmt"
+
+var ErrUserNotFound = fmt.Errorf("user not found")
+
+const (
+	// Gender values match Gender of auth.proto without the GENDER_ prefix, lowercased
+	GenderMale   = "male"
+	GenderFemale = "female"
+	GenderOther  = "other"
+)
+
 // UserAccount - db schema
 type UserAccount struct {
-	ID        int
-	Username  string
-	Password  string
-	CreatedAt string
+	ID            int
+	Username      string
+	Password      string
+	Phone         string
+	PhoneVerified bool
+	CreatedAt     string
+
+	// profile, fields of User message of auth.proto
+	Name          string
+	Email         string
+	EmailVerified bool
+	Gender        string
+	Address       Address
+}
+
+type Address struct {
+	Street  string
+	City    string
+	State   string
+	Zipcode string
 }
@@ -0,0 +1,27 @@
+package entity
+
+import "time"
+
+// WebAuthnCredential - db schema
+type WebAuthnCredential struct {
+	ID              int
+	UserID          int
+	CredentialID    []byte
+	PublicKey       []byte
+	AttestationType string
+	AAGUID          []byte
+	SignCount       uint32
+	Transports      []string
+	BackupEligible  bool
+	BackupState     bool
+	CreatedAt       string
+}
+
+// WebAuthnSession - state kept between the begin and finish steps of a ceremony
+type WebAuthnSession struct {
+	ID        string
+	UserID    int
+	Ceremony  string
+	Data      []byte
+	ExpiresAt time.Time
+}
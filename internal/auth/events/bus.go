@@ -0,0 +1,150 @@
+// Package events - in process bus of security events, published by the use cases and
+// watched by API streams
+package events
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/bogatyr285/auth-go/internal/auth/entity"
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrSlowSubscriber - the subscriber didn't keep up and missed events
+	ErrSlowSubscriber = errors.New("subscriber is too slow, events were dropped")
+	ErrBusClosed      = errors.New("event bus is closed")
+)
+
+// Bus - fans events out to subscribers. Publish never blocks the use cases: a subscriber
+// whose buffer is full is closed with ErrSlowSubscriber, so it resubscribes instead of
+// missing events silently. It's a service.Service closing subscriptions on Stop
+type Bus struct {
+	mu     sync.Mutex
+	subs   map[*Subscription]struct{}
+	buffer int
+	closed bool
+	done   chan struct{}
+}
+
+// NewBus - buffer is the number of events a subscriber may lag behind
+func NewBus(buffer int) *Bus {
+	return &Bus{
+		subs:   map[*Subscription]struct{}{},
+		buffer: buffer,
+		done:   make(chan struct{}),
+	}
+}
+
+// Publish - ID and time are set when they're empty
+func (b *Bus) Publish(e entity.SecurityEvent) {
+	if e.ID == "" {
+		e.ID = uuid.NewString()
+	}
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for s := range b.subs {
+		if s.filter != nil && !s.filter(e) {
+			continue
+		}
+
+		select {
+		case s.events <- e:
+		default:
+			b.unsubscribe(s, ErrSlowSubscriber)
+		}
+	}
+}
+
+// Subscribe - events published from now on which match the filter, all when it's nil
+func (b *Bus) Subscribe(filter func(entity.SecurityEvent) bool) *Subscription {
+	s := &Subscription{
+		bus:    b,
+		filter: filter,
+		events: make(chan entity.SecurityEvent, b.buffer),
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		s.err = ErrBusClosed
+		close(s.events)
+		return s
+	}
+	b.subs[s] = struct{}{}
+
+	return s
+}
+
+// unsubscribe - b.mu is held
+func (b *Bus) unsubscribe(s *Subscription, err error) {
+	if _, ok := b.subs[s]; !ok {
+		return
+	}
+
+	delete(b.subs, s)
+	s.err = err
+	close(s.events)
+}
+
+func (b *Bus) Init(ctx context.Context) error {
+	return nil
+}
+
+func (b *Bus) Run(ctx context.Context) error {
+	<-b.done
+	return nil
+}
+
+// Stop - closes subscriptions with ErrBusClosed, so streams end before servers drain
+func (b *Bus) Stop() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return
+	}
+	b.closed = true
+
+	for s := range b.subs {
+		b.unsubscribe(s, ErrBusClosed)
+	}
+	close(b.done)
+}
+
+type Subscription struct {
+	bus    *Bus
+	filter func(entity.SecurityEvent) bool
+	events chan entity.SecurityEvent
+	// err - why the subscription ended, guarded by bus.mu
+	err error
+}
+
+// Events - closed once the subscription ends, Err tells why
+func (s *Subscription) Events() <-chan entity.SecurityEvent {
+	return s.events
+}
+
+// Err - ErrSlowSubscriber or ErrBusClosed once Events is closed, nil while it's open or
+// when the subscriber closed it
+func (s *Subscription) Err() error {
+	s.bus.mu.Lock()
+	defer s.bus.mu.Unlock()
+
+	return s.err
+}
+
+func (s *Subscription) Close() {
+	s.bus.mu.Lock()
+	defer s.bus.mu.Unlock()
+
+	s.bus.unsubscribe(s, nil)
+}
@@ -0,0 +1,83 @@
+package events_test
+
+import (
+	"testing"
+
+	"github.com/bogatyr285/auth-go/internal/auth/entity"
+	"github.com/bogatyr285/auth-go/internal/auth/events"
+	"github.com/stretchr/testify/require"
+)
+
+func login(username string) entity.SecurityEvent {
+	return entity.SecurityEvent{Type: entity.SecurityEventLogin, Username: username}
+}
+
+func TestBus(t *testing.T) {
+	t.Run("filtered events", func(t *testing.T) {
+		bus := events.NewBus(8)
+		alice := bus.Subscribe(func(e entity.SecurityEvent) bool { return e.Username == "alice" })
+		all := bus.Subscribe(nil)
+
+		bus.Publish(login("alice"))
+		bus.Publish(login("bob"))
+
+		e := <-alice.Events()
+		require.Equal(t, "alice", e.Username)
+		require.NotEmpty(t, e.ID)
+		require.False(t, e.Time.IsZero())
+		require.Empty(t, alice.Events())
+
+		require.Equal(t, "alice", (<-all.Events()).Username)
+		require.Equal(t, "bob", (<-all.Events()).Username)
+	})
+
+	t.Run("slow subscriber", func(t *testing.T) {
+		bus := events.NewBus(2)
+		slow := bus.Subscribe(nil)
+		fast := bus.Subscribe(nil)
+
+		for i := 0; i < 3; i++ {
+			bus.Publish(login("alice"))
+			<-fast.Events()
+		}
+
+		// buffered events are delivered, then the subscription ends
+		<-slow.Events()
+		<-slow.Events()
+		_, ok := <-slow.Events()
+		require.False(t, ok)
+		require.ErrorIs(t, slow.Err(), events.ErrSlowSubscriber)
+
+		// the publisher isn't held up and others keep receiving
+		bus.Publish(login("bob"))
+		require.Equal(t, "bob", (<-fast.Events()).Username)
+		require.NoError(t, fast.Err())
+	})
+
+	t.Run("close", func(t *testing.T) {
+		bus := events.NewBus(1)
+		s := bus.Subscribe(nil)
+		s.Close()
+		s.Close()
+
+		bus.Publish(login("alice"))
+		_, ok := <-s.Events()
+		require.False(t, ok)
+		require.NoError(t, s.Err())
+	})
+
+	t.Run("stop", func(t *testing.T) {
+		bus := events.NewBus(1)
+		s := bus.Subscribe(nil)
+
+		bus.Stop()
+		_, ok := <-s.Events()
+		require.False(t, ok)
+		require.ErrorIs(t, s.Err(), events.ErrBusClosed)
+
+		late := bus.Subscribe(nil)
+		_, ok = <-late.Events()
+		require.False(t, ok)
+		require.ErrorIs(t, late.Err(), events.ErrBusClosed)
+	})
+}
@@ -0,0 +1,206 @@
+// Package ldap - password check by LDAP bind, for Active Directory and other directories
+package ldap
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/bogatyr285/auth-go/internal/auth/entity"
+	"github.com/go-ldap/ldap/v3"
+)
+
+var (
+	ErrInvalidCredentials = errors.New("invalid credentials")
+	ErrUserNotFound       = errors.New("user not found in directory")
+)
+
+type Config struct {
+	// URL - ldap:// or ldaps:// address of the directory
+	URL string
+	// StartTLS - upgrade ldap:// connections before sending credentials
+	StartTLS  bool
+	TLSConfig *tls.Config
+
+	// BindDN, BindPassword - service account users are searched with
+	BindDN       string
+	BindPassword string
+	BaseDN       string
+	// UserFilter - filter of the user entry, %s is replaced with the escaped username,
+	// e.g. (userPrincipalName=%s) for Active Directory
+	UserFilter string
+	// GroupRoles - roles granted to members of the groups, keyed by group DN
+	GroupRoles map[string]string
+
+	Timeout time.Duration
+}
+
+type Directory struct {
+	cfg Config
+	// groupRoles - keyed by normalized group DN
+	groupRoles map[string]string
+}
+
+func New(cfg Config) (*Directory, error) {
+	if cfg.URL == "" || cfg.BaseDN == "" {
+		return nil, fmt.Errorf("ldap url and base dn are required")
+	}
+
+	if strings.Count(cfg.UserFilter, "%s") != 1 {
+		return nil, fmt.Errorf("ldap user filter must contain single %%s")
+	}
+
+	if cfg.Timeout == 0 {
+		cfg.Timeout = 5 * time.Second
+	}
+
+	d := &Directory{cfg: cfg, groupRoles: make(map[string]string, len(cfg.GroupRoles))}
+	for group, role := range cfg.GroupRoles {
+		dn, err := normalizeDN(group)
+		if err != nil {
+			return nil, fmt.Errorf("invalid group dn %q: %s", group, err)
+		}
+		d.groupRoles[dn] = role
+	}
+
+	return d, nil
+}
+
+// Authenticate - finds the user entry with the service account and binds as the user
+// with the password, roles are mapped from memberOf of the entry
+func (d *Directory) Authenticate(ctx context.Context, username, password string) (entity.DirectoryUser, error) {
+	// an empty password would be an unauthenticated bind, which succeeds for any DN
+	if password == "" {
+		return entity.DirectoryUser{}, ErrInvalidCredentials
+	}
+
+	conn, err := d.dial(ctx)
+	if err != nil {
+		return entity.DirectoryUser{}, err
+	}
+	defer conn.Close()
+
+	if err = conn.Bind(d.cfg.BindDN, d.cfg.BindPassword); err != nil {
+		return entity.DirectoryUser{}, fmt.Errorf("failed to bind service account: %s", err)
+	}
+
+	res, err := conn.Search(ldap.NewSearchRequest(
+		d.cfg.BaseDN,
+		ldap.ScopeWholeSubtree,
+		ldap.NeverDerefAliases,
+		2,
+		int(d.cfg.Timeout.Seconds()),
+		false,
+		fmt.Sprintf(d.cfg.UserFilter, ldap.EscapeFilter(username)),
+		[]string{"cn", "displayName", "mail", "memberOf"},
+		nil,
+	))
+	if err != nil {
+		return entity.DirectoryUser{}, fmt.Errorf("failed to search user: %s", err)
+	}
+
+	if len(res.Entries) == 0 {
+		return entity.DirectoryUser{}, ErrUserNotFound
+	}
+
+	// ambiguous filters must not let a user sign in as someone else
+	if len(res.Entries) > 1 {
+		return entity.DirectoryUser{}, fmt.Errorf("username %q matches several entries", username)
+	}
+
+	entry := res.Entries[0]
+	if err = conn.Bind(entry.DN, password); err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials) {
+			return entity.DirectoryUser{}, ErrInvalidCredentials
+		}
+
+		return entity.DirectoryUser{}, fmt.Errorf("failed to bind user: %s", err)
+	}
+
+	name := entry.GetAttributeValue("displayName")
+	if name == "" {
+		name = entry.GetAttributeValue("cn")
+	}
+
+	return entity.DirectoryUser{
+		Username: username,
+		Name:     name,
+		Email:    entry.GetAttributeValue("mail"),
+		Roles:    d.roles(entry.GetAttributeValues("memberOf")),
+	}, nil
+}
+
+// Roles - every role groups are mapped to, they are managed by the directory
+func (d *Directory) Roles() []string {
+	roles := make([]string, 0, len(d.groupRoles))
+	for _, role := range d.groupRoles {
+		roles = append(roles, role)
+	}
+
+	slices.Sort(roles)
+	return slices.Compact(roles)
+}
+
+func (d *Directory) roles(groups []string) []string {
+	var roles []string
+	for _, group := range groups {
+		dn, err := normalizeDN(group)
+		if err != nil {
+			continue
+		}
+
+		if role, ok := d.groupRoles[dn]; ok {
+			roles = append(roles, role)
+		}
+	}
+
+	slices.Sort(roles)
+	return slices.Compact(roles)
+}
+
+func (d *Directory) dial(ctx context.Context) (*ldap.Conn, error) {
+	dialer := &net.Dialer{Timeout: d.cfg.Timeout}
+	if deadline, ok := ctx.Deadline(); ok {
+		dialer.Deadline = deadline
+	}
+
+	conn, err := ldap.DialURL(d.cfg.URL, ldap.DialWithDialer(dialer), ldap.DialWithTLSConfig(d.cfg.TLSConfig))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to directory: %s", err)
+	}
+	conn.SetTimeout(d.cfg.Timeout)
+
+	if d.cfg.StartTLS {
+		if err = conn.StartTLS(d.cfg.TLSConfig); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("failed to start tls: %s", err)
+		}
+	}
+
+	return conn, nil
+}
+
+// normalizeDN - DNs differ in case and spacing between directories and configs
+func normalizeDN(dn string) (string, error) {
+	parsed, err := ldap.ParseDN(dn)
+	if err != nil {
+		return "", err
+	}
+
+	rdns := make([]string, 0, len(parsed.RDNs))
+	for _, rdn := range parsed.RDNs {
+		attrs := make([]string, 0, len(rdn.Attributes))
+		for _, attr := range rdn.Attributes {
+			attrs = append(attrs, strings.ToLower(attr.Type)+"="+strings.ToLower(attr.Value))
+		}
+		slices.Sort(attrs)
+		rdns = append(rdns, strings.Join(attrs, "+"))
+	}
+
+	return strings.Join(rdns, ","), nil
+}
@@ -0,0 +1,212 @@
+package ldap_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/bogatyr285/auth-go/internal/auth/ldap"
+	ber "github.com/go-asn1-ber/asn1-ber"
+	goldap "github.com/go-ldap/ldap/v3"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	baseDN     = "DC=corp,DC=example,DC=com"
+	serviceDN  = "CN=svc-auth,OU=Service," + baseDN
+	servicePwd = "svc-pass"
+)
+
+type directoryEntry struct {
+	dn       string
+	upn      string
+	password string
+	attrs    map[string][]string
+}
+
+// fakeDirectory - LDAP server stand-in answering simple binds and equality searches
+// by userPrincipalName, which is all the directory backend needs
+type fakeDirectory struct {
+	entries []directoryEntry
+}
+
+func (d *fakeDirectory) serve(t *testing.T) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { l.Close() })
+
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go d.handle(conn)
+		}
+	}()
+
+	return "ldap://" + l.Addr().String()
+}
+
+func (d *fakeDirectory) handle(conn net.Conn) {
+	defer conn.Close()
+
+	for {
+		packet, err := ber.ReadPacket(conn)
+		if err != nil || len(packet.Children) < 2 {
+			return
+		}
+
+		messageID := packet.Children[0].Value.(int64)
+		op := packet.Children[1]
+
+		switch op.Tag {
+		case goldap.ApplicationBindRequest:
+			code := int64(goldap.LDAPResultInvalidCredentials)
+			if d.bind(op.Children[1].Value.(string), op.Children[2].Data.String()) {
+				code = goldap.LDAPResultSuccess
+			}
+			d.write(conn, messageID, result(goldap.ApplicationBindResponse, code))
+		case goldap.ApplicationSearchRequest:
+			filter, err := goldap.DecompileFilter(op.Children[6])
+			if err != nil {
+				d.write(conn, messageID, result(goldap.ApplicationSearchResultDone, goldap.LDAPResultProtocolError))
+				continue
+			}
+
+			for _, e := range d.entries {
+				if filter == fmt.Sprintf("(userPrincipalName=%s)", goldap.EscapeFilter(e.upn)) {
+					d.write(conn, messageID, searchEntry(e))
+				}
+			}
+			d.write(conn, messageID, result(goldap.ApplicationSearchResultDone, goldap.LDAPResultSuccess))
+		case goldap.ApplicationUnbindRequest:
+			return
+		}
+	}
+}
+
+func (d *fakeDirectory) bind(dn, password string) bool {
+	if strings.EqualFold(dn, serviceDN) {
+		return password == servicePwd
+	}
+
+	for _, e := range d.entries {
+		if strings.EqualFold(dn, e.dn) {
+			return password == e.password
+		}
+	}
+
+	return false
+}
+
+func (d *fakeDirectory) write(conn net.Conn, messageID int64, op *ber.Packet) {
+	envelope := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "LDAP Response")
+	envelope.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, messageID, "Message ID"))
+	envelope.AppendChild(op)
+	_, _ = conn.Write(envelope.Bytes())
+}
+
+func result(tag ber.Tag, code int64) *ber.Packet {
+	p := ber.Encode(ber.ClassApplication, ber.TypeConstructed, tag, nil, "Result")
+	p.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, code, "Result Code"))
+	p.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "Matched DN"))
+	p.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "Diagnostic Message"))
+	return p
+}
+
+func searchEntry(e directoryEntry) *ber.Packet {
+	p := ber.Encode(ber.ClassApplication, ber.TypeConstructed, goldap.ApplicationSearchResultEntry, nil, "Search Result Entry")
+	p.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, e.dn, "Object Name"))
+
+	attrs := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "Attributes")
+	for name, values := range e.attrs {
+		attr := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "Attribute")
+		attr.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, name, "Type"))
+
+		vals := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSet, nil, "Values")
+		for _, v := range values {
+			vals.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, v, "Value"))
+		}
+		attr.AppendChild(vals)
+		attrs.AppendChild(attr)
+	}
+	p.AppendChild(attrs)
+
+	return p
+}
+
+func TestDirectory(t *testing.T) {
+	directory := &fakeDirectory{entries: []directoryEntry{{
+		dn:       "CN=Alice Smith,OU=Staff," + baseDN,
+		upn:      "alice@corp.example.com",
+		password: "alice-pass",
+		attrs: map[string][]string{
+			"cn":          {"Alice Smith"},
+			"displayName": {"Alice S."},
+			"mail":        {"alice@example.com"},
+			"memberOf":    {"CN=Admins,OU=Groups," + baseDN, "CN=Staff,OU=Groups," + baseDN, "CN=Unmapped,OU=Groups," + baseDN},
+		},
+	}}}
+
+	d, err := ldap.New(ldap.Config{
+		URL:          directory.serve(t),
+		BindDN:       serviceDN,
+		BindPassword: servicePwd,
+		BaseDN:       baseDN,
+		UserFilter:   "(userPrincipalName=%s)",
+		// DNs of the config are matched regardless of case and spacing
+		GroupRoles: map[string]string{
+			"cn=admins, ou=groups, dc=corp, dc=example, dc=com": "admin",
+			"cn=staff,ou=groups,dc=corp,dc=example,dc=com":      "user",
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"admin", "user"}, d.Roles())
+
+	ctx := context.Background()
+
+	t.Run("bind as the user", func(t *testing.T) {
+		user, err := d.Authenticate(ctx, "alice@corp.example.com", "alice-pass")
+		require.NoError(t, err)
+		require.Equal(t, "alice@corp.example.com", user.Username)
+		require.Equal(t, "Alice S.", user.Name)
+		require.Equal(t, "alice@example.com", user.Email)
+		require.Equal(t, []string{"admin", "user"}, user.Roles)
+	})
+
+	t.Run("wrong password", func(t *testing.T) {
+		_, err := d.Authenticate(ctx, "alice@corp.example.com", "wrong")
+		require.ErrorIs(t, err, ldap.ErrInvalidCredentials)
+
+		// unauthenticated bind must not be mistaken for a successful one
+		_, err = d.Authenticate(ctx, "alice@corp.example.com", "")
+		require.ErrorIs(t, err, ldap.ErrInvalidCredentials)
+	})
+
+	t.Run("unknown user", func(t *testing.T) {
+		_, err := d.Authenticate(ctx, "bob@corp.example.com", "alice-pass")
+		require.ErrorIs(t, err, ldap.ErrUserNotFound)
+
+		// filter syntax in usernames is escaped
+		_, err = d.Authenticate(ctx, "*", "alice-pass")
+		require.ErrorIs(t, err, ldap.ErrUserNotFound)
+	})
+
+	t.Run("service account is rejected", func(t *testing.T) {
+		broken, err := ldap.New(ldap.Config{
+			URL:        directory.serve(t),
+			BindDN:     serviceDN,
+			BaseDN:     baseDN,
+			UserFilter: "(userPrincipalName=%s)",
+		})
+		require.NoError(t, err)
+
+		_, err = broken.Authenticate(ctx, "alice@corp.example.com", "alice-pass")
+		require.Error(t, err)
+		require.False(t, errors.Is(err, ldap.ErrInvalidCredentials))
+	})
+}
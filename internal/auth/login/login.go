@@ -0,0 +1,192 @@
+// Package login - password check of local accounts and external directories, shared
+// by REST and gRPC login
+package login
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/bogatyr285/auth-go/internal/auth/entity"
+	"github.com/labstack/gommon/log"
+)
+
+var ErrInvalidCredentials = errors.New("invalid credentials")
+
+type Repository interface {
+	RoleRepository
+	FindUserByEmail(ctx context.Context, username string) (entity.UserAccount, error)
+	GetUserById(ctx context.Context, ID int) (entity.UserAccount, error)
+	ExistsUserByUsername(ctx context.Context, username string) (bool, error)
+	GetIdentity(ctx context.Context, provider, subject string) (entity.Identity, error)
+	ProvisionFederatedUser(ctx context.Context, u entity.UserAccount, identity entity.Identity) (int, error)
+}
+
+type RoleRepository interface {
+	ListUserRoles(ctx context.Context, userID int) ([]string, error)
+	GrantUserRole(ctx context.Context, userID int, role string) error
+	RevokeUserRole(ctx context.Context, userID int, role string) error
+}
+
+type CryptoPassword interface {
+	ComparePasswords(fromUser, fromDB string) bool
+}
+
+// Backend - external directory checking passwords, e.g. LDAP
+type Backend interface {
+	// Authenticate - fails for unknown users and wrong passwords alike
+	Authenticate(ctx context.Context, username, password string) (entity.DirectoryUser, error)
+	// Roles - roles the backend maps groups to, they follow the directory on every login
+	Roles() []string
+}
+
+// Directory - when the backend is asked for a username
+type Directory struct {
+	Name    string
+	Backend Backend
+	// Domains - usernames of the domains (the part after @) are checked by the directory
+	// only and can't be registered locally
+	Domains []string
+	// Fallback - the directory is asked, in order, when the local password check fails.
+	// Usernames already taken by accounts the directory didn't provision are refused
+	Fallback bool
+}
+
+type Authenticator struct {
+	repo        Repository
+	cp          CryptoPassword
+	directories []Directory
+}
+
+func NewAuthenticator(repo Repository, cp CryptoPassword, directories ...Directory) *Authenticator {
+	return &Authenticator{repo: repo, cp: cp, directories: directories}
+}
+
+// Authenticate - the user the password belongs to, users of directories are provisioned
+// locally on first login. ErrInvalidCredentials is returned when no check succeeds
+func (a *Authenticator) Authenticate(ctx context.Context, username, password string) (entity.UserAccount, error) {
+	if d, ok := DomainDirectory(a.directories, username); ok {
+		return a.directoryLogin(ctx, d, username, password)
+	}
+
+	user, err := a.repo.FindUserByEmail(ctx, username)
+	if err == nil && a.cp.ComparePasswords(user.Password, password) {
+		return user, nil
+	}
+
+	for _, d := range a.directories {
+		if !d.Fallback {
+			continue
+		}
+
+		user, err := a.directoryLogin(ctx, d, username, password)
+		if err == nil {
+			return user, nil
+		}
+
+		if !errors.Is(err, ErrInvalidCredentials) {
+			log.Errorf("Failed to authenticate with %s: %s", d.Name, err)
+		}
+	}
+
+	return entity.UserAccount{}, ErrInvalidCredentials
+}
+
+// DomainDirectory - the directory owning the domain of the username, such usernames
+// belong to the directory and can't be registered locally
+func DomainDirectory(directories []Directory, username string) (Directory, bool) {
+	at := strings.LastIndex(username, "@")
+	if at < 0 {
+		return Directory{}, false
+	}
+
+	domain := username[at+1:]
+	for _, d := range directories {
+		if slices.ContainsFunc(d.Domains, func(s string) bool { return strings.EqualFold(s, domain) }) {
+			return d, true
+		}
+	}
+
+	return Directory{}, false
+}
+
+func (a *Authenticator) directoryLogin(ctx context.Context, d Directory, username, password string) (entity.UserAccount, error) {
+	du, err := d.Backend.Authenticate(ctx, username, password)
+	if err != nil {
+		log.Infof("Directory %s rejected %s: %s", d.Name, username, err)
+		return entity.UserAccount{}, ErrInvalidCredentials
+	}
+
+	user, err := a.provision(ctx, d, du)
+	if err != nil {
+		return entity.UserAccount{}, err
+	}
+
+	if err = SyncRoles(ctx, a.repo, user.ID, d.Backend.Roles(), du.Roles); err != nil {
+		return entity.UserAccount{}, err
+	}
+
+	return user, nil
+}
+
+// provision - the local account of the directory user, created without password so
+// it can't be used when the directory is unavailable. The account is linked to the
+// directory's identity of the user, any other account of the username was registered
+// by someone else and is never taken over
+func (a *Authenticator) provision(ctx context.Context, d Directory, du entity.DirectoryUser) (entity.UserAccount, error) {
+	if identity, err := a.repo.GetIdentity(ctx, d.Name, du.Username); err == nil {
+		return a.repo.GetUserById(ctx, identity.UserID)
+	}
+
+	exists, err := a.repo.ExistsUserByUsername(ctx, du.Username)
+	if err != nil {
+		return entity.UserAccount{}, err
+	}
+
+	if exists {
+		log.Infof("Directory %s user %s collides with local account", d.Name, du.Username)
+		return entity.UserAccount{}, ErrInvalidCredentials
+	}
+
+	userID, err := a.repo.ProvisionFederatedUser(ctx, entity.UserAccount{
+		Username: du.Username,
+		Name:     du.Name,
+		Email:    du.Email,
+	}, entity.Identity{
+		Provider: d.Name,
+		Subject:  du.Username,
+		Email:    du.Email,
+	})
+	if err != nil {
+		return entity.UserAccount{}, fmt.Errorf("failed to provision user: %s", err)
+	}
+
+	return a.repo.GetUserById(ctx, userID)
+}
+
+// SyncRoles - roles managed by an external source are granted or revoked to match the
+// ones it grants, other roles of the user are left as they are
+func SyncRoles(ctx context.Context, repo RoleRepository, userID int, managed, granted []string) error {
+	current, err := repo.ListUserRoles(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	for _, role := range managed {
+		has, should := slices.Contains(current, role), slices.Contains(granted, role)
+		switch {
+		case should && !has:
+			err = repo.GrantUserRole(ctx, userID, role)
+		case has && !should:
+			err = repo.RevokeUserRole(ctx, userID, role)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
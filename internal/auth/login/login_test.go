@@ -0,0 +1,132 @@
+package login_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/bogatyr285/auth-go/internal/auth/entity"
+	"github.com/bogatyr285/auth-go/internal/auth/login"
+	"github.com/bogatyr285/auth-go/internal/auth/repository"
+	"github.com/bogatyr285/auth-go/pkg/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeBackend - directory of users by username, each with a password and roles
+type fakeBackend struct {
+	passwords map[string]string
+	roles     map[string][]string
+	calls     int
+}
+
+func (b *fakeBackend) Authenticate(_ context.Context, username, password string) (entity.DirectoryUser, error) {
+	b.calls++
+	if p, ok := b.passwords[username]; !ok || p != password {
+		return entity.DirectoryUser{}, login.ErrInvalidCredentials
+	}
+
+	return entity.DirectoryUser{Username: username, Name: "Directory User", Roles: b.roles[username]}, nil
+}
+
+func (b *fakeBackend) Roles() []string {
+	return []string{"admin", "support"}
+}
+
+func TestAuthenticate(t *testing.T) {
+	storage, err := repository.New(filepath.Join(t.TempDir(), "login.sqlite"))
+	require.NoError(t, err)
+	t.Cleanup(func() { storage.Close() })
+
+	ctx := context.Background()
+	hasher := crypto.NewPasswordHasher()
+
+	hash, err := hasher.HashPassword("local-pass")
+	require.NoError(t, err)
+	require.NoError(t, storage.RegisterUser(ctx, entity.UserAccount{Username: "carol@example.com", Password: string(hash)}))
+
+	// accounts registered before the directory users first login, without password
+	// like federated ones
+	require.NoError(t, storage.RegisterUser(ctx, entity.UserAccount{Username: "eve@corp.example.com"}))
+	require.NoError(t, storage.RegisterUser(ctx, entity.UserAccount{Username: "frank"}))
+
+	corp := &fakeBackend{
+		passwords: map[string]string{"alice@corp.example.com": "corp-pass", "eve@corp.example.com": "corp-pass"},
+		roles:     map[string][]string{"alice@corp.example.com": {"admin"}, "eve@corp.example.com": {"admin"}},
+	}
+	legacy := &fakeBackend{
+		passwords: map[string]string{"dave": "legacy-pass", "carol@example.com": "legacy-pass", "frank": "legacy-pass"},
+	}
+
+	auth := login.NewAuthenticator(&storage, hasher,
+		login.Directory{Name: "corp", Backend: corp, Domains: []string{"corp.example.com"}},
+		login.Directory{Name: "legacy", Backend: legacy, Fallback: true},
+	)
+
+	rolesOf := func(t *testing.T, user entity.UserAccount) []string {
+		roles, err := storage.ListUserRoles(ctx, user.ID)
+		require.NoError(t, err)
+		return roles
+	}
+
+	t.Run("local password", func(t *testing.T) {
+		user, err := auth.Authenticate(ctx, "carol@example.com", "local-pass")
+		require.NoError(t, err)
+		require.Equal(t, "carol@example.com", user.Username)
+		require.Zero(t, legacy.calls)
+	})
+
+	t.Run("domain is checked by its directory only", func(t *testing.T) {
+		user, err := auth.Authenticate(ctx, "alice@corp.example.com", "corp-pass")
+		require.NoError(t, err)
+		require.Equal(t, "alice@corp.example.com", user.Username)
+		require.Empty(t, user.Password)
+		require.Equal(t, []string{entity.RoleUser, "admin"}, rolesOf(t, user))
+
+		_, err = auth.Authenticate(ctx, "alice@corp.example.com", "wrong")
+		require.ErrorIs(t, err, login.ErrInvalidCredentials)
+		require.Zero(t, legacy.calls)
+	})
+
+	t.Run("roles follow the directory", func(t *testing.T) {
+		user, err := storage.FindUserByEmail(ctx, "alice@corp.example.com")
+		require.NoError(t, err)
+		require.NoError(t, storage.GrantUserRole(ctx, user.ID, "auditor"))
+
+		corp.roles["alice@corp.example.com"] = []string{"support"}
+		_, err = auth.Authenticate(ctx, "alice@corp.example.com", "corp-pass")
+		require.NoError(t, err)
+
+		// roles the directory doesn't manage are kept
+		require.Equal(t, []string{entity.RoleUser, "auditor", "support"}, rolesOf(t, user))
+	})
+
+	t.Run("fallback provisions the user once", func(t *testing.T) {
+		first, err := auth.Authenticate(ctx, "dave", "legacy-pass")
+		require.NoError(t, err)
+
+		second, err := auth.Authenticate(ctx, "dave", "legacy-pass")
+		require.NoError(t, err)
+		require.Equal(t, first.ID, second.ID)
+
+		_, err = auth.Authenticate(ctx, "dave", "")
+		require.ErrorIs(t, err, login.ErrInvalidCredentials)
+	})
+
+	t.Run("fallback doesn't take over local accounts", func(t *testing.T) {
+		_, err := auth.Authenticate(ctx, "carol@example.com", "legacy-pass")
+		require.ErrorIs(t, err, login.ErrInvalidCredentials)
+	})
+	t.Run("directories don't take over accounts registered by others", func(t *testing.T) {
+		for _, username := range []string{"eve@corp.example.com", "frank"} {
+			_, err := auth.Authenticate(ctx, username, "corp-pass")
+			require.ErrorIs(t, err, login.ErrInvalidCredentials)
+
+			_, err = auth.Authenticate(ctx, username, "legacy-pass")
+			require.ErrorIs(t, err, login.ErrInvalidCredentials)
+
+			user, err := storage.FindUserByEmail(ctx, username)
+			require.NoError(t, err)
+			require.Equal(t, []string{entity.RoleUser}, rolesOf(t, user))
+		}
+	})
+}
@@ -0,0 +1,258 @@
+package oauth
+
+import (
+	"context"
+	"html/template"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/bogatyr285/auth-go/internal/auth/entity"
+)
+
+var authorizePage = template.Must(template.New("authorize").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>Sign in</title></head>
+<body>
+{{if .Client.Name}}<h1>Sign in to continue to {{.Client.Name}}</h1>{{end}}
+{{if .Error}}<p role="alert">{{.Error}}</p>{{end}}
+{{if .Request}}
+<form method="post" action="/authorize">
+<input type="hidden" name="response_type" value="{{.Request.ResponseType}}">
+<input type="hidden" name="client_id" value="{{.Request.ClientID}}">
+<input type="hidden" name="redirect_uri" value="{{.Request.RedirectURI}}">
+<input type="hidden" name="scope" value="{{.Request.Scope}}">
+<input type="hidden" name="state" value="{{.Request.State}}">
+<input type="hidden" name="code_challenge" value="{{.Request.CodeChallenge}}">
+<input type="hidden" name="code_challenge_method" value="{{.Request.CodeChallengeMethod}}">
+<input type="hidden" name="nonce" value="{{.Request.Nonce}}">
+<label>Username <input name="username" autocomplete="username"></label>
+<label>Password <input name="password" type="password" autocomplete="current-password"></label>
+{{if .Request.Scope}}<p>{{.Client.Name}} requests access to: {{.Request.Scope}}</p>{{end}}
+<button name="consent" value="allow">Allow</button>
+<button name="consent" value="deny">Deny</button>
+</form>
+{{end}}
+</body>
+</html>
+`))
+
+type authorizeRequest struct {
+	ResponseType        string
+	ClientID            string
+	RedirectURI         string
+	Scope               string
+	State               string
+	CodeChallenge       string
+	CodeChallengeMethod string
+	Nonce               string
+}
+
+type authorizePageData struct {
+	Client  entity.OAuthClient
+	Request *authorizeRequest
+	Error   string
+}
+
+func parseAuthorizeRequest(form url.Values) authorizeRequest {
+	return authorizeRequest{
+		ResponseType:        form.Get("response_type"),
+		ClientID:            form.Get("client_id"),
+		RedirectURI:         form.Get("redirect_uri"),
+		Scope:               form.Get("scope"),
+		State:               form.Get("state"),
+		CodeChallenge:       form.Get("code_challenge"),
+		CodeChallengeMethod: form.Get("code_challenge_method"),
+		Nonce:               form.Get("nonce"),
+	}
+}
+
+// validate - errors of the client or the redirect URI are shown to the user, since
+// redirecting to an unverified URI would make the server an open redirector. Other
+// errors are returned to the client through the redirect URI
+func (p *Provider) validate(ctx context.Context, req authorizeRequest) (entity.OAuthClient, string, *Error) {
+	client, err := p.repo.GetOAuthClient(ctx, req.ClientID)
+	if err != nil {
+		return entity.OAuthClient{}, "Unknown client.", nil
+	}
+
+	if !slices.Contains(client.RedirectURIs, req.RedirectURI) {
+		return entity.OAuthClient{}, "The redirect URI isn't registered for the client.", nil
+	}
+
+	if req.ResponseType != "code" {
+		return client, "", &Error{Code: "unsupported_response_type", Description: "only response_type=code is supported"}
+	}
+
+	if req.CodeChallenge == "" {
+		return client, "", errInvalidRequest("code_challenge is required")
+	}
+
+	if req.CodeChallengeMethod != "S256" {
+		return client, "", errInvalidRequest("code_challenge_method must be S256")
+	}
+
+	if !scopeSubset(req.Scope, strings.Join(client.Scopes, " ")) {
+		return client, "", errInvalidScope("scope isn't allowed for the client")
+	}
+
+	return client, "", nil
+}
+
+func (p *Provider) authorize(w http.ResponseWriter, r *http.Request) {
+	req := parseAuthorizeRequest(r.URL.Query())
+
+	client, pageErr, oauthErr := p.validate(r.Context(), req)
+	if pageErr != "" {
+		renderAuthorizePage(w, http.StatusBadRequest, authorizePageData{Error: pageErr})
+		return
+	}
+
+	if oauthErr != nil {
+		redirect(w, r, req.RedirectURI, errorParams(oauthErr, req.State))
+		return
+	}
+
+	renderAuthorizePage(w, http.StatusOK, authorizePageData{Client: client, Request: &req})
+}
+
+// authorizeSubmit - signs the user in and records the consent
+func (p *Provider) authorizeSubmit(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		renderAuthorizePage(w, http.StatusBadRequest, authorizePageData{Error: "Malformed request."})
+		return
+	}
+
+	ctx := r.Context()
+	req := parseAuthorizeRequest(r.PostForm)
+
+	client, pageErr, oauthErr := p.validate(ctx, req)
+	if pageErr != "" {
+		renderAuthorizePage(w, http.StatusBadRequest, authorizePageData{Error: pageErr})
+		return
+	}
+
+	if oauthErr != nil {
+		redirect(w, r, req.RedirectURI, errorParams(oauthErr, req.State))
+		return
+	}
+
+	if r.PostForm.Get("consent") != "allow" {
+		redirect(w, r, req.RedirectURI, errorParams(&Error{Code: "access_denied", Description: "the user denied the request"}, req.State))
+		return
+	}
+
+	user, status, pageErr := p.signIn(ctx, r.PostForm.Get("username"), r.PostForm.Get("password"))
+	if pageErr != "" {
+		renderAuthorizePage(w, status, authorizePageData{Client: client, Request: &req, Error: pageErr})
+		return
+	}
+
+	code, err := p.issueCode(ctx, user, req)
+	if err != nil {
+		redirect(w, r, req.RedirectURI, errorParams(&Error{Code: "server_error"}, req.State))
+		return
+	}
+
+	params := url.Values{"code": {code}}
+	if req.State != "" {
+		params.Set("state", req.State)
+	}
+
+	redirect(w, r, req.RedirectURI, params)
+}
+
+// signIn - checks credentials entered on pages of the provider, the error is the one to
+// show on the page. Accounts with a second factor can't sign in with a password only,
+// so they are rejected
+func (p *Provider) signIn(ctx context.Context, username, password string) (entity.UserAccount, int, string) {
+	user, err := p.repo.FindUserByEmail(ctx, username)
+	if err != nil || !p.cp.ComparePasswords(user.Password, password) {
+		return entity.UserAccount{}, http.StatusUnauthorized, "Invalid username or password."
+	}
+
+	creds, err := p.repo.ListWebAuthnCredentials(ctx, user.ID)
+	if err != nil {
+		return entity.UserAccount{}, http.StatusInternalServerError, "Something went wrong, try again."
+	}
+
+	if len(creds) > 0 {
+		return entity.UserAccount{}, http.StatusForbidden, "The account requires a second factor, which isn't supported here."
+	}
+
+	return user, http.StatusOK, ""
+}
+
+func (p *Provider) issueCode(ctx context.Context, user entity.UserAccount, req authorizeRequest) (string, error) {
+	code, err := GenerateSecret()
+	if err != nil {
+		return "", err
+	}
+
+	if err := p.repo.SaveOAuthGrant(ctx, entity.OAuthGrant{UserID: user.ID, ClientID: req.ClientID, Scope: req.Scope}); err != nil {
+		return "", err
+	}
+
+	err = p.repo.SaveAuthorizationCode(ctx, entity.AuthorizationCode{
+		CodeHash:      HashSecret(code),
+		ClientID:      req.ClientID,
+		UserID:        user.ID,
+		RedirectURI:   req.RedirectURI,
+		Scope:         req.Scope,
+		CodeChallenge: req.CodeChallenge,
+		ExpiresAt:     time.Now().Add(authCodeTTL),
+		Nonce:         req.Nonce,
+		AuthTime:      time.Now(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return code, nil
+}
+
+func errorParams(e *Error, state string) url.Values {
+	params := url.Values{"error": {e.Code}}
+	if e.Description != "" {
+		params.Set("error_description", e.Description)
+	}
+	if state != "" {
+		params.Set("state", state)
+	}
+
+	return params
+}
+
+// redirect - params are merged into the query of the registered redirect URI
+func redirect(w http.ResponseWriter, r *http.Request, redirectURI string, params url.Values) {
+	u, err := url.Parse(redirectURI)
+	if err != nil {
+		renderAuthorizePage(w, http.StatusBadRequest, authorizePageData{Error: "The redirect URI is invalid."})
+		return
+	}
+
+	q := u.Query()
+	for k, v := range params {
+		q[k] = v
+	}
+	u.RawQuery = q.Encode()
+
+	w.Header().Set("Cache-Control", "no-store")
+	http.Redirect(w, r, u.String(), http.StatusFound)
+}
+
+func renderAuthorizePage(w http.ResponseWriter, status int, data authorizePageData) {
+	renderPage(w, status, authorizePage, data)
+}
+
+// renderPage - pages of the provider take credentials, so they must not be framed
+func renderPage(w http.ResponseWriter, status int, page *template.Template, data interface{}) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("X-Frame-Options", "DENY")
+	w.Header().Set("Content-Security-Policy", "frame-ancestors 'none'")
+	w.WriteHeader(status)
+	_ = page.Execute(w, data)
+}
@@ -0,0 +1,373 @@
+package oauth
+
+import (
+	"context"
+	"crypto/rand"
+	"html/template"
+	"math/big"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/bogatyr285/auth-go/internal/auth/entity"
+	pkgjwt "github.com/bogatyr285/auth-go/pkg/jwt"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	deviceCodeGrantType = "urn:ietf:params:oauth:grant-type:device_code"
+
+	deviceCodeTTL      = 10 * time.Minute
+	devicePollInterval = 5 * time.Second
+	// deviceSlowDownStep - the interval is raised by it every time the device polls too often
+	deviceSlowDownStep = 5 * time.Second
+	// deviceSignInMaxAge - devices are decided by users who just signed in, tokens of
+	// older sessions or refreshes aren't accepted
+	deviceSignInMaxAge = 10 * time.Minute
+
+	// userCodeAlphabet - consonants only, so codes don't spell words and are easy to type
+	userCodeAlphabet = "BCDFGHJKLMNPQRSTVWXZ"
+	userCodeLength   = 8
+)
+
+var devicePage = template.Must(template.New("device").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>Connect a device</title></head>
+<body>
+<h1>Connect a device</h1>
+{{if .Error}}<p role="alert">{{.Error}}</p>{{end}}
+{{if .Done}}<p>{{.Done}}</p>{{else}}
+{{if .Client.Name}}<p>{{.Client.Name}} requests access{{if .Scope}} to: {{.Scope}}{{end}}.</p>{{end}}
+<form method="post" action="/device" id="device">
+<label>Code shown on the device <input name="user_code" value="{{.UserCode}}" autocomplete="off"></label>
+<input type="hidden" name="access_token">
+<input type="hidden" name="consent">
+<label>Username <input id="username" autocomplete="username"></label>
+<label>Password <input id="password" type="password" autocomplete="current-password"></label>
+<button value="allow">Allow</button>
+<button value="deny">Deny</button>
+</form>
+<script>
+// the user signs in with the API, second factor included, and the device is decided
+// with the access token of the sign in
+const form = document.getElementById("device");
+const decode = s => Uint8Array.from(atob(s.replace(/-/g, "+").replace(/_/g, "/")), c => c.charCodeAt(0));
+const encode = b => btoa(String.fromCharCode(...new Uint8Array(b))).replace(/\+/g, "-").replace(/\//g, "_").replace(/=+$/, "");
+
+async function post(path, body) {
+  const res = await fetch(path, {method: "POST", headers: {"Content-Type": "application/json"}, body: JSON.stringify(body)});
+  return {status: res.status, body: await res.json().catch(() => ({}))};
+}
+
+async function passkey(mfaToken) {
+  const begin = await post("/webauthn/login/begin", {mfa_token: mfaToken});
+  const options = begin.body.options.publicKey;
+  options.challenge = decode(options.challenge);
+  (options.allowCredentials || []).forEach(c => c.id = decode(c.id));
+
+  const credential = await navigator.credentials.get({publicKey: options});
+  const finish = await post("/webauthn/login/finish", {session_id: begin.body.session_id, credential: {
+    id: credential.id,
+    rawId: encode(credential.rawId),
+    type: credential.type,
+    response: {
+      clientDataJSON: encode(credential.response.clientDataJSON),
+      authenticatorData: encode(credential.response.authenticatorData),
+      signature: encode(credential.response.signature),
+      userHandle: credential.response.userHandle ? encode(credential.response.userHandle) : undefined,
+    },
+  }});
+  return finish.body.access_token;
+}
+
+form.addEventListener("submit", async event => {
+  event.preventDefault();
+  form.consent.value = event.submitter.value;
+
+  try {
+    const login = await post("/login", {username: form.username.value, password: form.password.value});
+    if (login.status === 200) {
+      form.access_token.value = login.body.access_token;
+    } else if (login.status === 202 && login.body.methods.includes("webauthn")) {
+      form.access_token.value = await passkey(login.body.mfa_token);
+    }
+  } catch (e) {
+    // the page tells the user to sign in again
+  }
+  form.submit();
+});
+</script>
+{{end}}
+</body>
+</html>
+`))
+
+type devicePageData struct {
+	Client   entity.OAuthClient
+	Scope    string
+	UserCode string
+	Error    string
+	Done     string
+}
+
+type DeviceAuthorizationResponse struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete"`
+	ExpiresIn               int64  `json:"expires_in"`
+	Interval                int64  `json:"interval"`
+}
+
+// deviceAuthorization - RFC 8628 device authorization endpoint
+func (p *Provider) deviceAuthorization(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeJSON(w, http.StatusBadRequest, errInvalidRequest("malformed form body"))
+		return
+	}
+
+	ctx := r.Context()
+	client, err := p.authenticateClient(ctx, r)
+	if err != nil {
+		oauthErr := err.(*Error)
+		writeJSON(w, oauthErr.status, oauthErr)
+		return
+	}
+
+	scope := r.PostForm.Get("scope")
+	if !scopeSubset(scope, strings.Join(client.Scopes, " ")) {
+		writeJSON(w, http.StatusBadRequest, errInvalidScope("scope isn't allowed for the client"))
+		return
+	}
+
+	deviceCode, err := GenerateSecret()
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, &Error{Code: "server_error"})
+		return
+	}
+
+	userCode, err := generateUserCode()
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, &Error{Code: "server_error"})
+		return
+	}
+
+	err = p.repo.SaveDeviceAuthorization(ctx, entity.DeviceAuthorization{
+		DeviceCodeHash: HashSecret(deviceCode),
+		UserCode:       userCode,
+		ClientID:       client.ID,
+		Scope:          scope,
+		Interval:       devicePollInterval,
+		ExpiresAt:      time.Now().Add(deviceCodeTTL),
+	})
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, &Error{Code: "server_error"})
+		return
+	}
+
+	verificationURI := strings.TrimSuffix(p.jm.Issuer(), "/") + "/device"
+	writeJSON(w, http.StatusOK, DeviceAuthorizationResponse{
+		DeviceCode:              deviceCode,
+		UserCode:                formatUserCode(userCode),
+		VerificationURI:         verificationURI,
+		VerificationURIComplete: verificationURI + "?" + url.Values{"user_code": {formatUserCode(userCode)}}.Encode(),
+		ExpiresIn:               int64(deviceCodeTTL.Seconds()),
+		Interval:                int64(devicePollInterval.Seconds()),
+	})
+}
+
+// device - verification page, the code is prefilled when the user comes from
+// verification_uri_complete
+func (p *Provider) device(w http.ResponseWriter, r *http.Request) {
+	data := devicePageData{UserCode: r.URL.Query().Get("user_code")}
+
+	if data.UserCode != "" {
+		if d, client, ok := p.pendingDevice(r.Context(), data.UserCode); ok {
+			data.Client, data.Scope = client, d.Scope
+		}
+	}
+
+	renderPage(w, http.StatusOK, devicePage, data)
+}
+
+// deviceSubmit - the user approves or denies the device with the access token of a
+// fresh sign in, so second factors are passed the same way as on login
+func (p *Provider) deviceSubmit(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		renderPage(w, http.StatusBadRequest, devicePage, devicePageData{Error: "Malformed request."})
+		return
+	}
+
+	ctx := r.Context()
+	data := devicePageData{UserCode: r.PostForm.Get("user_code")}
+
+	d, client, ok := p.pendingDevice(ctx, data.UserCode)
+	if !ok {
+		data.Error = "The code is invalid or expired."
+		renderPage(w, http.StatusBadRequest, devicePage, data)
+		return
+	}
+	data.Client, data.Scope = client, d.Scope
+
+	user, ok := p.signedInUser(ctx, r.PostForm.Get("access_token"))
+	if !ok {
+		data.Error = "Sign in to connect the device."
+		renderPage(w, http.StatusUnauthorized, devicePage, data)
+		return
+	}
+
+	approved := r.PostForm.Get("consent") == "allow"
+	if approved {
+		if err := p.repo.SaveOAuthGrant(ctx, entity.OAuthGrant{UserID: user.ID, ClientID: d.ClientID, Scope: d.Scope}); err != nil {
+			data.Error = "Something went wrong, try again."
+			renderPage(w, http.StatusInternalServerError, devicePage, data)
+			return
+		}
+	}
+
+	if err := p.repo.DecideDeviceAuthorization(ctx, d.UserCode, user.ID, approved); err != nil {
+		data.Error = "The code is invalid or expired."
+		renderPage(w, http.StatusBadRequest, devicePage, data)
+		return
+	}
+
+	data.Done = "The device was denied access."
+	if approved {
+		data.Done = "The device is connected, you may return to it."
+	}
+
+	renderPage(w, http.StatusOK, devicePage, data)
+}
+
+// signedInUser - the user of the first-party access token issued by a sign in within
+// deviceSignInMaxAge
+func (p *Provider) signedInUser(ctx context.Context, accessToken string) (entity.UserAccount, bool) {
+	verified, err := p.jm.VerifyToken(accessToken)
+	if err != nil {
+		return entity.UserAccount{}, false
+	}
+
+	claims := verified.Claims.(jwt.MapClaims)
+	if !pkgjwt.FirstParty(claims) {
+		return entity.UserAccount{}, false
+	}
+
+	authTime, ok := claims[pkgjwt.AuthTimeClaim].(float64)
+	if !ok || time.Since(time.Unix(int64(authTime), 0)) > deviceSignInMaxAge {
+		return entity.UserAccount{}, false
+	}
+
+	sub, err := claims.GetSubject()
+	if err != nil {
+		return entity.UserAccount{}, false
+	}
+
+	user, err := p.repo.FindUserByEmail(ctx, sub)
+	if err != nil {
+		return entity.UserAccount{}, false
+	}
+
+	return user, true
+}
+
+func (p *Provider) pendingDevice(ctx context.Context, userCode string) (entity.DeviceAuthorization, entity.OAuthClient, bool) {
+	d, err := p.repo.GetDeviceAuthorizationByUserCode(ctx, normalizeUserCode(userCode))
+	if err != nil || d.Status != entity.DeviceAuthorizationPending || time.Now().After(d.ExpiresAt) {
+		return entity.DeviceAuthorization{}, entity.OAuthClient{}, false
+	}
+
+	client, err := p.repo.GetOAuthClient(ctx, d.ClientID)
+	if err != nil {
+		return entity.DeviceAuthorization{}, entity.OAuthClient{}, false
+	}
+
+	return d, client, true
+}
+
+// deviceCodeGrant - polled by the device until the user decides, devices polling
+// more often than the interval are asked to slow down
+func (p *Provider) deviceCodeGrant(ctx context.Context, r *http.Request) (TokenResponse, error) {
+	client, err := p.authenticateClient(ctx, r)
+	if err != nil {
+		return TokenResponse{}, err
+	}
+
+	hash := HashSecret(r.PostForm.Get("device_code"))
+	d, err := p.repo.GetDeviceAuthorization(ctx, hash)
+	if err != nil || d.ClientID != client.ID {
+		return TokenResponse{}, errInvalidGrant("device code is invalid")
+	}
+
+	now := time.Now()
+	if now.After(d.ExpiresAt) {
+		return TokenResponse{}, &Error{Code: "expired_token", Description: "device code expired", status: http.StatusBadRequest}
+	}
+
+	switch d.Status {
+	case entity.DeviceAuthorizationPending:
+		interval, code := d.Interval, "authorization_pending"
+		if !d.LastPolledAt.IsZero() && now.Sub(d.LastPolledAt) < d.Interval {
+			interval, code = d.Interval+deviceSlowDownStep, "slow_down"
+		}
+
+		if err = p.repo.PollDeviceAuthorization(ctx, hash, now, interval); err != nil {
+			return TokenResponse{}, err
+		}
+
+		return TokenResponse{}, &Error{Code: code, status: http.StatusBadRequest}
+	case entity.DeviceAuthorizationDenied:
+		return TokenResponse{}, &Error{Code: "access_denied", Description: "the user denied the request", status: http.StatusBadRequest}
+	case entity.DeviceAuthorizationApproved:
+	default:
+		return TokenResponse{}, errInvalidGrant("device code is already used")
+	}
+
+	if err = p.repo.ConsumeDeviceAuthorization(ctx, hash); err != nil {
+		return TokenResponse{}, errInvalidGrant("device code is already used")
+	}
+
+	user, err := p.repo.GetUserById(ctx, d.UserID)
+	if err != nil {
+		return TokenResponse{}, errInvalidGrant("user not found")
+	}
+
+	res, err := p.issueUserTokens(ctx, user, client.ID, d.Scope)
+	if err != nil {
+		return TokenResponse{}, err
+	}
+	p.publishSignIn(user, client.ID)
+
+	if slices.Contains(strings.Fields(d.Scope), ScopeOpenID) {
+		if res.IDToken, err = p.issueIDToken(ctx, user, client.ID, d.Scope, "", d.ApprovedAt); err != nil {
+			return TokenResponse{}, err
+		}
+	}
+
+	return res, nil
+}
+
+func generateUserCode() (string, error) {
+	code := make([]byte, userCodeLength)
+	alphabetSize := big.NewInt(int64(len(userCodeAlphabet)))
+	for i := range code {
+		n, err := rand.Int(rand.Reader, alphabetSize)
+		if err != nil {
+			return "", err
+		}
+		code[i] = userCodeAlphabet[n.Int64()]
+	}
+
+	return string(code), nil
+}
+
+// formatUserCode - XXXX-XXXX as shown to the user
+func formatUserCode(code string) string {
+	return code[:userCodeLength/2] + "-" + code[userCodeLength/2:]
+}
+
+// normalizeUserCode - users may type the code in lower case, with or without the dash
+func normalizeUserCode(code string) string {
+	return strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(code))
+}
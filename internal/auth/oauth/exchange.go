@@ -0,0 +1,220 @@
+package oauth
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	pkgjwt "github.com/bogatyr285/auth-go/pkg/jwt"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	tokenExchangeGrantType = "urn:ietf:params:oauth:grant-type:token-exchange"
+	accessTokenType        = "urn:ietf:params:oauth:token-type:access_token"
+
+	// ActClaim - RFC 8693 actor of the token, nested when the subject token had one
+	ActClaim = pkgjwt.ActClaim
+)
+
+// ExchangePolicy - tokens the client may get in exchange for tokens of users
+type ExchangePolicy struct {
+	ClientID string
+	// Audiences - audience values the client may request
+	Audiences []string
+	// Scopes - upper bound of scopes of issued tokens
+	Scopes []string
+	// ActorRoles - actors with any of the roles may act for the subject (e.g. moderators),
+	// actor tokens are rejected when it's empty
+	ActorRoles []string
+}
+
+// WithTokenExchange - enables token exchange grant, exchanges made by actors are
+// written to audit
+func WithTokenExchange(policies []ExchangePolicy, audit *slog.Logger) Option {
+	return func(p *Provider) {
+		p.exchangePolicies = policies
+		p.audit = audit
+		p.grants[tokenExchangeGrantType] = p.tokenExchangeGrant
+	}
+}
+
+// tokenExchangeGrant - RFC 8693, the subject token is swapped for a token of another
+// audience with a scope no wider than both the subject token's and the policy's one.
+// Roles aren't carried over, so the issued token is never more powerful than the subject one
+func (p *Provider) tokenExchangeGrant(ctx context.Context, r *http.Request) (TokenResponse, error) {
+	client, err := p.authenticateClient(ctx, r)
+	if err != nil {
+		return TokenResponse{}, err
+	}
+
+	if !client.Confidential {
+		return TokenResponse{}, errUnauthorizedClient("public clients can't exchange tokens")
+	}
+
+	if t := r.PostForm.Get("requested_token_type"); t != "" && t != accessTokenType {
+		return TokenResponse{}, errInvalidRequest("only access tokens may be requested")
+	}
+
+	audience := r.PostForm.Get("audience")
+	if audience == "" {
+		return TokenResponse{}, errInvalidRequest("audience is required")
+	}
+
+	policy, ok := p.exchangePolicy(client.ID, audience)
+	if !ok {
+		return TokenResponse{}, &Error{Code: "invalid_target", Description: "the client may not exchange tokens for the audience", status: http.StatusBadRequest}
+	}
+
+	subject, err := p.exchangedToken(r.PostForm.Get("subject_token"), r.PostForm.Get("subject_token_type"))
+	if err != nil {
+		return TokenResponse{}, err
+	}
+
+	subjectID, err := subject.GetSubject()
+	if err != nil {
+		return TokenResponse{}, errInvalidGrant("subject token is invalid")
+	}
+
+	scope, err := exchangeScope(r.PostForm.Get("scope"), subject, policy)
+	if err != nil {
+		return TokenResponse{}, err
+	}
+
+	claims := jwt.MapClaims{
+		"aud":         audience,
+		ScopeClaim:    scope,
+		ClientIDClaim: client.ID,
+	}
+
+	// the token mustn't outlive the one it was exchanged for
+	if exp, err := subject.GetExpirationTime(); err == nil && exp != nil && exp.Before(time.Now().Add(p.accessTokenTTL)) {
+		claims["exp"] = exp.Unix()
+	}
+
+	if act, ok := subject[ActClaim]; ok {
+		claims[ActClaim] = act
+	}
+
+	var actorID string
+	if r.PostForm.Has("actor_token") {
+		actor, err := p.exchangedToken(r.PostForm.Get("actor_token"), r.PostForm.Get("actor_token_type"))
+		if err != nil {
+			return TokenResponse{}, err
+		}
+
+		if actorID, err = actor.GetSubject(); err != nil {
+			return TokenResponse{}, errInvalidGrant("actor token is invalid")
+		}
+
+		if !hasAnyRole(actor, policy.ActorRoles) {
+			return TokenResponse{}, errInvalidGrant("the actor may not act for the subject")
+		}
+
+		act := map[string]interface{}{"sub": actorID}
+		if prior, ok := subject[ActClaim]; ok {
+			act[ActClaim] = prior
+		}
+		claims[ActClaim] = act
+	}
+
+	accessToken, err := p.jm.IssueTokenWithClaims(subjectID, claims)
+	if err != nil {
+		return TokenResponse{}, err
+	}
+
+	if actorID != "" {
+		p.audit.Info("impersonation",
+			slog.String("actor", actorID),
+			slog.String("subject", subjectID),
+			slog.String("client_id", client.ID),
+			slog.String("audience", audience),
+			slog.String("scope", scope),
+		)
+	}
+
+	expiresIn := int64(p.accessTokenTTL.Seconds())
+	if exp, ok := claims["exp"].(int64); ok {
+		expiresIn = exp - time.Now().Unix()
+	}
+
+	return TokenResponse{
+		AccessToken:     accessToken,
+		IssuedTokenType: accessTokenType,
+		TokenType:       "Bearer",
+		ExpiresIn:       expiresIn,
+		Scope:           scope,
+	}, nil
+}
+
+func (p *Provider) exchangePolicy(clientID, audience string) (ExchangePolicy, bool) {
+	for _, policy := range p.exchangePolicies {
+		if policy.ClientID == clientID && slices.Contains(policy.Audiences, audience) {
+			return policy, true
+		}
+	}
+
+	return ExchangePolicy{}, false
+}
+
+// exchangedToken - subject and actor tokens are access tokens of users issued by the provider
+func (p *Provider) exchangedToken(token, tokenType string) (jwt.MapClaims, error) {
+	if tokenType != accessTokenType {
+		return nil, errInvalidRequest("only access tokens may be exchanged")
+	}
+
+	verified, err := p.jm.VerifyToken(token)
+	if err != nil {
+		return nil, errInvalidGrant("token is invalid")
+	}
+
+	claims := verified.Claims.(jwt.MapClaims)
+	if _, ok := claims[pkgjwt.TokenUseClaim]; ok {
+		return nil, errInvalidGrant("not an access token")
+	}
+
+	if pkgjwt.ClientSubject(claims) {
+		return nil, errInvalidGrant("tokens of clients have no user to act for")
+	}
+
+	return claims, nil
+}
+
+// exchangeScope - scope of the policy narrowed down by the subject token's one, tokens
+// without scope are first party ones and carry the full authority of the user
+func exchangeScope(requested string, subject jwt.MapClaims, policy ExchangePolicy) (string, error) {
+	allowed := policy.Scopes
+	if subjectScope, ok := subject[ScopeClaim].(string); ok {
+		allowed = slices.DeleteFunc(slices.Clone(allowed), func(s string) bool {
+			return !slices.Contains(strings.Fields(subjectScope), s)
+		})
+	}
+
+	scope := strings.Join(allowed, " ")
+	if requested != "" {
+		if !scopeSubset(requested, scope) {
+			return "", errInvalidScope("scope exceeds the one the subject token may be exchanged for")
+		}
+		scope = requested
+	}
+
+	if scope == "" {
+		return "", errInvalidScope("no scope may be granted")
+	}
+
+	return scope, nil
+}
+
+func hasAnyRole(claims jwt.MapClaims, roles []string) bool {
+	granted, _ := claims[pkgjwt.RolesClaim].([]interface{})
+	for _, role := range granted {
+		if name, ok := role.(string); ok && slices.Contains(roles, name) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,470 @@
+// Package oauth - OAuth 2.1 authorization server: authorization code with PKCE, refresh token,
+// client credentials, device and token exchange grants, with OpenID Connect on top of the
+// user facing flows
+package oauth
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/bogatyr285/auth-go/internal/auth/entity"
+	pkgjwt "github.com/bogatyr285/auth-go/pkg/jwt"
+	"github.com/go-chi/chi/v5"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	authCodeTTL = time.Minute
+
+	// ScopeClaim - space delimited scopes of access tokens issued to clients
+	ScopeClaim    = "scope"
+	ClientIDClaim = pkgjwt.ClientIDClaim
+)
+
+type Repository interface {
+	FindUserByEmail(ctx context.Context, username string) (entity.UserAccount, error)
+	GetUserById(ctx context.Context, ID int) (entity.UserAccount, error)
+	GetUserProfile(ctx context.Context, userID int) (entity.UserAccount, error)
+	ListWebAuthnCredentials(ctx context.Context, userID int) ([]entity.WebAuthnCredential, error)
+
+	GetOAuthClient(ctx context.Context, ID string) (entity.OAuthClient, error)
+	SaveAuthorizationCode(ctx context.Context, code entity.AuthorizationCode) error
+	ConsumeAuthorizationCode(ctx context.Context, codeHash string) (entity.AuthorizationCode, error)
+	SaveOAuthGrant(ctx context.Context, g entity.OAuthGrant) error
+	GetOAuthGrant(ctx context.Context, userID int, clientID string) (entity.OAuthGrant, error)
+	SaveOAuthRefreshToken(ctx context.Context, userID int, clientID, tokenHash string) error
+	GetOAuthGrantByRefreshToken(ctx context.Context, tokenHash string) (entity.OAuthGrant, error)
+	RevokeOAuthRefreshToken(ctx context.Context, tokenHash string) error
+	FindClientSecret(ctx context.Context, clientID, secretHash string) (entity.ClientSecret, error)
+
+	SaveDeviceAuthorization(ctx context.Context, d entity.DeviceAuthorization) error
+	GetDeviceAuthorization(ctx context.Context, deviceCodeHash string) (entity.DeviceAuthorization, error)
+	GetDeviceAuthorizationByUserCode(ctx context.Context, userCode string) (entity.DeviceAuthorization, error)
+	DecideDeviceAuthorization(ctx context.Context, userCode string, userID int, approved bool) error
+	PollDeviceAuthorization(ctx context.Context, deviceCodeHash string, polledAt time.Time, interval time.Duration) error
+	ConsumeDeviceAuthorization(ctx context.Context, deviceCodeHash string) error
+}
+
+type CryptoPassword interface {
+	ComparePasswords(fromUser, fromDB string) bool
+}
+
+type JWTManager interface {
+	IssueTokenWithClaims(userID string, extra jwt.MapClaims) (string, error)
+	VerifyToken(tokenString string) (*jwt.Token, error)
+	Issuer() string
+	PublicKey() ed25519.PublicKey
+	KeyID() string
+}
+
+// Publisher - receives security events, e.g. events.Bus
+type Publisher interface {
+	Publish(e entity.SecurityEvent)
+}
+
+// grantHandler - issues tokens for a grant_type of the token endpoint
+type grantHandler func(ctx context.Context, r *http.Request) (TokenResponse, error)
+
+type Provider struct {
+	repo Repository
+	cp   CryptoPassword
+	jm   JWTManager
+	// accessTokenTTL - lifetime of access tokens issued by jm, reported as expires_in
+	accessTokenTTL time.Duration
+
+	grants map[string]grantHandler
+
+	exchangePolicies []ExchangePolicy
+	audit            *slog.Logger
+
+	events Publisher
+}
+
+// Option - enables optional grants of Provider
+type Option func(p *Provider)
+
+func NewProvider(repo Repository, cp CryptoPassword, jm JWTManager, accessTokenTTL time.Duration, opts ...Option) *Provider {
+	p := &Provider{
+		repo:           repo,
+		cp:             cp,
+		jm:             jm,
+		accessTokenTTL: accessTokenTTL,
+	}
+
+	p.grants = map[string]grantHandler{
+		"authorization_code": p.authorizationCodeGrant,
+		"refresh_token":      p.refreshTokenGrant,
+		"client_credentials": p.clientCredentialsGrant,
+		deviceCodeGrantType:  p.deviceCodeGrant,
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// WithEvents - sign ins of users to clients are published, events are dropped when unset
+func WithEvents(e Publisher) Option {
+	return func(p *Provider) {
+		p.events = e
+	}
+}
+
+// Routes - registers endpoints of the provider, they must be public for AuthMiddleware
+func (p *Provider) Routes(r chi.Router) {
+	r.Get("/authorize", p.authorize)
+	r.Post("/authorize", p.authorizeSubmit)
+	r.Post("/token", p.token)
+	r.Post("/device_authorization", p.deviceAuthorization)
+	r.Get("/device", p.device)
+	r.Post("/device", p.deviceSubmit)
+	r.Get("/userinfo", p.userinfo)
+	r.Post("/userinfo", p.userinfo)
+	r.Get("/.well-known/openid-configuration", p.discovery)
+	r.Get("/.well-known/jwks.json", p.jwks)
+}
+
+// Error - RFC 6749 error response
+type Error struct {
+	Code        string `json:"error"`
+	Description string `json:"error_description,omitempty"`
+
+	status int
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code, e.Description)
+}
+
+func errInvalidRequest(description string) *Error {
+	return &Error{Code: "invalid_request", Description: description, status: http.StatusBadRequest}
+}
+
+func errInvalidClient(description string) *Error {
+	return &Error{Code: "invalid_client", Description: description, status: http.StatusUnauthorized}
+}
+
+func errInvalidGrant(description string) *Error {
+	return &Error{Code: "invalid_grant", Description: description, status: http.StatusBadRequest}
+}
+
+func errUnauthorizedClient(description string) *Error {
+	return &Error{Code: "unauthorized_client", Description: description, status: http.StatusBadRequest}
+}
+
+func errInvalidScope(description string) *Error {
+	return &Error{Code: "invalid_scope", Description: description, status: http.StatusBadRequest}
+}
+
+type TokenResponse struct {
+	AccessToken     string `json:"access_token"`
+	IssuedTokenType string `json:"issued_token_type,omitempty"`
+	TokenType       string `json:"token_type"`
+	ExpiresIn       int64  `json:"expires_in"`
+	RefreshToken    string `json:"refresh_token,omitempty"`
+	Scope           string `json:"scope,omitempty"`
+	IDToken         string `json:"id_token,omitempty"`
+}
+
+func (p *Provider) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeJSON(w, http.StatusBadRequest, errInvalidRequest("malformed form body"))
+		return
+	}
+
+	grant, ok := p.grants[r.PostForm.Get("grant_type")]
+	if !ok {
+		writeJSON(w, http.StatusBadRequest, &Error{Code: "unsupported_grant_type", Description: "grant_type is not supported"})
+		return
+	}
+
+	res, err := grant(r.Context(), r)
+	if err != nil {
+		if oauthErr, ok := err.(*Error); ok {
+			if oauthErr.status == http.StatusUnauthorized {
+				w.Header().Set("WWW-Authenticate", `Basic realm="token"`)
+			}
+			writeJSON(w, oauthErr.status, oauthErr)
+			return
+		}
+
+		writeJSON(w, http.StatusInternalServerError, &Error{Code: "server_error"})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, res)
+}
+
+func (p *Provider) authorizationCodeGrant(ctx context.Context, r *http.Request) (TokenResponse, error) {
+	client, err := p.authenticateClient(ctx, r)
+	if err != nil {
+		return TokenResponse{}, err
+	}
+
+	code, err := p.repo.ConsumeAuthorizationCode(ctx, HashSecret(r.PostForm.Get("code")))
+	if err != nil {
+		return TokenResponse{}, errInvalidGrant("authorization code is invalid or already used")
+	}
+
+	if code.ClientID != client.ID || time.Now().After(code.ExpiresAt) {
+		return TokenResponse{}, errInvalidGrant("authorization code is invalid or expired")
+	}
+
+	if code.RedirectURI != r.PostForm.Get("redirect_uri") {
+		return TokenResponse{}, errInvalidGrant("redirect_uri doesn't match the authorization request")
+	}
+
+	if !verifyCodeChallenge(code.CodeChallenge, r.PostForm.Get("code_verifier")) {
+		return TokenResponse{}, errInvalidGrant("code_verifier doesn't match code_challenge")
+	}
+
+	user, err := p.repo.GetUserById(ctx, code.UserID)
+	if err != nil {
+		return TokenResponse{}, errInvalidGrant("user not found")
+	}
+
+	res, err := p.issueUserTokens(ctx, user, client.ID, code.Scope)
+	if err != nil {
+		return TokenResponse{}, err
+	}
+	p.publishSignIn(user, client.ID)
+
+	if slices.Contains(strings.Fields(code.Scope), ScopeOpenID) {
+		if res.IDToken, err = p.issueIDToken(ctx, user, client.ID, code.Scope, code.Nonce, code.AuthTime); err != nil {
+			return TokenResponse{}, err
+		}
+	}
+
+	return res, nil
+}
+
+// refreshTokenGrant - refresh tokens belong to the grant of the client, so they work only
+// for that client within the consented scope. Every refresh rotates the token
+func (p *Provider) refreshTokenGrant(ctx context.Context, r *http.Request) (TokenResponse, error) {
+	client, err := p.authenticateClient(ctx, r)
+	if err != nil {
+		return TokenResponse{}, err
+	}
+
+	hash := HashSecret(r.PostForm.Get("refresh_token"))
+	grant, err := p.repo.GetOAuthGrantByRefreshToken(ctx, hash)
+	if err != nil {
+		return TokenResponse{}, errInvalidGrant("refresh token is invalid")
+	}
+
+	if grant.ClientID != client.ID {
+		return TokenResponse{}, errInvalidGrant("refresh token wasn't issued to the client")
+	}
+
+	scope := grant.Scope
+	if requested := r.PostForm.Get("scope"); requested != "" {
+		if !scopeSubset(requested, grant.Scope) {
+			return TokenResponse{}, errInvalidScope("scope exceeds the one granted")
+		}
+		scope = requested
+	}
+
+	user, err := p.repo.GetUserById(ctx, grant.UserID)
+	if err != nil {
+		return TokenResponse{}, errInvalidGrant("user not found")
+	}
+
+	if err = p.repo.RevokeOAuthRefreshToken(ctx, hash); err != nil {
+		return TokenResponse{}, errInvalidGrant("refresh token is invalid")
+	}
+
+	return p.issueUserTokens(ctx, user, client.ID, scope)
+}
+
+// clientCredentialsGrant - tokens of confidential clients acting on their own behalf,
+// sub is the client ID marked by sub_type so it's never taken for a username. There is
+// no refresh token since the client can always authenticate again
+func (p *Provider) clientCredentialsGrant(ctx context.Context, r *http.Request) (TokenResponse, error) {
+	client, err := p.authenticateClient(ctx, r)
+	if err != nil {
+		return TokenResponse{}, err
+	}
+
+	if !client.Confidential {
+		return TokenResponse{}, errUnauthorizedClient("public clients can't use client_credentials")
+	}
+
+	scope := strings.Join(client.Scopes, " ")
+	if requested := r.PostForm.Get("scope"); requested != "" {
+		if !scopeSubset(requested, scope) {
+			return TokenResponse{}, errInvalidScope("scope isn't allowed for the client")
+		}
+		scope = requested
+	}
+
+	accessToken, err := p.jm.IssueTokenWithClaims(client.ID, jwt.MapClaims{
+		ScopeClaim:              scope,
+		ClientIDClaim:           client.ID,
+		pkgjwt.SubjectTypeClaim: pkgjwt.SubjectTypeClient,
+	})
+	if err != nil {
+		return TokenResponse{}, err
+	}
+
+	return TokenResponse{
+		AccessToken: accessToken,
+		TokenType:   "Bearer",
+		ExpiresIn:   int64(p.accessTokenTTL.Seconds()),
+		Scope:       scope,
+	}, nil
+}
+
+// authenticateClient - confidential clients authenticate with client_secret_basic or
+// client_secret_post, public clients only identify themselves with client_id
+func (p *Provider) authenticateClient(ctx context.Context, r *http.Request) (entity.OAuthClient, error) {
+	clientID, secret, basic := r.BasicAuth()
+	if basic {
+		if r.PostForm.Get("client_secret") != "" {
+			return entity.OAuthClient{}, errInvalidRequest("only one client authentication method may be used")
+		}
+
+		// credentials of the basic scheme are form encoded, RFC 6749 2.3.1
+		var err error
+		if clientID, err = url.QueryUnescape(clientID); err != nil {
+			return entity.OAuthClient{}, errInvalidClient("malformed client credentials")
+		}
+		if secret, err = url.QueryUnescape(secret); err != nil {
+			return entity.OAuthClient{}, errInvalidClient("malformed client credentials")
+		}
+
+		if formID := r.PostForm.Get("client_id"); formID != "" && formID != clientID {
+			return entity.OAuthClient{}, errInvalidRequest("client_id doesn't match client credentials")
+		}
+	} else {
+		clientID = r.PostForm.Get("client_id")
+		secret = r.PostForm.Get("client_secret")
+	}
+
+	if clientID == "" {
+		return entity.OAuthClient{}, errInvalidClient("client_id is required")
+	}
+
+	client, err := p.repo.GetOAuthClient(ctx, clientID)
+	if err != nil {
+		return entity.OAuthClient{}, errInvalidClient("unknown client")
+	}
+
+	if !client.Confidential {
+		if secret != "" {
+			return entity.OAuthClient{}, errInvalidClient("public clients don't have secrets")
+		}
+
+		return client, nil
+	}
+
+	if secret == "" {
+		return entity.OAuthClient{}, errInvalidClient("client authentication is required")
+	}
+
+	s, err := p.repo.FindClientSecret(ctx, client.ID, HashSecret(secret))
+	if err != nil || (s.ExpiresAt != nil && time.Now().After(*s.ExpiresAt)) {
+		return entity.OAuthClient{}, errInvalidClient("client authentication failed")
+	}
+
+	return client, nil
+}
+
+// publishSignIn - the user signed in to the client, refreshes aren't sign ins
+func (p *Provider) publishSignIn(user entity.UserAccount, clientID string) {
+	if p.events != nil {
+		p.events.Publish(entity.SecurityEvent{
+			Type:     entity.SecurityEventOAuthSignIn,
+			UserID:   user.ID,
+			Username: user.Username,
+			ClientID: clientID,
+		})
+	}
+}
+
+// issueUserTokens - access token limited to the scope and a refresh token of the client's
+// grant. Roles of the user aren't included, clients get the scope the user consented to
+func (p *Provider) issueUserTokens(ctx context.Context, user entity.UserAccount, clientID, scope string) (TokenResponse, error) {
+	accessToken, err := p.jm.IssueTokenWithClaims(user.Username, jwt.MapClaims{
+		ScopeClaim:    scope,
+		ClientIDClaim: clientID,
+	})
+	if err != nil {
+		return TokenResponse{}, err
+	}
+
+	refreshToken, err := GenerateSecret()
+	if err != nil {
+		return TokenResponse{}, err
+	}
+
+	if err = p.repo.SaveOAuthRefreshToken(ctx, user.ID, clientID, HashSecret(refreshToken)); err != nil {
+		return TokenResponse{}, err
+	}
+
+	return TokenResponse{
+		AccessToken:  accessToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(p.accessTokenTTL.Seconds()),
+		RefreshToken: refreshToken,
+		Scope:        scope,
+	}, nil
+}
+
+// verifyCodeChallenge - S256 is the only supported method
+func verifyCodeChallenge(challenge, verifier string) bool {
+	if len(verifier) < 43 || len(verifier) > 128 {
+		return false
+	}
+
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:]) == challenge
+}
+
+// HashSecret - hash of client secrets and authorization codes to store, they are random
+// so a fast hash doesn't make guessing them easier
+func HashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// GenerateSecret - 256 bits of randomness, base64url encoded
+func GenerateSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// scopeSubset - every scope of requested is in granted
+func scopeSubset(requested, granted string) bool {
+	grantedScopes := strings.Fields(granted)
+	for _, s := range strings.Fields(requested) {
+		if !slices.Contains(grantedScopes, s) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,836 @@
+package oauth_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bogatyr285/auth-go/internal/auth/entity"
+	"github.com/bogatyr285/auth-go/internal/auth/events"
+	"github.com/bogatyr285/auth-go/internal/auth/oauth"
+	"github.com/bogatyr285/auth-go/internal/auth/repository"
+	"github.com/bogatyr285/auth-go/pkg/crypto"
+	"github.com/bogatyr285/auth-go/pkg/jwt"
+	"github.com/go-chi/chi/v5"
+	gojwt "github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testClientID    = "client-1"
+	testRedirectURI = "https://app.example.com/callback"
+	testUsername    = "alice@example.com"
+	testPassword    = "rLy_5tr0nG!"
+)
+
+func newTestJWTManager(t *testing.T) *jwt.JWTManager {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	privDER, err := x509.MarshalPKCS8PrivateKey(priv)
+	require.NoError(t, err)
+	pubDER, err := x509.MarshalPKIXPublicKey(pub)
+	require.NoError(t, err)
+
+	jm, err := jwt.NewJWTManager("https://auth.example.com", time.Hour,
+		pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER}),
+		pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privDER}),
+	)
+	require.NoError(t, err)
+
+	return jm
+}
+
+func newTestProvider(t *testing.T, opts ...oauth.Option) (*httptest.Server, *jwt.JWTManager, *repository.SQLLiteStorage) {
+	ctx := context.Background()
+
+	storage, err := repository.New(filepath.Join(t.TempDir(), "db.sqlite"))
+	require.NoError(t, err)
+	t.Cleanup(func() { storage.Close() })
+
+	hasher := crypto.NewPasswordHasher()
+	password, err := hasher.HashPassword(testPassword)
+	require.NoError(t, err)
+	require.NoError(t, storage.RegisterUser(ctx, entity.UserAccount{
+		Username: testUsername,
+		Password: string(password),
+		Name:     "Alice",
+		Email:    testUsername,
+		Address:  entity.Address{City: "Berlin"},
+	}))
+	require.NoError(t, storage.SaveOAuthClient(ctx, entity.OAuthClient{
+		ID:           testClientID,
+		Name:         "Example App",
+		RedirectURIs: []string{testRedirectURI},
+		Scopes:       []string{"openid", "profile", "email", "address"},
+	}))
+
+	jm := newTestJWTManager(t)
+	router := chi.NewRouter()
+	oauth.NewProvider(&storage, hasher, jm, time.Hour, opts...).Routes(router)
+
+	srv := httptest.NewServer(router)
+	t.Cleanup(srv.Close)
+	// redirects to the client are inspected instead of followed
+	srv.Client().CheckRedirect = func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }
+
+	return srv, jm, &storage
+}
+
+func pkcePair() (string, string) {
+	verifier := base64.RawURLEncoding.EncodeToString([]byte(strings.Repeat("v", 48)))
+	sum := sha256.Sum256([]byte(verifier))
+	return verifier, base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func authorizeParams(challenge string) url.Values {
+	return url.Values{
+		"response_type":         {"code"},
+		"client_id":             {testClientID},
+		"redirect_uri":          {testRedirectURI},
+		"scope":                 {"profile email"},
+		"state":                 {"xyz"},
+		"code_challenge":        {challenge},
+		"code_challenge_method": {"S256"},
+	}
+}
+
+// authorize - signs in on the consent page, returns the query of the redirect to the client
+func authorize(t *testing.T, srv *httptest.Server, params url.Values) url.Values {
+	form := url.Values{"username": {testUsername}, "password": {testPassword}, "consent": {"allow"}}
+	for k, v := range params {
+		form[k] = v
+	}
+
+	res, err := srv.Client().PostForm(srv.URL+"/authorize", form)
+	require.NoError(t, err)
+	res.Body.Close()
+	require.Equal(t, http.StatusFound, res.StatusCode)
+
+	location, err := url.Parse(res.Header.Get("Location"))
+	require.NoError(t, err)
+	require.Equal(t, testRedirectURI, location.Scheme+"://"+location.Host+location.Path)
+
+	return location.Query()
+}
+
+func token(t *testing.T, srv *httptest.Server, form url.Values) (int, map[string]interface{}) {
+	return tokenWithBasicAuth(t, srv, form, "", "")
+}
+
+func tokenWithBasicAuth(t *testing.T, srv *httptest.Server, form url.Values, clientID, secret string) (int, map[string]interface{}) {
+	req, err := http.NewRequest(http.MethodPost, srv.URL+"/token", strings.NewReader(form.Encode()))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if clientID != "" {
+		req.SetBasicAuth(url.QueryEscape(clientID), url.QueryEscape(secret))
+	}
+
+	res, err := srv.Client().Do(req)
+	require.NoError(t, err)
+	defer res.Body.Close()
+
+	require.Equal(t, "no-store", res.Header.Get("Cache-Control"))
+
+	var body map[string]interface{}
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&body))
+
+	return res.StatusCode, body
+}
+
+func TestAuthorizationCodeFlow(t *testing.T) {
+	srv, jm, _ := newTestProvider(t)
+	verifier, challenge := pkcePair()
+
+	t.Run("consent page", func(t *testing.T) {
+		res, err := srv.Client().Get(srv.URL + "/authorize?" + authorizeParams(challenge).Encode())
+		require.NoError(t, err)
+		res.Body.Close()
+
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		require.Equal(t, "DENY", res.Header.Get("X-Frame-Options"))
+	})
+
+	t.Run("code exchange and refresh", func(t *testing.T) {
+		query := authorize(t, srv, authorizeParams(challenge))
+		require.Equal(t, "xyz", query.Get("state"))
+
+		exchange := url.Values{
+			"grant_type":    {"authorization_code"},
+			"client_id":     {testClientID},
+			"redirect_uri":  {testRedirectURI},
+			"code":          {query.Get("code")},
+			"code_verifier": {verifier},
+		}
+
+		status, tokens := token(t, srv, exchange)
+		require.Equal(t, http.StatusOK, status)
+		require.Equal(t, "Bearer", tokens["token_type"])
+		require.Equal(t, "profile email", tokens["scope"])
+
+		parsed, err := jm.VerifyToken(tokens["access_token"].(string))
+		require.NoError(t, err)
+		claims := parsed.Claims.(gojwt.MapClaims)
+		require.Equal(t, testUsername, claims["sub"])
+		require.Equal(t, testClientID, claims[oauth.ClientIDClaim])
+		require.NotContains(t, claims, jwt.RolesClaim)
+
+		// codes are single use
+		status, body := token(t, srv, exchange)
+		require.Equal(t, http.StatusBadRequest, status)
+		require.Equal(t, "invalid_grant", body["error"])
+
+		refresh := url.Values{
+			"grant_type":    {"refresh_token"},
+			"client_id":     {testClientID},
+			"refresh_token": {tokens["refresh_token"].(string)},
+			"scope":         {"profile"},
+		}
+		status, refreshed := token(t, srv, refresh)
+		require.Equal(t, http.StatusOK, status)
+		require.Equal(t, "profile", refreshed["scope"])
+
+		// refresh tokens are rotated
+		status, body = token(t, srv, refresh)
+		require.Equal(t, http.StatusBadRequest, status)
+		require.Equal(t, "invalid_grant", body["error"])
+		refresh.Set("refresh_token", refreshed["refresh_token"].(string))
+
+		refresh.Set("scope", "profile admin")
+		status, body = token(t, srv, refresh)
+		require.Equal(t, http.StatusBadRequest, status)
+		require.Equal(t, "invalid_scope", body["error"])
+	})
+
+	t.Run("sign ins keep their own refresh tokens", func(t *testing.T) {
+		signIn := func(t *testing.T) string {
+			status, tokens := token(t, srv, url.Values{
+				"grant_type":    {"authorization_code"},
+				"client_id":     {testClientID},
+				"redirect_uri":  {testRedirectURI},
+				"code":          {authorize(t, srv, authorizeParams(challenge)).Get("code")},
+				"code_verifier": {verifier},
+			})
+			require.Equal(t, http.StatusOK, status)
+			return tokens["refresh_token"].(string)
+		}
+
+		// e.g. the app on the phone and on the laptop
+		for _, refreshToken := range []string{signIn(t), signIn(t)} {
+			status, _ := token(t, srv, url.Values{
+				"grant_type":    {"refresh_token"},
+				"client_id":     {testClientID},
+				"refresh_token": {refreshToken},
+			})
+			require.Equal(t, http.StatusOK, status)
+		}
+	})
+
+	t.Run("wrong code verifier", func(t *testing.T) {
+		query := authorize(t, srv, authorizeParams(challenge))
+
+		status, body := token(t, srv, url.Values{
+			"grant_type":    {"authorization_code"},
+			"client_id":     {testClientID},
+			"redirect_uri":  {testRedirectURI},
+			"code":          {query.Get("code")},
+			"code_verifier": {base64.RawURLEncoding.EncodeToString([]byte(strings.Repeat("w", 48)))},
+		})
+		require.Equal(t, http.StatusBadRequest, status)
+		require.Equal(t, "invalid_grant", body["error"])
+	})
+
+	t.Run("pkce is required", func(t *testing.T) {
+		params := authorizeParams("")
+		query := authorize(t, srv, params)
+		require.Equal(t, "invalid_request", query.Get("error"))
+
+		params = authorizeParams(challenge)
+		params.Set("code_challenge_method", "plain")
+		query = authorize(t, srv, params)
+		require.Equal(t, "invalid_request", query.Get("error"))
+	})
+
+	t.Run("scope is limited to the client's", func(t *testing.T) {
+		params := authorizeParams(challenge)
+		params.Set("scope", "profile admin")
+
+		res, err := srv.Client().Get(srv.URL + "/authorize?" + params.Encode())
+		require.NoError(t, err)
+		res.Body.Close()
+		require.Equal(t, http.StatusFound, res.StatusCode)
+
+		location, err := url.Parse(res.Header.Get("Location"))
+		require.NoError(t, err)
+		require.Equal(t, "invalid_scope", location.Query().Get("error"))
+
+		query := authorize(t, srv, params)
+		require.Equal(t, "invalid_scope", query.Get("error"))
+		require.Empty(t, query.Get("code"))
+	})
+
+	t.Run("denied consent", func(t *testing.T) {
+		params := authorizeParams(challenge)
+		params.Set("consent", "deny")
+		query := authorize(t, srv, params)
+		require.Equal(t, "access_denied", query.Get("error"))
+		require.Equal(t, "xyz", query.Get("state"))
+	})
+
+	t.Run("unregistered redirect uri isn't redirected to", func(t *testing.T) {
+		params := authorizeParams(challenge)
+		params.Set("redirect_uri", "https://evil.example.com/callback")
+
+		res, err := srv.Client().Get(srv.URL + "/authorize?" + params.Encode())
+		require.NoError(t, err)
+		res.Body.Close()
+		require.Equal(t, http.StatusBadRequest, res.StatusCode)
+	})
+
+	t.Run("unknown client and grant type", func(t *testing.T) {
+		status, body := token(t, srv, url.Values{"grant_type": {"authorization_code"}, "client_id": {"nope"}})
+		require.Equal(t, http.StatusUnauthorized, status)
+		require.Equal(t, "invalid_client", body["error"])
+
+		status, body = token(t, srv, url.Values{"grant_type": {"password"}})
+		require.Equal(t, http.StatusBadRequest, status)
+		require.Equal(t, "unsupported_grant_type", body["error"])
+	})
+}
+
+func TestSignInEvents(t *testing.T) {
+	bus := events.NewBus(8)
+	srv, _, _ := newTestProvider(t, oauth.WithEvents(bus))
+	sub := bus.Subscribe(nil)
+	verifier, challenge := pkcePair()
+
+	query := authorize(t, srv, authorizeParams(challenge))
+	status, tokens := token(t, srv, url.Values{
+		"grant_type":    {"authorization_code"},
+		"client_id":     {testClientID},
+		"redirect_uri":  {testRedirectURI},
+		"code":          {query.Get("code")},
+		"code_verifier": {verifier},
+	})
+	require.Equal(t, http.StatusOK, status)
+
+	e := <-sub.Events()
+	require.Equal(t, entity.SecurityEventOAuthSignIn, e.Type)
+	require.Equal(t, testUsername, e.Username)
+	require.Equal(t, testClientID, e.ClientID)
+
+	// refreshes aren't sign ins
+	status, _ = token(t, srv, url.Values{
+		"grant_type":    {"refresh_token"},
+		"client_id":     {testClientID},
+		"refresh_token": {tokens["refresh_token"].(string)},
+	})
+	require.Equal(t, http.StatusOK, status)
+	require.Empty(t, sub.Events())
+}
+
+func TestClientCredentials(t *testing.T) {
+	srv, jm, storage := newTestProvider(t)
+	ctx := context.Background()
+
+	const serviceID = "billing-service"
+	require.NoError(t, storage.SaveOAuthClient(ctx, entity.OAuthClient{
+		ID:           serviceID,
+		Name:         "Billing",
+		Confidential: true,
+		Scopes:       []string{"users:read", "invoices:write"},
+	}))
+	require.NoError(t, storage.AddClientSecret(ctx, entity.ClientSecret{ClientID: serviceID, SecretHash: oauth.HashSecret("old-secret")}, time.Now()))
+
+	grant := url.Values{"grant_type": {"client_credentials"}}
+
+	t.Run("basic auth", func(t *testing.T) {
+		status, tokens := tokenWithBasicAuth(t, srv, grant, serviceID, "old-secret")
+		require.Equal(t, http.StatusOK, status)
+		require.Equal(t, "users:read invoices:write", tokens["scope"])
+		require.NotContains(t, tokens, "refresh_token")
+
+		parsed, err := jm.VerifyToken(tokens["access_token"].(string))
+		require.NoError(t, err)
+		claims := parsed.Claims.(gojwt.MapClaims)
+		require.Equal(t, serviceID, claims["sub"])
+		require.Equal(t, jwt.SubjectTypeClient, claims[jwt.SubjectTypeClaim])
+		require.NotContains(t, claims, "roles")
+
+		// sub isn't a user
+		req, err := http.NewRequest(http.MethodGet, srv.URL+"/userinfo", nil)
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer "+tokens["access_token"].(string))
+		res, err := srv.Client().Do(req)
+		require.NoError(t, err)
+		res.Body.Close()
+		require.Equal(t, http.StatusUnauthorized, res.StatusCode)
+	})
+
+	t.Run("post auth with narrowed scope", func(t *testing.T) {
+		status, tokens := token(t, srv, url.Values{
+			"grant_type":    {"client_credentials"},
+			"client_id":     {serviceID},
+			"client_secret": {"old-secret"},
+			"scope":         {"users:read"},
+		})
+		require.Equal(t, http.StatusOK, status)
+		require.Equal(t, "users:read", tokens["scope"])
+
+		status, body := tokenWithBasicAuth(t, srv, url.Values{"grant_type": {"client_credentials"}, "scope": {"admin"}}, serviceID, "old-secret")
+		require.Equal(t, http.StatusBadRequest, status)
+		require.Equal(t, "invalid_scope", body["error"])
+	})
+
+	t.Run("client authentication", func(t *testing.T) {
+		status, body := tokenWithBasicAuth(t, srv, grant, serviceID, "wrong")
+		require.Equal(t, http.StatusUnauthorized, status)
+		require.Equal(t, "invalid_client", body["error"])
+
+		status, body = token(t, srv, url.Values{"grant_type": {"client_credentials"}, "client_id": {serviceID}})
+		require.Equal(t, http.StatusUnauthorized, status)
+		require.Equal(t, "invalid_client", body["error"])
+
+		// public clients can't act on their own behalf
+		status, body = token(t, srv, url.Values{"grant_type": {"client_credentials"}, "client_id": {testClientID}})
+		require.Equal(t, http.StatusBadRequest, status)
+		require.Equal(t, "unauthorized_client", body["error"])
+	})
+
+	t.Run("secret rotation", func(t *testing.T) {
+		overlapUntil := time.Now().Add(time.Hour)
+		require.NoError(t, storage.AddClientSecret(ctx, entity.ClientSecret{ClientID: serviceID, SecretHash: oauth.HashSecret("new-secret")}, overlapUntil))
+
+		// both secrets work during the overlap
+		for _, secret := range []string{"old-secret", "new-secret"} {
+			status, _ := tokenWithBasicAuth(t, srv, grant, serviceID, secret)
+			require.Equal(t, http.StatusOK, status, secret)
+		}
+
+		// the next rotation without overlap expires the previous secrets right away
+		require.NoError(t, storage.AddClientSecret(ctx, entity.ClientSecret{ClientID: serviceID, SecretHash: oauth.HashSecret("newest-secret")}, time.Now()))
+		for _, secret := range []string{"old-secret", "new-secret"} {
+			status, _ := tokenWithBasicAuth(t, srv, grant, serviceID, secret)
+			require.Equal(t, http.StatusUnauthorized, status, secret)
+		}
+
+		status, _ := tokenWithBasicAuth(t, srv, grant, serviceID, "newest-secret")
+		require.Equal(t, http.StatusOK, status)
+	})
+}
+
+func TestOpenIDConnect(t *testing.T) {
+	srv, jm, storage := newTestProvider(t)
+	verifier, challenge := pkcePair()
+	require.NoError(t, storage.SetEmailVerified(context.Background(), 1, testUsername))
+
+	signIn := func(t *testing.T, scope string) map[string]interface{} {
+		params := authorizeParams(challenge)
+		params.Set("scope", scope)
+		params.Set("nonce", "n-0S6_WzA2Mj")
+		query := authorize(t, srv, params)
+
+		status, tokens := token(t, srv, url.Values{
+			"grant_type":    {"authorization_code"},
+			"client_id":     {testClientID},
+			"redirect_uri":  {testRedirectURI},
+			"code":          {query.Get("code")},
+			"code_verifier": {verifier},
+		})
+		require.Equal(t, http.StatusOK, status)
+
+		return tokens
+	}
+
+	userinfo := func(t *testing.T, accessToken string) (int, map[string]interface{}) {
+		req, err := http.NewRequest(http.MethodGet, srv.URL+"/userinfo", nil)
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer "+accessToken)
+
+		res, err := srv.Client().Do(req)
+		require.NoError(t, err)
+		defer res.Body.Close()
+
+		var body map[string]interface{}
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&body))
+		return res.StatusCode, body
+	}
+
+	t.Run("discovery", func(t *testing.T) {
+		res, err := srv.Client().Get(srv.URL + "/.well-known/openid-configuration")
+		require.NoError(t, err)
+		defer res.Body.Close()
+
+		var doc map[string]interface{}
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&doc))
+		require.Equal(t, "https://auth.example.com", doc["issuer"])
+		require.Equal(t, "https://auth.example.com/userinfo", doc["userinfo_endpoint"])
+		require.Contains(t, doc["grant_types_supported"], "client_credentials")
+	})
+
+	t.Run("id token", func(t *testing.T) {
+		tokens := signIn(t, "openid profile email")
+		require.NotEmpty(t, tokens["id_token"])
+
+		// the token verifies with the published key
+		res, err := srv.Client().Get(srv.URL + "/.well-known/jwks.json")
+		require.NoError(t, err)
+		defer res.Body.Close()
+
+		var jwks struct {
+			Keys []struct {
+				Kid string `json:"kid"`
+				X   string `json:"x"`
+			} `json:"keys"`
+		}
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&jwks))
+		require.Len(t, jwks.Keys, 1)
+
+		key, err := base64.RawURLEncoding.DecodeString(jwks.Keys[0].X)
+		require.NoError(t, err)
+		parsed, err := gojwt.Parse(tokens["id_token"].(string), func(*gojwt.Token) (interface{}, error) {
+			return ed25519.PublicKey(key), nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, jwks.Keys[0].Kid, parsed.Header["kid"])
+
+		claims := parsed.Claims.(gojwt.MapClaims)
+		require.Equal(t, testUsername, claims["sub"])
+		require.Equal(t, testClientID, claims["aud"])
+		require.Equal(t, "n-0S6_WzA2Mj", claims["nonce"])
+		require.Equal(t, "Alice", claims["name"])
+		require.Equal(t, testUsername, claims["email"])
+		require.Equal(t, true, claims["email_verified"])
+		require.Contains(t, claims, "auth_time")
+		require.NotContains(t, claims, "address")
+
+		// ID tokens aren't access tokens
+		status, _ := userinfo(t, tokens["id_token"].(string))
+		require.Equal(t, http.StatusUnauthorized, status)
+	})
+
+	t.Run("userinfo honors scopes", func(t *testing.T) {
+		tokens := signIn(t, "openid email address")
+
+		status, info := userinfo(t, tokens["access_token"].(string))
+		require.Equal(t, http.StatusOK, status)
+		require.Equal(t, testUsername, info["sub"])
+		require.Equal(t, testUsername, info["email"])
+		require.Equal(t, map[string]interface{}{
+			"street_address": "",
+			"locality":       "Berlin",
+			"region":         "",
+			"postal_code":    "",
+		}, info["address"])
+		require.NotContains(t, info, "name")
+	})
+
+	t.Run("userinfo requires openid scope", func(t *testing.T) {
+		tokens := signIn(t, "profile")
+		require.NotContains(t, tokens, "id_token")
+
+		status, body := userinfo(t, tokens["access_token"].(string))
+		require.Equal(t, http.StatusForbidden, status)
+		require.Equal(t, "insufficient_scope", body["error"])
+
+		accessToken, err := jm.IssueToken(testUsername)
+		require.NoError(t, err)
+		status, _ = userinfo(t, accessToken)
+		require.Equal(t, http.StatusForbidden, status)
+
+		status, _ = userinfo(t, "garbage")
+		require.Equal(t, http.StatusUnauthorized, status)
+	})
+}
+
+func TestDeviceFlow(t *testing.T) {
+	srv, jm, storage := newTestProvider(t)
+	ctx := context.Background()
+
+	// signedIn - access token of the user who just signed in with the API
+	signedIn, err := jm.IssueTokenWithClaims(testUsername, gojwt.MapClaims{jwt.AuthTimeClaim: time.Now().Unix()})
+	require.NoError(t, err)
+
+	deviceAuthorization := func(t *testing.T) oauth.DeviceAuthorizationResponse {
+		res, err := srv.Client().PostForm(srv.URL+"/device_authorization", url.Values{
+			"client_id": {testClientID},
+			"scope":     {"openid profile"},
+		})
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+
+		var d oauth.DeviceAuthorizationResponse
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&d))
+		return d
+	}
+
+	decideWith := func(t *testing.T, accessToken, userCode, consent string) int {
+		res, err := srv.Client().PostForm(srv.URL+"/device", url.Values{
+			"user_code":    {userCode},
+			"access_token": {accessToken},
+			"consent":      {consent},
+		})
+		require.NoError(t, err)
+		res.Body.Close()
+		return res.StatusCode
+	}
+
+	decide := func(t *testing.T, userCode, consent string) int {
+		return decideWith(t, signedIn, userCode, consent)
+	}
+
+	poll := func(t *testing.T, deviceCode string) (int, map[string]interface{}) {
+		return token(t, srv, url.Values{
+			"grant_type":  {"urn:ietf:params:oauth:grant-type:device_code"},
+			"client_id":   {testClientID},
+			"device_code": {deviceCode},
+		})
+	}
+
+	// the device polled long enough ago
+	rewindPoll := func(t *testing.T, deviceCode string) {
+		require.NoError(t, storage.PollDeviceAuthorization(ctx, oauth.HashSecret(deviceCode), time.Now().Add(-time.Minute), 5*time.Second))
+	}
+
+	t.Run("approved", func(t *testing.T) {
+		d := deviceAuthorization(t)
+		require.Regexp(t, `^[A-Z]{4}-[A-Z]{4}$`, d.UserCode)
+		require.Equal(t, "https://auth.example.com/device", d.VerificationURI)
+		require.Equal(t, int64(5), d.Interval)
+
+		status, body := poll(t, d.DeviceCode)
+		require.Equal(t, http.StatusBadRequest, status)
+		require.Equal(t, "authorization_pending", body["error"])
+
+		// polling again right away
+		status, body = poll(t, d.DeviceCode)
+		require.Equal(t, http.StatusBadRequest, status)
+		require.Equal(t, "slow_down", body["error"])
+
+		stored, err := storage.GetDeviceAuthorization(ctx, oauth.HashSecret(d.DeviceCode))
+		require.NoError(t, err)
+		require.Equal(t, 10*time.Second, stored.Interval)
+
+		require.Equal(t, "https://auth.example.com/device?user_code="+d.UserCode, d.VerificationURIComplete)
+		res, err := srv.Client().Get(srv.URL + "/device?user_code=" + url.QueryEscape(d.UserCode))
+		require.NoError(t, err)
+		res.Body.Close()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+
+		// the code may be typed in lower case without the dash
+		require.Equal(t, http.StatusOK, decide(t, strings.ToLower(strings.ReplaceAll(d.UserCode, "-", "")), "allow"))
+		require.Equal(t, http.StatusBadRequest, decide(t, d.UserCode, "allow"))
+
+		rewindPoll(t, d.DeviceCode)
+		status, tokens := poll(t, d.DeviceCode)
+		require.Equal(t, http.StatusOK, status)
+		require.NotEmpty(t, tokens["access_token"])
+		require.NotEmpty(t, tokens["refresh_token"])
+		require.NotEmpty(t, tokens["id_token"])
+
+		status, body = poll(t, d.DeviceCode)
+		require.Equal(t, http.StatusBadRequest, status)
+		require.Equal(t, "invalid_grant", body["error"])
+	})
+
+	t.Run("decided by users who just signed in", func(t *testing.T) {
+		d := deviceAuthorization(t)
+
+		refreshed, err := jm.IssueTokenWithClaims(testUsername, nil)
+		require.NoError(t, err)
+		stale, err := jm.IssueTokenWithClaims(testUsername, gojwt.MapClaims{jwt.AuthTimeClaim: time.Now().Add(-time.Hour).Unix()})
+		require.NoError(t, err)
+		mfa, err := jm.IssueTokenWithClaims(testUsername, gojwt.MapClaims{jwt.AuthTimeClaim: time.Now().Unix(), jwt.TokenUseClaim: "mfa"})
+		require.NoError(t, err)
+
+		for name, accessToken := range map[string]string{"none": "", "refreshed": refreshed, "stale": stale, "mfa": mfa} {
+			require.Equal(t, http.StatusUnauthorized, decideWith(t, accessToken, d.UserCode, "allow"), name)
+		}
+
+		stored, err := storage.GetDeviceAuthorization(ctx, oauth.HashSecret(d.DeviceCode))
+		require.NoError(t, err)
+		require.Equal(t, entity.DeviceAuthorizationPending, stored.Status)
+	})
+
+	t.Run("scope is limited to the client's", func(t *testing.T) {
+		res, err := srv.Client().PostForm(srv.URL+"/device_authorization", url.Values{
+			"client_id": {testClientID},
+			"scope":     {"openid admin"},
+		})
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.Equal(t, http.StatusBadRequest, res.StatusCode)
+
+		var body map[string]interface{}
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&body))
+		require.Equal(t, "invalid_scope", body["error"])
+	})
+
+	t.Run("denied", func(t *testing.T) {
+		d := deviceAuthorization(t)
+		require.Equal(t, http.StatusOK, decide(t, d.UserCode, "deny"))
+
+		status, body := poll(t, d.DeviceCode)
+		require.Equal(t, http.StatusBadRequest, status)
+		require.Equal(t, "access_denied", body["error"])
+	})
+
+	t.Run("invalid codes", func(t *testing.T) {
+		require.Equal(t, http.StatusBadRequest, decide(t, "BCDF-GHJK", "allow"))
+
+		status, body := poll(t, "unknown")
+		require.Equal(t, http.StatusBadRequest, status)
+		require.Equal(t, "invalid_grant", body["error"])
+	})
+}
+
+func TestTokenExchange(t *testing.T) {
+	var audit bytes.Buffer
+	srv, jm, storage := newTestProvider(t, oauth.WithTokenExchange([]oauth.ExchangePolicy{
+		{ClientID: "gateway", Audiences: []string{"orders-api"}, Scopes: []string{"orders:read", "orders:write"}},
+		{ClientID: "support", Audiences: []string{"orders-api"}, Scopes: []string{"orders:read"}, ActorRoles: []string{entity.RoleModerator}},
+	}, slog.New(slog.NewJSONHandler(&audit, nil))))
+	ctx := context.Background()
+
+	for _, id := range []string{"gateway", "support"} {
+		require.NoError(t, storage.SaveOAuthClient(ctx, entity.OAuthClient{ID: id, Name: id, Confidential: true}))
+		require.NoError(t, storage.AddClientSecret(ctx, entity.ClientSecret{ClientID: id, SecretHash: oauth.HashSecret(id + "-secret")}, time.Now()))
+	}
+
+	subjectToken, err := jm.IssueTokenWithClaims(testUsername, gojwt.MapClaims{
+		jwt.RolesClaim:   []string{"admin"},
+		oauth.ScopeClaim: "openid orders:read",
+	})
+	require.NoError(t, err)
+
+	exchange := func(clientID string, form url.Values) (int, map[string]interface{}) {
+		form.Set("grant_type", "urn:ietf:params:oauth:grant-type:token-exchange")
+		form.Set("subject_token_type", "urn:ietf:params:oauth:token-type:access_token")
+		if !form.Has("subject_token") {
+			form.Set("subject_token", subjectToken)
+		}
+		return tokenWithBasicAuth(t, srv, form, clientID, clientID+"-secret")
+	}
+
+	claimsOf := func(t *testing.T, token interface{}) gojwt.MapClaims {
+		parsed, err := jm.VerifyToken(token.(string))
+		require.NoError(t, err)
+		return parsed.Claims.(gojwt.MapClaims)
+	}
+
+	t.Run("delegation narrows the token", func(t *testing.T) {
+		status, tokens := exchange("gateway", url.Values{"audience": {"orders-api"}})
+		require.Equal(t, http.StatusOK, status)
+		require.Equal(t, "urn:ietf:params:oauth:token-type:access_token", tokens["issued_token_type"])
+		// orders:write isn't in the subject token
+		require.Equal(t, "orders:read", tokens["scope"])
+
+		claims := claimsOf(t, tokens["access_token"])
+		require.Equal(t, testUsername, claims["sub"])
+		require.Equal(t, "orders-api", claims["aud"])
+		require.Equal(t, "gateway", claims["client_id"])
+		require.NotContains(t, claims, "roles")
+		require.NotContains(t, claims, "act")
+
+		// the token is for the orders service, not for the provider
+		req, err := http.NewRequest(http.MethodGet, srv.URL+"/userinfo", nil)
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer "+tokens["access_token"].(string))
+		res, err := srv.Client().Do(req)
+		require.NoError(t, err)
+		res.Body.Close()
+		require.Equal(t, http.StatusUnauthorized, res.StatusCode)
+	})
+
+	t.Run("scope and audience are limited by the policy", func(t *testing.T) {
+		status, body := exchange("gateway", url.Values{"audience": {"orders-api"}, "scope": {"openid"}})
+		require.Equal(t, http.StatusBadRequest, status)
+		require.Equal(t, "invalid_scope", body["error"])
+
+		status, body = exchange("gateway", url.Values{"audience": {"billing-api"}})
+		require.Equal(t, http.StatusBadRequest, status)
+		require.Equal(t, "invalid_target", body["error"])
+	})
+
+	t.Run("impersonation is audited", func(t *testing.T) {
+		actorToken, err := jm.IssueTokenWithClaims("bob@example.com", gojwt.MapClaims{jwt.RolesClaim: []string{entity.RoleModerator}})
+		require.NoError(t, err)
+
+		status, tokens := exchange("support", url.Values{
+			"audience":         {"orders-api"},
+			"actor_token":      {actorToken},
+			"actor_token_type": {"urn:ietf:params:oauth:token-type:access_token"},
+		})
+		require.Equal(t, http.StatusOK, status)
+
+		claims := claimsOf(t, tokens["access_token"])
+		require.Equal(t, testUsername, claims["sub"])
+		require.Equal(t, map[string]interface{}{"sub": "bob@example.com"}, claims["act"])
+
+		require.Contains(t, audit.String(), `"msg":"impersonation"`)
+		require.Contains(t, audit.String(), `"actor":"bob@example.com"`)
+
+		// the exchanged token keeps the actor when exchanged again
+		status, tokens = exchange("gateway", url.Values{"audience": {"orders-api"}, "subject_token": {tokens["access_token"].(string)}})
+		require.Equal(t, http.StatusOK, status)
+		require.Equal(t, map[string]interface{}{"sub": "bob@example.com"}, claimsOf(t, tokens["access_token"])["act"])
+	})
+
+	t.Run("actors need a role of the policy", func(t *testing.T) {
+		actorToken, err := jm.IssueTokenWithClaims("mallory@example.com", nil)
+		require.NoError(t, err)
+
+		status, body := exchange("support", url.Values{
+			"audience":         {"orders-api"},
+			"actor_token":      {actorToken},
+			"actor_token_type": {"urn:ietf:params:oauth:token-type:access_token"},
+		})
+		require.Equal(t, http.StatusBadRequest, status)
+		require.Equal(t, "invalid_grant", body["error"])
+
+		// the gateway policy doesn't allow actors at all
+		status, body = exchange("gateway", url.Values{
+			"audience":         {"orders-api"},
+			"actor_token":      {actorToken},
+			"actor_token_type": {"urn:ietf:params:oauth:token-type:access_token"},
+		})
+		require.Equal(t, http.StatusBadRequest, status)
+		require.Equal(t, "invalid_grant", body["error"])
+	})
+
+	t.Run("tokens of clients can't be exchanged", func(t *testing.T) {
+		clientToken, err := jm.IssueTokenWithClaims("gateway", gojwt.MapClaims{
+			oauth.ClientIDClaim:  "gateway",
+			jwt.SubjectTypeClaim: jwt.SubjectTypeClient,
+		})
+		require.NoError(t, err)
+
+		status, body := exchange("gateway", url.Values{"audience": {"orders-api"}, "subject_token": {clientToken}})
+		require.Equal(t, http.StatusBadRequest, status)
+		require.Equal(t, "invalid_grant", body["error"])
+	})
+
+	t.Run("ID tokens can't be exchanged", func(t *testing.T) {
+		idToken, err := jm.IssueTokenWithClaims(testUsername, gojwt.MapClaims{jwt.TokenUseClaim: "id"})
+		require.NoError(t, err)
+
+		status, body := exchange("gateway", url.Values{"audience": {"orders-api"}, "subject_token": {idToken}})
+		require.Equal(t, http.StatusBadRequest, status)
+		require.Equal(t, "invalid_grant", body["error"])
+	})
+}
@@ -0,0 +1,220 @@
+package oauth
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/bogatyr285/auth-go/internal/auth/entity"
+	pkgjwt "github.com/bogatyr285/auth-go/pkg/jwt"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// scopes of OpenID Connect, each one but openid releases a set of user claims
+const (
+	ScopeOpenID  = "openid"
+	ScopeProfile = "profile"
+	ScopeEmail   = "email"
+	ScopePhone   = "phone"
+	ScopeAddress = "address"
+
+	// tokenUseID - ID tokens are for clients only and must not be accepted as access tokens
+	tokenUseID = "id"
+)
+
+type discoveryDocument struct {
+	Issuer                            string   `json:"issuer"`
+	AuthorizationEndpoint             string   `json:"authorization_endpoint"`
+	DeviceAuthorizationEndpoint       string   `json:"device_authorization_endpoint"`
+	TokenEndpoint                     string   `json:"token_endpoint"`
+	UserinfoEndpoint                  string   `json:"userinfo_endpoint"`
+	JWKSURI                           string   `json:"jwks_uri"`
+	ScopesSupported                   []string `json:"scopes_supported"`
+	ResponseTypesSupported            []string `json:"response_types_supported"`
+	GrantTypesSupported               []string `json:"grant_types_supported"`
+	SubjectTypesSupported             []string `json:"subject_types_supported"`
+	IDTokenSigningAlgValuesSupported  []string `json:"id_token_signing_alg_values_supported"`
+	TokenEndpointAuthMethodsSupported []string `json:"token_endpoint_auth_methods_supported"`
+	CodeChallengeMethodsSupported     []string `json:"code_challenge_methods_supported"`
+	ClaimsSupported                   []string `json:"claims_supported"`
+}
+
+// discovery - endpoints are relative to the issuer, so the issuer of JWTManager
+// must be the public URL of the service
+func (p *Provider) discovery(w http.ResponseWriter, r *http.Request) {
+	issuer := p.jm.Issuer()
+	base := strings.TrimSuffix(issuer, "/")
+
+	grantTypes := make([]string, 0, len(p.grants))
+	for grant := range p.grants {
+		grantTypes = append(grantTypes, grant)
+	}
+	slices.Sort(grantTypes)
+
+	writeJSON(w, http.StatusOK, discoveryDocument{
+		Issuer:                            issuer,
+		AuthorizationEndpoint:             base + "/authorize",
+		DeviceAuthorizationEndpoint:       base + "/device_authorization",
+		TokenEndpoint:                     base + "/token",
+		UserinfoEndpoint:                  base + "/userinfo",
+		JWKSURI:                           base + "/.well-known/jwks.json",
+		ScopesSupported:                   []string{ScopeOpenID, ScopeProfile, ScopeEmail, ScopePhone, ScopeAddress},
+		ResponseTypesSupported:            []string{"code"},
+		GrantTypesSupported:               grantTypes,
+		SubjectTypesSupported:             []string{"public"},
+		IDTokenSigningAlgValuesSupported:  []string{"EdDSA"},
+		TokenEndpointAuthMethodsSupported: []string{"client_secret_basic", "client_secret_post", "none"},
+		CodeChallengeMethodsSupported:     []string{"S256"},
+		ClaimsSupported: []string{
+			"sub", "iss", "aud", "exp", "iat", "auth_time", "nonce",
+			"name", "preferred_username", "gender", "email", "email_verified",
+			"phone_number", "phone_number_verified", "address",
+		},
+	})
+}
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+func (p *Provider) jwks(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string][]jwk{
+		"keys": {{
+			Kty: "OKP",
+			Crv: "Ed25519",
+			X:   base64.RawURLEncoding.EncodeToString(p.jm.PublicKey()),
+			Use: "sig",
+			Alg: "EdDSA",
+			Kid: p.jm.KeyID(),
+		}},
+	})
+}
+
+// issueIDToken - audience is the client, claims of the user depend on the scope granted
+func (p *Provider) issueIDToken(ctx context.Context, user entity.UserAccount, clientID, scope, nonce string, authTime time.Time) (string, error) {
+	profile, err := p.repo.GetUserProfile(ctx, user.ID)
+	if err != nil {
+		return "", err
+	}
+
+	claims := userClaims(profile, scope)
+	claims["aud"] = clientID
+	claims["auth_time"] = authTime.Unix()
+	claims[pkgjwt.TokenUseClaim] = tokenUseID
+	if nonce != "" {
+		claims["nonce"] = nonce
+	}
+
+	return p.jm.IssueTokenWithClaims(user.Username, claims)
+}
+
+// userinfo - claims of the user the access token was issued for, it must be
+// issued with openid scope
+func (p *Provider) userinfo(w http.ResponseWriter, r *http.Request) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		bearerError(w, http.StatusUnauthorized, "invalid_request", "bearer token is required")
+		return
+	}
+
+	verified, err := p.jm.VerifyToken(token)
+	if err != nil {
+		bearerError(w, http.StatusUnauthorized, "invalid_token", "token is invalid")
+		return
+	}
+
+	claims := verified.Claims.(jwt.MapClaims)
+	if _, ok := claims[pkgjwt.TokenUseClaim]; ok {
+		bearerError(w, http.StatusUnauthorized, "invalid_token", "not an access token")
+		return
+	}
+
+	if pkgjwt.ClientSubject(claims) {
+		bearerError(w, http.StatusUnauthorized, "invalid_token", "token of a client has no user")
+		return
+	}
+
+	if !pkgjwt.ForIssuer(claims) {
+		bearerError(w, http.StatusUnauthorized, "invalid_token", "token is for another audience")
+		return
+	}
+
+	scope, _ := claims[ScopeClaim].(string)
+	if !slices.Contains(strings.Fields(scope), ScopeOpenID) {
+		bearerError(w, http.StatusForbidden, "insufficient_scope", "openid scope is required")
+		return
+	}
+
+	sub, err := claims.GetSubject()
+	if err != nil {
+		bearerError(w, http.StatusUnauthorized, "invalid_token", "token is invalid")
+		return
+	}
+
+	user, err := p.repo.FindUserByEmail(r.Context(), sub)
+	if err != nil {
+		bearerError(w, http.StatusUnauthorized, "invalid_token", "user not found")
+		return
+	}
+
+	profile, err := p.repo.GetUserProfile(r.Context(), user.ID)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, &Error{Code: "server_error"})
+		return
+	}
+
+	info := userClaims(profile, scope)
+	info["sub"] = sub
+	writeJSON(w, http.StatusOK, info)
+}
+
+// userClaims - standard claims of the user released by the scope, empty fields are omitted
+func userClaims(user entity.UserAccount, scope string) jwt.MapClaims {
+	scopes := strings.Fields(scope)
+	claims := jwt.MapClaims{}
+
+	if slices.Contains(scopes, ScopeProfile) {
+		claims["preferred_username"] = user.Username
+		if user.Name != "" {
+			claims["name"] = user.Name
+		}
+		if user.Gender != "" {
+			claims["gender"] = user.Gender
+		}
+	}
+
+	if slices.Contains(scopes, ScopeEmail) && user.Email != "" {
+		claims["email"] = user.Email
+		claims["email_verified"] = user.EmailVerified
+	}
+
+	if slices.Contains(scopes, ScopePhone) && user.Phone != "" {
+		claims["phone_number"] = user.Phone
+		claims["phone_number_verified"] = user.PhoneVerified
+	}
+
+	if slices.Contains(scopes, ScopeAddress) && user.Address != (entity.Address{}) {
+		claims["address"] = map[string]string{
+			"street_address": user.Address.Street,
+			"locality":       user.Address.City,
+			"region":         user.Address.State,
+			"postal_code":    user.Address.Zipcode,
+		}
+	}
+
+	return claims
+}
+
+// bearerError - RFC 6750 error response
+func bearerError(w http.ResponseWriter, status int, code, description string) {
+	w.Header().Set("WWW-Authenticate", `Bearer error="`+code+`", error_description="`+description+`"`)
+	writeJSON(w, status, &Error{Code: code, Description: description})
+}
@@ -0,0 +1,121 @@
+// Package pat - personal access tokens, accepted by REST and gRPC along with JWTs
+package pat
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/bogatyr285/auth-go/internal/auth/entity"
+)
+
+// Prefix - makes leaked tokens recognizable by secret scanners
+const Prefix = "agp_"
+
+// touchInterval - last use is recorded at most this often, so that scripts calling
+// the API in a loop don't write on every request
+const touchInterval = time.Minute
+
+var ErrInvalidToken = errors.New("invalid access token")
+
+type Repository interface {
+	GetPersonalAccessToken(ctx context.Context, tokenHash string) (entity.PersonalAccessToken, error)
+	TouchPersonalAccessToken(ctx context.Context, ID string, usedAt time.Time) error
+	GetUserById(ctx context.Context, ID int) (entity.UserAccount, error)
+	ListUserRoles(ctx context.Context, userID int) ([]string, error)
+}
+
+// Generate - new token and the hash it is stored by
+func Generate() (string, string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+
+	token := Prefix + base64.RawURLEncoding.EncodeToString(b)
+	return token, Hash(token), nil
+}
+
+func Hash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// IsToken - the bearer token is a personal access token rather than a JWT
+func IsToken(bearer string) bool {
+	return strings.HasPrefix(bearer, Prefix)
+}
+
+// Principal - user the token belongs to
+type Principal struct {
+	TokenID  string
+	UserID   int
+	Username string
+	Roles    []string
+	Scopes   []entity.Permission
+}
+
+// HasPermission - the scopes of the token include the permission and the user's roles
+// still grant it
+func (p Principal) HasPermission(permission entity.Permission) bool {
+	return slices.Contains(p.Scopes, permission) && entity.HasPermission(p.Roles, permission)
+}
+
+type Verifier struct {
+	repo Repository
+}
+
+func NewVerifier(repo Repository) *Verifier {
+	return &Verifier{repo: repo}
+}
+
+// Verify - principal of an existing unexpired token, ErrInvalidToken otherwise
+func (v *Verifier) Verify(ctx context.Context, token string) (Principal, error) {
+	if !IsToken(token) {
+		return Principal{}, ErrInvalidToken
+	}
+
+	t, err := v.repo.GetPersonalAccessToken(ctx, Hash(token))
+	if err != nil {
+		if errors.Is(err, entity.ErrAccessTokenNotFound) {
+			return Principal{}, ErrInvalidToken
+		}
+
+		return Principal{}, err
+	}
+
+	now := time.Now()
+	if now.After(t.ExpiresAt) {
+		return Principal{}, ErrInvalidToken
+	}
+
+	user, err := v.repo.GetUserById(ctx, t.UserID)
+	if err != nil {
+		return Principal{}, ErrInvalidToken
+	}
+
+	roles, err := v.repo.ListUserRoles(ctx, t.UserID)
+	if err != nil {
+		return Principal{}, err
+	}
+
+	if t.LastUsedAt == nil || now.Sub(*t.LastUsedAt) > touchInterval {
+		if err = v.repo.TouchPersonalAccessToken(ctx, t.ID, now); err != nil {
+			return Principal{}, err
+		}
+	}
+
+	return Principal{
+		TokenID:  t.ID,
+		UserID:   user.ID,
+		Username: user.Username,
+		Roles:    roles,
+		Scopes:   t.Scopes,
+	}, nil
+}
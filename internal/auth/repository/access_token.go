@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bogatyr285/auth-go/internal/auth/entity"
+)
+
+func (s *SQLLiteStorage) SavePersonalAccessToken(ctx context.Context, t entity.PersonalAccessToken) error {
+	query := `INSERT INTO personal_access_tokens(id, user_id, name, token_hash, scopes, expires_at, created_at) VALUES(?,?,?,?,?,?,?)`
+	_, err := s.db.ExecContext(ctx, query,
+		t.ID,
+		t.UserID,
+		t.Name,
+		t.TokenHash,
+		joinPermissions(t.Scopes),
+		t.ExpiresAt.UTC(),
+		t.CreatedAt.UTC(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert access token: %s", err)
+	}
+
+	return nil
+}
+
+const accessTokenColumns = `id, user_id, name, token_hash, scopes, expires_at, last_used_at, created_at`
+
+func scanAccessToken(row interface{ Scan(dest ...any) error }) (entity.PersonalAccessToken, error) {
+	var t entity.PersonalAccessToken
+	var scopes string
+	var lastUsedAt sql.NullTime
+	if err := row.Scan(&t.ID, &t.UserID, &t.Name, &t.TokenHash, &scopes, &t.ExpiresAt, &lastUsedAt, &t.CreatedAt); err != nil {
+		return entity.PersonalAccessToken{}, err
+	}
+
+	for _, scope := range strings.Fields(scopes) {
+		t.Scopes = append(t.Scopes, entity.Permission(scope))
+	}
+	if lastUsedAt.Valid {
+		t.LastUsedAt = &lastUsedAt.Time
+	}
+
+	return t, nil
+}
+
+func (s *SQLLiteStorage) GetPersonalAccessToken(ctx context.Context, tokenHash string) (entity.PersonalAccessToken, error) {
+	query := `SELECT ` + accessTokenColumns + ` FROM personal_access_tokens WHERE token_hash = ?`
+
+	t, err := scanAccessToken(s.db.QueryRowContext(ctx, query, tokenHash))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.PersonalAccessToken{}, entity.ErrAccessTokenNotFound
+		}
+
+		return entity.PersonalAccessToken{}, fmt.Errorf("failed to get access token: %s", err)
+	}
+
+	return t, nil
+}
+
+func (s *SQLLiteStorage) ListPersonalAccessTokens(ctx context.Context, userID int) ([]entity.PersonalAccessToken, error) {
+	query := `SELECT ` + accessTokenColumns + ` FROM personal_access_tokens WHERE user_id = ? ORDER BY created_at, id`
+	rows, err := s.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list access tokens: %s", err)
+	}
+	defer rows.Close()
+
+	tokens := []entity.PersonalAccessToken{}
+	for rows.Next() {
+		t, err := scanAccessToken(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list access tokens: %s", err)
+		}
+
+		tokens = append(tokens, t)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list access tokens: %s", err)
+	}
+
+	return tokens, nil
+}
+
+// TouchPersonalAccessToken - records when the token was used
+func (s *SQLLiteStorage) TouchPersonalAccessToken(ctx context.Context, ID string, usedAt time.Time) error {
+	_, err := s.db.ExecContext(ctx, `UPDATE personal_access_tokens SET last_used_at = ? WHERE id = ?`, usedAt.UTC(), ID)
+	if err != nil {
+		return fmt.Errorf("failed to update access token: %s", err)
+	}
+
+	return nil
+}
+
+// DeletePersonalAccessToken - revokes the token, tokens of other users are not found
+func (s *SQLLiteStorage) DeletePersonalAccessToken(ctx context.Context, userID int, ID string) error {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM personal_access_tokens WHERE id = ? AND user_id = ?`, ID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete access token: %s", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to delete access token: %s", err)
+	}
+
+	if affected == 0 {
+		return entity.ErrAccessTokenNotFound
+	}
+
+	return nil
+}
+
+func joinPermissions(permissions []entity.Permission) string {
+	s := make([]string, len(permissions))
+	for i, p := range permissions {
+		s[i] = string(p)
+	}
+
+	return strings.Join(s, " ")
+}
@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/bogatyr285/auth-go/internal/auth/entity"
+)
+
+// SaveDeviceAuthorization - expired requests are removed on the way, so user codes
+// may be reused
+func (s *SQLLiteStorage) SaveDeviceAuthorization(ctx context.Context, d entity.DeviceAuthorization) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to save device authorization: %s", err)
+	}
+	defer tx.Rollback()
+
+	if _, err = tx.ExecContext(ctx, `DELETE FROM oauth_device_codes WHERE expires_at < ?`, time.Now().UTC()); err != nil {
+		return fmt.Errorf("failed to save device authorization: %s", err)
+	}
+
+	query := `INSERT INTO oauth_device_codes(device_code_hash, user_code, client_id, scope, status, interval_seconds, expires_at)
+		VALUES(?,?,?,?,?,?,?)`
+	_, err = tx.ExecContext(ctx, query,
+		d.DeviceCodeHash,
+		d.UserCode,
+		d.ClientID,
+		d.Scope,
+		entity.DeviceAuthorizationPending,
+		int(d.Interval.Seconds()),
+		d.ExpiresAt.UTC(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save device authorization: %s", err)
+	}
+
+	return tx.Commit()
+}
+
+const deviceAuthorizationColumns = `device_code_hash, user_code, client_id, scope, status, user_id,
+	interval_seconds, last_polled_at, approved_at, expires_at`
+
+func (s *SQLLiteStorage) GetDeviceAuthorization(ctx context.Context, deviceCodeHash string) (entity.DeviceAuthorization, error) {
+	query := `SELECT ` + deviceAuthorizationColumns + ` FROM oauth_device_codes WHERE device_code_hash = ?`
+	return scanDeviceAuthorization(s.db.QueryRowContext(ctx, query, deviceCodeHash))
+}
+
+func (s *SQLLiteStorage) GetDeviceAuthorizationByUserCode(ctx context.Context, userCode string) (entity.DeviceAuthorization, error) {
+	query := `SELECT ` + deviceAuthorizationColumns + ` FROM oauth_device_codes WHERE user_code = ?`
+	return scanDeviceAuthorization(s.db.QueryRowContext(ctx, query, userCode))
+}
+
+func scanDeviceAuthorization(row *sql.Row) (entity.DeviceAuthorization, error) {
+	var d entity.DeviceAuthorization
+	var userID sql.NullInt64
+	var interval int
+	var lastPolledAt, approvedAt sql.NullTime
+
+	err := row.Scan(
+		&d.DeviceCodeHash,
+		&d.UserCode,
+		&d.ClientID,
+		&d.Scope,
+		&d.Status,
+		&userID,
+		&interval,
+		&lastPolledAt,
+		&approvedAt,
+		&d.ExpiresAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.DeviceAuthorization{}, fmt.Errorf("device authorization not found")
+		}
+
+		return entity.DeviceAuthorization{}, fmt.Errorf("failed to get device authorization: %s", err)
+	}
+
+	d.UserID = int(userID.Int64)
+	d.Interval = time.Duration(interval) * time.Second
+	d.LastPolledAt = lastPolledAt.Time
+	d.ApprovedAt = approvedAt.Time
+
+	return d, nil
+}
+
+// DecideDeviceAuthorization - approves or denies a pending request on behalf of the user
+func (s *SQLLiteStorage) DecideDeviceAuthorization(ctx context.Context, userCode string, userID int, approved bool) error {
+	status := entity.DeviceAuthorizationDenied
+	if approved {
+		status = entity.DeviceAuthorizationApproved
+	}
+
+	query := `UPDATE oauth_device_codes SET status = ?, user_id = ?, approved_at = ? WHERE user_code = ? AND status = ?`
+	res, err := s.db.ExecContext(ctx, query, status, userID, time.Now().UTC(), userCode, entity.DeviceAuthorizationPending)
+	if err != nil {
+		return fmt.Errorf("failed to decide device authorization: %s", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to decide device authorization: %s", err)
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("device authorization not found or already decided")
+	}
+
+	return nil
+}
+
+// PollDeviceAuthorization - records the poll, interval is raised when the device polls too often
+func (s *SQLLiteStorage) PollDeviceAuthorization(ctx context.Context, deviceCodeHash string, polledAt time.Time, interval time.Duration) error {
+	query := `UPDATE oauth_device_codes SET last_polled_at = ?, interval_seconds = ? WHERE device_code_hash = ?`
+	if _, err := s.db.ExecContext(ctx, query, polledAt.UTC(), int(interval.Seconds()), deviceCodeHash); err != nil {
+		return fmt.Errorf("failed to poll device authorization: %s", err)
+	}
+
+	return nil
+}
+
+// ConsumeDeviceAuthorization - tokens are issued for an approved request only once
+func (s *SQLLiteStorage) ConsumeDeviceAuthorization(ctx context.Context, deviceCodeHash string) error {
+	query := `UPDATE oauth_device_codes SET status = ? WHERE device_code_hash = ? AND status = ?`
+	res, err := s.db.ExecContext(ctx, query, entity.DeviceAuthorizationConsumed, deviceCodeHash, entity.DeviceAuthorizationApproved)
+	if err != nil {
+		return fmt.Errorf("failed to consume device authorization: %s", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to consume device authorization: %s", err)
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("device authorization not approved or already used")
+	}
+
+	return nil
+}
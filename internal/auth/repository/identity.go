@@ -0,0 +1,233 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/bogatyr285/auth-go/internal/auth/entity"
+)
+
+func (s *SQLLiteStorage) GetIdentity(ctx context.Context, provider, subject string) (entity.Identity, error) {
+	query := `SELECT user_id, email, created_at FROM identities WHERE provider = ? AND subject = ?`
+
+	identity := entity.Identity{Provider: provider, Subject: subject}
+	err := s.db.QueryRowContext(ctx, query, provider, subject).Scan(&identity.UserID, &identity.Email, &identity.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.Identity{}, fmt.Errorf("identity not found")
+		}
+
+		return entity.Identity{}, fmt.Errorf("failed to get identity: %s", err)
+	}
+
+	return identity, nil
+}
+
+// ProvisionFederatedUser - registers the user together with the identity, returns ID of the user
+func (s *SQLLiteStorage) ProvisionFederatedUser(ctx context.Context, u entity.UserAccount, identity entity.Identity) (int, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to provision user: %s", err)
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO users(username, password, name, email, email_verified) VALUES(?,?,?,?,?)`
+	res, err := tx.ExecContext(ctx, query, u.Username, u.Password, u.Name, u.Email, u.EmailVerified)
+	if err != nil {
+		return 0, fmt.Errorf("failed to provision user: %s", err)
+	}
+
+	userID, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("failed to provision user: %s", err)
+	}
+
+	query = `INSERT INTO identities(provider, subject, user_id, email, created_at) VALUES(?,?,?,?,?)`
+	_, err = tx.ExecContext(ctx, query, identity.Provider, identity.Subject, userID, identity.Email, time.Now().UTC())
+	if err != nil {
+		return 0, fmt.Errorf("failed to provision user: %s", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to provision user: %s", err)
+	}
+
+	return int(userID), nil
+}
+
+func (s *SQLLiteStorage) SaveFederatedLogin(ctx context.Context, login entity.FederatedLogin) error {
+	query := `INSERT INTO federated_logins(state_hash, provider, nonce, code_verifier, user_id, expires_at) VALUES(?,?,?,?,?,?)`
+	_, err := s.db.ExecContext(ctx, query, login.StateHash, login.Provider, login.Nonce, login.CodeVerifier, login.UserID, login.ExpiresAt.UTC())
+	if err != nil {
+		return fmt.Errorf("failed to save federated login: %s", err)
+	}
+
+	return nil
+}
+
+// TakeFederatedLogin - returns the login and deletes it, so every state is accepted only once
+func (s *SQLLiteStorage) TakeFederatedLogin(ctx context.Context, stateHash string) (entity.FederatedLogin, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return entity.FederatedLogin{}, fmt.Errorf("failed to take federated login: %s", err)
+	}
+	defer tx.Rollback()
+
+	login := entity.FederatedLogin{StateHash: stateHash}
+	query := `SELECT provider, nonce, code_verifier, user_id, expires_at FROM federated_logins WHERE state_hash = ?`
+	err = tx.QueryRowContext(ctx, query, stateHash).Scan(&login.Provider, &login.Nonce, &login.CodeVerifier, &login.UserID, &login.ExpiresAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.FederatedLogin{}, fmt.Errorf("federated login not found")
+		}
+
+		return entity.FederatedLogin{}, fmt.Errorf("failed to take federated login: %s", err)
+	}
+
+	// logins abandoned at the provider are cleaned up along the way
+	if _, err = tx.ExecContext(ctx, `DELETE FROM federated_logins WHERE state_hash = ? OR expires_at < ?`, stateHash, time.Now().UTC()); err != nil {
+		return entity.FederatedLogin{}, fmt.Errorf("failed to take federated login: %s", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return entity.FederatedLogin{}, fmt.Errorf("failed to take federated login: %s", err)
+	}
+
+	return login, nil
+}
+
+func (s *SQLLiteStorage) LinkIdentity(ctx context.Context, identity entity.Identity) error {
+	query := `INSERT INTO identities(provider, subject, user_id, email, created_at) VALUES(?,?,?,?,?)`
+	_, err := s.db.ExecContext(ctx, query, identity.Provider, identity.Subject, identity.UserID, identity.Email, time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("failed to link identity: %s", err)
+	}
+
+	return nil
+}
+
+// ListLoginMethods - password, verified phone, passkeys and linked identities of the user
+func (s *SQLLiteStorage) ListLoginMethods(ctx context.Context, userID int) ([]entity.Identity, error) {
+	return listLoginMethods(ctx, s.db, userID)
+}
+
+// UnlinkLoginMethod - removes the login method unless it's the last one of the user,
+// the check and the removal are done in one transaction
+func (s *SQLLiteStorage) UnlinkLoginMethod(ctx context.Context, userID int, provider, subject string) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to unlink login method: %s", err)
+	}
+	defer tx.Rollback()
+
+	methods, err := listLoginMethods(ctx, tx, userID)
+	if err != nil {
+		return err
+	}
+
+	if !slices.ContainsFunc(methods, func(m entity.Identity) bool { return m.Provider == provider && m.Subject == subject }) {
+		return entity.ErrIdentityNotFound
+	}
+
+	if len(methods) == 1 {
+		return entity.ErrLastLoginMethod
+	}
+
+	switch provider {
+	case entity.IdentityPassword:
+		_, err = tx.ExecContext(ctx, `UPDATE users SET password = '' WHERE id = ?`, userID)
+	case entity.IdentityPhone:
+		_, err = tx.ExecContext(ctx, `UPDATE users SET phone = NULL, phone_verified = FALSE WHERE id = ?`, userID)
+	case entity.IdentityWebAuthn:
+		var credentialID []byte
+		if credentialID, err = base64.RawURLEncoding.DecodeString(subject); err == nil {
+			_, err = tx.ExecContext(ctx, `DELETE FROM webauthn_credentials WHERE user_id = ? AND credential_id = ?`, userID, credentialID)
+		}
+	default:
+		_, err = tx.ExecContext(ctx, `DELETE FROM identities WHERE user_id = ? AND provider = ? AND subject = ?`, userID, provider, subject)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to unlink login method: %s", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to unlink login method: %s", err)
+	}
+
+	return nil
+}
+
+// querier - *sql.DB or *sql.Tx
+type querier interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+func listLoginMethods(ctx context.Context, q querier, userID int) ([]entity.Identity, error) {
+	var (
+		username, password string
+		phone              sql.NullString
+		phoneVerified      bool
+		createdAt          time.Time
+	)
+	query := `SELECT username, password, phone, phone_verified, created_at FROM users WHERE id = ?`
+	if err := q.QueryRowContext(ctx, query, userID).Scan(&username, &password, &phone, &phoneVerified, &createdAt); err != nil {
+		return nil, fmt.Errorf("failed to list login methods: %s", err)
+	}
+
+	var methods []entity.Identity
+	if password != "" {
+		methods = append(methods, entity.Identity{Provider: entity.IdentityPassword, Subject: username, UserID: userID, CreatedAt: createdAt})
+	}
+
+	if phone.Valid && phoneVerified {
+		methods = append(methods, entity.Identity{Provider: entity.IdentityPhone, Subject: phone.String, UserID: userID, CreatedAt: createdAt})
+	}
+
+	rows, err := q.QueryContext(ctx, `SELECT credential_id, created_at FROM webauthn_credentials WHERE user_id = ? ORDER BY id`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list login methods: %s", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var credentialID []byte
+		identity := entity.Identity{Provider: entity.IdentityWebAuthn, UserID: userID}
+		if err = rows.Scan(&credentialID, &identity.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to list login methods: %s", err)
+		}
+
+		identity.Subject = base64.RawURLEncoding.EncodeToString(credentialID)
+		methods = append(methods, identity)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list login methods: %s", err)
+	}
+
+	rows, err = q.QueryContext(ctx, `SELECT provider, subject, email, created_at FROM identities WHERE user_id = ? ORDER BY created_at`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list login methods: %s", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		identity := entity.Identity{UserID: userID}
+		if err = rows.Scan(&identity.Provider, &identity.Subject, &identity.Email, &identity.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to list login methods: %s", err)
+		}
+
+		methods = append(methods, identity)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list login methods: %s", err)
+	}
+
+	return methods, nil
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/bogatyr285/auth-go/internal/auth/entity"
+)
+
+func (s *SQLLiteStorage) SaveMagicLink(ctx context.Context, link entity.MagicLink) error {
+	query := `INSERT INTO magic_links(id, email, created_at, expires_at) VALUES(?,?,?,?)`
+	_, err := s.db.ExecContext(ctx, query, link.ID, link.Email, link.CreatedAt.UTC(), link.ExpiresAt.UTC())
+	if err != nil {
+		return fmt.Errorf("failed to insert magic link: %s", err)
+	}
+
+	return nil
+}
+
+func (s *SQLLiteStorage) CountMagicLinksSince(ctx context.Context, email string, since time.Time) (int, error) {
+	query := `SELECT COUNT(*) FROM magic_links WHERE email = ? AND created_at > ?`
+
+	var count int
+	if err := s.db.QueryRowContext(ctx, query, email, since.UTC()).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count magic links: %s", err)
+	}
+
+	return count, nil
+}
+
+// ConsumeMagicLink - marks the link as used, fails if it was used before
+func (s *SQLLiteStorage) ConsumeMagicLink(ctx context.Context, ID string) (entity.MagicLink, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return entity.MagicLink{}, fmt.Errorf("failed to consume magic link: %s", err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, `UPDATE magic_links SET used_at = ? WHERE id = ? AND used_at IS NULL`, time.Now().UTC(), ID)
+	if err != nil {
+		return entity.MagicLink{}, fmt.Errorf("failed to consume magic link: %s", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return entity.MagicLink{}, fmt.Errorf("failed to consume magic link: %s", err)
+	}
+
+	if affected == 0 {
+		return entity.MagicLink{}, fmt.Errorf("magic link not found or already used")
+	}
+
+	link := entity.MagicLink{ID: ID}
+	query := `SELECT email, created_at, expires_at FROM magic_links WHERE id = ?`
+	if err = tx.QueryRowContext(ctx, query, ID).Scan(&link.Email, &link.CreatedAt, &link.ExpiresAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.MagicLink{}, fmt.Errorf("magic link not found or already used")
+		}
+
+		return entity.MagicLink{}, fmt.Errorf("failed to consume magic link: %s", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return entity.MagicLink{}, fmt.Errorf("failed to consume magic link: %s", err)
+	}
+
+	return link, nil
+}
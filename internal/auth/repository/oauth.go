@@ -0,0 +1,300 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bogatyr285/auth-go/internal/auth/entity"
+)
+
+func (s *SQLLiteStorage) SaveOAuthClient(ctx context.Context, c entity.OAuthClient) error {
+	query := `INSERT INTO oauth_clients(id, name, redirect_uris, confidential, scopes) VALUES(?,?,?,?,?)`
+	_, err := s.db.ExecContext(ctx, query,
+		c.ID,
+		c.Name,
+		strings.Join(c.RedirectURIs, " "),
+		c.Confidential,
+		strings.Join(c.Scopes, " "),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert oauth client: %s", err)
+	}
+
+	return nil
+}
+
+const oauthClientColumns = `id, name, redirect_uris, confidential, scopes, created_at`
+
+func scanOAuthClient(row interface{ Scan(dest ...any) error }) (entity.OAuthClient, error) {
+	var c entity.OAuthClient
+	var redirectURIs, scopes string
+	if err := row.Scan(&c.ID, &c.Name, &redirectURIs, &c.Confidential, &scopes, &c.CreatedAt); err != nil {
+		return entity.OAuthClient{}, err
+	}
+
+	c.RedirectURIs = strings.Fields(redirectURIs)
+	c.Scopes = strings.Fields(scopes)
+	return c, nil
+}
+
+func (s *SQLLiteStorage) GetOAuthClient(ctx context.Context, ID string) (entity.OAuthClient, error) {
+	query := `SELECT ` + oauthClientColumns + ` FROM oauth_clients WHERE id = ?`
+
+	c, err := scanOAuthClient(s.db.QueryRowContext(ctx, query, ID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.OAuthClient{}, fmt.Errorf("oauth client not found")
+		}
+
+		return entity.OAuthClient{}, fmt.Errorf("failed to get oauth client: %s", err)
+	}
+
+	return c, nil
+}
+
+func (s *SQLLiteStorage) ListOAuthClients(ctx context.Context) ([]entity.OAuthClient, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT `+oauthClientColumns+` FROM oauth_clients ORDER BY created_at, id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list oauth clients: %s", err)
+	}
+	defer rows.Close()
+
+	clients := []entity.OAuthClient{}
+	for rows.Next() {
+		c, err := scanOAuthClient(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list oauth clients: %s", err)
+		}
+
+		clients = append(clients, c)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list oauth clients: %s", err)
+	}
+
+	return clients, nil
+}
+
+// UpdateOAuthClient - whether the client is confidential can't be changed
+func (s *SQLLiteStorage) UpdateOAuthClient(ctx context.Context, c entity.OAuthClient) error {
+	query := `UPDATE oauth_clients SET name = ?, redirect_uris = ?, scopes = ? WHERE id = ?`
+	res, err := s.db.ExecContext(ctx, query, c.Name, strings.Join(c.RedirectURIs, " "), strings.Join(c.Scopes, " "), c.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update oauth client: %s", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to update oauth client: %s", err)
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("oauth client not found")
+	}
+
+	return nil
+}
+
+// DeleteOAuthClient - removes the client with its secrets, codes, grants and refresh tokens
+func (s *SQLLiteStorage) DeleteOAuthClient(ctx context.Context, ID string) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to delete oauth client: %s", err)
+	}
+	defer tx.Rollback()
+
+	for _, table := range []string{"oauth_client_secrets", "oauth_codes", "oauth_refresh_tokens", "oauth_grants", "oauth_device_codes"} {
+		if _, err = tx.ExecContext(ctx, `DELETE FROM `+table+` WHERE client_id = ?`, ID); err != nil {
+			return fmt.Errorf("failed to delete oauth client: %s", err)
+		}
+	}
+
+	res, err := tx.ExecContext(ctx, `DELETE FROM oauth_clients WHERE id = ?`, ID)
+	if err != nil {
+		return fmt.Errorf("failed to delete oauth client: %s", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to delete oauth client: %s", err)
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("oauth client not found")
+	}
+
+	return tx.Commit()
+}
+
+// AddClientSecret - secrets of the client which are valid longer than overlapUntil
+// expire at overlapUntil, so callers can switch to the new secret in the meantime
+func (s *SQLLiteStorage) AddClientSecret(ctx context.Context, secret entity.ClientSecret, overlapUntil time.Time) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to add client secret: %s", err)
+	}
+	defer tx.Rollback()
+
+	query := `UPDATE oauth_client_secrets SET expires_at = ? WHERE client_id = ? AND (expires_at IS NULL OR expires_at > ?)`
+	if _, err = tx.ExecContext(ctx, query, overlapUntil.UTC(), secret.ClientID, overlapUntil.UTC()); err != nil {
+		return fmt.Errorf("failed to add client secret: %s", err)
+	}
+
+	if _, err = tx.ExecContext(ctx, `INSERT INTO oauth_client_secrets(client_id, secret_hash) VALUES(?,?)`, secret.ClientID, secret.SecretHash); err != nil {
+		return fmt.Errorf("failed to add client secret: %s", err)
+	}
+
+	return tx.Commit()
+}
+
+// FindClientSecret - expired secrets are returned as well, callers check ExpiresAt
+func (s *SQLLiteStorage) FindClientSecret(ctx context.Context, clientID, secretHash string) (entity.ClientSecret, error) {
+	secret := entity.ClientSecret{ClientID: clientID, SecretHash: secretHash}
+	var expiresAt sql.NullTime
+
+	query := `SELECT id, created_at, expires_at FROM oauth_client_secrets WHERE client_id = ? AND secret_hash = ?`
+	err := s.db.QueryRowContext(ctx, query, clientID, secretHash).Scan(&secret.ID, &secret.CreatedAt, &expiresAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.ClientSecret{}, fmt.Errorf("client secret not found")
+		}
+
+		return entity.ClientSecret{}, fmt.Errorf("failed to find client secret: %s", err)
+	}
+
+	if expiresAt.Valid {
+		secret.ExpiresAt = &expiresAt.Time
+	}
+
+	return secret, nil
+}
+
+func (s *SQLLiteStorage) SaveAuthorizationCode(ctx context.Context, code entity.AuthorizationCode) error {
+	query := `INSERT INTO oauth_codes(code_hash, client_id, user_id, redirect_uri, scope, code_challenge, expires_at, nonce, auth_time)
+		VALUES(?,?,?,?,?,?,?,?,?)`
+	_, err := s.db.ExecContext(ctx, query,
+		code.CodeHash,
+		code.ClientID,
+		code.UserID,
+		code.RedirectURI,
+		code.Scope,
+		code.CodeChallenge,
+		code.ExpiresAt.UTC(),
+		code.Nonce,
+		code.AuthTime.UTC(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert authorization code: %s", err)
+	}
+
+	return nil
+}
+
+// ConsumeAuthorizationCode - marks the code used and returns it, every code can be consumed only once
+func (s *SQLLiteStorage) ConsumeAuthorizationCode(ctx context.Context, codeHash string) (entity.AuthorizationCode, error) {
+	res, err := s.db.ExecContext(ctx, `UPDATE oauth_codes SET used_at = ? WHERE code_hash = ? AND used_at IS NULL`, time.Now().UTC(), codeHash)
+	if err != nil {
+		return entity.AuthorizationCode{}, fmt.Errorf("failed to consume authorization code: %s", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return entity.AuthorizationCode{}, fmt.Errorf("failed to consume authorization code: %s", err)
+	}
+
+	if affected == 0 {
+		return entity.AuthorizationCode{}, fmt.Errorf("authorization code not found or already used")
+	}
+
+	code := entity.AuthorizationCode{CodeHash: codeHash}
+	var authTime sql.NullTime
+	query := `SELECT client_id, user_id, redirect_uri, scope, code_challenge, expires_at, nonce, auth_time FROM oauth_codes WHERE code_hash = ?`
+	err = s.db.QueryRowContext(ctx, query, codeHash).Scan(
+		&code.ClientID,
+		&code.UserID,
+		&code.RedirectURI,
+		&code.Scope,
+		&code.CodeChallenge,
+		&code.ExpiresAt,
+		&code.Nonce,
+		&authTime,
+	)
+	if err != nil {
+		return entity.AuthorizationCode{}, fmt.Errorf("failed to consume authorization code: %s", err)
+	}
+
+	code.AuthTime = authTime.Time
+
+	return code, nil
+}
+
+// SaveOAuthGrant - replaces scope previously consented to the client
+func (s *SQLLiteStorage) SaveOAuthGrant(ctx context.Context, g entity.OAuthGrant) error {
+	query := `INSERT INTO oauth_grants(user_id, client_id, scope) VALUES(?,?,?)
+		ON CONFLICT(user_id, client_id) DO UPDATE SET scope = excluded.scope, updated_at = CURRENT_TIMESTAMP`
+	if _, err := s.db.ExecContext(ctx, query, g.UserID, g.ClientID, g.Scope); err != nil {
+		return fmt.Errorf("failed to save oauth grant: %s", err)
+	}
+
+	return nil
+}
+
+func (s *SQLLiteStorage) GetOAuthGrant(ctx context.Context, userID int, clientID string) (entity.OAuthGrant, error) {
+	g := entity.OAuthGrant{UserID: userID, ClientID: clientID}
+	query := `SELECT scope FROM oauth_grants WHERE user_id = ? AND client_id = ?`
+	if err := s.db.QueryRowContext(ctx, query, userID, clientID).Scan(&g.Scope); err != nil {
+		return entity.OAuthGrant{}, fmt.Errorf("failed to get oauth grant: %s", err)
+	}
+
+	return g, nil
+}
+
+// SaveOAuthRefreshToken - refresh token of the client on behalf of the user. Every sign in
+// gets its own token, so the sessions of the user with the client don't replace each other
+func (s *SQLLiteStorage) SaveOAuthRefreshToken(ctx context.Context, userID int, clientID, tokenHash string) error {
+	query := `INSERT INTO oauth_refresh_tokens(token_hash, user_id, client_id) VALUES(?,?,?)`
+	if _, err := s.db.ExecContext(ctx, query, tokenHash, userID, clientID); err != nil {
+		return fmt.Errorf("failed to save oauth refresh token: %s", err)
+	}
+
+	return nil
+}
+
+// GetOAuthGrantByRefreshToken - the grant the refresh token was issued for
+func (s *SQLLiteStorage) GetOAuthGrantByRefreshToken(ctx context.Context, tokenHash string) (entity.OAuthGrant, error) {
+	var g entity.OAuthGrant
+	query := `
+		SELECT g.user_id, g.client_id, g.scope FROM oauth_refresh_tokens t
+		JOIN oauth_grants g ON g.user_id = t.user_id AND g.client_id = t.client_id
+		WHERE t.token_hash = ?`
+	if err := s.db.QueryRowContext(ctx, query, tokenHash).Scan(&g.UserID, &g.ClientID, &g.Scope); err != nil {
+		return entity.OAuthGrant{}, fmt.Errorf("failed to get oauth grant: %s", err)
+	}
+
+	return g, nil
+}
+
+// RevokeOAuthRefreshToken - the token can be revoked only once, so a token used twice
+// at the same time is rotated only once
+func (s *SQLLiteStorage) RevokeOAuthRefreshToken(ctx context.Context, tokenHash string) error {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM oauth_refresh_tokens WHERE token_hash = ?`, tokenHash)
+	if err != nil {
+		return fmt.Errorf("failed to revoke oauth refresh token: %s", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to revoke oauth refresh token: %s", err)
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("failed to revoke oauth refresh token: token not found")
+	}
+
+	return nil
+}
@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/bogatyr285/auth-go/internal/auth/entity"
+)
+
+func (s *SQLLiteStorage) FindUserByPhone(ctx context.Context, phone string) (entity.UserAccount, error) {
+	query := `SELECT id, username, password, phone_verified FROM users WHERE phone = ?`
+
+	user := entity.UserAccount{Phone: phone}
+	err := s.db.QueryRowContext(ctx, query, phone).Scan(&user.ID, &user.Username, &user.Password, &user.PhoneVerified)
+	if err != nil {
+		return entity.UserAccount{}, fmt.Errorf("failed to find user by phone: %s", err)
+	}
+
+	return user, nil
+}
+
+// FindUserByPendingPhone - the account which claimed the number last, numbers of
+// registrations are pending until verified so nobody can hold a number they don't own
+func (s *SQLLiteStorage) FindUserByPendingPhone(ctx context.Context, phone string) (entity.UserAccount, error) {
+	query := `SELECT id, username FROM users WHERE pending_phone = ? ORDER BY id DESC LIMIT 1`
+
+	user := entity.UserAccount{Phone: phone}
+	err := s.db.QueryRowContext(ctx, query, phone).Scan(&user.ID, &user.Username)
+	if err != nil {
+		return entity.UserAccount{}, fmt.Errorf("failed to find user by pending phone: %s", err)
+	}
+
+	return user, nil
+}
+
+// SetPhoneVerified - the number moves to the account which claimed it last, any other
+// account loses it
+func (s *SQLLiteStorage) SetPhoneVerified(ctx context.Context, phone string) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to verify phone: %s", err)
+	}
+	defer tx.Rollback()
+
+	var userID int
+	err = tx.QueryRowContext(ctx, `SELECT id FROM users WHERE pending_phone = ? ORDER BY id DESC LIMIT 1`, phone).Scan(&userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("failed to verify phone: user not found")
+		}
+
+		return fmt.Errorf("failed to verify phone: %s", err)
+	}
+
+	if _, err = tx.ExecContext(ctx, `UPDATE users SET phone = NULL, phone_verified = FALSE WHERE phone = ?`, phone); err != nil {
+		return fmt.Errorf("failed to verify phone: %s", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE users SET phone = ?, phone_verified = TRUE, pending_phone = NULL WHERE id = ?`, phone, userID)
+	if err != nil {
+		return fmt.Errorf("failed to verify phone: %s", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to verify phone: %s", err)
+	}
+
+	return nil
+}
+
+func (s *SQLLiteStorage) SaveOTP(ctx context.Context, otp entity.OTP) error {
+	query := `INSERT INTO otp_codes(phone, purpose, code_hash, created_at, expires_at) VALUES(?,?,?,?,?)`
+	_, err := s.db.ExecContext(ctx, query, otp.Phone, otp.Purpose, otp.CodeHash, otp.CreatedAt.UTC(), otp.ExpiresAt.UTC())
+	if err != nil {
+		return fmt.Errorf("failed to insert otp: %s", err)
+	}
+
+	return nil
+}
+
+func (s *SQLLiteStorage) CountOTPsSince(ctx context.Context, phone string, since time.Time) (int, error) {
+	query := `SELECT COUNT(*) FROM otp_codes WHERE phone = ? AND created_at > ?`
+
+	var count int
+	if err := s.db.QueryRowContext(ctx, query, phone, since.UTC()).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count otp: %s", err)
+	}
+
+	return count, nil
+}
+
+// FindActiveOTP - the latest code, earlier ones are superseded by it
+func (s *SQLLiteStorage) FindActiveOTP(ctx context.Context, phone, purpose string) (entity.OTP, error) {
+	query := `SELECT id, code_hash, attempts, created_at, expires_at, consumed_at IS NOT NULL FROM otp_codes WHERE phone = ? AND purpose = ? ORDER BY id DESC LIMIT 1`
+
+	otp := entity.OTP{Phone: phone, Purpose: purpose}
+	var consumed bool
+	err := s.db.QueryRowContext(ctx, query, phone, purpose).Scan(&otp.ID, &otp.CodeHash, &otp.Attempts, &otp.CreatedAt, &otp.ExpiresAt, &consumed)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.OTP{}, fmt.Errorf("otp not found")
+		}
+
+		return entity.OTP{}, fmt.Errorf("failed to find otp: %s", err)
+	}
+
+	if consumed {
+		return entity.OTP{}, fmt.Errorf("otp not found")
+	}
+
+	return otp, nil
+}
+
+// RegisterOTPAttempt - counts a verification attempt, false when the limit is already reached
+func (s *SQLLiteStorage) RegisterOTPAttempt(ctx context.Context, ID, maxAttempts int) (bool, error) {
+	res, err := s.db.ExecContext(ctx, `UPDATE otp_codes SET attempts = attempts + 1 WHERE id = ? AND attempts < ? AND consumed_at IS NULL`, ID, maxAttempts)
+	if err != nil {
+		return false, fmt.Errorf("failed to register otp attempt: %s", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to register otp attempt: %s", err)
+	}
+
+	return affected == 1, nil
+}
+
+func (s *SQLLiteStorage) ConsumeOTP(ctx context.Context, ID int) error {
+	res, err := s.db.ExecContext(ctx, `UPDATE otp_codes SET consumed_at = ? WHERE id = ? AND consumed_at IS NULL`, time.Now().UTC(), ID)
+	if err != nil {
+		return fmt.Errorf("failed to consume otp: %s", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to consume otp: %s", err)
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("otp already used")
+	}
+
+	return nil
+}
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/bogatyr285/auth-go/internal/auth/entity"
+)
+
+// GetUserProfile - the user with profile fields, without password
+func (s *SQLLiteStorage) GetUserProfile(ctx context.Context, userID int) (entity.UserAccount, error) {
+	query := `
+		SELECT username, phone, phone_verified, created_at, name, email, email_verified, gender,
+			address_street, address_city, address_state, address_zipcode
+		FROM users WHERE id = ?`
+
+	user := entity.UserAccount{ID: userID}
+	var phone sql.NullString
+	err := s.db.QueryRowContext(ctx, query, userID).Scan(
+		&user.Username,
+		&phone,
+		&user.PhoneVerified,
+		&user.CreatedAt,
+		&user.Name,
+		&user.Email,
+		&user.EmailVerified,
+		&user.Gender,
+		&user.Address.Street,
+		&user.Address.City,
+		&user.Address.State,
+		&user.Address.Zipcode,
+	)
+	if err != nil {
+		return entity.UserAccount{}, fmt.Errorf("failed to get user profile: %s", err)
+	}
+
+	user.Phone = phone.String
+	return user, nil
+}
+
+// SetEmailVerified - the email is the one the user proved to own
+func (s *SQLLiteStorage) SetEmailVerified(ctx context.Context, userID int, email string) error {
+	res, err := s.db.ExecContext(ctx, `UPDATE users SET email = ?, email_verified = TRUE WHERE id = ?`, email, userID)
+	if err != nil {
+		return fmt.Errorf("failed to verify email: %s", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to verify email: %s", err)
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("failed to verify email: user not found")
+	}
+
+	return nil
+}
+
+// FindUserByVerifiedEmail - the only user who proved to own the email, addresses shared by
+// several users aren't trusted to name either of them
+func (s *SQLLiteStorage) FindUserByVerifiedEmail(ctx context.Context, email string) (entity.UserAccount, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT id, username FROM users WHERE email = ? AND email_verified LIMIT 2`, email)
+	if err != nil {
+		return entity.UserAccount{}, fmt.Errorf("failed to find user by email: %s", err)
+	}
+	defer rows.Close()
+
+	var users []entity.UserAccount
+	for rows.Next() {
+		user := entity.UserAccount{Email: email, EmailVerified: true}
+		if err = rows.Scan(&user.ID, &user.Username); err != nil {
+			return entity.UserAccount{}, fmt.Errorf("failed to find user by email: %s", err)
+		}
+		users = append(users, user)
+	}
+	if err = rows.Err(); err != nil {
+		return entity.UserAccount{}, fmt.Errorf("failed to find user by email: %s", err)
+	}
+
+	if len(users) != 1 {
+		return entity.UserAccount{}, entity.ErrUserNotFound
+	}
+
+	return users[0], nil
+}
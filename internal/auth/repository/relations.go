@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bogatyr285/auth-go/internal/auth/entity"
+)
+
+// tuples are never updated in place: every write creates a revision, tuples are
+// created and deleted at revisions, so reads can be done at any revision
+const liveAtRevision = `created_rev <= ? AND (deleted_rev IS NULL OR deleted_rev > ?)`
+
+// WriteTuples - applies updates atomically, returns the revision they are visible at
+func (s *SQLLiteStorage) WriteTuples(ctx context.Context, updates []entity.TupleUpdate) (int64, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to write tuples: %s", err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, `INSERT INTO relation_revisions DEFAULT VALUES`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to write tuples: %s", err)
+	}
+
+	rev, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("failed to write tuples: %s", err)
+	}
+
+	match := `namespace = ? AND object_id = ? AND relation = ? AND subject_namespace = ? AND subject_id = ? AND subject_relation = ? AND deleted_rev IS NULL`
+	for _, u := range updates {
+		t := u.Tuple
+		args := []interface{}{t.Object.Namespace, t.Object.ID, t.Relation, t.Subject.Namespace, t.Subject.ID, t.Subject.Relation}
+
+		switch u.Operation {
+		case entity.TupleTouch:
+			query := `INSERT INTO relation_tuples(namespace, object_id, relation, subject_namespace, subject_id, subject_relation, created_rev)
+				SELECT ?, ?, ?, ?, ?, ?, ? WHERE NOT EXISTS (SELECT 1 FROM relation_tuples WHERE ` + match + `)`
+			_, err = tx.ExecContext(ctx, query, append(append(args, rev), args...)...)
+		case entity.TupleDelete:
+			_, err = tx.ExecContext(ctx, `UPDATE relation_tuples SET deleted_rev = ? WHERE `+match, append([]interface{}{rev}, args...)...)
+		default:
+			err = fmt.Errorf("unknown operation %q", u.Operation)
+		}
+		if err != nil {
+			return 0, fmt.Errorf("failed to write tuple %s: %s", t, err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to write tuples: %s", err)
+	}
+
+	return rev, nil
+}
+
+func (s *SQLLiteStorage) LatestRevision(ctx context.Context) (int64, error) {
+	var rev int64
+	if err := s.db.QueryRowContext(ctx, `SELECT COALESCE(MAX(id), 0) FROM relation_revisions`).Scan(&rev); err != nil {
+		return 0, fmt.Errorf("failed to get latest revision: %s", err)
+	}
+
+	return rev, nil
+}
+
+// ReadTuples - tuples of the object with the relation as of the revision
+func (s *SQLLiteStorage) ReadTuples(ctx context.Context, object entity.Object, relation string, rev int64) ([]entity.RelationTuple, error) {
+	query := `SELECT subject_namespace, subject_id, subject_relation FROM relation_tuples
+		WHERE namespace = ? AND object_id = ? AND relation = ? AND ` + liveAtRevision + ` ORDER BY id`
+	rows, err := s.db.QueryContext(ctx, query, object.Namespace, object.ID, relation, rev, rev)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tuples: %s", err)
+	}
+	defer rows.Close()
+
+	var tuples []entity.RelationTuple
+	for rows.Next() {
+		t := entity.RelationTuple{Object: object, Relation: relation}
+		if err = rows.Scan(&t.Subject.Namespace, &t.Subject.ID, &t.Subject.Relation); err != nil {
+			return nil, fmt.Errorf("failed to read tuples: %s", err)
+		}
+
+		tuples = append(tuples, t)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read tuples: %s", err)
+	}
+
+	return tuples, nil
+}
+
+// ListObjectIDs - objects of the namespace having any tuple as of the revision
+func (s *SQLLiteStorage) ListObjectIDs(ctx context.Context, namespace string, rev int64) ([]string, error) {
+	query := `SELECT DISTINCT object_id FROM relation_tuples WHERE namespace = ? AND ` + liveAtRevision + ` ORDER BY object_id`
+	rows, err := s.db.QueryContext(ctx, query, namespace, rev, rev)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list objects: %s", err)
+	}
+	defer rows.Close()
+
+	var IDs []string
+	for rows.Next() {
+		var ID string
+		if err = rows.Scan(&ID); err != nil {
+			return nil, fmt.Errorf("failed to list objects: %s", err)
+		}
+
+		IDs = append(IDs, ID)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list objects: %s", err)
+	}
+
+	return IDs, nil
+}
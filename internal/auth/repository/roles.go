@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bogatyr285/auth-go/internal/auth/entity"
+)
+
+// ListUserRoles - granted roles, entity.RoleUser is implied for every user and isn't stored
+func (s *SQLLiteStorage) ListUserRoles(ctx context.Context, userID int) ([]string, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT role FROM user_roles WHERE user_id = ? ORDER BY role`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list user roles: %s", err)
+	}
+	defer rows.Close()
+
+	roles := []string{entity.RoleUser}
+	for rows.Next() {
+		var role string
+		if err = rows.Scan(&role); err != nil {
+			return nil, fmt.Errorf("failed to list user roles: %s", err)
+		}
+
+		roles = append(roles, role)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list user roles: %s", err)
+	}
+
+	return roles, nil
+}
+
+func (s *SQLLiteStorage) GrantUserRole(ctx context.Context, userID int, role string) error {
+	_, err := s.db.ExecContext(ctx, `INSERT OR IGNORE INTO user_roles(user_id, role) VALUES(?,?)`, userID, role)
+	if err != nil {
+		return fmt.Errorf("failed to grant role: %s", err)
+	}
+
+	return nil
+}
+
+func (s *SQLLiteStorage) RevokeUserRole(ctx context.Context, userID int, role string) error {
+	_, err := s.db.ExecContext(ctx, `DELETE FROM user_roles WHERE user_id = ? AND role = ?`, userID, role)
+	if err != nil {
+		return fmt.Errorf("failed to revoke role: %s", err)
+	}
+
+	return nil
+}
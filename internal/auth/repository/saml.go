@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// ConsumeSAMLAssertion - records the assertion ID until it expires, false is returned
+// when the assertion was already consumed
+func (s *SQLLiteStorage) ConsumeSAMLAssertion(ctx context.Context, provider, id string, expiresAt time.Time) (bool, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to consume assertion: %s", err)
+	}
+	defer tx.Rollback()
+
+	if _, err = tx.ExecContext(ctx, `DELETE FROM saml_assertions WHERE expires_at < ?`, time.Now().UTC()); err != nil {
+		return false, fmt.Errorf("failed to consume assertion: %s", err)
+	}
+
+	query := `INSERT OR IGNORE INTO saml_assertions(provider, id, expires_at) VALUES(?,?,?)`
+	res, err := tx.ExecContext(ctx, query, provider, id, expiresAt.UTC())
+	if err != nil {
+		return false, fmt.Errorf("failed to consume assertion: %s", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to consume assertion: %s", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to consume assertion: %s", err)
+	}
+
+	return n == 1, nil
+}
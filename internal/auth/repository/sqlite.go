@@ -16,30 +16,17 @@ type SQLLiteStorage struct {
 	db *sql.DB
 }
 
-func New(dbPath string) (SQLLiteStorage, error) {
-	db, err := sql.Open("sqlite3", dbPath)
-	if err != nil {
-		return SQLLiteStorage{}, err
-	}
-
-	stmt, err := db.Prepare(`
+// schema - tables are created in order, so referenced tables must go first
+var schema = []string{
+	`
 		CREATE TABLE IF NOT EXISTS users (
 			id INTEGER PRIMARY KEY,
 			username text not null,
 			password text not null,
 			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 		);
-	`)
-	if err != nil {
-		return SQLLiteStorage{}, fmt.Errorf("db schema init err: %s", err)
-	}
-
-	_, err = stmt.Exec()
-	if err != nil {
-		return SQLLiteStorage{}, err
-	}
-
-	stmt, err = db.Prepare(`
+	`,
+	`
 		CREATE TABLE IF NOT EXISTS tokens (
 			id INTEGER PRIMARY KEY,
 			user_id INT NOT NULL UNIQUE,
@@ -48,30 +35,321 @@ func New(dbPath string) (SQLLiteStorage, error) {
 			expired_at TIMESTAMP NOT NULL,
 			FOREIGN KEY (user_id) REFERENCES users(id)
 		);
-	`)
-	if err != nil {
-		return SQLLiteStorage{}, fmt.Errorf("db schema init err: %s", err)
-	}
+	`,
+	`
+		CREATE TABLE IF NOT EXISTS webauthn_credentials (
+			id INTEGER PRIMARY KEY,
+			user_id INT NOT NULL,
+			credential_id BLOB NOT NULL UNIQUE,
+			public_key BLOB NOT NULL,
+			attestation_type text NOT NULL,
+			aaguid BLOB,
+			sign_count INT NOT NULL DEFAULT 0,
+			transports text NOT NULL DEFAULT '',
+			backup_eligible BOOLEAN NOT NULL DEFAULT FALSE,
+			backup_state BOOLEAN NOT NULL DEFAULT FALSE,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			FOREIGN KEY (user_id) REFERENCES users(id)
+		);
+	`,
+	`
+		CREATE TABLE IF NOT EXISTS webauthn_sessions (
+			id text PRIMARY KEY,
+			user_id INT NOT NULL,
+			ceremony text NOT NULL,
+			data BLOB NOT NULL,
+			expires_at TIMESTAMP NOT NULL
+		);
+	`,
+	`
+		CREATE TABLE IF NOT EXISTS magic_links (
+			id text PRIMARY KEY,
+			email text NOT NULL,
+			created_at TIMESTAMP NOT NULL,
+			expires_at TIMESTAMP NOT NULL,
+			used_at TIMESTAMP
+		);
+	`,
+	`CREATE INDEX IF NOT EXISTS magic_links_email_idx ON magic_links(email, created_at);`,
+	`
+		CREATE TABLE IF NOT EXISTS otp_codes (
+			id INTEGER PRIMARY KEY,
+			phone text NOT NULL,
+			purpose text NOT NULL,
+			code_hash text NOT NULL,
+			attempts INT NOT NULL DEFAULT 0,
+			created_at TIMESTAMP NOT NULL,
+			expires_at TIMESTAMP NOT NULL,
+			consumed_at TIMESTAMP
+		);
+	`,
+	`CREATE INDEX IF NOT EXISTS otp_codes_phone_idx ON otp_codes(phone, purpose, created_at);`,
+	`
+		CREATE TABLE IF NOT EXISTS user_roles (
+			user_id INT NOT NULL,
+			role text NOT NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (user_id, role),
+			FOREIGN KEY (user_id) REFERENCES users(id)
+		);
+	`,
+	`
+		CREATE TABLE IF NOT EXISTS relation_revisions (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		);
+	`,
+	`
+		CREATE TABLE IF NOT EXISTS relation_tuples (
+			id INTEGER PRIMARY KEY,
+			namespace text NOT NULL,
+			object_id text NOT NULL,
+			relation text NOT NULL,
+			subject_namespace text NOT NULL,
+			subject_id text NOT NULL,
+			subject_relation text NOT NULL DEFAULT '',
+			created_rev INT NOT NULL,
+			deleted_rev INT,
+			FOREIGN KEY (created_rev) REFERENCES relation_revisions(id)
+		);
+	`,
+	`CREATE INDEX IF NOT EXISTS relation_tuples_object_idx ON relation_tuples(namespace, object_id, relation);`,
+	`
+		CREATE TABLE IF NOT EXISTS oauth_clients (
+			id text PRIMARY KEY,
+			name text NOT NULL,
+			redirect_uris text NOT NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		);
+	`,
+	`
+		CREATE TABLE IF NOT EXISTS oauth_codes (
+			code_hash text PRIMARY KEY,
+			client_id text NOT NULL,
+			user_id INT NOT NULL,
+			redirect_uri text NOT NULL,
+			scope text NOT NULL,
+			code_challenge text NOT NULL,
+			expires_at TIMESTAMP NOT NULL,
+			used_at TIMESTAMP,
+			FOREIGN KEY (client_id) REFERENCES oauth_clients(id),
+			FOREIGN KEY (user_id) REFERENCES users(id)
+		);
+	`,
+	`
+		CREATE TABLE IF NOT EXISTS oauth_grants (
+			user_id INT NOT NULL,
+			client_id text NOT NULL,
+			scope text NOT NULL,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (user_id, client_id),
+			FOREIGN KEY (user_id) REFERENCES users(id),
+			FOREIGN KEY (client_id) REFERENCES oauth_clients(id)
+		);
+	`,
+	`
+		CREATE TABLE IF NOT EXISTS oauth_refresh_tokens (
+			token_hash text PRIMARY KEY,
+			user_id INT NOT NULL,
+			client_id text NOT NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			FOREIGN KEY (user_id) REFERENCES users(id),
+			FOREIGN KEY (client_id) REFERENCES oauth_clients(id)
+		);
+	`,
+	`
+		CREATE TABLE IF NOT EXISTS oauth_client_secrets (
+			id INTEGER PRIMARY KEY,
+			client_id text NOT NULL,
+			secret_hash text NOT NULL UNIQUE,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			expires_at TIMESTAMP,
+			FOREIGN KEY (client_id) REFERENCES oauth_clients(id)
+		);
+	`,
+	`
+		CREATE TABLE IF NOT EXISTS oauth_device_codes (
+			device_code_hash text PRIMARY KEY,
+			user_code text NOT NULL UNIQUE,
+			client_id text NOT NULL,
+			scope text NOT NULL,
+			status text NOT NULL,
+			user_id INT,
+			interval_seconds INT NOT NULL,
+			last_polled_at TIMESTAMP,
+			approved_at TIMESTAMP,
+			expires_at TIMESTAMP NOT NULL,
+			FOREIGN KEY (client_id) REFERENCES oauth_clients(id),
+			FOREIGN KEY (user_id) REFERENCES users(id)
+		);
+	`,
+	`
+		CREATE TABLE IF NOT EXISTS identities (
+			provider text NOT NULL,
+			subject text NOT NULL,
+			user_id INT NOT NULL,
+			email text NOT NULL DEFAULT '',
+			created_at TIMESTAMP NOT NULL,
+			PRIMARY KEY (provider, subject),
+			FOREIGN KEY (user_id) REFERENCES users(id)
+		);
+	`,
+	`
+		CREATE TABLE IF NOT EXISTS federated_logins (
+			state_hash text PRIMARY KEY,
+			provider text NOT NULL,
+			nonce text NOT NULL,
+			code_verifier text NOT NULL,
+			expires_at TIMESTAMP NOT NULL
+		);
+	`,
+	`
+		CREATE TABLE IF NOT EXISTS saml_assertions (
+			provider text NOT NULL,
+			id text NOT NULL,
+			expires_at TIMESTAMP NOT NULL,
+			PRIMARY KEY (provider, id)
+		);
+	`,
+	`
+		CREATE TABLE IF NOT EXISTS personal_access_tokens (
+			id text PRIMARY KEY,
+			user_id INT NOT NULL,
+			name text NOT NULL,
+			token_hash text NOT NULL UNIQUE,
+			scopes text NOT NULL DEFAULT '',
+			expires_at TIMESTAMP NOT NULL,
+			last_used_at TIMESTAMP,
+			created_at TIMESTAMP NOT NULL,
+			FOREIGN KEY (user_id) REFERENCES users(id)
+		);
+	`,
+}
+
+// columns - added to tables which already exist in deployed databases
+var columns = []struct {
+	table      string
+	name       string
+	definition string
+}{
+	{"users", "phone", "text"},
+	{"users", "phone_verified", "BOOLEAN NOT NULL DEFAULT FALSE"},
+	{"users", "pending_phone", "text"},
+	{"users", "name", "text NOT NULL DEFAULT ''"},
+	{"users", "email", "text NOT NULL DEFAULT ''"},
+	{"users", "email_verified", "BOOLEAN NOT NULL DEFAULT FALSE"},
+	{"users", "gender", "text NOT NULL DEFAULT ''"},
+	{"users", "address_street", "text NOT NULL DEFAULT ''"},
+	{"users", "address_city", "text NOT NULL DEFAULT ''"},
+	{"users", "address_state", "text NOT NULL DEFAULT ''"},
+	{"users", "address_zipcode", "text NOT NULL DEFAULT ''"},
+	{"oauth_clients", "confidential", "BOOLEAN NOT NULL DEFAULT FALSE"},
+	{"oauth_clients", "scopes", "text NOT NULL DEFAULT ''"},
+	{"oauth_codes", "nonce", "text NOT NULL DEFAULT ''"},
+	{"oauth_codes", "auth_time", "TIMESTAMP"},
+	{"federated_logins", "user_id", "INT NOT NULL DEFAULT 0"},
+}
+
+// migrations - data moved to the added columns
+var migrations = []string{
+	// unverified numbers are pending, they don't hold the number under users_phone_idx
+	`UPDATE users SET pending_phone = phone, phone = NULL WHERE phone IS NOT NULL AND NOT phone_verified;`,
+}
 
-	_, err = stmt.Exec()
+// indexes - created after columns are in place
+var indexes = []string{
+	`CREATE UNIQUE INDEX IF NOT EXISTS users_phone_idx ON users(phone);`,
+}
+
+func New(dbPath string) (SQLLiteStorage, error) {
+	db, err := sql.Open("sqlite3", dbPath)
 	if err != nil {
 		return SQLLiteStorage{}, err
 	}
 
+	for _, query := range schema {
+		stmt, err := db.Prepare(query)
+		if err != nil {
+			return SQLLiteStorage{}, fmt.Errorf("db schema init err: %s", err)
+		}
+
+		if _, err = stmt.Exec(); err != nil {
+			return SQLLiteStorage{}, err
+		}
+	}
+
+	for _, c := range columns {
+		if err = addColumnIfMissing(db, c.table, c.name, c.definition); err != nil {
+			return SQLLiteStorage{}, fmt.Errorf("db schema init err: %s", err)
+		}
+	}
+
+	for _, query := range migrations {
+		if _, err = db.Exec(query); err != nil {
+			return SQLLiteStorage{}, fmt.Errorf("db migration err: %s", err)
+		}
+	}
+
+	for _, query := range indexes {
+		if _, err = db.Exec(query); err != nil {
+			return SQLLiteStorage{}, fmt.Errorf("db schema init err: %s", err)
+		}
+	}
+
 	return SQLLiteStorage{db: db}, nil
 }
 
+func addColumnIfMissing(db *sql.DB, table, name, definition string) error {
+	var count int
+	err := db.QueryRow(`SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?`, table, name).Scan(&count)
+	if err != nil {
+		return err
+	}
+
+	if count > 0 {
+		return nil
+	}
+
+	_, err = db.Exec(fmt.Sprintf(`ALTER TABLE %s ADD COLUMN %s %s`, table, name, definition))
+	return err
+}
+
+// Ping - the database is reachable, probed by health checks
+func (s *SQLLiteStorage) Ping(ctx context.Context) error {
+	return s.db.PingContext(ctx)
+}
+
 func (s *SQLLiteStorage) Close() error {
 	return s.db.Close()
 }
 
 func (s *SQLLiteStorage) RegisterUser(ctx context.Context, u entity.UserAccount) error {
-	stmt, err := s.db.PrepareContext(ctx, `INSERT INTO users(username, password) VALUES(?,?)`)
+	stmt, err := s.db.PrepareContext(ctx, `
+		INSERT INTO users(username, password, phone, pending_phone, name, email, gender, address_street, address_city, address_state, address_zipcode)
+		VALUES(?,?,?,?,?,?,?,?,?,?,?)`)
 	if err != nil {
 		return err
 	}
 
-	if _, err = stmt.Exec(u.Username, u.Password); err != nil {
+	// the number is pending until the code sent to it is verified
+	var phone, pendingPhone sql.NullString
+	switch {
+	case u.Phone != "" && u.PhoneVerified:
+		phone = sql.NullString{String: u.Phone, Valid: true}
+	case u.Phone != "":
+		pendingPhone = sql.NullString{String: u.Phone, Valid: true}
+	}
+
+	_, err = stmt.Exec(u.Username, u.Password, phone, pendingPhone,
+		u.Name,
+		u.Email,
+		u.Gender,
+		u.Address.Street,
+		u.Address.City,
+		u.Address.State,
+		u.Address.Zipcode,
+	)
+	if err != nil {
 		return err
 	}
 
@@ -106,10 +384,15 @@ func (s *SQLLiteStorage) GetUserById(ctx context.Context, ID int) (entity.UserAc
 
 	var username string
 	if err = stmt.QueryRow(ID).Scan(&username); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.UserAccount{}, entity.ErrUserNotFound
+		}
+
 		return entity.UserAccount{}, fmt.Errorf("failed to get username: %s", err)
 	}
 
 	return entity.UserAccount{
+		ID:       ID,
 		Username: username,
 		Password: "",
 	}, nil
@@ -153,6 +436,10 @@ func (s *SQLLiteStorage) SelectUserByToken(ctx context.Context, token string) (e
 	var ID int
 	var username string
 	if err := row.Scan(&ID, &username); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.UserAccount{}, entity.ErrUserNotFound
+		}
+
 		return entity.UserAccount{}, fmt.Errorf("failed to check token: %s", err)
 	}
 
@@ -173,6 +460,10 @@ func (s *SQLLiteStorage) ExistsUserByUsername(ctx context.Context, username stri
 
 	var ID int
 	if err := row.Scan(&ID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+
 		return false, fmt.Errorf("failed to check username: %s", err)
 	}
 
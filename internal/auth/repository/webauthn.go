@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bogatyr285/auth-go/internal/auth/entity"
+)
+
+func (s *SQLLiteStorage) SaveWebAuthnCredential(ctx context.Context, c entity.WebAuthnCredential) error {
+	query := `INSERT INTO webauthn_credentials(user_id, credential_id, public_key, attestation_type, aaguid, sign_count, transports, backup_eligible, backup_state) VALUES(?,?,?,?,?,?,?,?,?)`
+	_, err := s.db.ExecContext(ctx, query,
+		c.UserID,
+		c.CredentialID,
+		c.PublicKey,
+		c.AttestationType,
+		c.AAGUID,
+		c.SignCount,
+		strings.Join(c.Transports, ","),
+		c.BackupEligible,
+		c.BackupState,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert webauthn credential: %s", err)
+	}
+
+	return nil
+}
+
+func (s *SQLLiteStorage) ListWebAuthnCredentials(ctx context.Context, userID int) ([]entity.WebAuthnCredential, error) {
+	query := `SELECT id, credential_id, public_key, attestation_type, aaguid, sign_count, transports, backup_eligible, backup_state, created_at FROM webauthn_credentials WHERE user_id = ?`
+	rows, err := s.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webauthn credentials: %s", err)
+	}
+	defer rows.Close()
+
+	var credentials []entity.WebAuthnCredential
+	for rows.Next() {
+		c := entity.WebAuthnCredential{UserID: userID}
+		var transports string
+		if err = rows.Scan(
+			&c.ID,
+			&c.CredentialID,
+			&c.PublicKey,
+			&c.AttestationType,
+			&c.AAGUID,
+			&c.SignCount,
+			&transports,
+			&c.BackupEligible,
+			&c.BackupState,
+			&c.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to list webauthn credentials: %s", err)
+		}
+
+		if transports != "" {
+			c.Transports = strings.Split(transports, ",")
+		}
+
+		credentials = append(credentials, c)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list webauthn credentials: %s", err)
+	}
+
+	return credentials, nil
+}
+
+func (s *SQLLiteStorage) UpdateWebAuthnSignCount(ctx context.Context, credentialID []byte, signCount uint32, backupState bool) error {
+	query := `UPDATE webauthn_credentials SET sign_count = ?, backup_state = ? WHERE credential_id = ?`
+	if _, err := s.db.ExecContext(ctx, query, signCount, backupState, credentialID); err != nil {
+		return fmt.Errorf("failed to update webauthn sign count: %s", err)
+	}
+
+	return nil
+}
+
+func (s *SQLLiteStorage) SaveWebAuthnSession(ctx context.Context, session entity.WebAuthnSession) error {
+	query := `INSERT INTO webauthn_sessions(id, user_id, ceremony, data, expires_at) VALUES(?,?,?,?,?)`
+	_, err := s.db.ExecContext(ctx, query, session.ID, session.UserID, session.Ceremony, session.Data, session.ExpiresAt.UTC())
+	if err != nil {
+		return fmt.Errorf("failed to insert webauthn session: %s", err)
+	}
+
+	return nil
+}
+
+// TakeWebAuthnSession - returns the session and deletes it, so every ceremony can be finished only once
+func (s *SQLLiteStorage) TakeWebAuthnSession(ctx context.Context, ID string) (entity.WebAuthnSession, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return entity.WebAuthnSession{}, fmt.Errorf("failed to take webauthn session: %s", err)
+	}
+	defer tx.Rollback()
+
+	session := entity.WebAuthnSession{ID: ID}
+	query := `SELECT user_id, ceremony, data, expires_at FROM webauthn_sessions WHERE id = ?`
+	err = tx.QueryRowContext(ctx, query, ID).Scan(&session.UserID, &session.Ceremony, &session.Data, &session.ExpiresAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.WebAuthnSession{}, fmt.Errorf("webauthn session not found")
+		}
+
+		return entity.WebAuthnSession{}, fmt.Errorf("failed to take webauthn session: %s", err)
+	}
+
+	// expired sessions of abandoned ceremonies are cleaned up along the way
+	if _, err = tx.ExecContext(ctx, `DELETE FROM webauthn_sessions WHERE id = ? OR expires_at < ?`, ID, time.Now().UTC()); err != nil {
+		return entity.WebAuthnSession{}, fmt.Errorf("failed to take webauthn session: %s", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return entity.WebAuthnSession{}, fmt.Errorf("failed to take webauthn session: %s", err)
+	}
+
+	return session, nil
+}
@@ -0,0 +1,169 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bogatyr285/auth-go/internal/auth/entity"
+	"github.com/bogatyr285/auth-go/internal/auth/pat"
+	"github.com/bogatyr285/auth-go/internal/gateway/http/gen"
+	"github.com/google/uuid"
+	"github.com/labstack/gommon/log"
+)
+
+const (
+	accessTokenDefaultTTL = 30 * 24 * time.Hour
+	accessTokenMaxTTL     = 365 * 24 * time.Hour
+)
+
+func (u AuthUseCase) GetPersonalAccessTokens(ctx context.Context, request gen.GetPersonalAccessTokensRequestObject) (gen.GetPersonalAccessTokensResponseObject, error) {
+	user, ok := u.currentUser(ctx)
+	if !ok {
+		return gen.GetPersonalAccessTokens401JSONResponse{Error: "unauth"}, nil
+	}
+
+	tokens, err := u.ur.ListPersonalAccessTokens(ctx, user.ID)
+	if err != nil {
+		log.Errorf("Failed to list access tokens: %s", err)
+		return gen.GetPersonalAccessTokens500JSONResponse{Error: "internal error"}, nil
+	}
+
+	res := make(gen.GetPersonalAccessTokens200JSONResponse, 0, len(tokens))
+	for _, t := range tokens {
+		res = append(res, personalAccessToken(t))
+	}
+
+	return res, nil
+}
+
+func (u AuthUseCase) PostPersonalAccessTokens(ctx context.Context, request gen.PostPersonalAccessTokensRequestObject) (gen.PostPersonalAccessTokensResponseObject, error) {
+	user, ok := u.currentUser(ctx)
+	if !ok {
+		return gen.PostPersonalAccessTokens401JSONResponse{Error: "unauth"}, nil
+	}
+
+	// personal access tokens carry no auth time, so they can't create more tokens
+	if !recentlyAuthenticated(ctx) {
+		return gen.PostPersonalAccessTokens403JSONResponse{Error: "sign in again to create access tokens"}, nil
+	}
+
+	roles, err := u.ur.ListUserRoles(ctx, user.ID)
+	if err != nil {
+		log.Errorf("Failed to list roles of %s: %s", user.Username, err)
+		return gen.PostPersonalAccessTokens500JSONResponse{Error: "internal error"}, nil
+	}
+
+	now := time.Now()
+	t := entity.PersonalAccessToken{
+		ID:        uuid.NewString(),
+		UserID:    user.ID,
+		Name:      strings.TrimSpace(request.Body.Name),
+		ExpiresAt: now.Add(accessTokenDefaultTTL),
+		CreatedAt: now,
+	}
+	for _, scope := range request.Body.Scopes {
+		t.Scopes = append(t.Scopes, entity.Permission(scope))
+	}
+	if request.Body.ExpiresAt != nil {
+		t.ExpiresAt = *request.Body.ExpiresAt
+	}
+
+	if err = validateAccessToken(t, roles); err != nil {
+		return gen.PostPersonalAccessTokens400JSONResponse{Error: err.Error()}, nil
+	}
+
+	token, hash, err := pat.Generate()
+	if err != nil {
+		return gen.PostPersonalAccessTokens500JSONResponse{}, err
+	}
+	t.TokenHash = hash
+
+	if err = u.ur.SavePersonalAccessToken(ctx, t); err != nil {
+		log.Errorf("Failed to save access token: %s", err)
+		return gen.PostPersonalAccessTokens500JSONResponse{Error: "internal error"}, nil
+	}
+
+	u.publish(entity.SecurityEvent{
+		Type:     entity.SecurityEventAccessTokenCreated,
+		UserID:   user.ID,
+		Username: user.Username,
+		TokenID:  t.ID,
+	})
+
+	res := personalAccessToken(t)
+	res.Token = &token
+
+	return gen.PostPersonalAccessTokens201JSONResponse(res), nil
+}
+
+func (u AuthUseCase) DeletePersonalAccessTokensId(ctx context.Context, request gen.DeletePersonalAccessTokensIdRequestObject) (gen.DeletePersonalAccessTokensIdResponseObject, error) {
+	user, ok := u.currentUser(ctx)
+	if !ok {
+		return gen.DeletePersonalAccessTokensId401JSONResponse{Error: "unauth"}, nil
+	}
+
+	err := u.ur.DeletePersonalAccessToken(ctx, user.ID, request.Id)
+	switch {
+	case errors.Is(err, entity.ErrAccessTokenNotFound):
+		return gen.DeletePersonalAccessTokensId404JSONResponse{Error: err.Error()}, nil
+	case err != nil:
+		log.Errorf("Failed to delete access token: %s", err)
+		return gen.DeletePersonalAccessTokensId500JSONResponse{Error: "internal error"}, nil
+	}
+
+	u.publish(entity.SecurityEvent{
+		Type:     entity.SecurityEventAccessTokenRevoked,
+		UserID:   user.ID,
+		Username: user.Username,
+		TokenID:  request.Id,
+	})
+
+	return gen.DeletePersonalAccessTokensId204Response{}, nil
+}
+
+func personalAccessToken(t entity.PersonalAccessToken) gen.PersonalAccessToken {
+	res := gen.PersonalAccessToken{
+		Id:         t.ID,
+		Name:       t.Name,
+		Scopes:     make([]string, 0, len(t.Scopes)),
+		ExpiresAt:  t.ExpiresAt.UTC(),
+		LastUsedAt: t.LastUsedAt,
+		CreatedAt:  t.CreatedAt.UTC(),
+	}
+	for _, scope := range t.Scopes {
+		res.Scopes = append(res.Scopes, string(scope))
+	}
+
+	return res
+}
+
+// validateAccessToken - tokens expire within accessTokenMaxTTL and are scoped to
+// permissions the roles of the user grant
+func validateAccessToken(t entity.PersonalAccessToken, roles []string) error {
+	if t.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+
+	if len(t.Scopes) == 0 {
+		return fmt.Errorf("at least one scope is required")
+	}
+
+	for _, scope := range t.Scopes {
+		if !entity.HasPermission(roles, scope) {
+			return fmt.Errorf("scope %s is not granted to the account", scope)
+		}
+	}
+
+	if !t.ExpiresAt.After(t.CreatedAt) {
+		return fmt.Errorf("expires_at must be in the future")
+	}
+
+	if t.ExpiresAt.After(t.CreatedAt.Add(accessTokenMaxTTL)) {
+		return fmt.Errorf("expires_at must be within a year")
+	}
+
+	return nil
+}
@@ -0,0 +1,143 @@
+package usecase_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bogatyr285/auth-go/internal/auth/entity"
+	"github.com/bogatyr285/auth-go/internal/auth/events"
+	"github.com/bogatyr285/auth-go/internal/auth/pat"
+	"github.com/bogatyr285/auth-go/internal/gateway/http/gen"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPersonalAccessTokens(t *testing.T) {
+	srv, useCase := newTestServerWithUseCase(t)
+
+	admin := gen.LoginUserRequest{Username: "alice@example.com", Password: "rLy_5tr0nG!"}
+	require.Equal(t, http.StatusCreated, doJSON(t, srv, "/register", "", admin, nil))
+	require.NoError(t, useCase.GrantAdmins(context.Background(), []string{admin.Username}))
+
+	var tokens gen.LoginUserResponse
+	require.Equal(t, http.StatusOK, doJSON(t, srv, "/login", "", admin, &tokens))
+
+	create := func(t *testing.T, bearer string, req gen.PersonalAccessTokenRequest) (gen.PersonalAccessToken, int) {
+		var res gen.PersonalAccessToken
+		status := doJSON(t, srv, "/personal-access-tokens", bearer, req, &res)
+		return res, status
+	}
+
+	list := func(t *testing.T) []gen.PersonalAccessToken {
+		var res []gen.PersonalAccessToken
+		require.Equal(t, http.StatusOK, doRequest(t, srv, http.MethodGet, "/personal-access-tokens", tokens.AccessToken, nil, &res))
+		return res
+	}
+
+	ci, status := create(t, tokens.AccessToken, gen.PersonalAccessTokenRequest{Name: "ci", Scopes: []string{"users:read"}})
+	require.Equal(t, http.StatusCreated, status)
+	require.NotNil(t, ci.Token)
+	require.True(t, strings.HasPrefix(*ci.Token, pat.Prefix))
+	require.WithinDuration(t, time.Now().Add(30*24*time.Hour), ci.ExpiresAt, time.Minute)
+
+	t.Run("token authenticates within its scopes", func(t *testing.T) {
+		var user gen.UserInfo
+		require.Equal(t, http.StatusOK, doRequest(t, srv, http.MethodGet, "/users/1", *ci.Token, nil, &user))
+		require.Equal(t, admin.Username, user.Username)
+
+		// admins may manage roles, the token may not
+		require.Equal(t, http.StatusForbidden, doRequest(t, srv, http.MethodGet, "/admin/users/1/roles", *ci.Token, nil, nil))
+
+		require.Equal(t, http.StatusUnauthorized, doRequest(t, srv, http.MethodGet, "/users/1", pat.Prefix+"forged", nil, nil))
+	})
+
+	t.Run("tokens are listed without values", func(t *testing.T) {
+		listed := list(t)
+		require.Len(t, listed, 1)
+		require.Equal(t, ci.Id, listed[0].Id)
+		require.Equal(t, []string{"users:read"}, listed[0].Scopes)
+		require.Nil(t, listed[0].Token)
+		require.NotNil(t, listed[0].LastUsedAt)
+	})
+
+	t.Run("invalid requests", func(t *testing.T) {
+		for name, req := range map[string]gen.PersonalAccessTokenRequest{
+			"no name":        {Scopes: []string{"users:read"}},
+			"no scopes":      {Name: "ci"},
+			"unknown scope":  {Name: "ci", Scopes: []string{"root"}},
+			"expired":        {Name: "ci", Scopes: []string{"users:read"}, ExpiresAt: ptr(time.Now().Add(-time.Hour))},
+			"too long-lived": {Name: "ci", Scopes: []string{"users:read"}, ExpiresAt: ptr(time.Now().Add(400 * 24 * time.Hour))},
+		} {
+			_, status := create(t, tokens.AccessToken, req)
+			require.Equal(t, http.StatusBadRequest, status, name)
+		}
+	})
+
+	t.Run("tokens can't create tokens", func(t *testing.T) {
+		_, status := create(t, *ci.Token, gen.PersonalAccessTokenRequest{Name: "more", Scopes: []string{"users:read"}})
+		require.Equal(t, http.StatusForbidden, status)
+	})
+
+	t.Run("routes without a scope reject tokens", func(t *testing.T) {
+		require.Equal(t, http.StatusForbidden, doRequest(t, srv, http.MethodGet, "/identities", *ci.Token, nil, nil))
+		require.Equal(t, http.StatusForbidden, doRequest(t, srv, http.MethodPost, "/webauthn/register/begin", *ci.Token, nil, nil))
+		require.Equal(t, http.StatusForbidden, doRequest(t, srv, http.MethodDelete, "/personal-access-tokens/"+ci.Id, *ci.Token, nil, nil))
+		require.Len(t, list(t), 1)
+	})
+
+	t.Run("scopes of users follow their roles", func(t *testing.T) {
+		bob := gen.LoginUserRequest{Username: "bob@example.com", Password: "rLy_5tr0nG!"}
+		require.Equal(t, http.StatusCreated, doJSON(t, srv, "/register", "", bob, nil))
+
+		var bobTokens gen.LoginUserResponse
+		require.Equal(t, http.StatusOK, doJSON(t, srv, "/login", "", bob, &bobTokens))
+
+		_, status := create(t, bobTokens.AccessToken, gen.PersonalAccessTokenRequest{Name: "ci", Scopes: []string{"users:read"}})
+		require.Equal(t, http.StatusBadRequest, status)
+
+		// tokens of other users aren't revoked
+		require.Equal(t, http.StatusNotFound, doRequest(t, srv, http.MethodDelete, "/personal-access-tokens/"+ci.Id, bobTokens.AccessToken, nil, nil))
+	})
+
+	t.Run("revoked tokens are rejected", func(t *testing.T) {
+		require.Equal(t, http.StatusNoContent, doRequest(t, srv, http.MethodDelete, "/personal-access-tokens/"+ci.Id, tokens.AccessToken, nil, nil))
+		require.Empty(t, list(t))
+
+		require.Equal(t, http.StatusUnauthorized, doRequest(t, srv, http.MethodGet, "/users/1", *ci.Token, nil, nil))
+		require.Equal(t, http.StatusNotFound, doRequest(t, srv, http.MethodDelete, "/personal-access-tokens/"+ci.Id, tokens.AccessToken, nil, nil))
+	})
+}
+
+func TestPersonalAccessTokenEvents(t *testing.T) {
+	bus := events.NewBus(8)
+	srv := newTestServerWithEvents(t, bus)
+	sub := bus.Subscribe(nil)
+
+	user := gen.LoginUserRequest{Username: "alice@example.com", Password: "rLy_5tr0nG!"}
+	require.Equal(t, http.StatusCreated, doJSON(t, srv, "/register", "", user, nil))
+
+	var tokens gen.LoginUserResponse
+	require.Equal(t, http.StatusOK, doJSON(t, srv, "/login", "", user, &tokens))
+
+	var token gen.PersonalAccessToken
+	require.Equal(t, http.StatusCreated, doJSON(t, srv, "/personal-access-tokens", tokens.AccessToken,
+		gen.PersonalAccessTokenRequest{Name: "ci", Scopes: []string{"profile:read"}}, &token))
+	require.Equal(t, http.StatusNoContent, doRequest(t, srv, http.MethodDelete, "/personal-access-tokens/"+token.Id, tokens.AccessToken, nil, nil))
+
+	for _, typ := range []entity.SecurityEventType{
+		entity.SecurityEventLogin,
+		entity.SecurityEventAccessTokenCreated,
+		entity.SecurityEventAccessTokenRevoked,
+	} {
+		e := <-sub.Events()
+		require.Equal(t, typ, e.Type)
+		require.Equal(t, user.Username, e.Username)
+	}
+	require.Empty(t, sub.Events())
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
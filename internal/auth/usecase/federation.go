@@ -0,0 +1,223 @@
+package usecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/bogatyr285/auth-go/internal/auth/entity"
+	"github.com/bogatyr285/auth-go/internal/auth/oauth"
+	"github.com/bogatyr285/auth-go/internal/gateway/http/gen"
+	"github.com/bogatyr285/auth-go/pkg/oidc"
+	"github.com/labstack/gommon/log"
+)
+
+const (
+	// federatedLoginTTL - how long the user may take to sign in at the provider
+	federatedLoginTTL = 10 * time.Minute
+
+	// linkCookie - state of the link flow kept by the browser which started it, so the
+	// callback can't be completed in another browser with a state obtained elsewhere
+	linkCookie     = "federation_link"
+	linkCookiePath = "/federation/callback"
+)
+
+var (
+	errUsernameTaken   = fmt.Errorf("an account with the username already exists")
+	errUnknownProvider = fmt.Errorf("unknown provider")
+)
+
+// IdentityProvider - external OpenID Connect provider users may sign in with
+type IdentityProvider interface {
+	AuthCodeURL(ctx context.Context, state, nonce, codeChallenge string) (string, error)
+	// Exchange - claims of the ID token issued for the code, validated against the provider's keys
+	Exchange(ctx context.Context, code, codeVerifier, nonce string) (oidc.Claims, error)
+}
+
+// WithFederation - enables login with external providers, keyed by the name used in
+// /federation/login?provider=
+func WithFederation(providers map[string]IdentityProvider) Option {
+	return func(u *AuthUseCase) {
+		u.idps = providers
+	}
+}
+
+func (u AuthUseCase) GetFederationLogin(ctx context.Context, request gen.GetFederationLoginRequestObject) (gen.GetFederationLoginResponseObject, error) {
+	location, _, err := u.startFederatedLogin(ctx, request.Params.Provider, 0)
+	switch {
+	case errors.Is(err, errUnknownProvider):
+		return gen.GetFederationLogin404JSONResponse{Error: err.Error()}, nil
+	case err != nil:
+		log.Errorf("Failed to start login with %s: %s", request.Params.Provider, err)
+		return gen.GetFederationLogin500JSONResponse{Error: "internal error"}, nil
+	}
+
+	return gen.GetFederationLogin302Response{
+		Headers: gen.GetFederationLogin302ResponseHeaders{Location: location},
+	}, nil
+}
+
+func (u AuthUseCase) GetFederationCallback(ctx context.Context, request gen.GetFederationCallbackRequestObject) (gen.GetFederationCallbackResponseObject, error) {
+	login, err := u.ur.TakeFederatedLogin(ctx, oauth.HashSecret(request.Params.State))
+	if err != nil || time.Now().After(login.ExpiresAt) {
+		return gen.GetFederationCallback401JSONResponse{Error: "login is invalid or expired"}, nil
+	}
+
+	if login.UserID != 0 && !linkStarted(request.Params.FederationLink, request.Params.State) {
+		return gen.GetFederationCallback401JSONResponse{Error: "linking wasn't started in this browser"}, nil
+	}
+
+	if request.Params.Error != nil {
+		return gen.GetFederationCallback401JSONResponse{Error: "provider refused sign in: " + *request.Params.Error}, nil
+	}
+
+	idp, ok := u.idps[login.Provider]
+	if !ok || request.Params.Code == nil {
+		return gen.GetFederationCallback401JSONResponse{Error: "unauth"}, nil
+	}
+
+	claims, err := idp.Exchange(ctx, *request.Params.Code, login.CodeVerifier, login.Nonce)
+	if err != nil {
+		log.Errorf("Failed to sign in with %s: %s", login.Provider, err)
+		return gen.GetFederationCallback401JSONResponse{Error: "unauth"}, nil
+	}
+
+	if login.UserID != 0 {
+		return u.linkIdentity(ctx, login, claims)
+	}
+
+	user, err := u.federatedUser(ctx, login.Provider, claims)
+	if err != nil {
+		if errors.Is(err, errUsernameTaken) {
+			return gen.GetFederationCallback409JSONResponse{Error: err.Error()}, nil
+		}
+
+		log.Errorf("Failed to provision user of %s: %s", login.Provider, err)
+		return gen.GetFederationCallback500JSONResponse{Error: "internal error"}, nil
+	}
+
+	session, err := u.accounts.SignIn(ctx, user)
+	if err != nil {
+		return gen.GetFederationCallback500JSONResponse{}, err
+	}
+
+	if session.MFARequired() {
+		return gen.GetFederationCallback202JSONResponse{
+			MfaToken: session.MFAToken,
+			Methods:  session.MFAMethods,
+		}, nil
+	}
+
+	return gen.GetFederationCallback200JSONResponse{
+		AccessToken:  session.AccessToken,
+		RefreshToken: session.RefreshToken,
+	}, nil
+}
+
+// federatedUser - the user linked to the identity, provisioned on first login. Existing
+// accounts are never linked by email, since that would hand them to whoever controls
+// the email at the provider
+func (u AuthUseCase) federatedUser(ctx context.Context, provider string, claims oidc.Claims) (entity.UserAccount, error) {
+	if identity, err := u.ur.GetIdentity(ctx, provider, claims.Subject); err == nil {
+		return u.ur.GetUserById(ctx, identity.UserID)
+	}
+
+	user := entity.UserAccount{
+		Username: provider + ":" + claims.Subject,
+		Name:     claims.Name,
+	}
+	if claims.Email != "" && claims.EmailVerified {
+		user.Username = claims.Email
+		user.Email = claims.Email
+		user.EmailVerified = true
+	}
+
+	exists, err := u.ur.ExistsUserByUsername(ctx, user.Username)
+	if err != nil {
+		return entity.UserAccount{}, err
+	}
+
+	if exists {
+		return entity.UserAccount{}, errUsernameTaken
+	}
+
+	// no password, so the account can't be used with password login
+	userID, err := u.ur.ProvisionFederatedUser(ctx, user, entity.Identity{
+		Provider: provider,
+		Subject:  claims.Subject,
+		Email:    claims.Email,
+	})
+	if err != nil {
+		return entity.UserAccount{}, err
+	}
+
+	return u.ur.GetUserById(ctx, userID)
+}
+
+// startFederatedLogin - saves the state of the login and returns the provider's
+// authorization URL with the state, userID is set when the identity is being linked
+func (u AuthUseCase) startFederatedLogin(ctx context.Context, provider string, userID int) (string, string, error) {
+	idp, ok := u.idps[provider]
+	if !ok {
+		return "", "", errUnknownProvider
+	}
+
+	var secrets [3]string
+	for i := range secrets {
+		s, err := oauth.GenerateSecret()
+		if err != nil {
+			return "", "", err
+		}
+		secrets[i] = s
+	}
+	state, nonce, verifier := secrets[0], secrets[1], secrets[2]
+
+	err := u.ur.SaveFederatedLogin(ctx, entity.FederatedLogin{
+		StateHash:    oauth.HashSecret(state),
+		Provider:     provider,
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		UserID:       userID,
+		ExpiresAt:    time.Now().Add(federatedLoginTTL),
+	})
+	if err != nil {
+		return "", "", err
+	}
+
+	location, err := idp.AuthCodeURL(ctx, state, nonce, codeChallenge(verifier))
+	if err != nil {
+		return "", "", err
+	}
+
+	return location, state, nil
+}
+
+// linkCookieFor - Set-Cookie of the link flow, Lax so it's sent along with the redirect
+// back from the provider
+func linkCookieFor(state string) string {
+	return (&http.Cookie{
+		Name:     linkCookie,
+		Value:    state,
+		Path:     linkCookiePath,
+		MaxAge:   int(federatedLoginTTL.Seconds()),
+		HttpOnly: true,
+		Secure:   true,
+		SameSite: http.SameSiteLaxMode,
+	}).String()
+}
+
+// linkStarted - the browser has the cookie of the link flow with the state
+func linkStarted(cookie *string, state string) bool {
+	return cookie != nil && subtle.ConstantTimeCompare([]byte(*cookie), []byte(state)) == 1
+}
+
+// codeChallenge - S256 PKCE challenge of the verifier
+func codeChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
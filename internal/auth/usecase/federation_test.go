@@ -0,0 +1,240 @@
+package usecase_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bogatyr285/auth-go/internal/auth/usecase"
+	"github.com/bogatyr285/auth-go/internal/gateway/http/gen"
+	"github.com/bogatyr285/auth-go/pkg/oidc"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	idpClientID     = "auth-go"
+	idpClientSecret = "idp-secret"
+	idpCallbackURL  = "http://localhost:8080/federation/callback"
+)
+
+// mockIdP - OpenID Connect provider issuing ID tokens of the identity set by the test
+type mockIdP struct {
+	*httptest.Server
+	key *rsa.PrivateKey
+
+	mu       sync.Mutex
+	identity jwt.MapClaims
+	// requests - authorization requests by issued code
+	requests map[string]url.Values
+}
+
+func newMockIdP(t *testing.T) *mockIdP {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	idp := &mockIdP{key: key, requests: map[string]url.Values{}}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 idp.URL,
+			"authorization_endpoint": idp.URL + "/authorize",
+			"token_endpoint":         idp.URL + "/token",
+			"jwks_uri":               idp.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": "idp-key",
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("/token", idp.token)
+
+	idp.Server = httptest.NewServer(mux)
+	t.Cleanup(idp.Close)
+
+	return idp
+}
+
+// signIn - the user signs in at the provider, returns the code of the redirect back
+func (idp *mockIdP) signIn(t *testing.T, location string, identity jwt.MapClaims) string {
+	authURL, err := url.Parse(location)
+	require.NoError(t, err)
+	require.Equal(t, idp.URL+"/authorize", authURL.Scheme+"://"+authURL.Host+authURL.Path)
+
+	query := authURL.Query()
+	require.Equal(t, idpClientID, query.Get("client_id"))
+	require.Equal(t, idpCallbackURL, query.Get("redirect_uri"))
+	require.Equal(t, "S256", query.Get("code_challenge_method"))
+
+	idp.mu.Lock()
+	defer idp.mu.Unlock()
+
+	code := uuid.NewString()
+	idp.requests[code] = query
+	idp.identity = identity
+
+	return code
+}
+
+func (idp *mockIdP) token(w http.ResponseWriter, r *http.Request) {
+	idp.mu.Lock()
+	defer idp.mu.Unlock()
+
+	clientID, secret, _ := r.BasicAuth()
+	if clientID != idpClientID || secret != idpClientSecret {
+		w.WriteHeader(http.StatusUnauthorized)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid_client"})
+		return
+	}
+
+	query, ok := idp.requests[r.PostFormValue("code")]
+	delete(idp.requests, r.PostFormValue("code"))
+
+	sum := sha256.Sum256([]byte(r.PostFormValue("code_verifier")))
+	if !ok || base64.RawURLEncoding.EncodeToString(sum[:]) != query.Get("code_challenge") {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	claims := jwt.MapClaims{
+		"iss":   idp.URL,
+		"aud":   idpClientID,
+		"iat":   time.Now().Unix(),
+		"exp":   time.Now().Add(time.Minute).Unix(),
+		"nonce": query.Get("nonce"),
+	}
+	for k, v := range idp.identity {
+		claims[k] = v
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = "idp-key"
+	idToken, err := token.SignedString(idp.key)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(map[string]string{"access_token": "opaque", "token_type": "Bearer", "id_token": idToken})
+}
+
+func TestFederatedLogin(t *testing.T) {
+	idp := newMockIdP(t)
+	srv := newTestServer(t, usecase.WithFederation(map[string]usecase.IdentityProvider{
+		"mock": oidc.NewProvider(oidc.Config{
+			Issuer:       idp.URL,
+			ClientID:     idpClientID,
+			ClientSecret: idpClientSecret,
+			RedirectURL:  idpCallbackURL,
+		}, idp.Client()),
+	}))
+	// redirects to the provider are inspected instead of followed
+	srv.Client().CheckRedirect = func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }
+
+	// login starts the flow and returns the callback query the provider redirects back with
+	login := func(t *testing.T, identity jwt.MapClaims) url.Values {
+		res, err := srv.Client().Get(srv.URL + "/federation/login?provider=mock")
+		require.NoError(t, err)
+		res.Body.Close()
+		require.Equal(t, http.StatusFound, res.StatusCode)
+
+		location := res.Header.Get("Location")
+		state := mustQuery(t, location).Get("state")
+		require.NotEmpty(t, state)
+
+		return url.Values{"state": {state}, "code": {idp.signIn(t, location, identity)}}
+	}
+
+	callback := func(t *testing.T, query url.Values, out interface{}) int {
+		return doRequest(t, srv, http.MethodGet, "/federation/callback?"+query.Encode(), "", nil, out)
+	}
+
+	subjectOf := func(t *testing.T, accessToken string) string {
+		claims := jwt.MapClaims{}
+		_, _, err := jwt.NewParser().ParseUnverified(accessToken, claims)
+		require.NoError(t, err)
+		sub, err := claims.GetSubject()
+		require.NoError(t, err)
+		return sub
+	}
+
+	alice := jwt.MapClaims{"sub": "idp-alice", "email": "alice@corp.example.com", "email_verified": true, "name": "Alice"}
+
+	t.Run("first login provisions the user", func(t *testing.T) {
+		var tokens gen.LoginUserResponse
+		require.Equal(t, http.StatusOK, callback(t, login(t, alice), &tokens))
+		require.NotEmpty(t, tokens.RefreshToken)
+		require.Equal(t, "alice@corp.example.com", subjectOf(t, tokens.AccessToken))
+
+		// the account has no password, empty ones don't match the API spec
+		require.Equal(t, http.StatusBadRequest, doJSON(t, srv, "/login", "", gen.LoginUserRequest{Username: "alice@corp.example.com", Password: ""}, nil))
+		require.Equal(t, http.StatusUnauthorized, doJSON(t, srv, "/login", "", gen.LoginUserRequest{Username: "alice@corp.example.com", Password: "rLy_5tr0nG!"}, nil))
+	})
+
+	t.Run("next login finds the linked user", func(t *testing.T) {
+		renamed := jwt.MapClaims{"sub": "idp-alice", "email": "alice@new.example.com", "email_verified": true}
+
+		var tokens gen.LoginUserResponse
+		require.Equal(t, http.StatusOK, callback(t, login(t, renamed), &tokens))
+		require.Equal(t, "alice@corp.example.com", subjectOf(t, tokens.AccessToken))
+	})
+
+	t.Run("unverified email isn't used as username", func(t *testing.T) {
+		var tokens gen.LoginUserResponse
+		require.Equal(t, http.StatusOK, callback(t, login(t, jwt.MapClaims{"sub": "idp-eve", "email": "eve@example.com"}), &tokens))
+		require.Equal(t, "mock:idp-eve", subjectOf(t, tokens.AccessToken))
+	})
+
+	t.Run("local accounts aren't taken over", func(t *testing.T) {
+		local := gen.LoginUserRequest{Username: "bob@example.com", Password: "rLy_5tr0nG!"}
+		require.Equal(t, http.StatusCreated, doJSON(t, srv, "/register", "", local, nil))
+
+		bob := jwt.MapClaims{"sub": "idp-bob", "email": "bob@example.com", "email_verified": true}
+		require.Equal(t, http.StatusConflict, callback(t, login(t, bob), nil))
+	})
+
+	t.Run("state is accepted once", func(t *testing.T) {
+		query := login(t, alice)
+		require.Equal(t, http.StatusOK, callback(t, query, nil))
+		require.Equal(t, http.StatusUnauthorized, callback(t, query, nil))
+
+		require.Equal(t, http.StatusUnauthorized, callback(t, url.Values{"state": {"forged"}, "code": {"x"}}, nil))
+	})
+
+	t.Run("tokens of another audience are rejected", func(t *testing.T) {
+		other := jwt.MapClaims{"sub": "idp-alice", "aud": "another-client"}
+		require.Equal(t, http.StatusUnauthorized, callback(t, login(t, other), nil))
+	})
+
+	t.Run("provider errors", func(t *testing.T) {
+		query := login(t, alice)
+		query.Del("code")
+		query.Set("error", "access_denied")
+		require.Equal(t, http.StatusUnauthorized, callback(t, query, nil))
+
+		require.Equal(t, http.StatusNotFound, doRequest(t, srv, http.MethodGet, "/federation/login?provider=unknown", "", nil, nil))
+	})
+}
+
+func mustQuery(t *testing.T, rawURL string) url.Values {
+	u, err := url.Parse(rawURL)
+	require.NoError(t, err)
+	return u.Query()
+}
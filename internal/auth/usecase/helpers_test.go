@@ -0,0 +1,134 @@
+package usecase_test
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bogatyr285/auth-go/internal/auth/account"
+	"github.com/bogatyr285/auth-go/internal/auth/events"
+	"github.com/bogatyr285/auth-go/internal/auth/repository"
+	"github.com/bogatyr285/auth-go/internal/auth/usecase"
+	"github.com/bogatyr285/auth-go/internal/buildinfo"
+	"github.com/bogatyr285/auth-go/internal/gateway/http/gen"
+	"github.com/bogatyr285/auth-go/internal/gateway/http/validation"
+	"github.com/bogatyr285/auth-go/pkg/crypto"
+	"github.com/bogatyr285/auth-go/pkg/jwt"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testRPID   = "localhost"
+	testOrigin = "http://localhost:8080"
+)
+
+func newTestJWTManager(t *testing.T) *jwt.JWTManager {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	privDER, err := x509.MarshalPKCS8PrivateKey(priv)
+	require.NoError(t, err)
+	pubDER, err := x509.MarshalPKIXPublicKey(pub)
+	require.NoError(t, err)
+
+	jm, err := jwt.NewJWTManager("auth-service", time.Hour,
+		pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER}),
+		pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privDER}),
+	)
+	require.NoError(t, err)
+
+	return jm
+}
+
+func newTestServer(t *testing.T, opts ...usecase.Option) *httptest.Server {
+	srv, _ := newTestServerWithUseCase(t, opts...)
+	return srv
+}
+
+func newTestServerWithUseCase(t *testing.T, opts ...usecase.Option) (*httptest.Server, usecase.AuthUseCase) {
+	srv, useCase, _ := newTestServerWithStorage(t, opts...)
+	return srv, useCase
+}
+
+// newTestServerWithStorage - the server with the storage behind it, to set up state the API can't
+func newTestServerWithStorage(t *testing.T, opts ...usecase.Option) (*httptest.Server, usecase.AuthUseCase, *repository.SQLLiteStorage) {
+	return startTestServer(t, nil, opts...)
+}
+
+// newTestServerWithEvents - the server publishing logins along with the other events to the bus
+func newTestServerWithEvents(t *testing.T, bus *events.Bus, opts ...usecase.Option) *httptest.Server {
+	srv, _, _ := startTestServer(t, []account.Option{account.WithEvents(bus)}, append(opts, usecase.WithEvents(bus))...)
+	return srv
+}
+
+func startTestServer(t *testing.T, accountOpts []account.Option, opts ...usecase.Option) (*httptest.Server, usecase.AuthUseCase, *repository.SQLLiteStorage) {
+	storage, err := repository.New(filepath.Join(t.TempDir(), "db.sqlite"))
+	require.NoError(t, err)
+	t.Cleanup(func() { storage.Close() })
+
+	wa, err := webauthn.New(&webauthn.Config{
+		RPID:          testRPID,
+		RPDisplayName: "auth-go",
+		RPOrigins:     []string{testOrigin},
+	})
+	require.NoError(t, err)
+
+	jm := newTestJWTManager(t)
+	accounts := account.New(&storage, crypto.NewPasswordHasher(), jm, buildinfo.New(), accountOpts...)
+	useCase := usecase.NewUseCase(&storage, accounts, jm,
+		append([]usecase.Option{usecase.WithWebAuthn(wa)}, opts...)...,
+	)
+
+	spec, err := gen.GetSwagger()
+	require.NoError(t, err)
+	// handlers are checked against the spec as well
+	validator, err := validation.New(spec, slog.New(slog.NewTextHandler(io.Discard, nil)), validation.WithResponseValidation())
+	require.NoError(t, err)
+
+	router := chi.NewRouter()
+	router.Use(useCase.AuthMiddleware)
+	router.Use(validator.Middleware)
+
+	srv := httptest.NewServer(gen.HandlerFromMux(gen.NewStrictHandler(useCase, nil), router))
+	t.Cleanup(srv.Close)
+
+	return srv, useCase, &storage
+}
+
+func doJSON(t *testing.T, srv *httptest.Server, path, token string, body, out interface{}) int {
+	return doRequest(t, srv, http.MethodPost, path, token, body, out)
+}
+
+func doRequest(t *testing.T, srv *httptest.Server, method, path, token string, body, out interface{}) int {
+	payload, err := json.Marshal(body)
+	require.NoError(t, err)
+
+	req, err := http.NewRequest(method, srv.URL+path, bytes.NewReader(payload))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	res, err := srv.Client().Do(req)
+	require.NoError(t, err)
+	defer res.Body.Close()
+
+	if out != nil && res.Header.Get("Content-Type") == "application/json" {
+		require.NoError(t, json.NewDecoder(res.Body).Decode(out))
+	}
+
+	return res.StatusCode
+}
@@ -0,0 +1,159 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/bogatyr285/auth-go/internal/auth/entity"
+	"github.com/bogatyr285/auth-go/internal/gateway/http/gen"
+	"github.com/bogatyr285/auth-go/pkg/oidc"
+	"github.com/labstack/gommon/log"
+)
+
+// reauthMaxAge - how long after signing in the user may change login methods
+const reauthMaxAge = 10 * time.Minute
+
+// recentlyAuthenticated - the access token was issued by a login within reauthMaxAge
+func recentlyAuthenticated(ctx context.Context) bool {
+	authTime, ok := ctx.Value(authTimeCtxKey).(time.Time)
+	return ok && time.Since(authTime) < reauthMaxAge
+}
+
+func (u AuthUseCase) currentUser(ctx context.Context) (entity.UserAccount, bool) {
+	sub, ok := subjectFromContext(ctx)
+	if !ok {
+		return entity.UserAccount{}, false
+	}
+
+	user, err := u.ur.FindUserByEmail(ctx, sub)
+	if err != nil {
+		return entity.UserAccount{}, false
+	}
+
+	return user, true
+}
+
+func (u AuthUseCase) GetIdentities(ctx context.Context, request gen.GetIdentitiesRequestObject) (gen.GetIdentitiesResponseObject, error) {
+	user, ok := u.currentUser(ctx)
+	if !ok {
+		return gen.GetIdentities401JSONResponse{Error: "unauth"}, nil
+	}
+
+	methods, err := u.ur.ListLoginMethods(ctx, user.ID)
+	if err != nil {
+		log.Errorf("Failed to list login methods: %s", err)
+		return gen.GetIdentities500JSONResponse{Error: "internal error"}, nil
+	}
+
+	identities := make([]gen.Identity, 0, len(methods))
+	for _, m := range methods {
+		identity := gen.Identity{Provider: m.Provider, Subject: m.Subject, CreatedAt: m.CreatedAt}
+		if m.Email != "" {
+			identity.Email = &m.Email
+		}
+		identities = append(identities, identity)
+	}
+
+	return gen.GetIdentities200JSONResponse{Identities: identities}, nil
+}
+
+func (u AuthUseCase) DeleteIdentitiesProviderSubject(ctx context.Context, request gen.DeleteIdentitiesProviderSubjectRequestObject) (gen.DeleteIdentitiesProviderSubjectResponseObject, error) {
+	user, ok := u.currentUser(ctx)
+	if !ok {
+		return gen.DeleteIdentitiesProviderSubject401JSONResponse{Error: "unauth"}, nil
+	}
+
+	if !recentlyAuthenticated(ctx) {
+		return gen.DeleteIdentitiesProviderSubject403JSONResponse{Error: "sign in again to change login methods"}, nil
+	}
+
+	err := u.ur.UnlinkLoginMethod(ctx, user.ID, request.Provider, request.Subject)
+	switch {
+	case errors.Is(err, entity.ErrIdentityNotFound):
+		return gen.DeleteIdentitiesProviderSubject404JSONResponse{Error: err.Error()}, nil
+	case errors.Is(err, entity.ErrLastLoginMethod):
+		return gen.DeleteIdentitiesProviderSubject409JSONResponse{Error: err.Error()}, nil
+	case err != nil:
+		log.Errorf("Failed to unlink login method: %s", err)
+		return gen.DeleteIdentitiesProviderSubject500JSONResponse{Error: "internal error"}, nil
+	}
+
+	e := entity.SecurityEvent{
+		Type:     entity.SecurityEventIdentityUnlinked,
+		UserID:   user.ID,
+		Username: user.Username,
+		Provider: request.Provider,
+	}
+	if request.Provider == entity.IdentityPassword {
+		e.Type = entity.SecurityEventPasswordRemoved
+	}
+	u.publish(e)
+
+	return gen.DeleteIdentitiesProviderSubject204Response{}, nil
+}
+
+// PostFederationLink - the provider's URL is returned instead of a redirect, so the
+// page calling it with the access token can send the browser there along with the cookie
+func (u AuthUseCase) PostFederationLink(ctx context.Context, request gen.PostFederationLinkRequestObject) (gen.PostFederationLinkResponseObject, error) {
+	user, ok := u.currentUser(ctx)
+	if !ok {
+		return gen.PostFederationLink401JSONResponse{Error: "unauth"}, nil
+	}
+
+	if !recentlyAuthenticated(ctx) {
+		return gen.PostFederationLink403JSONResponse{Error: "sign in again to change login methods"}, nil
+	}
+
+	location, state, err := u.startFederatedLogin(ctx, request.Params.Provider, user.ID)
+	switch {
+	case errors.Is(err, errUnknownProvider):
+		return gen.PostFederationLink404JSONResponse{Error: err.Error()}, nil
+	case err != nil:
+		log.Errorf("Failed to start linking %s: %s", request.Params.Provider, err)
+		return gen.PostFederationLink500JSONResponse{Error: "internal error"}, nil
+	}
+
+	return gen.PostFederationLink200JSONResponse{
+		Body:    gen.FederationLinkResponse{Location: location},
+		Headers: gen.PostFederationLink200ResponseHeaders{SetCookie: linkCookieFor(state)},
+	}, nil
+}
+
+// linkIdentity - links the identity to the user who started the login, identities of
+// other accounts stay where they are
+func (u AuthUseCase) linkIdentity(ctx context.Context, login entity.FederatedLogin, claims oidc.Claims) (gen.GetFederationCallbackResponseObject, error) {
+	if identity, err := u.ur.GetIdentity(ctx, login.Provider, claims.Subject); err == nil {
+		if identity.UserID != login.UserID {
+			return gen.GetFederationCallback409JSONResponse{Error: "the identity is linked to another account"}, nil
+		}
+
+		return gen.GetFederationCallback204Response{}, nil
+	}
+
+	user, err := u.ur.GetUserById(ctx, login.UserID)
+	if err != nil {
+		log.Errorf("Failed to find user linking %s: %s", login.Provider, err)
+		return gen.GetFederationCallback500JSONResponse{Error: "internal error"}, nil
+	}
+
+	err = u.ur.LinkIdentity(ctx, entity.Identity{
+		Provider: login.Provider,
+		Subject:  claims.Subject,
+		UserID:   login.UserID,
+		Email:    claims.Email,
+	})
+	if err != nil {
+		log.Errorf("Failed to link identity of %s: %s", login.Provider, err)
+		return gen.GetFederationCallback500JSONResponse{Error: "internal error"}, nil
+	}
+
+	u.publish(entity.SecurityEvent{
+		Type:     entity.SecurityEventIdentityLinked,
+		UserID:   login.UserID,
+		Username: user.Username,
+		Provider: login.Provider,
+	})
+
+	return gen.GetFederationCallback204Response{}, nil
+}
@@ -0,0 +1,234 @@
+package usecase_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"testing"
+
+	"github.com/bogatyr285/auth-go/internal/auth/entity"
+	"github.com/bogatyr285/auth-go/internal/auth/events"
+	"github.com/bogatyr285/auth-go/internal/auth/usecase"
+	"github.com/bogatyr285/auth-go/internal/gateway/http/gen"
+	"github.com/bogatyr285/auth-go/pkg/oidc"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccountLinking(t *testing.T) {
+	idp := newMockIdP(t)
+	bus := events.NewBus(8)
+	srv := newTestServerWithEvents(t, bus, usecase.WithFederation(map[string]usecase.IdentityProvider{
+		"mock": oidc.NewProvider(oidc.Config{
+			Issuer:       idp.URL,
+			ClientID:     idpClientID,
+			ClientSecret: idpClientSecret,
+			RedirectURL:  idpCallbackURL,
+		}, idp.Client()),
+	}))
+	// changes of login methods, logins are left out
+	sub := bus.Subscribe(func(e entity.SecurityEvent) bool { return e.Type != entity.SecurityEventLogin })
+	srv.Client().CheckRedirect = func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }
+
+	// redirect starts the flow at path and returns the callback the provider redirects back to,
+	// with the cookies the browser got when starting it. Linking is started with a POST
+	// answered with the provider's URL, login with a GET redirected there
+	redirect := func(t *testing.T, path, token string, identity jwt.MapClaims) *http.Request {
+		method := http.MethodGet
+		if token != "" {
+			method = http.MethodPost
+		}
+
+		req, err := http.NewRequest(method, srv.URL+path, nil)
+		require.NoError(t, err)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+
+		res, err := srv.Client().Do(req)
+		require.NoError(t, err)
+		defer res.Body.Close()
+
+		location := res.Header.Get("Location")
+		if method == http.MethodPost {
+			var link gen.FederationLinkResponse
+			require.Equal(t, http.StatusOK, res.StatusCode)
+			require.NoError(t, json.NewDecoder(res.Body).Decode(&link))
+			location = link.Location
+		} else {
+			require.Equal(t, http.StatusFound, res.StatusCode)
+		}
+
+		query := url.Values{"state": {mustQuery(t, location).Get("state")}, "code": {idp.signIn(t, location, identity)}}
+
+		req, err = http.NewRequest(http.MethodGet, srv.URL+"/federation/callback?"+query.Encode(), nil)
+		require.NoError(t, err)
+		for _, c := range res.Cookies() {
+			req.AddCookie(c)
+		}
+
+		return req
+	}
+
+	callback := func(t *testing.T, req *http.Request, out interface{}) int {
+		res, err := srv.Client().Do(req)
+		require.NoError(t, err)
+		defer res.Body.Close()
+
+		if out != nil {
+			require.NoError(t, json.NewDecoder(res.Body).Decode(out))
+		}
+
+		return res.StatusCode
+	}
+
+	identitiesOf := func(t *testing.T, token string) []gen.Identity {
+		var res gen.IdentitiesResponse
+		require.Equal(t, http.StatusOK, doRequest(t, srv, http.MethodGet, "/identities", token, nil, &res))
+		return res.Identities
+	}
+
+	carol := gen.LoginUserRequest{Username: "carol@example.com", Password: "rLy_5tr0nG!"}
+	require.Equal(t, http.StatusCreated, doJSON(t, srv, "/register", "", carol, nil))
+
+	var tokens gen.LoginUserResponse
+	require.Equal(t, http.StatusOK, doJSON(t, srv, "/login", "", carol, &tokens))
+
+	idpCarol := jwt.MapClaims{"sub": "idp-carol", "email": "carol@corp.example.com", "email_verified": true}
+
+	t.Run("password is listed", func(t *testing.T) {
+		identities := identitiesOf(t, tokens.AccessToken)
+		require.Len(t, identities, 1)
+		require.Equal(t, "password", identities[0].Provider)
+		require.Equal(t, carol.Username, identities[0].Subject)
+
+		require.Equal(t, http.StatusUnauthorized, doRequest(t, srv, http.MethodGet, "/identities", "", nil, nil))
+	})
+
+	t.Run("last login method is kept", func(t *testing.T) {
+		require.Equal(t, http.StatusConflict, doRequest(t, srv, http.MethodDelete, "/identities/password/"+carol.Username, tokens.AccessToken, nil, nil))
+	})
+
+	t.Run("identity is linked", func(t *testing.T) {
+		require.Equal(t, http.StatusNoContent, callback(t, redirect(t, "/federation/link?provider=mock", tokens.AccessToken, idpCarol), nil))
+
+		identities := identitiesOf(t, tokens.AccessToken)
+		require.Len(t, identities, 2)
+		require.Equal(t, "mock", identities[1].Provider)
+		require.Equal(t, "idp-carol", identities[1].Subject)
+
+		e := <-sub.Events()
+		require.Equal(t, entity.SecurityEventIdentityLinked, e.Type)
+		require.Equal(t, carol.Username, e.Username)
+		require.Equal(t, "mock", e.Provider)
+
+		// the provider signs in to the same account
+		var federated gen.LoginUserResponse
+		require.Equal(t, http.StatusOK, callback(t, redirect(t, "/federation/login?provider=mock", "", idpCarol), &federated))
+		require.Len(t, identitiesOf(t, federated.AccessToken), 2)
+
+		// linking again changes nothing
+		require.Equal(t, http.StatusNoContent, callback(t, redirect(t, "/federation/link?provider=mock", tokens.AccessToken, idpCarol), nil))
+		require.Empty(t, sub.Events())
+	})
+
+	t.Run("identities of other accounts aren't linked", func(t *testing.T) {
+		idpDave := jwt.MapClaims{"sub": "idp-dave", "email": "dave@corp.example.com", "email_verified": true}
+		require.Equal(t, http.StatusOK, callback(t, redirect(t, "/federation/login?provider=mock", "", idpDave), nil))
+
+		require.Equal(t, http.StatusConflict, callback(t, redirect(t, "/federation/link?provider=mock", tokens.AccessToken, idpDave), nil))
+		require.Len(t, identitiesOf(t, tokens.AccessToken), 2)
+	})
+
+	t.Run("linking is finished in the browser which started it", func(t *testing.T) {
+		req := redirect(t, "/federation/link?provider=mock", tokens.AccessToken, jwt.MapClaims{"sub": "idp-erin"})
+		res, err := srv.Client().Get(req.URL.String())
+		require.NoError(t, err)
+		res.Body.Close()
+		require.Equal(t, http.StatusUnauthorized, res.StatusCode)
+
+		// the state is used up by the attempt
+		require.Equal(t, http.StatusUnauthorized, callback(t, req, nil))
+		require.Len(t, identitiesOf(t, tokens.AccessToken), 2)
+	})
+
+	t.Run("refreshed tokens need to sign in again", func(t *testing.T) {
+		var refreshed gen.TokenResponse
+		require.Equal(t, http.StatusOK, doJSON(t, srv, "/refresh", "", gen.TokenRequest{RefreshToken: tokens.RefreshToken}, &refreshed))
+
+		require.Equal(t, http.StatusForbidden, doRequest(t, srv, http.MethodPost, "/federation/link?provider=mock", refreshed.AccessToken, nil, nil))
+		require.Equal(t, http.StatusForbidden, doRequest(t, srv, http.MethodDelete, "/identities/mock/idp-carol", refreshed.AccessToken, nil, nil))
+	})
+
+	t.Run("login methods are unlinked", func(t *testing.T) {
+		require.Equal(t, http.StatusNotFound, doRequest(t, srv, http.MethodDelete, "/identities/mock/idp-dave", tokens.AccessToken, nil, nil))
+		require.Equal(t, http.StatusNotFound, doRequest(t, srv, http.MethodPost, "/federation/link?provider=unknown", tokens.AccessToken, nil, nil))
+
+		require.Equal(t, http.StatusNoContent, doRequest(t, srv, http.MethodDelete, "/identities/mock/idp-carol", tokens.AccessToken, nil, nil))
+		require.Len(t, identitiesOf(t, tokens.AccessToken), 1)
+
+		e := <-sub.Events()
+		require.Equal(t, entity.SecurityEventIdentityUnlinked, e.Type)
+		require.Equal(t, carol.Username, e.Username)
+		require.Equal(t, "mock", e.Provider)
+		require.Equal(t, http.StatusConflict, doRequest(t, srv, http.MethodDelete, "/identities/password/"+carol.Username, tokens.AccessToken, nil, nil))
+
+		// the provider no longer signs in to the account
+		var federated gen.LoginUserResponse
+		require.Equal(t, http.StatusOK, callback(t, redirect(t, "/federation/login?provider=mock", "", idpCarol), &federated))
+		identities := identitiesOf(t, federated.AccessToken)
+		require.Len(t, identities, 1)
+		require.Equal(t, "mock", identities[0].Provider)
+	})
+
+	t.Run("linked from a browser", func(t *testing.T) {
+		jar, err := cookiejar.New(nil)
+		require.NoError(t, err)
+		browser := &http.Client{Jar: httpsJar{jar}}
+
+		// the page's script starts linking with the access token it holds
+		req, err := http.NewRequest(http.MethodPost, srv.URL+"/federation/link?provider=mock", nil)
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer "+tokens.AccessToken)
+
+		res, err := browser.Do(req)
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+
+		var link gen.FederationLinkResponse
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&link))
+
+		// and sends the browser to the provider, which redirects it back to the callback
+		// without the access token, only the cookie tells who started linking
+		identity := jwt.MapClaims{"sub": "idp-carol-work"}
+		query := url.Values{"state": {mustQuery(t, link.Location).Get("state")}, "code": {idp.signIn(t, link.Location, identity)}}
+		res, err = browser.Get(srv.URL + "/federation/callback?" + query.Encode())
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.Equal(t, http.StatusNoContent, res.StatusCode)
+
+		require.Len(t, identitiesOf(t, tokens.AccessToken), 2)
+	})
+}
+
+// httpsJar - keeps cookies of the plain HTTP test server as if it was served over HTTPS,
+// so the Secure cookies are sent back the way a browser does in a deployment
+type httpsJar struct {
+	http.CookieJar
+}
+
+func (j httpsJar) SetCookies(u *url.URL, cookies []*http.Cookie) {
+	j.CookieJar.SetCookies(withHTTPS(u), cookies)
+}
+
+func (j httpsJar) Cookies(u *url.URL) []*http.Cookie {
+	return j.CookieJar.Cookies(withHTTPS(u))
+}
+
+func withHTTPS(u *url.URL) *url.URL {
+	secure := *u
+	secure.Scheme = "https"
+	return &secure
+}
@@ -0,0 +1,151 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/bogatyr285/auth-go/internal/auth/entity"
+	"github.com/bogatyr285/auth-go/internal/gateway/http/gen"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/labstack/gommon/log"
+)
+
+const tokenUseMagicLink = "magic_link"
+
+type Notifier interface {
+	Notify(ctx context.Context, to, subject, body string) error
+}
+
+// MagicLinkSettings - passwordless login with a link sent by email
+type MagicLinkSettings struct {
+	Notifier Notifier
+	// LinkURL - token is added to its query
+	LinkURL string
+	TTL     time.Duration
+	// Limit - links per address within Window, zero disables throttling
+	Limit  int
+	Window time.Duration
+}
+
+// WithMagicLink - enables magic link login
+func WithMagicLink(s MagicLinkSettings) Option {
+	return func(u *AuthUseCase) {
+		u.ml = s
+	}
+}
+
+func (u AuthUseCase) PostMagicLink(ctx context.Context, request gen.PostMagicLinkRequestObject) (gen.PostMagicLinkResponseObject, error) {
+	if u.ml.Notifier == nil {
+		return gen.PostMagicLink500JSONResponse{Error: "magic link login is not configured"}, nil
+	}
+
+	email := strings.TrimSpace(request.Body.Email)
+	if email == "" {
+		return gen.PostMagicLink400JSONResponse{Error: "email is required"}, nil
+	}
+
+	now := time.Now()
+	if u.ml.Limit > 0 {
+		count, err := u.ur.CountMagicLinksSince(ctx, email, now.Add(-u.ml.Window))
+		if err != nil {
+			log.Errorf("Failed to count magic links: %s", err)
+			return gen.PostMagicLink500JSONResponse{Error: "internal error"}, nil
+		}
+
+		if count >= u.ml.Limit {
+			return gen.PostMagicLink429JSONResponse{Error: "too many links requested, try again later"}, nil
+		}
+	}
+
+	// requests for unknown addresses are recorded as well, so they are throttled the same way
+	link := entity.MagicLink{
+		ID:        uuid.NewString(),
+		Email:     email,
+		CreatedAt: now,
+		ExpiresAt: now.Add(u.ml.TTL),
+	}
+	if err := u.ur.SaveMagicLink(ctx, link); err != nil {
+		log.Errorf("Failed to save magic link: %s", err)
+		return gen.PostMagicLink500JSONResponse{Error: "internal error"}, nil
+	}
+
+	// links are sent only to addresses users proved to own, other accounts could be
+	// registered by anyone with the address as username
+	if _, err := u.ur.FindUserByVerifiedEmail(ctx, email); err != nil {
+		return gen.PostMagicLink202Response{}, nil
+	}
+
+	token, err := u.jm.IssueTokenWithClaims(email, jwt.MapClaims{
+		tokenUseClaim: tokenUseMagicLink,
+		"jti":         link.ID,
+		"exp":         link.ExpiresAt.Unix(),
+	})
+	if err != nil {
+		return gen.PostMagicLink500JSONResponse{}, err
+	}
+
+	linkURL, err := url.Parse(u.ml.LinkURL)
+	if err != nil {
+		return gen.PostMagicLink500JSONResponse{}, err
+	}
+	query := linkURL.Query()
+	query.Set("token", token)
+	linkURL.RawQuery = query.Encode()
+
+	body := fmt.Sprintf("Follow the link to log in:\n\n%s\n\nThe link expires in %s and works only once.", linkURL, u.ml.TTL)
+	if err = u.ml.Notifier.Notify(ctx, email, "Your login link", body); err != nil {
+		log.Errorf("Failed to send magic link: %s", err)
+		return gen.PostMagicLink500JSONResponse{Error: "failed to send link"}, nil
+	}
+
+	return gen.PostMagicLink202Response{}, nil
+}
+
+func (u AuthUseCase) PostMagicLinkConsume(ctx context.Context, request gen.PostMagicLinkConsumeRequestObject) (gen.PostMagicLinkConsumeResponseObject, error) {
+	claims, err := u.verifyTokenUse(request.Body.Token, tokenUseMagicLink)
+	if err != nil {
+		return gen.PostMagicLinkConsume401JSONResponse{Error: "unauth"}, nil
+	}
+
+	jti, _ := claims["jti"].(string)
+	email, _ := claims.GetSubject()
+	if jti == "" || email == "" {
+		return gen.PostMagicLinkConsume401JSONResponse{Error: "unauth"}, nil
+	}
+
+	link, err := u.ur.ConsumeMagicLink(ctx, jti)
+	if err != nil {
+		log.Errorf("Failed to consume magic link: %s", err)
+		return gen.PostMagicLinkConsume401JSONResponse{Error: "unauth"}, nil
+	}
+
+	if link.Email != email || time.Now().After(link.ExpiresAt) {
+		return gen.PostMagicLinkConsume401JSONResponse{Error: "unauth"}, nil
+	}
+
+	user, err := u.ur.FindUserByVerifiedEmail(ctx, email)
+	if err != nil {
+		return gen.PostMagicLinkConsume401JSONResponse{Error: "unauth"}, nil
+	}
+
+	session, err := u.accounts.SignIn(ctx, user)
+	if err != nil {
+		return gen.PostMagicLinkConsume500JSONResponse{}, err
+	}
+
+	if session.MFARequired() {
+		return gen.PostMagicLinkConsume202JSONResponse{
+			MfaToken: session.MFAToken,
+			Methods:  session.MFAMethods,
+		}, nil
+	}
+
+	return gen.PostMagicLinkConsume200JSONResponse{
+		AccessToken:  session.AccessToken,
+		RefreshToken: session.RefreshToken,
+	}, nil
+}
@@ -0,0 +1,84 @@
+package usecase_test
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/bogatyr285/auth-go/internal/auth/usecase"
+	"github.com/bogatyr285/auth-go/internal/gateway/http/gen"
+	"github.com/stretchr/testify/require"
+)
+
+// inbox - notifier which keeps sent messages
+type inbox struct {
+	messages map[string][]string
+}
+
+func (i *inbox) Notify(_ context.Context, to, _, body string) error {
+	i.messages[to] = append(i.messages[to], body)
+	return nil
+}
+
+var linkRe = regexp.MustCompile(`https?://\S+`)
+
+func (i *inbox) lastLinkToken(t *testing.T, to string) string {
+	messages := i.messages[to]
+	require.NotEmpty(t, messages)
+
+	link, err := url.Parse(linkRe.FindString(messages[len(messages)-1]))
+	require.NoError(t, err)
+
+	return link.Query().Get("token")
+}
+
+func TestMagicLink(t *testing.T) {
+	mailbox := &inbox{messages: map[string][]string{}}
+	srv, _, storage := newTestServerWithStorage(t, usecase.WithMagicLink(usecase.MagicLinkSettings{
+		Notifier: mailbox,
+		LinkURL:  "http://localhost:8080/magic-link",
+		TTL:      time.Minute,
+		Limit:    2,
+		Window:   time.Hour,
+	}))
+
+	// the username isn't the address, the link goes to the verified email
+	email := "bob@example.com"
+	credentials := gen.LoginUserRequest{Username: "bob", Password: "rLy_5tr0nG!"}
+	require.Equal(t, http.StatusCreated, doJSON(t, srv, "/register", "", credentials, nil))
+	bob, err := storage.FindUserByEmail(context.Background(), credentials.Username)
+	require.NoError(t, err)
+	require.NoError(t, storage.SetEmailVerified(context.Background(), bob.ID, email))
+
+	require.Equal(t, http.StatusAccepted, doJSON(t, srv, "/magic-link", "", gen.MagicLinkRequest{Email: email}, nil))
+	token := mailbox.lastLinkToken(t, email)
+
+	var tokens gen.LoginUserResponse
+	require.Equal(t, http.StatusOK, doJSON(t, srv, "/magic-link/consume", "", gen.MagicLinkConsumeRequest{Token: token}, &tokens))
+	require.NotEmpty(t, tokens.AccessToken)
+	require.NotEmpty(t, tokens.RefreshToken)
+
+	// link works only once
+	require.Equal(t, http.StatusUnauthorized, doJSON(t, srv, "/magic-link/consume", "", gen.MagicLinkConsumeRequest{Token: token}, nil))
+
+	// link token is not an access token and access token is not a link token
+	require.Equal(t, http.StatusUnauthorized, doJSON(t, srv, "/webauthn/register/begin", token, nil, nil))
+	require.Equal(t, http.StatusUnauthorized, doJSON(t, srv, "/magic-link/consume", "", gen.MagicLinkConsumeRequest{Token: tokens.AccessToken}, nil))
+
+	// unknown address gets the same answer, but nothing is sent
+	require.Equal(t, http.StatusAccepted, doJSON(t, srv, "/magic-link", "", gen.MagicLinkRequest{Email: "nobody@example.com"}, nil))
+	require.Empty(t, mailbox.messages["nobody@example.com"])
+
+	// accounts named after the address without owning it don't get links
+	squatter := gen.LoginUserRequest{Username: "carol@example.com", Password: "rLy_5tr0nG!"}
+	require.Equal(t, http.StatusCreated, doJSON(t, srv, "/register", "", squatter, nil))
+	require.Equal(t, http.StatusAccepted, doJSON(t, srv, "/magic-link", "", gen.MagicLinkRequest{Email: squatter.Username}, nil))
+	require.Empty(t, mailbox.messages[squatter.Username])
+
+	require.Equal(t, http.StatusAccepted, doJSON(t, srv, "/magic-link", "", gen.MagicLinkRequest{Email: email}, nil))
+	require.Equal(t, http.StatusTooManyRequests, doJSON(t, srv, "/magic-link", "", gen.MagicLinkRequest{Email: email}, nil))
+	require.Len(t, mailbox.messages[email], 2)
+}
@@ -0,0 +1,226 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/bogatyr285/auth-go/internal/auth/entity"
+	"github.com/bogatyr285/auth-go/internal/auth/oauth"
+	"github.com/bogatyr285/auth-go/internal/gateway/http/gen"
+	"github.com/google/uuid"
+	"github.com/labstack/gommon/log"
+)
+
+// defaultSecretOverlap - how long previous secrets of a client stay valid after rotation
+const defaultSecretOverlap = 24 * time.Hour
+
+func (u AuthUseCase) GetAdminOauthClients(ctx context.Context, request gen.GetAdminOauthClientsRequestObject) (gen.GetAdminOauthClientsResponseObject, error) {
+	clients, err := u.ur.ListOAuthClients(ctx)
+	if err != nil {
+		log.Errorf("Failed to list oauth clients: %s", err)
+		return gen.GetAdminOauthClients500JSONResponse{Error: "internal error"}, nil
+	}
+
+	res := make(gen.GetAdminOauthClients200JSONResponse, 0, len(clients))
+	for _, c := range clients {
+		res = append(res, oauthClient(c))
+	}
+
+	return res, nil
+}
+
+func (u AuthUseCase) PostAdminOauthClients(ctx context.Context, request gen.PostAdminOauthClientsRequestObject) (gen.PostAdminOauthClientsResponseObject, error) {
+	client := entity.OAuthClient{
+		ID:           uuid.NewString(),
+		Name:         strings.TrimSpace(request.Body.Name),
+		RedirectURIs: request.Body.RedirectUris,
+	}
+	if request.Body.Confidential != nil {
+		client.Confidential = *request.Body.Confidential
+	}
+	if request.Body.Scopes != nil {
+		client.Scopes = *request.Body.Scopes
+	}
+
+	if err := validateOAuthClient(client); err != nil {
+		return gen.PostAdminOauthClients400JSONResponse{Error: err.Error()}, nil
+	}
+
+	if err := u.ur.SaveOAuthClient(ctx, client); err != nil {
+		log.Errorf("Failed to save oauth client: %s", err)
+		return gen.PostAdminOauthClients500JSONResponse{Error: "internal error"}, nil
+	}
+
+	res := oauthClient(client)
+	if client.Confidential {
+		secret, err := u.addClientSecret(ctx, client.ID, time.Now())
+		if err != nil {
+			log.Errorf("Failed to add client secret: %s", err)
+			return gen.PostAdminOauthClients500JSONResponse{Error: "internal error"}, nil
+		}
+
+		res.ClientSecret = &secret
+	}
+
+	return gen.PostAdminOauthClients201JSONResponse(res), nil
+}
+
+func (u AuthUseCase) GetAdminOauthClientsClientId(ctx context.Context, request gen.GetAdminOauthClientsClientIdRequestObject) (gen.GetAdminOauthClientsClientIdResponseObject, error) {
+	client, err := u.ur.GetOAuthClient(ctx, request.ClientId)
+	if err != nil {
+		return gen.GetAdminOauthClientsClientId404JSONResponse{Error: "client not found"}, nil
+	}
+
+	return gen.GetAdminOauthClientsClientId200JSONResponse(oauthClient(client)), nil
+}
+
+func (u AuthUseCase) PutAdminOauthClientsClientId(ctx context.Context, request gen.PutAdminOauthClientsClientIdRequestObject) (gen.PutAdminOauthClientsClientIdResponseObject, error) {
+	client, err := u.ur.GetOAuthClient(ctx, request.ClientId)
+	if err != nil {
+		return gen.PutAdminOauthClientsClientId404JSONResponse{Error: "client not found"}, nil
+	}
+
+	client.Name = strings.TrimSpace(request.Body.Name)
+	client.RedirectURIs = request.Body.RedirectUris
+	client.Scopes = request.Body.Scopes
+
+	if err := validateOAuthClient(client); err != nil {
+		return gen.PutAdminOauthClientsClientId400JSONResponse{Error: err.Error()}, nil
+	}
+
+	if err := u.ur.UpdateOAuthClient(ctx, client); err != nil {
+		log.Errorf("Failed to update oauth client: %s", err)
+		return gen.PutAdminOauthClientsClientId500JSONResponse{Error: "internal error"}, nil
+	}
+
+	return gen.PutAdminOauthClientsClientId200JSONResponse(oauthClient(client)), nil
+}
+
+func (u AuthUseCase) DeleteAdminOauthClientsClientId(ctx context.Context, request gen.DeleteAdminOauthClientsClientIdRequestObject) (gen.DeleteAdminOauthClientsClientIdResponseObject, error) {
+	if _, err := u.ur.GetOAuthClient(ctx, request.ClientId); err != nil {
+		return gen.DeleteAdminOauthClientsClientId404JSONResponse{Error: "client not found"}, nil
+	}
+
+	if err := u.ur.DeleteOAuthClient(ctx, request.ClientId); err != nil {
+		log.Errorf("Failed to delete oauth client: %s", err)
+		return gen.DeleteAdminOauthClientsClientId500JSONResponse{Error: "internal error"}, nil
+	}
+
+	return gen.DeleteAdminOauthClientsClientId204Response{}, nil
+}
+
+func (u AuthUseCase) PostAdminOauthClientsClientIdSecrets(ctx context.Context, request gen.PostAdminOauthClientsClientIdSecretsRequestObject) (gen.PostAdminOauthClientsClientIdSecretsResponseObject, error) {
+	overlap := defaultSecretOverlap
+	if request.Body.OverlapSeconds != nil {
+		if *request.Body.OverlapSeconds < 0 {
+			return gen.PostAdminOauthClientsClientIdSecrets400JSONResponse{Error: "overlap_seconds must not be negative"}, nil
+		}
+		overlap = time.Duration(*request.Body.OverlapSeconds) * time.Second
+	}
+
+	client, err := u.ur.GetOAuthClient(ctx, request.ClientId)
+	if err != nil {
+		return gen.PostAdminOauthClientsClientIdSecrets404JSONResponse{Error: "client not found"}, nil
+	}
+
+	if !client.Confidential {
+		return gen.PostAdminOauthClientsClientIdSecrets400JSONResponse{Error: "public clients don't have secrets"}, nil
+	}
+
+	previousExpireAt := time.Now().Add(overlap)
+	secret, err := u.addClientSecret(ctx, client.ID, previousExpireAt)
+	if err != nil {
+		log.Errorf("Failed to add client secret: %s", err)
+		return gen.PostAdminOauthClientsClientIdSecrets500JSONResponse{Error: "internal error"}, nil
+	}
+
+	return gen.PostAdminOauthClientsClientIdSecrets201JSONResponse{
+		ClientId:         client.ID,
+		ClientSecret:     secret,
+		PreviousExpireAt: previousExpireAt.UTC(),
+	}, nil
+}
+
+// addClientSecret - generates a new secret, previous ones expire at overlapUntil
+func (u AuthUseCase) addClientSecret(ctx context.Context, clientID string, overlapUntil time.Time) (string, error) {
+	secret, err := oauth.GenerateSecret()
+	if err != nil {
+		return "", err
+	}
+
+	err = u.ur.AddClientSecret(ctx, entity.ClientSecret{
+		ClientID:   clientID,
+		SecretHash: oauth.HashSecret(secret),
+	}, overlapUntil)
+	if err != nil {
+		return "", err
+	}
+
+	return secret, nil
+}
+
+func oauthClient(c entity.OAuthClient) gen.OAuthClient {
+	res := gen.OAuthClient{
+		ClientId:     c.ID,
+		Name:         c.Name,
+		RedirectUris: c.RedirectURIs,
+		Confidential: c.Confidential,
+		Scopes:       c.Scopes,
+	}
+	if res.RedirectUris == nil {
+		res.RedirectUris = []string{}
+	}
+	if res.Scopes == nil {
+		res.Scopes = []string{}
+	}
+
+	return res
+}
+
+// validateOAuthClient - public clients need redirect URIs, while confidential ones
+// may use client_credentials grant only
+func validateOAuthClient(c entity.OAuthClient) error {
+	if c.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+
+	if len(c.RedirectURIs) == 0 && !c.Confidential {
+		return fmt.Errorf("at least one redirect uri is required")
+	}
+
+	for _, uri := range c.RedirectURIs {
+		if err := validateRedirectURI(uri); err != nil {
+			return err
+		}
+	}
+
+	for _, scope := range c.Scopes {
+		if scope == "" || strings.ContainsAny(scope, " \"\\") {
+			return fmt.Errorf("invalid scope %q", scope)
+		}
+	}
+
+	return nil
+}
+
+// validateRedirectURI - absolute URI without fragment, plain http is allowed only for
+// loopback redirects of native apps
+func validateRedirectURI(uri string) error {
+	u, err := url.Parse(uri)
+	if err != nil || !u.IsAbs() || u.Fragment != "" || strings.ContainsAny(uri, " ") {
+		return fmt.Errorf("invalid redirect uri %q", uri)
+	}
+
+	if u.Scheme == "http" {
+		host := u.Hostname()
+		if ip := net.ParseIP(host); host != "localhost" && (ip == nil || !ip.IsLoopback()) {
+			return fmt.Errorf("redirect uri %q must use https", uri)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,94 @@
+package usecase_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/bogatyr285/auth-go/internal/gateway/http/gen"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOAuthClients(t *testing.T) {
+	srv, useCase := newTestServerWithUseCase(t)
+
+	admin := gen.LoginUserRequest{Username: "alice@example.com", Password: "rLy_5tr0nG!"}
+	user := gen.LoginUserRequest{Username: "bob@example.com", Password: "rLy_5tr0nG!"}
+	for _, credentials := range []gen.LoginUserRequest{admin, user} {
+		require.Equal(t, http.StatusCreated, doJSON(t, srv, "/register", "", credentials, nil))
+	}
+	require.NoError(t, useCase.GrantAdmins(context.Background(), []string{admin.Username}))
+
+	login := func(credentials gen.LoginUserRequest) string {
+		var tokens gen.LoginUserResponse
+		require.Equal(t, http.StatusOK, doJSON(t, srv, "/login", "", credentials, &tokens))
+		return tokens.AccessToken
+	}
+	adminToken, userToken := login(admin), login(user)
+
+	request := gen.OAuthClientRequest{
+		Name:         "Example App",
+		RedirectUris: []string{"https://app.example.com/callback", "http://127.0.0.1:8000/cb", "com.example.app:/cb"},
+	}
+
+	require.Equal(t, http.StatusForbidden, doJSON(t, srv, "/admin/oauth/clients", userToken, request, nil))
+
+	var client gen.OAuthClient
+	require.Equal(t, http.StatusCreated, doJSON(t, srv, "/admin/oauth/clients", adminToken, request, &client))
+	require.NotEmpty(t, client.ClientId)
+	require.Equal(t, request.RedirectUris, client.RedirectUris)
+
+	for _, uri := range []string{"http://app.example.com/callback", "/callback", "https://app.example.com/cb#frag"} {
+		invalid := gen.OAuthClientRequest{Name: "Example App", RedirectUris: []string{uri}}
+		require.Equal(t, http.StatusBadRequest, doJSON(t, srv, "/admin/oauth/clients", adminToken, invalid, nil), uri)
+	}
+	require.Equal(t, http.StatusBadRequest, doJSON(t, srv, "/admin/oauth/clients", adminToken, gen.OAuthClientRequest{Name: "x"}, nil))
+	require.Nil(t, client.ClientSecret)
+
+	t.Run("confidential clients", func(t *testing.T) {
+		confidential, scopes := true, []string{"users:read"}
+		var service gen.OAuthClient
+		require.Equal(t, http.StatusCreated, doJSON(t, srv, "/admin/oauth/clients", adminToken, gen.OAuthClientRequest{
+			Name:         "Billing",
+			RedirectUris: []string{},
+			Confidential: &confidential,
+			Scopes:       &scopes,
+		}, &service))
+		require.NotNil(t, service.ClientSecret)
+		require.Equal(t, scopes, service.Scopes)
+
+		path := "/admin/oauth/clients/" + service.ClientId
+		var secret gen.ClientSecret
+		require.Equal(t, http.StatusCreated, doJSON(t, srv, path+"/secrets", adminToken, gen.RotateClientSecretRequest{}, &secret))
+		require.NotEqual(t, *service.ClientSecret, secret.ClientSecret)
+		require.WithinDuration(t, time.Now().Add(24*time.Hour), secret.PreviousExpireAt, time.Minute)
+
+		// public clients have no secrets to rotate
+		require.Equal(t, http.StatusBadRequest, doJSON(t, srv, "/admin/oauth/clients/"+client.ClientId+"/secrets", adminToken, gen.RotateClientSecretRequest{}, nil))
+		require.Equal(t, http.StatusNotFound, doJSON(t, srv, "/admin/oauth/clients/nope/secrets", adminToken, gen.RotateClientSecretRequest{}, nil))
+	})
+
+	t.Run("crud", func(t *testing.T) {
+		path := "/admin/oauth/clients/" + client.ClientId
+
+		var clients []gen.OAuthClient
+		require.Equal(t, http.StatusOK, doRequest(t, srv, http.MethodGet, "/admin/oauth/clients", adminToken, nil, &clients))
+		require.Len(t, clients, 2)
+
+		update := gen.OAuthClientUpdate{Name: "Renamed App", RedirectUris: []string{"https://app.example.com/cb"}, Scopes: []string{}}
+		require.Equal(t, http.StatusOK, doRequest(t, srv, http.MethodPut, path, adminToken, update, &client))
+		require.Equal(t, "Renamed App", client.Name)
+
+		var fetched gen.OAuthClient
+		require.Equal(t, http.StatusOK, doRequest(t, srv, http.MethodGet, path, adminToken, nil, &fetched))
+		require.Equal(t, client, fetched)
+
+		update.RedirectUris = []string{}
+		require.Equal(t, http.StatusBadRequest, doRequest(t, srv, http.MethodPut, path, adminToken, update, nil))
+
+		require.Equal(t, http.StatusForbidden, doRequest(t, srv, http.MethodDelete, path, userToken, nil, nil))
+		require.Equal(t, http.StatusNoContent, doRequest(t, srv, http.MethodDelete, path, adminToken, nil, nil))
+		require.Equal(t, http.StatusNotFound, doRequest(t, srv, http.MethodGet, path, adminToken, nil, nil))
+	})
+}
@@ -0,0 +1,169 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	"github.com/bogatyr285/auth-go/internal/auth/authz"
+	"github.com/bogatyr285/auth-go/internal/auth/entity"
+	"github.com/bogatyr285/auth-go/internal/gateway/http/gen"
+	"github.com/labstack/gommon/log"
+)
+
+type RelationEngine interface {
+	Check(ctx context.Context, tuple entity.RelationTuple, token string) (bool, string, error)
+	Expand(ctx context.Context, object entity.Object, relation, token string) (entity.UsersetTree, string, error)
+	Write(ctx context.Context, updates []entity.TupleUpdate) (string, error)
+	ListObjects(ctx context.Context, namespace, relation string, subject entity.Subject, token string) ([]string, string, error)
+}
+
+// WithRelations - enables relationship based authorization API
+func WithRelations(re RelationEngine) Option {
+	return func(u *AuthUseCase) {
+		u.re = re
+	}
+}
+
+func (u AuthUseCase) PostRelationsCheck(ctx context.Context, request gen.PostRelationsCheckRequestObject) (gen.PostRelationsCheckResponseObject, error) {
+	if u.re == nil {
+		return gen.PostRelationsCheck500JSONResponse{Error: "relations are not configured"}, nil
+	}
+
+	tuple, err := parseTuple(request.Body.Tuple)
+	if err != nil {
+		return gen.PostRelationsCheck400JSONResponse{Error: err.Error()}, nil
+	}
+
+	allowed, token, err := u.re.Check(ctx, tuple, stringValue(request.Body.ConsistencyToken))
+	if err != nil {
+		if isRelationRequestError(err) {
+			return gen.PostRelationsCheck400JSONResponse{Error: err.Error()}, nil
+		}
+
+		log.Errorf("Failed to check relation: %s", err)
+		return gen.PostRelationsCheck500JSONResponse{Error: "internal error"}, nil
+	}
+
+	return gen.PostRelationsCheck200JSONResponse{
+		Allowed:          allowed,
+		ConsistencyToken: token,
+	}, nil
+}
+
+func (u AuthUseCase) PostRelationsExpand(ctx context.Context, request gen.PostRelationsExpandRequestObject) (gen.PostRelationsExpandResponseObject, error) {
+	if u.re == nil {
+		return gen.PostRelationsExpand500JSONResponse{Error: "relations are not configured"}, nil
+	}
+
+	object, err := entity.ParseObject(request.Body.Object)
+	if err != nil {
+		return gen.PostRelationsExpand400JSONResponse{Error: err.Error()}, nil
+	}
+
+	tree, token, err := u.re.Expand(ctx, object, request.Body.Relation, stringValue(request.Body.ConsistencyToken))
+	if err != nil {
+		if isRelationRequestError(err) {
+			return gen.PostRelationsExpand400JSONResponse{Error: err.Error()}, nil
+		}
+
+		log.Errorf("Failed to expand relation: %s", err)
+		return gen.PostRelationsExpand500JSONResponse{Error: "internal error"}, nil
+	}
+
+	return gen.PostRelationsExpand200JSONResponse{
+		Tree:             usersetTree(tree),
+		ConsistencyToken: token,
+	}, nil
+}
+
+func (u AuthUseCase) PostRelationsWrite(ctx context.Context, request gen.PostRelationsWriteRequestObject) (gen.PostRelationsWriteResponseObject, error) {
+	if u.re == nil {
+		return gen.PostRelationsWrite500JSONResponse{Error: "relations are not configured"}, nil
+	}
+
+	updates := make([]entity.TupleUpdate, 0, len(request.Body.Updates))
+	for _, update := range request.Body.Updates {
+		tuple, err := parseTuple(update.Tuple)
+		if err != nil {
+			return gen.PostRelationsWrite400JSONResponse{Error: err.Error()}, nil
+		}
+
+		updates = append(updates, entity.TupleUpdate{
+			Operation: string(update.Operation),
+			Tuple:     tuple,
+		})
+	}
+
+	token, err := u.re.Write(ctx, updates)
+	if err != nil {
+		if isRelationRequestError(err) {
+			return gen.PostRelationsWrite400JSONResponse{Error: err.Error()}, nil
+		}
+
+		log.Errorf("Failed to write relations: %s", err)
+		return gen.PostRelationsWrite500JSONResponse{Error: "internal error"}, nil
+	}
+
+	return gen.PostRelationsWrite200JSONResponse{ConsistencyToken: token}, nil
+}
+
+func (u AuthUseCase) PostRelationsObjects(ctx context.Context, request gen.PostRelationsObjectsRequestObject) (gen.PostRelationsObjectsResponseObject, error) {
+	if u.re == nil {
+		return gen.PostRelationsObjects500JSONResponse{Error: "relations are not configured"}, nil
+	}
+
+	subject, err := entity.ParseSubject(request.Body.Subject)
+	if err != nil {
+		return gen.PostRelationsObjects400JSONResponse{Error: err.Error()}, nil
+	}
+
+	objects, token, err := u.re.ListObjects(ctx, request.Body.Namespace, request.Body.Relation, subject, stringValue(request.Body.ConsistencyToken))
+	if err != nil {
+		if isRelationRequestError(err) {
+			return gen.PostRelationsObjects400JSONResponse{Error: err.Error()}, nil
+		}
+
+		log.Errorf("Failed to list objects: %s", err)
+		return gen.PostRelationsObjects500JSONResponse{Error: "internal error"}, nil
+	}
+
+	return gen.PostRelationsObjects200JSONResponse{
+		Objects:          objects,
+		ConsistencyToken: token,
+	}, nil
+}
+
+func parseTuple(t gen.RelationTuple) (entity.RelationTuple, error) {
+	return entity.ParseTuple(t.Object, t.Relation, t.Subject)
+}
+
+func usersetTree(tree entity.UsersetTree) gen.UsersetTree {
+	res := gen.UsersetTree{
+		Object:   tree.Object.String(),
+		Relation: tree.Relation,
+		Subjects: []string{},
+		Children: []gen.UsersetTree{},
+	}
+
+	for _, s := range tree.Subjects {
+		res.Subjects = append(res.Subjects, s.String())
+	}
+
+	for _, child := range tree.Children {
+		res.Children = append(res.Children, usersetTree(child))
+	}
+
+	return res
+}
+
+func isRelationRequestError(err error) bool {
+	return errors.Is(err, authz.ErrInvalidRequest) || errors.Is(err, authz.ErrInvalidConsistencyToken)
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+
+	return *s
+}
@@ -0,0 +1,85 @@
+package usecase_test
+
+import (
+	"context"
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"github.com/bogatyr285/auth-go/internal/auth/authz"
+	"github.com/bogatyr285/auth-go/internal/auth/entity"
+	"github.com/bogatyr285/auth-go/internal/auth/repository"
+	"github.com/bogatyr285/auth-go/internal/auth/usecase"
+	"github.com/bogatyr285/auth-go/internal/gateway/http/gen"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRelations(t *testing.T) {
+	storage, err := repository.New(filepath.Join(t.TempDir(), "relations.sqlite"))
+	require.NoError(t, err)
+	t.Cleanup(func() { storage.Close() })
+
+	engine, err := authz.NewEngine(&storage, []entity.NamespaceConfig{{Name: "document", Relations: map[string]entity.RelationConfig{
+		"owner":  {},
+		"viewer": {Computed: []string{"owner"}},
+	}}})
+	require.NoError(t, err)
+
+	srv, useCase := newTestServerWithUseCase(t, usecase.WithRelations(engine))
+
+	admin := gen.LoginUserRequest{Username: "alice@example.com", Password: "rLy_5tr0nG!"}
+	user := gen.LoginUserRequest{Username: "bob@example.com", Password: "rLy_5tr0nG!"}
+	tokens := map[string]string{}
+	for _, credentials := range []gen.LoginUserRequest{admin, user} {
+		require.Equal(t, http.StatusCreated, doJSON(t, srv, "/register", "", credentials, nil))
+		if credentials == admin {
+			require.NoError(t, useCase.GrantAdmins(context.Background(), []string{admin.Username}))
+		}
+
+		var res gen.LoginUserResponse
+		require.Equal(t, http.StatusOK, doJSON(t, srv, "/login", "", credentials, &res))
+		tokens[credentials.Username] = res.AccessToken
+	}
+	adminToken, userToken := tokens[admin.Username], tokens[user.Username]
+
+	readme := gen.RelationTuple{Object: "document:readme", Relation: "owner", Subject: "user:bob"}
+
+	require.Equal(t, http.StatusForbidden, doJSON(t, srv, "/relations/write", userToken, gen.WriteRequest{
+		Updates: []gen.RelationTupleUpdate{{Operation: gen.Touch, Tuple: readme}},
+	}, nil))
+
+	var written gen.WriteResponse
+	require.Equal(t, http.StatusOK, doJSON(t, srv, "/relations/write", adminToken, gen.WriteRequest{
+		Updates: []gen.RelationTupleUpdate{{Operation: gen.Touch, Tuple: readme}},
+	}, &written))
+	require.NotEmpty(t, written.ConsistencyToken)
+
+	var check gen.CheckResponse
+	require.Equal(t, http.StatusOK, doJSON(t, srv, "/relations/check", adminToken, gen.CheckRequest{
+		Tuple:            gen.RelationTuple{Object: "document:readme", Relation: "viewer", Subject: "user:bob"},
+		ConsistencyToken: &written.ConsistencyToken,
+	}, &check))
+	require.True(t, check.Allowed)
+
+	var objects gen.ListObjectsResponse
+	require.Equal(t, http.StatusOK, doJSON(t, srv, "/relations/objects", adminToken, gen.ListObjectsRequest{
+		Namespace: "document",
+		Relation:  "viewer",
+		Subject:   "user:bob",
+	}, &objects))
+	require.Equal(t, []string{"readme"}, objects.Objects)
+
+	var expand gen.ExpandResponse
+	require.Equal(t, http.StatusOK, doJSON(t, srv, "/relations/expand", adminToken, gen.ExpandRequest{
+		Object:   "document:readme",
+		Relation: "viewer",
+	}, &expand))
+	require.Equal(t, []string{"user:bob"}, expand.Tree.Children[0].Subjects)
+
+	require.Equal(t, http.StatusBadRequest, doJSON(t, srv, "/relations/check", adminToken, gen.CheckRequest{
+		Tuple: gen.RelationTuple{Object: "readme", Relation: "viewer", Subject: "user:bob"},
+	}, nil))
+	require.Equal(t, http.StatusBadRequest, doJSON(t, srv, "/relations/check", adminToken, gen.CheckRequest{
+		Tuple: gen.RelationTuple{Object: "spreadsheet:q1", Relation: "viewer", Subject: "user:bob"},
+	}, nil))
+}
@@ -0,0 +1,102 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bogatyr285/auth-go/internal/auth/entity"
+	"github.com/bogatyr285/auth-go/internal/gateway/http/gen"
+	"github.com/labstack/gommon/log"
+)
+
+func (u AuthUseCase) GetAdminUsersIdRoles(ctx context.Context, request gen.GetAdminUsersIdRolesRequestObject) (gen.GetAdminUsersIdRolesResponseObject, error) {
+	if _, err := u.ur.GetUserById(ctx, request.Id); err != nil {
+		return gen.GetAdminUsersIdRoles404JSONResponse{Error: "user not found"}, nil
+	}
+
+	roles, err := u.ur.ListUserRoles(ctx, request.Id)
+	if err != nil {
+		log.Errorf("Failed to list roles: %s", err)
+		return gen.GetAdminUsersIdRoles500JSONResponse{Error: "internal error"}, nil
+	}
+
+	return gen.GetAdminUsersIdRoles200JSONResponse{Id: request.Id, Roles: roles}, nil
+}
+
+func (u AuthUseCase) PostAdminUsersIdRoles(ctx context.Context, request gen.PostAdminUsersIdRolesRequestObject) (gen.PostAdminUsersIdRolesResponseObject, error) {
+	role := string(request.Body.Role)
+	if err := validateGrantableRole(role); err != nil {
+		return gen.PostAdminUsersIdRoles400JSONResponse{Error: err.Error()}, nil
+	}
+
+	if _, err := u.ur.GetUserById(ctx, request.Id); err != nil {
+		return gen.PostAdminUsersIdRoles404JSONResponse{Error: "user not found"}, nil
+	}
+
+	if err := u.ur.GrantUserRole(ctx, request.Id, role); err != nil {
+		log.Errorf("Failed to grant role: %s", err)
+		return gen.PostAdminUsersIdRoles500JSONResponse{Error: "internal error"}, nil
+	}
+
+	roles, err := u.ur.ListUserRoles(ctx, request.Id)
+	if err != nil {
+		log.Errorf("Failed to list roles: %s", err)
+		return gen.PostAdminUsersIdRoles500JSONResponse{Error: "internal error"}, nil
+	}
+
+	return gen.PostAdminUsersIdRoles200JSONResponse{Id: request.Id, Roles: roles}, nil
+}
+
+func (u AuthUseCase) DeleteAdminUsersIdRolesRole(ctx context.Context, request gen.DeleteAdminUsersIdRolesRoleRequestObject) (gen.DeleteAdminUsersIdRolesRoleResponseObject, error) {
+	if err := validateGrantableRole(request.Role); err != nil {
+		return gen.DeleteAdminUsersIdRolesRole400JSONResponse{Error: err.Error()}, nil
+	}
+
+	if _, err := u.ur.GetUserById(ctx, request.Id); err != nil {
+		return gen.DeleteAdminUsersIdRolesRole404JSONResponse{Error: "user not found"}, nil
+	}
+
+	if err := u.ur.RevokeUserRole(ctx, request.Id, request.Role); err != nil {
+		log.Errorf("Failed to revoke role: %s", err)
+		return gen.DeleteAdminUsersIdRolesRole500JSONResponse{Error: "internal error"}, nil
+	}
+
+	roles, err := u.ur.ListUserRoles(ctx, request.Id)
+	if err != nil {
+		log.Errorf("Failed to list roles: %s", err)
+		return gen.DeleteAdminUsersIdRolesRole500JSONResponse{Error: "internal error"}, nil
+	}
+
+	return gen.DeleteAdminUsersIdRolesRole200JSONResponse{Id: request.Id, Roles: roles}, nil
+}
+
+// GrantAdmins - grants admin role to existing users on startup, so the first admin
+// doesn't have to be created by hand. Every admin must be registered already, otherwise
+// whoever registers the name first would become admin on the next startup
+func (u AuthUseCase) GrantAdmins(ctx context.Context, usernames []string) error {
+	for _, username := range usernames {
+		user, err := u.ur.FindUserByEmail(ctx, username)
+		if err != nil {
+			return fmt.Errorf("failed to find admin %s, register the user before configuring it: %s", username, err)
+		}
+
+		if err = u.ur.GrantUserRole(ctx, user.ID, entity.RoleAdmin); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateGrantableRole - entity.RoleUser is implied for everyone, so it can't be granted or revoked
+func validateGrantableRole(role string) error {
+	if !entity.IsRole(role) {
+		return fmt.Errorf("unknown role %q", role)
+	}
+
+	if role == entity.RoleUser {
+		return fmt.Errorf("role %q is implied for every user", role)
+	}
+
+	return nil
+}
@@ -0,0 +1,61 @@
+package usecase_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/bogatyr285/auth-go/internal/gateway/http/gen"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoles(t *testing.T) {
+	srv, useCase := newTestServerWithUseCase(t)
+
+	admin := gen.LoginUserRequest{Username: "alice@example.com", Password: "rLy_5tr0nG!"}
+	user := gen.LoginUserRequest{Username: "bob@example.com", Password: "rLy_5tr0nG!"}
+	for _, credentials := range []gen.LoginUserRequest{admin, user} {
+		require.Equal(t, http.StatusCreated, doJSON(t, srv, "/register", "", credentials, nil))
+	}
+
+	// admins which aren't registered yet could be claimed by anyone
+	require.Error(t, useCase.GrantAdmins(context.Background(), []string{"nobody@example.com"}))
+	require.NoError(t, useCase.GrantAdmins(context.Background(), []string{admin.Username}))
+
+	login := func(credentials gen.LoginUserRequest) string {
+		var tokens gen.LoginUserResponse
+		require.Equal(t, http.StatusOK, doJSON(t, srv, "/login", "", credentials, &tokens))
+		return tokens.AccessToken
+	}
+	adminToken, userToken := login(admin), login(user)
+
+	t.Run("permissions are checked", func(t *testing.T) {
+		require.Equal(t, http.StatusForbidden, doRequest(t, srv, http.MethodGet, "/users/1", userToken, nil, nil))
+		require.Equal(t, http.StatusForbidden, doRequest(t, srv, http.MethodGet, "/admin/users/2/roles", userToken, nil, nil))
+		require.Equal(t, http.StatusForbidden, doJSON(t, srv, "/admin/users/2/roles", userToken, gen.RoleRequest{Role: gen.Admin}, nil))
+
+		require.Equal(t, http.StatusOK, doRequest(t, srv, http.MethodGet, "/users/2", adminToken, nil, nil))
+	})
+
+	t.Run("grant and revoke", func(t *testing.T) {
+		var roles gen.UserRoles
+		require.Equal(t, http.StatusOK, doRequest(t, srv, http.MethodGet, "/admin/users/2/roles", adminToken, nil, &roles))
+		require.Equal(t, []string{"user"}, roles.Roles)
+
+		require.Equal(t, http.StatusOK, doJSON(t, srv, "/admin/users/2/roles", adminToken, gen.RoleRequest{Role: gen.Moderator}, &roles))
+		require.Equal(t, gen.UserRoles{Id: 2, Roles: []string{"user", "moderator"}}, roles)
+
+		// roles are embedded in tokens, so the new role works after the next login
+		require.Equal(t, http.StatusForbidden, doRequest(t, srv, http.MethodGet, "/users/1", userToken, nil, nil))
+		require.Equal(t, http.StatusOK, doRequest(t, srv, http.MethodGet, "/users/1", login(user), nil, nil))
+
+		require.Equal(t, http.StatusOK, doRequest(t, srv, http.MethodDelete, "/admin/users/2/roles/moderator", adminToken, nil, &roles))
+		require.Equal(t, []string{"user"}, roles.Roles)
+	})
+
+	t.Run("invalid requests", func(t *testing.T) {
+		require.Equal(t, http.StatusBadRequest, doJSON(t, srv, "/admin/users/2/roles", adminToken, gen.RoleRequest{Role: "root"}, nil))
+		require.Equal(t, http.StatusBadRequest, doRequest(t, srv, http.MethodDelete, "/admin/users/2/roles/user", adminToken, nil, nil))
+		require.Equal(t, http.StatusNotFound, doRequest(t, srv, http.MethodGet, "/admin/users/99/roles", adminToken, nil, nil))
+	})
+}
@@ -0,0 +1,192 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/bogatyr285/auth-go/internal/auth/entity"
+	"github.com/bogatyr285/auth-go/internal/auth/login"
+	"github.com/bogatyr285/auth-go/internal/auth/oauth"
+	"github.com/bogatyr285/auth-go/internal/gateway/http/gen"
+	"github.com/bogatyr285/auth-go/pkg/oidc"
+	"github.com/bogatyr285/auth-go/pkg/saml"
+	"github.com/labstack/gommon/log"
+)
+
+// SAMLServiceProvider - the service itself as seen by identity providers
+type SAMLServiceProvider interface {
+	Metadata() ([]byte, error)
+}
+
+// SAMLIdentityProvider - SAML identity provider users may sign in with
+type SAMLIdentityProvider interface {
+	AuthnRequestURL(relayState string) (location, requestID string, err error)
+	// ParseResponse - assertion of the response to the request, validated against the provider's certificates
+	ParseResponse(samlResponse, requestID string) (saml.Assertion, error)
+}
+
+// SAMLConnection - identity provider with mapping of its attributes
+type SAMLConnection struct {
+	Provider SAMLIdentityProvider
+	// EmailAttribute, NameAttribute - attributes of the user fields, emails asserted by
+	// the provider are trusted as verified
+	EmailAttribute string
+	NameAttribute  string
+	// RolesAttribute - attribute of groups of the user, mapped to roles by GroupRoles.
+	// Mapped roles are granted and revoked on every login
+	RolesAttribute string
+	GroupRoles     map[string]string
+}
+
+type SAMLSettings struct {
+	ServiceProvider SAMLServiceProvider
+	// Connections - keyed by the name used in /saml/login?provider=
+	Connections map[string]SAMLConnection
+}
+
+// WithSAML - enables login with SAML identity providers
+func WithSAML(settings SAMLSettings) Option {
+	return func(u *AuthUseCase) {
+		u.saml = settings
+	}
+}
+
+func (u AuthUseCase) GetSamlMetadata(ctx context.Context, request gen.GetSamlMetadataRequestObject) (gen.GetSamlMetadataResponseObject, error) {
+	if u.saml.ServiceProvider == nil {
+		return gen.GetSamlMetadata404JSONResponse{Error: "saml is disabled"}, nil
+	}
+
+	metadata, err := u.saml.ServiceProvider.Metadata()
+	if err != nil {
+		log.Errorf("Failed to build saml metadata: %s", err)
+		return gen.GetSamlMetadata500JSONResponse{Error: "internal error"}, nil
+	}
+
+	return gen.GetSamlMetadata200ApplicationsamlmetadataXmlResponse{
+		Body:          bytes.NewReader(metadata),
+		ContentLength: int64(len(metadata)),
+	}, nil
+}
+
+func (u AuthUseCase) GetSamlLogin(ctx context.Context, request gen.GetSamlLoginRequestObject) (gen.GetSamlLoginResponseObject, error) {
+	conn, ok := u.saml.Connections[request.Params.Provider]
+	if !ok {
+		return gen.GetSamlLogin404JSONResponse{Error: "unknown provider"}, nil
+	}
+
+	state, err := oauth.GenerateSecret()
+	if err != nil {
+		return gen.GetSamlLogin500JSONResponse{}, err
+	}
+
+	location, requestID, err := conn.Provider.AuthnRequestURL(state)
+	if err != nil {
+		log.Errorf("Failed to build authn request of %s: %s", request.Params.Provider, err)
+		return gen.GetSamlLogin500JSONResponse{Error: "provider is unavailable"}, nil
+	}
+
+	// the request ID plays the part of the nonce, responses must be in response to it
+	err = u.ur.SaveFederatedLogin(ctx, entity.FederatedLogin{
+		StateHash: oauth.HashSecret(state),
+		Provider:  request.Params.Provider,
+		Nonce:     requestID,
+		ExpiresAt: time.Now().Add(federatedLoginTTL),
+	})
+	if err != nil {
+		log.Errorf("Failed to save saml login: %s", err)
+		return gen.GetSamlLogin500JSONResponse{Error: "internal error"}, nil
+	}
+
+	return gen.GetSamlLogin302Response{
+		Headers: gen.GetSamlLogin302ResponseHeaders{Location: location},
+	}, nil
+}
+
+func (u AuthUseCase) PostSamlAcs(ctx context.Context, request gen.PostSamlAcsRequestObject) (gen.PostSamlAcsResponseObject, error) {
+	samlLogin, err := u.ur.TakeFederatedLogin(ctx, oauth.HashSecret(request.Body.RelayState))
+	if err != nil || time.Now().After(samlLogin.ExpiresAt) {
+		return gen.PostSamlAcs401JSONResponse{Error: "login is invalid or expired"}, nil
+	}
+
+	conn, ok := u.saml.Connections[samlLogin.Provider]
+	if !ok {
+		return gen.PostSamlAcs401JSONResponse{Error: "unauth"}, nil
+	}
+
+	assertion, err := conn.Provider.ParseResponse(request.Body.SAMLResponse, samlLogin.Nonce)
+	if err != nil {
+		log.Errorf("Failed to sign in with %s: %s", samlLogin.Provider, err)
+		return gen.PostSamlAcs401JSONResponse{Error: "unauth"}, nil
+	}
+
+	fresh, err := u.ur.ConsumeSAMLAssertion(ctx, samlLogin.Provider, assertion.ID, assertion.ExpiresAt)
+	if err != nil {
+		log.Errorf("Failed to consume assertion of %s: %s", samlLogin.Provider, err)
+		return gen.PostSamlAcs500JSONResponse{Error: "internal error"}, nil
+	}
+
+	if !fresh {
+		log.Errorf("Assertion %s of %s replayed", assertion.ID, samlLogin.Provider)
+		return gen.PostSamlAcs401JSONResponse{Error: "unauth"}, nil
+	}
+
+	email := assertion.First(conn.EmailAttribute)
+	user, err := u.federatedUser(ctx, samlLogin.Provider, oidc.Claims{
+		Subject:       assertion.Subject,
+		Email:         email,
+		EmailVerified: email != "",
+		Name:          assertion.First(conn.NameAttribute),
+	})
+	if err != nil {
+		if errors.Is(err, errUsernameTaken) {
+			return gen.PostSamlAcs409JSONResponse{Error: err.Error()}, nil
+		}
+
+		log.Errorf("Failed to provision user of %s: %s", samlLogin.Provider, err)
+		return gen.PostSamlAcs500JSONResponse{Error: "internal error"}, nil
+	}
+
+	if err = u.syncSAMLRoles(ctx, conn, user.ID, assertion); err != nil {
+		log.Errorf("Failed to sync roles of %s: %s", user.Username, err)
+		return gen.PostSamlAcs500JSONResponse{Error: "internal error"}, nil
+	}
+
+	session, err := u.accounts.SignIn(ctx, user)
+	if err != nil {
+		return gen.PostSamlAcs500JSONResponse{}, err
+	}
+
+	if session.MFARequired() {
+		return gen.PostSamlAcs202JSONResponse{
+			MfaToken: session.MFAToken,
+			Methods:  session.MFAMethods,
+		}, nil
+	}
+
+	return gen.PostSamlAcs200JSONResponse{
+		AccessToken:  session.AccessToken,
+		RefreshToken: session.RefreshToken,
+	}, nil
+}
+
+func (u AuthUseCase) syncSAMLRoles(ctx context.Context, conn SAMLConnection, userID int, assertion saml.Assertion) error {
+	if conn.RolesAttribute == "" {
+		return nil
+	}
+
+	managed := make([]string, 0, len(conn.GroupRoles))
+	for _, role := range conn.GroupRoles {
+		managed = append(managed, role)
+	}
+
+	var granted []string
+	for _, group := range assertion.Attributes[conn.RolesAttribute] {
+		if role, ok := conn.GroupRoles[group]; ok {
+			granted = append(granted, role)
+		}
+	}
+
+	return login.SyncRoles(ctx, u.ur, userID, managed, granted)
+}
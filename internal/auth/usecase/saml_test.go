@@ -0,0 +1,337 @@
+package usecase_test
+
+import (
+	"bytes"
+	"compress/flate"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/beevik/etree"
+	"github.com/bogatyr285/auth-go/internal/auth/usecase"
+	"github.com/bogatyr285/auth-go/internal/gateway/http/gen"
+	pkgjwt "github.com/bogatyr285/auth-go/pkg/jwt"
+	"github.com/bogatyr285/auth-go/pkg/saml"
+	gosaml "github.com/crewjam/saml"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	dsig "github.com/russellhaering/goxmldsig"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	spEntityID  = "http://localhost:8080/saml/metadata"
+	spACSURL    = "http://localhost:8080/saml/acs"
+	idpEntityID = "https://idp.corp.example.com"
+	idpSSOURL   = "https://idp.corp.example.com/sso"
+)
+
+// samlIdP - identity provider signing assertions with a locally generated key
+type samlIdP struct {
+	key  *rsa.PrivateKey
+	cert *x509.Certificate
+}
+
+func newSAMLIdP(t *testing.T) *samlIdP {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "idp.corp.example.com"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return &samlIdP{key: key, cert: cert}
+}
+
+func (idp *samlIdP) metadata(t *testing.T) []byte {
+	metadata, err := xml.Marshal(gosaml.EntityDescriptor{
+		EntityID: idpEntityID,
+		IDPSSODescriptors: []gosaml.IDPSSODescriptor{{
+			SSODescriptor: gosaml.SSODescriptor{RoleDescriptor: gosaml.RoleDescriptor{
+				ProtocolSupportEnumeration: "urn:oasis:names:tc:SAML:2.0:protocol",
+				KeyDescriptors: []gosaml.KeyDescriptor{{
+					Use: "signing",
+					KeyInfo: gosaml.KeyInfo{X509Data: gosaml.X509Data{X509Certificates: []gosaml.X509Certificate{{
+						Data: base64.StdEncoding.EncodeToString(idp.cert.Raw),
+					}}}},
+				}},
+			}},
+			SingleSignOnServices: []gosaml.Endpoint{{Binding: gosaml.HTTPRedirectBinding, Location: idpSSOURL}},
+		}},
+	})
+	require.NoError(t, err)
+
+	return metadata
+}
+
+// assertion - what the IdP asserts, tests break parts of it
+type assertion struct {
+	id           string
+	inResponseTo string
+	nameID       string
+	audience     string
+	notOnOrAfter time.Time
+	attributes   map[string][]string
+}
+
+// response - base64 encoded response with the assertion signed by the key
+func (idp *samlIdP) response(t *testing.T, key *rsa.PrivateKey, a assertion) string {
+	now := time.Now().UTC()
+
+	var attributes []gosaml.Attribute
+	for name, values := range a.attributes {
+		attr := gosaml.Attribute{Name: name, NameFormat: "urn:oasis:names:tc:SAML:2.0:attrname-format:basic"}
+		for _, v := range values {
+			attr.Values = append(attr.Values, gosaml.AttributeValue{Type: "xs:string", Value: v})
+		}
+		attributes = append(attributes, attr)
+	}
+
+	signed := &gosaml.Assertion{
+		ID:           a.id,
+		IssueInstant: now,
+		Version:      "2.0",
+		Issuer:       gosaml.Issuer{Value: idpEntityID},
+		Subject: &gosaml.Subject{
+			NameID: &gosaml.NameID{Format: string(gosaml.PersistentNameIDFormat), Value: a.nameID},
+			SubjectConfirmations: []gosaml.SubjectConfirmation{{
+				Method: "urn:oasis:names:tc:SAML:2.0:cm:bearer",
+				SubjectConfirmationData: &gosaml.SubjectConfirmationData{
+					InResponseTo: a.inResponseTo,
+					Recipient:    spACSURL,
+					NotOnOrAfter: a.notOnOrAfter,
+				},
+			}},
+		},
+		Conditions: &gosaml.Conditions{
+			NotBefore:            now.Add(-time.Minute),
+			NotOnOrAfter:         a.notOnOrAfter,
+			AudienceRestrictions: []gosaml.AudienceRestriction{{Audience: gosaml.Audience{Value: a.audience}}},
+		},
+		AttributeStatements: []gosaml.AttributeStatement{{Attributes: attributes}},
+	}
+
+	signingContext := dsig.NewDefaultSigningContext(dsig.TLSCertKeyStore(tls.Certificate{
+		Certificate: [][]byte{idp.cert.Raw},
+		PrivateKey:  key,
+		Leaf:        idp.cert,
+	}))
+	signingContext.Canonicalizer = dsig.MakeC14N10ExclusiveCanonicalizerWithPrefixList("")
+	require.NoError(t, signingContext.SetSignatureMethod(dsig.RSASHA256SignatureMethod))
+
+	signedEl, err := signingContext.SignEnveloped(signed.Element())
+	require.NoError(t, err)
+	signed.Signature = signedEl.Child[len(signedEl.Child)-1].(*etree.Element)
+
+	doc := etree.NewDocument()
+	doc.SetRoot((&gosaml.Response{
+		ID:           "id-" + uuid.NewString(),
+		InResponseTo: a.inResponseTo,
+		Version:      "2.0",
+		IssueInstant: now,
+		Destination:  spACSURL,
+		Issuer:       &gosaml.Issuer{Value: idpEntityID},
+		Status:       gosaml.Status{StatusCode: gosaml.StatusCode{Value: gosaml.StatusSuccess}},
+		Assertion:    signed,
+	}).Element())
+
+	raw, err := doc.WriteToBytes()
+	require.NoError(t, err)
+
+	return base64.StdEncoding.EncodeToString(raw)
+}
+
+func TestSAMLLogin(t *testing.T) {
+	idp := newSAMLIdP(t)
+
+	sp, err := saml.New(saml.Config{EntityID: spEntityID, ACSURL: spACSURL})
+	require.NoError(t, err)
+
+	corp, err := sp.Connect(idp.metadata(t))
+	require.NoError(t, err)
+
+	srv := newTestServer(t, usecase.WithSAML(usecase.SAMLSettings{
+		ServiceProvider: sp,
+		Connections: map[string]usecase.SAMLConnection{"corp": {
+			Provider:       corp,
+			EmailAttribute: "mail",
+			NameAttribute:  "displayName",
+			RolesAttribute: "groups",
+			GroupRoles:     map[string]string{"auth-admins": "admin"},
+		}},
+	}))
+	srv.Client().CheckRedirect = func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }
+
+	// login starts the flow, returns relay state and ID of the AuthnRequest sent to the IdP
+	login := func(t *testing.T) (string, string) {
+		res, err := srv.Client().Get(srv.URL + "/saml/login?provider=corp")
+		require.NoError(t, err)
+		res.Body.Close()
+		require.Equal(t, http.StatusFound, res.StatusCode)
+
+		location, err := url.Parse(res.Header.Get("Location"))
+		require.NoError(t, err)
+		require.Equal(t, idpSSOURL, location.Scheme+"://"+location.Host+location.Path)
+
+		deflated, err := base64.StdEncoding.DecodeString(location.Query().Get("SAMLRequest"))
+		require.NoError(t, err)
+		raw, err := io.ReadAll(flate.NewReader(bytes.NewReader(deflated)))
+		require.NoError(t, err)
+
+		var req gosaml.AuthnRequest
+		require.NoError(t, xml.Unmarshal(raw, &req))
+		require.Equal(t, spACSURL, req.AssertionConsumerServiceURL)
+		require.Equal(t, spEntityID, req.Issuer.Value)
+
+		return location.Query().Get("RelayState"), req.ID
+	}
+
+	acs := func(t *testing.T, relayState, response string, out interface{}) int {
+		form := url.Values{"RelayState": {relayState}, "SAMLResponse": {response}}
+		res, err := srv.Client().Post(srv.URL+"/saml/acs", "application/x-www-form-urlencoded", strings.NewReader(form.Encode()))
+		require.NoError(t, err)
+		defer res.Body.Close()
+
+		if out != nil && res.StatusCode == http.StatusOK {
+			require.NoError(t, json.NewDecoder(res.Body).Decode(out))
+		}
+
+		return res.StatusCode
+	}
+
+	claimsOf := func(t *testing.T, accessToken string) jwt.MapClaims {
+		claims := jwt.MapClaims{}
+		_, _, err := jwt.NewParser().ParseUnverified(accessToken, claims)
+		require.NoError(t, err)
+		return claims
+	}
+
+	alice := func(requestID string) assertion {
+		return assertion{
+			id:           "id-" + uuid.NewString(),
+			inResponseTo: requestID,
+			nameID:       "alice-persistent-id",
+			audience:     spEntityID,
+			notOnOrAfter: time.Now().Add(5 * time.Minute),
+			attributes: map[string][]string{
+				"mail":        {"alice@corp.example.com"},
+				"displayName": {"Alice"},
+				"groups":      {"staff", "auth-admins"},
+			},
+		}
+	}
+
+	t.Run("metadata", func(t *testing.T) {
+		res, err := srv.Client().Get(srv.URL + "/saml/metadata")
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+
+		var metadata gosaml.EntityDescriptor
+		require.NoError(t, xml.NewDecoder(res.Body).Decode(&metadata))
+		require.Equal(t, spEntityID, metadata.EntityID)
+		require.Equal(t, spACSURL, metadata.SPSSODescriptors[0].AssertionConsumerServices[0].Location)
+	})
+
+	t.Run("signed assertion logs the user in", func(t *testing.T) {
+		relayState, requestID := login(t)
+
+		var tokens gen.LoginUserResponse
+		require.Equal(t, http.StatusOK, acs(t, relayState, idp.response(t, idp.key, alice(requestID)), &tokens))
+		require.NotEmpty(t, tokens.RefreshToken)
+
+		claims := claimsOf(t, tokens.AccessToken)
+		require.Equal(t, "alice@corp.example.com", claims["sub"])
+		require.ElementsMatch(t, []interface{}{"user", "admin"}, claims[pkgjwt.RolesClaim])
+	})
+
+	t.Run("roles follow the groups", func(t *testing.T) {
+		relayState, requestID := login(t)
+		a := alice(requestID)
+		a.attributes["groups"] = []string{"staff"}
+
+		var tokens gen.LoginUserResponse
+		require.Equal(t, http.StatusOK, acs(t, relayState, idp.response(t, idp.key, a), &tokens))
+		require.Equal(t, []interface{}{"user"}, claimsOf(t, tokens.AccessToken)[pkgjwt.RolesClaim])
+	})
+
+	t.Run("responses are accepted once", func(t *testing.T) {
+		relayState, requestID := login(t)
+		response := idp.response(t, idp.key, alice(requestID))
+		require.Equal(t, http.StatusOK, acs(t, relayState, response, nil))
+		require.Equal(t, http.StatusUnauthorized, acs(t, relayState, response, nil))
+
+		// the same assertion answering a new request
+		a := alice(requestID)
+		relayState, requestID = login(t)
+		require.Equal(t, http.StatusOK, acs(t, relayState, idp.response(t, idp.key, assertion{
+			id: a.id, inResponseTo: requestID, nameID: a.nameID, audience: a.audience, notOnOrAfter: a.notOnOrAfter,
+		}), nil))
+
+		relayState, requestID = login(t)
+		require.Equal(t, http.StatusUnauthorized, acs(t, relayState, idp.response(t, idp.key, assertion{
+			id: a.id, inResponseTo: requestID, nameID: a.nameID, audience: a.audience, notOnOrAfter: a.notOnOrAfter,
+		}), nil))
+	})
+
+	t.Run("invalid assertions are rejected", func(t *testing.T) {
+		otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+
+		for name, tc := range map[string]struct {
+			key    *rsa.PrivateKey
+			modify func(a *assertion)
+		}{
+			"foreign signature": {key: otherKey, modify: func(a *assertion) {}},
+			"another audience":  {key: idp.key, modify: func(a *assertion) { a.audience = "https://other.example.com" }},
+			"expired":           {key: idp.key, modify: func(a *assertion) { a.notOnOrAfter = time.Now().Add(-time.Hour) }},
+			"another request":   {key: idp.key, modify: func(a *assertion) { a.inResponseTo = "id-unsolicited" }},
+		} {
+			t.Run(name, func(t *testing.T) {
+				relayState, requestID := login(t)
+				a := alice(requestID)
+				tc.modify(&a)
+				require.Equal(t, http.StatusUnauthorized, acs(t, relayState, idp.response(t, tc.key, a), nil))
+			})
+		}
+
+		relayState, _ := login(t)
+		require.Equal(t, http.StatusUnauthorized, acs(t, relayState, "not a response", nil))
+		require.Equal(t, http.StatusUnauthorized, acs(t, "forged", idp.response(t, idp.key, alice("id-x")), nil))
+	})
+
+	t.Run("local accounts aren't taken over", func(t *testing.T) {
+		local := gen.LoginUserRequest{Username: "bob@corp.example.com", Password: "rLy_5tr0nG!"}
+		require.Equal(t, http.StatusCreated, doJSON(t, srv, "/register", "", local, nil))
+
+		relayState, requestID := login(t)
+		a := alice(requestID)
+		a.nameID = "bob-persistent-id"
+		a.attributes["mail"] = []string{"bob@corp.example.com"}
+		require.Equal(t, http.StatusConflict, acs(t, relayState, idp.response(t, idp.key, a), nil))
+	})
+
+	t.Run("unknown provider", func(t *testing.T) {
+		require.Equal(t, http.StatusNotFound, doRequest(t, srv, http.MethodGet, "/saml/login?provider=unknown", "", nil, nil))
+	})
+}
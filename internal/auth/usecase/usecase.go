@@ -2,168 +2,323 @@ package usecase
 
 import (
 	"context"
-	"github.com/google/uuid"
+	"errors"
+	"fmt"
 	"github.com/labstack/gommon/log"
 	"net/http"
 	"strings"
+	"time"
 
+	"github.com/bogatyr285/auth-go/internal/auth/account"
 	"github.com/bogatyr285/auth-go/internal/auth/entity"
-	"github.com/bogatyr285/auth-go/internal/buildinfo"
+	"github.com/bogatyr285/auth-go/internal/auth/pat"
 	"github.com/bogatyr285/auth-go/internal/gateway/http/gen"
+	pkgjwt "github.com/bogatyr285/auth-go/pkg/jwt"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-webauthn/webauthn/webauthn"
 	"github.com/golang-jwt/jwt/v5"
 )
 
 type UserRepository interface {
-	RegisterUser(ctx context.Context, u entity.UserAccount) error
 	FindUserByEmail(ctx context.Context, username string) (entity.UserAccount, error)
 	GetUserById(ctx context.Context, ID int) (entity.UserAccount, error)
-	GenerateUserToken(ctx context.Context, userID int) (uuid.UUID, error)
-	ExistsToken(ctx context.Context, token string) (bool, error)
-	SelectUserByToken(ctx context.Context, token string) (entity.UserAccount, error)
 	ExistsUserByUsername(ctx context.Context, username string) (bool, error)
-	ExistsTokenByUserID(ctx context.Context, userID int) (string, error)
-}
 
-type CryptoPassword interface {
-	HashPassword(password string) ([]byte, error)
-	ComparePasswords(fromUser, fromDB string) bool
+	SaveWebAuthnCredential(ctx context.Context, c entity.WebAuthnCredential) error
+	ListWebAuthnCredentials(ctx context.Context, userID int) ([]entity.WebAuthnCredential, error)
+	UpdateWebAuthnSignCount(ctx context.Context, credentialID []byte, signCount uint32, backupState bool) error
+	SaveWebAuthnSession(ctx context.Context, session entity.WebAuthnSession) error
+	TakeWebAuthnSession(ctx context.Context, ID string) (entity.WebAuthnSession, error)
+
+	SaveMagicLink(ctx context.Context, link entity.MagicLink) error
+	CountMagicLinksSince(ctx context.Context, email string, since time.Time) (int, error)
+	ConsumeMagicLink(ctx context.Context, ID string) (entity.MagicLink, error)
+	FindUserByVerifiedEmail(ctx context.Context, email string) (entity.UserAccount, error)
+
+	ListUserRoles(ctx context.Context, userID int) ([]string, error)
+	GrantUserRole(ctx context.Context, userID int, role string) error
+	RevokeUserRole(ctx context.Context, userID int, role string) error
+	SaveOAuthClient(ctx context.Context, c entity.OAuthClient) error
+	GetOAuthClient(ctx context.Context, ID string) (entity.OAuthClient, error)
+	ListOAuthClients(ctx context.Context) ([]entity.OAuthClient, error)
+	UpdateOAuthClient(ctx context.Context, c entity.OAuthClient) error
+	DeleteOAuthClient(ctx context.Context, ID string) error
+	AddClientSecret(ctx context.Context, secret entity.ClientSecret, overlapUntil time.Time) error
+
+	GetIdentity(ctx context.Context, provider, subject string) (entity.Identity, error)
+	ProvisionFederatedUser(ctx context.Context, u entity.UserAccount, identity entity.Identity) (int, error)
+	SaveFederatedLogin(ctx context.Context, login entity.FederatedLogin) error
+	TakeFederatedLogin(ctx context.Context, stateHash string) (entity.FederatedLogin, error)
+	ConsumeSAMLAssertion(ctx context.Context, provider, id string, expiresAt time.Time) (bool, error)
+	LinkIdentity(ctx context.Context, identity entity.Identity) error
+	ListLoginMethods(ctx context.Context, userID int) ([]entity.Identity, error)
+	UnlinkLoginMethod(ctx context.Context, userID int, provider, subject string) error
+
+	SavePersonalAccessToken(ctx context.Context, t entity.PersonalAccessToken) error
+	GetPersonalAccessToken(ctx context.Context, tokenHash string) (entity.PersonalAccessToken, error)
+	ListPersonalAccessTokens(ctx context.Context, userID int) ([]entity.PersonalAccessToken, error)
+	TouchPersonalAccessToken(ctx context.Context, ID string, usedAt time.Time) error
+	DeletePersonalAccessToken(ctx context.Context, userID int, ID string) error
 }
 
 type JWTManager interface {
 	IssueToken(userID string) (string, error)
+	IssueTokenWithClaims(userID string, extra jwt.MapClaims) (string, error)
 	VerifyToken(tokenString string) (*jwt.Token, error)
 }
 
-type AuthUseCase struct {
-	ur UserRepository
-	cp CryptoPassword
-	jm JWTManager
-	bi buildinfo.BuildInfo
+// EventPublisher - receives security events, e.g. events.Bus
+type EventPublisher interface {
+	Publish(e entity.SecurityEvent)
 }
 
-func (u AuthUseCase) PostRefresh(ctx context.Context, request gen.PostRefreshRequestObject) (gen.PostRefreshResponseObject, error) {
-	exists, err := u.ur.ExistsToken(ctx, request.Body.RefreshToken)
-	if err != nil {
-		return gen.PostRefresh500JSONResponse{}, nil
-	}
+const (
+	// tokenUseClaim - marks tokens which must not be accepted as access tokens
+	tokenUseClaim = pkgjwt.TokenUseClaim
+	tokenUseMFA   = account.TokenUseMFA
+)
+
+// publicPaths - routes available without access token
+var publicPaths = map[string]bool{
+	"/login":                 true,
+	"/register":              true,
+	"/buildinfo":             true,
+	"/refresh":               true,
+	"/webauthn/login/begin":  true,
+	"/webauthn/login/finish": true,
+	"/magic-link":            true,
+	"/magic-link/consume":    true,
+	"/federation/login":      true,
+	"/federation/callback":   true,
+	"/saml/metadata":         true,
+	"/saml/login":            true,
+	"/saml/acs":              true,
+	// OAuth endpoints authenticate users and clients on their own
+	"/authorize":                        true,
+	"/token":                            true,
+	"/device_authorization":             true,
+	"/device":                           true,
+	"/userinfo":                         true,
+	"/.well-known/openid-configuration": true,
+	"/.well-known/jwks.json":            true,
+}
+
+// routePermissions - permission a route requires on top of authentication,
+// keyed by method and route pattern
+var routePermissions = map[string]entity.Permission{
+	"GET /users/{id}":                               entity.PermissionUsersRead,
+	"GET /admin/users/{id}/roles":                   entity.PermissionRolesManage,
+	"POST /admin/users/{id}/roles":                  entity.PermissionRolesManage,
+	"DELETE /admin/users/{id}/roles/{role}":         entity.PermissionRolesManage,
+	"POST /relations/check":                         entity.PermissionRelationsRead,
+	"POST /relations/expand":                        entity.PermissionRelationsRead,
+	"POST /relations/objects":                       entity.PermissionRelationsRead,
+	"POST /relations/write":                         entity.PermissionRelationsWrite,
+	"GET /admin/oauth/clients":                      entity.PermissionClientsManage,
+	"POST /admin/oauth/clients":                     entity.PermissionClientsManage,
+	"GET /admin/oauth/clients/{client_id}":          entity.PermissionClientsManage,
+	"PUT /admin/oauth/clients/{client_id}":          entity.PermissionClientsManage,
+	"DELETE /admin/oauth/clients/{client_id}":       entity.PermissionClientsManage,
+	"POST /admin/oauth/clients/{client_id}/secrets": entity.PermissionClientsManage,
+}
 
-	if !exists {
-		return gen.PostRefresh500JSONResponse{}, nil
+// permissionRoutes - resolves request path to the route pattern of routePermissions
+var permissionRoutes = func() *chi.Mux {
+	r := chi.NewRouter()
+	for route := range routePermissions {
+		method, pattern, _ := strings.Cut(route, " ")
+		r.MethodFunc(method, pattern, http.NotFound)
 	}
 
-	user, err := u.ur.SelectUserByToken(ctx, request.Body.RefreshToken)
-	if err != nil {
-		return gen.PostRefresh500JSONResponse{}, nil
+	return r
+}()
+
+func requiredPermission(r *http.Request) (entity.Permission, bool) {
+	rctx := chi.NewRouteContext()
+	if !permissionRoutes.Match(rctx, r.Method, r.URL.Path) {
+		return "", false
 	}
 
-	token, err := u.jm.IssueToken(user.Username)
+	p, ok := routePermissions[r.Method+" "+rctx.RoutePattern()]
+	return p, ok
+}
+
+type ctxKey int
+
+const (
+	subjectCtxKey ctxKey = iota
+	// authTimeCtxKey - when the subject signed in, missing for tokens issued by refresh
+	authTimeCtxKey
+)
+
+type AuthUseCase struct {
+	ur UserRepository
+	jm JWTManager
+	wa *webauthn.WebAuthn
+	ml MagicLinkSettings
+	re RelationEngine
+	// idps - external identity providers by name
+	idps   map[string]IdentityProvider
+	events EventPublisher
+	saml   SAMLSettings
+	// accounts - registration, login and tokens, shared with the gRPC API
+	accounts *account.Service
+}
+
+// Option - configures optional login methods of AuthUseCase
+type Option func(u *AuthUseCase)
+
+func (u AuthUseCase) PostRefresh(ctx context.Context, request gen.PostRefreshRequestObject) (gen.PostRefreshResponseObject, error) {
+	session, err := u.accounts.Refresh(ctx, request.Body.RefreshToken)
 	if err != nil {
-		return gen.PostRefresh500JSONResponse{}, err
+		if errors.Is(err, account.ErrInvalidRefreshToken) {
+			return gen.PostRefresh401JSONResponse{Error: "unauth"}, nil
+		}
+
+		log.Errorf("Failed to refresh token: %s", err)
+		return gen.PostRefresh500JSONResponse{Error: "internal error"}, nil
 	}
 
 	return gen.PostRefresh200JSONResponse{
-		AccessToken:  token,
-		RefreshToken: request.Body.RefreshToken,
+		AccessToken:  session.AccessToken,
+		RefreshToken: session.RefreshToken,
 	}, nil
 }
 
 func (u AuthUseCase) GetUsersId(ctx context.Context, request gen.GetUsersIdRequestObject) (gen.GetUsersIdResponseObject, error) {
-	if request.Id == 0 {
-		return gen.GetUsersId500JSONResponse{}, nil
-	}
-
-	user, err := u.ur.GetUserById(ctx, request.Id)
+	user, err := u.accounts.GetUser(ctx, request.Id)
 	if err != nil {
-		return gen.GetUsersId500JSONResponse{}, nil
+		if errors.Is(err, entity.ErrUserNotFound) {
+			return gen.GetUsersId404JSONResponse{Error: err.Error()}, nil
+		}
+
+		log.Errorf("Failed to get user %d: %s", request.Id, err)
+		return gen.GetUsersId500JSONResponse{Error: "internal error"}, nil
 	}
 
 	return gen.GetUsersId200JSONResponse{
+		Id:       user.ID,
 		Username: user.Username,
 	}, nil
 }
 
-func NewUseCase(ur UserRepository, cp CryptoPassword, jm JWTManager, bi buildinfo.BuildInfo) AuthUseCase {
-	return AuthUseCase{
-		ur: ur,
-		cp: cp,
-		jm: jm,
-		bi: bi,
+// NewUseCase - accounts are configured with the login directories and events, the
+// same service backs the gRPC API
+func NewUseCase(ur UserRepository, accounts *account.Service, jm JWTManager, opts ...Option) AuthUseCase {
+	u := AuthUseCase{
+		ur:       ur,
+		jm:       jm,
+		accounts: accounts,
 	}
+	for _, opt := range opts {
+		opt(&u)
+	}
+
+	return u
 }
 
-func (u AuthUseCase) PostLogin(ctx context.Context, request gen.PostLoginRequestObject) (gen.PostLoginResponseObject, error) {
-	user, err := u.ur.FindUserByEmail(ctx, request.Body.Username)
-	if err != nil {
-		return gen.PostLogin500JSONResponse{
-			Error: err.Error(),
-		}, nil
+// WithEvents - changes of login methods and personal access tokens are published, logins
+// are published by the account service
+func WithEvents(p EventPublisher) Option {
+	return func(u *AuthUseCase) {
+		u.events = p
 	}
+}
 
-	if !u.cp.ComparePasswords(user.Password, request.Body.Password) {
-		return gen.PostLogin401JSONResponse{Error: "unauth"}, nil
+func (u AuthUseCase) publish(e entity.SecurityEvent) {
+	if u.events != nil {
+		u.events.Publish(e)
 	}
+}
 
-	token, err := u.jm.IssueToken(user.Username)
+func (u AuthUseCase) PostLogin(ctx context.Context, request gen.PostLoginRequestObject) (gen.PostLoginResponseObject, error) {
+	session, err := u.accounts.Login(ctx, request.Body.Username, request.Body.Password)
 	if err != nil {
-		return gen.PostLogin500JSONResponse{}, err
-	}
+		if errors.Is(err, account.ErrInvalidCredentials) {
+			return gen.PostLogin401JSONResponse{Error: "unauth"}, nil
+		}
 
-	refreshToken, err := u.ur.ExistsTokenByUserID(ctx, user.ID)
-	if err != nil {
-		return gen.PostLogin500JSONResponse{}, err
+		log.Errorf("Failed to log in %s: %s", request.Body.Username, err)
+		return gen.PostLogin500JSONResponse{Error: "internal error"}, nil
 	}
 
-	if refreshToken == "" {
-		var rt uuid.UUID
-		rt, err = u.ur.GenerateUserToken(ctx, user.ID)
-		if err != nil {
-			return gen.PostLogin500JSONResponse{}, err
-		}
-
-		refreshToken = rt.String()
+	if session.MFARequired() {
+		return gen.PostLogin202JSONResponse{
+			MfaToken: session.MFAToken,
+			Methods:  session.MFAMethods,
+		}, nil
 	}
 
 	return gen.PostLogin200JSONResponse{
-		AccessToken:  token,
-		RefreshToken: refreshToken,
+		AccessToken:  session.AccessToken,
+		RefreshToken: session.RefreshToken,
 	}, nil
 }
 
-func (u AuthUseCase) PostRegister(ctx context.Context, request gen.PostRegisterRequestObject) (gen.PostRegisterResponseObject, error) {
-	hashedPassword, err := u.cp.HashPassword(request.Body.Password)
+// verifyMFAToken - returns username the mfa token was issued for
+func (u AuthUseCase) verifyMFAToken(mfaToken string) (string, error) {
+	claims, err := u.verifyTokenUse(mfaToken, tokenUseMFA)
 	if err != nil {
-		return gen.PostRegister500JSONResponse{}, nil
+		return "", err
 	}
 
-	user := entity.UserAccount{
-		Username: request.Body.Username,
-		Password: string(hashedPassword),
-	}
+	return claims.GetSubject()
+}
 
-	err = u.ur.RegisterUser(ctx, user)
+// verifyTokenUse - verifies special purpose token, access tokens are rejected
+func (u AuthUseCase) verifyTokenUse(tokenString, use string) (jwt.MapClaims, error) {
+	token, err := u.jm.VerifyToken(tokenString)
 	if err != nil {
-		return gen.PostRegister500JSONResponse{}, nil
+		return nil, err
+	}
+
+	claims := token.Claims.(jwt.MapClaims)
+	if claims[tokenUseClaim] != use {
+		return nil, fmt.Errorf("not a %s token", use)
 	}
+
+	return claims, nil
+}
+
+func subjectFromContext(ctx context.Context) (string, bool) {
+	sub, ok := ctx.Value(subjectCtxKey).(string)
+	return sub, ok && sub != ""
+}
+
+func (u AuthUseCase) PostRegister(ctx context.Context, request gen.PostRegisterRequestObject) (gen.PostRegisterResponseObject, error) {
+	user, err := u.accounts.Register(ctx, entity.UserAccount{Username: request.Body.Username}, request.Body.Password)
+	switch {
+	case errors.Is(err, account.ErrUsernameRequired):
+		return gen.PostRegister400JSONResponse{Error: err.Error()}, nil
+	case errors.Is(err, account.ErrUsernameTaken):
+		return gen.PostRegister409JSONResponse{Error: err.Error()}, nil
+	case err != nil:
+		log.Errorf("Failed to register %s: %s", request.Body.Username, err)
+		return gen.PostRegister500JSONResponse{Error: "internal error"}, nil
+	}
+
 	return gen.PostRegister201JSONResponse{
-		Username: request.Body.Username,
+		Username: user.Username,
 	}, nil
 }
 
 func (u AuthUseCase) GetBuildinfo(ctx context.Context, request gen.GetBuildinfoRequestObject) (gen.GetBuildinfoResponseObject, error) {
+	bi := u.accounts.BuildInfo()
+
 	return gen.GetBuildinfo200JSONResponse{
-		Arch:       u.bi.Arch,
-		BuildDate:  u.bi.BuildDate,
-		CommitHash: u.bi.CommitHash,
-		Compiler:   u.bi.Compiler,
-		GoVersion:  u.bi.GoVersion,
-		Os:         u.bi.OS,
-		Version:    u.bi.Version,
+		Arch:       bi.Arch,
+		BuildDate:  bi.BuildDate,
+		CommitHash: bi.CommitHash,
+		Compiler:   bi.Compiler,
+		GoVersion:  bi.GoVersion,
+		Os:         bi.OS,
+		Version:    bi.Version,
 	}, nil
 }
 
 func (u AuthUseCase) AuthMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if r.URL.Path == "/login" || r.URL.Path == "/register" || r.URL.Path == "/build" || r.URL.Path == "/refresh" {
+		if publicPaths[r.URL.Path] {
 			next.ServeHTTP(w, r)
 			return
 		}
@@ -183,6 +338,11 @@ func (u AuthUseCase) AuthMiddleware(next http.Handler) http.Handler {
 		}
 
 		tokenString := parts[1]
+		if pat.IsToken(tokenString) {
+			u.personalAccessTokenAuth(w, r, next, tokenString)
+			return
+		}
+
 		verifyToken, err := u.jm.VerifyToken(tokenString)
 		if err != nil {
 			log.Errorf("Failed to verify token: %s", err)
@@ -191,7 +351,20 @@ func (u AuthUseCase) AuthMiddleware(next http.Handler) http.Handler {
 		}
 
 		claims := verifyToken.Claims.(jwt.MapClaims)
-		exists, err := u.ur.ExistsUserByUsername(r.Context(), claims["sub"].(string))
+		if !pkgjwt.FirstParty(claims) {
+			log.Errorf("Token of %v which isn't a first-party access token was rejected", claims["sub"])
+			http.Error(w, "Invalid Authorization header format", http.StatusUnauthorized)
+			return
+		}
+
+		sub, err := claims.GetSubject()
+		if err != nil {
+			log.Errorf("Failed to verify token: %s", err)
+			http.Error(w, "Invalid Authorization header format", http.StatusUnauthorized)
+			return
+		}
+
+		exists, err := u.ur.ExistsUserByUsername(r.Context(), sub)
 		if err != nil {
 			log.Errorf("Failed to verify token: %s", err)
 			http.Error(w, "Internal server error", http.StatusInternalServerError)
@@ -199,11 +372,55 @@ func (u AuthUseCase) AuthMiddleware(next http.Handler) http.Handler {
 		}
 
 		if !exists {
-			log.Errorf("User not found: %s", claims["username"].(string))
+			log.Errorf("User not found: %s", sub)
 			http.Error(w, "User not found", http.StatusUnauthorized)
 			return
 		}
 
-		next.ServeHTTP(w, r.WithContext(r.Context()))
+		if p, ok := requiredPermission(r); ok && !entity.HasPermission(pkgjwt.RolesFromClaims(claims), p) {
+			log.Errorf("User %s lacks permission %s", sub, p)
+			http.Error(w, "Forbidden", http.StatusForbidden)
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), subjectCtxKey, sub)
+		if authTime, ok := claims[pkgjwt.AuthTimeClaim].(float64); ok {
+			ctx = context.WithValue(ctx, authTimeCtxKey, time.Unix(int64(authTime), 0))
+		}
+
+		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
+
+// personalAccessTokenAuth - AuthMiddleware for personal access tokens, their scopes
+// limit the permissions of the user's roles
+func (u AuthUseCase) personalAccessTokenAuth(w http.ResponseWriter, r *http.Request, next http.Handler, token string) {
+	principal, err := pat.NewVerifier(u.ur).Verify(r.Context(), token)
+	if err != nil {
+		log.Errorf("Failed to verify access token: %s", err)
+		if errors.Is(err, pat.ErrInvalidToken) {
+			http.Error(w, "Invalid access token", http.StatusUnauthorized)
+			return
+		}
+
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	// routes without a permission manage the account itself, e.g. tokens and passkeys,
+	// so they take sign in rather than a token of any scope
+	p, ok := requiredPermission(r)
+	if !ok {
+		log.Errorf("Access token %s of %s used for %s %s without a permission", principal.TokenID, principal.Username, r.Method, r.URL.Path)
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	if !principal.HasPermission(p) {
+		log.Errorf("Access token %s of %s lacks permission %s", principal.TokenID, principal.Username, p)
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), subjectCtxKey, principal.Username)))
+}
@@ -0,0 +1,422 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/bogatyr285/auth-go/internal/auth/entity"
+	"github.com/bogatyr285/auth-go/internal/gateway/http/gen"
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/google/uuid"
+	"github.com/labstack/gommon/log"
+)
+
+const (
+	ceremonyRegistration = "registration"
+	ceremonyLogin        = "login"
+	webAuthnSessionTTL   = 5 * time.Minute
+)
+
+// WithWebAuthn - enables passkey registration and login
+func WithWebAuthn(wa *webauthn.WebAuthn) Option {
+	return func(u *AuthUseCase) {
+		u.wa = wa
+	}
+}
+
+// webAuthnUser - adapts stored account and its credentials to webauthn.User
+type webAuthnUser struct {
+	account     entity.UserAccount
+	credentials []entity.WebAuthnCredential
+}
+
+func (w webAuthnUser) WebAuthnID() []byte {
+	return []byte(strconv.Itoa(w.account.ID))
+}
+
+func (w webAuthnUser) WebAuthnName() string {
+	return w.account.Username
+}
+
+func (w webAuthnUser) WebAuthnDisplayName() string {
+	return w.account.Username
+}
+
+func (w webAuthnUser) WebAuthnIcon() string {
+	return ""
+}
+
+func (w webAuthnUser) WebAuthnCredentials() []webauthn.Credential {
+	credentials := make([]webauthn.Credential, 0, len(w.credentials))
+	for _, c := range w.credentials {
+		transports := make([]protocol.AuthenticatorTransport, 0, len(c.Transports))
+		for _, t := range c.Transports {
+			transports = append(transports, protocol.AuthenticatorTransport(t))
+		}
+
+		credentials = append(credentials, webauthn.Credential{
+			ID:              c.CredentialID,
+			PublicKey:       c.PublicKey,
+			AttestationType: c.AttestationType,
+			Transport:       transports,
+			Flags: webauthn.CredentialFlags{
+				BackupEligible: c.BackupEligible,
+				BackupState:    c.BackupState,
+			},
+			Authenticator: webauthn.Authenticator{
+				AAGUID:    c.AAGUID,
+				SignCount: c.SignCount,
+			},
+		})
+	}
+
+	return credentials
+}
+
+func (u AuthUseCase) PostWebauthnRegisterBegin(ctx context.Context, request gen.PostWebauthnRegisterBeginRequestObject) (gen.PostWebauthnRegisterBeginResponseObject, error) {
+	if u.wa == nil {
+		return gen.PostWebauthnRegisterBegin500JSONResponse{Error: "webauthn is not configured"}, nil
+	}
+
+	username, ok := subjectFromContext(ctx)
+	if !ok {
+		return gen.PostWebauthnRegisterBegin401JSONResponse{Error: "unauth"}, nil
+	}
+
+	account, err := u.ur.FindUserByEmail(ctx, username)
+	if err != nil {
+		return gen.PostWebauthnRegisterBegin401JSONResponse{Error: "unauth"}, nil
+	}
+
+	if !recentlyAuthenticated(ctx) {
+		return gen.PostWebauthnRegisterBegin403JSONResponse{Error: "sign in again to register a passkey"}, nil
+	}
+
+	user, err := u.webAuthnUser(ctx, account)
+	if err != nil {
+		log.Errorf("Failed to load webauthn credentials: %s", err)
+		return gen.PostWebauthnRegisterBegin500JSONResponse{Error: "internal error"}, nil
+	}
+
+	exclusions := make([]protocol.CredentialDescriptor, 0, len(user.credentials))
+	for _, c := range user.WebAuthnCredentials() {
+		exclusions = append(exclusions, c.Descriptor())
+	}
+
+	creation, sessionData, err := u.wa.BeginRegistration(user,
+		webauthn.WithExclusions(exclusions),
+		webauthn.WithResidentKeyRequirement(protocol.ResidentKeyRequirementPreferred),
+	)
+	if err != nil {
+		log.Errorf("Failed to begin webauthn registration: %s", err)
+		return gen.PostWebauthnRegisterBegin500JSONResponse{Error: "internal error"}, nil
+	}
+
+	ceremony, err := u.startWebAuthnCeremony(ctx, account.ID, ceremonyRegistration, creation, sessionData)
+	if err != nil {
+		log.Errorf("Failed to save webauthn session: %s", err)
+		return gen.PostWebauthnRegisterBegin500JSONResponse{Error: "internal error"}, nil
+	}
+
+	return gen.PostWebauthnRegisterBegin200JSONResponse(ceremony), nil
+}
+
+func (u AuthUseCase) PostWebauthnRegisterFinish(ctx context.Context, request gen.PostWebauthnRegisterFinishRequestObject) (gen.PostWebauthnRegisterFinishResponseObject, error) {
+	if u.wa == nil {
+		return gen.PostWebauthnRegisterFinish500JSONResponse{Error: "webauthn is not configured"}, nil
+	}
+
+	username, ok := subjectFromContext(ctx)
+	if !ok {
+		return gen.PostWebauthnRegisterFinish401JSONResponse{Error: "unauth"}, nil
+	}
+
+	account, err := u.ur.FindUserByEmail(ctx, username)
+	if err != nil {
+		return gen.PostWebauthnRegisterFinish401JSONResponse{Error: "unauth"}, nil
+	}
+
+	if !recentlyAuthenticated(ctx) {
+		return gen.PostWebauthnRegisterFinish403JSONResponse{Error: "sign in again to register a passkey"}, nil
+	}
+
+	session, sessionData, err := u.takeWebAuthnSession(ctx, request.Body.SessionId, ceremonyRegistration)
+	if err != nil {
+		return gen.PostWebauthnRegisterFinish400JSONResponse{Error: err.Error()}, nil
+	}
+
+	if session.UserID != account.ID {
+		return gen.PostWebauthnRegisterFinish400JSONResponse{Error: "webauthn session belongs to another user"}, nil
+	}
+
+	body, err := json.Marshal(request.Body.Credential)
+	if err != nil {
+		return gen.PostWebauthnRegisterFinish400JSONResponse{Error: err.Error()}, nil
+	}
+
+	parsed, err := protocol.ParseCredentialCreationResponseBody(bytes.NewReader(body))
+	if err != nil {
+		return gen.PostWebauthnRegisterFinish400JSONResponse{Error: webAuthnErrorMessage(err)}, nil
+	}
+
+	user, err := u.webAuthnUser(ctx, account)
+	if err != nil {
+		log.Errorf("Failed to load webauthn credentials: %s", err)
+		return gen.PostWebauthnRegisterFinish500JSONResponse{Error: "internal error"}, nil
+	}
+
+	credential, err := u.wa.CreateCredential(user, sessionData, parsed)
+	if err != nil {
+		return gen.PostWebauthnRegisterFinish400JSONResponse{Error: webAuthnErrorMessage(err)}, nil
+	}
+
+	transports := make([]string, 0, len(credential.Transport))
+	for _, t := range credential.Transport {
+		transports = append(transports, string(t))
+	}
+
+	err = u.ur.SaveWebAuthnCredential(ctx, entity.WebAuthnCredential{
+		UserID:          account.ID,
+		CredentialID:    credential.ID,
+		PublicKey:       credential.PublicKey,
+		AttestationType: credential.AttestationType,
+		AAGUID:          credential.Authenticator.AAGUID,
+		SignCount:       credential.Authenticator.SignCount,
+		Transports:      transports,
+		BackupEligible:  credential.Flags.BackupEligible,
+		BackupState:     credential.Flags.BackupState,
+	})
+	if err != nil {
+		log.Errorf("Failed to save webauthn credential: %s", err)
+		return gen.PostWebauthnRegisterFinish500JSONResponse{Error: "internal error"}, nil
+	}
+
+	u.publish(entity.SecurityEvent{
+		Type:     entity.SecurityEventPasskeyRegistered,
+		UserID:   account.ID,
+		Username: account.Username,
+		Provider: entity.IdentityWebAuthn,
+	})
+
+	return gen.PostWebauthnRegisterFinish201JSONResponse{
+		CredentialId: base64.RawURLEncoding.EncodeToString(credential.ID),
+		Transports:   transports,
+	}, nil
+}
+
+func (u AuthUseCase) PostWebauthnLoginBegin(ctx context.Context, request gen.PostWebauthnLoginBeginRequestObject) (gen.PostWebauthnLoginBeginResponseObject, error) {
+	if u.wa == nil {
+		return gen.PostWebauthnLoginBegin500JSONResponse{Error: "webauthn is not configured"}, nil
+	}
+
+	var username string
+	if request.Body.MfaToken != nil {
+		sub, err := u.verifyMFAToken(*request.Body.MfaToken)
+		if err != nil {
+			return gen.PostWebauthnLoginBegin401JSONResponse{Error: "unauth"}, nil
+		}
+		username = sub
+	}
+
+	// without mfa token the login is discoverable, the username isn't looked up so the
+	// response doesn't tell which users exist or have passkeys
+	if username == "" {
+		assertion, sessionData, err := u.wa.BeginDiscoverableLogin()
+		if err != nil {
+			log.Errorf("Failed to begin webauthn login: %s", err)
+			return gen.PostWebauthnLoginBegin500JSONResponse{Error: "internal error"}, nil
+		}
+
+		ceremony, err := u.startWebAuthnCeremony(ctx, 0, ceremonyLogin, assertion, sessionData)
+		if err != nil {
+			log.Errorf("Failed to save webauthn session: %s", err)
+			return gen.PostWebauthnLoginBegin500JSONResponse{Error: "internal error"}, nil
+		}
+
+		return gen.PostWebauthnLoginBegin200JSONResponse(ceremony), nil
+	}
+
+	account, err := u.ur.FindUserByEmail(ctx, username)
+	if err != nil {
+		return gen.PostWebauthnLoginBegin401JSONResponse{Error: "unauth"}, nil
+	}
+
+	user, err := u.webAuthnUser(ctx, account)
+	if err != nil {
+		log.Errorf("Failed to load webauthn credentials: %s", err)
+		return gen.PostWebauthnLoginBegin500JSONResponse{Error: "internal error"}, nil
+	}
+
+	if len(user.credentials) == 0 {
+		return gen.PostWebauthnLoginBegin400JSONResponse{Error: "no passkeys registered"}, nil
+	}
+
+	assertion, sessionData, err := u.wa.BeginLogin(user)
+	if err != nil {
+		log.Errorf("Failed to begin webauthn login: %s", err)
+		return gen.PostWebauthnLoginBegin500JSONResponse{Error: "internal error"}, nil
+	}
+
+	ceremony, err := u.startWebAuthnCeremony(ctx, account.ID, ceremonyLogin, assertion, sessionData)
+	if err != nil {
+		log.Errorf("Failed to save webauthn session: %s", err)
+		return gen.PostWebauthnLoginBegin500JSONResponse{Error: "internal error"}, nil
+	}
+
+	return gen.PostWebauthnLoginBegin200JSONResponse(ceremony), nil
+}
+
+func (u AuthUseCase) PostWebauthnLoginFinish(ctx context.Context, request gen.PostWebauthnLoginFinishRequestObject) (gen.PostWebauthnLoginFinishResponseObject, error) {
+	if u.wa == nil {
+		return gen.PostWebauthnLoginFinish500JSONResponse{Error: "webauthn is not configured"}, nil
+	}
+
+	session, sessionData, err := u.takeWebAuthnSession(ctx, request.Body.SessionId, ceremonyLogin)
+	if err != nil {
+		return gen.PostWebauthnLoginFinish400JSONResponse{Error: err.Error()}, nil
+	}
+
+	body, err := json.Marshal(request.Body.Credential)
+	if err != nil {
+		return gen.PostWebauthnLoginFinish400JSONResponse{Error: err.Error()}, nil
+	}
+
+	parsed, err := protocol.ParseCredentialRequestResponseBody(bytes.NewReader(body))
+	if err != nil {
+		return gen.PostWebauthnLoginFinish400JSONResponse{Error: webAuthnErrorMessage(err)}, nil
+	}
+
+	var user webAuthnUser
+	var credential *webauthn.Credential
+	if session.UserID == 0 {
+		credential, err = u.wa.ValidateDiscoverableLogin(func(_, userHandle []byte) (webauthn.User, error) {
+			userID, err := strconv.Atoi(string(userHandle))
+			if err != nil {
+				return nil, fmt.Errorf("invalid user handle")
+			}
+
+			user, err = u.webAuthnUserByID(ctx, userID)
+			return user, err
+		}, sessionData, parsed)
+	} else {
+		user, err = u.webAuthnUserByID(ctx, session.UserID)
+		if err != nil {
+			log.Errorf("Failed to load webauthn user: %s", err)
+			return gen.PostWebauthnLoginFinish500JSONResponse{Error: "internal error"}, nil
+		}
+
+		credential, err = u.wa.ValidateLogin(user, sessionData, parsed)
+	}
+	if err != nil {
+		log.Errorf("Failed to validate webauthn assertion: %s", webAuthnErrorMessage(err))
+		return gen.PostWebauthnLoginFinish401JSONResponse{Error: "unauth"}, nil
+	}
+
+	// sign count went backwards - the authenticator is likely cloned
+	if credential.Authenticator.CloneWarning {
+		log.Errorf("Webauthn sign count regression for user %d", user.account.ID)
+		return gen.PostWebauthnLoginFinish401JSONResponse{Error: "unauth"}, nil
+	}
+
+	err = u.ur.UpdateWebAuthnSignCount(ctx, credential.ID, credential.Authenticator.SignCount, credential.Flags.BackupState)
+	if err != nil {
+		log.Errorf("Failed to update webauthn sign count: %s", err)
+		return gen.PostWebauthnLoginFinish500JSONResponse{Error: "internal error"}, nil
+	}
+
+	tokens, err := u.accounts.IssueTokens(ctx, user.account)
+	if err != nil {
+		return gen.PostWebauthnLoginFinish500JSONResponse{}, err
+	}
+
+	return gen.PostWebauthnLoginFinish200JSONResponse{
+		AccessToken:  tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+	}, nil
+}
+
+func (u AuthUseCase) webAuthnUser(ctx context.Context, account entity.UserAccount) (webAuthnUser, error) {
+	credentials, err := u.ur.ListWebAuthnCredentials(ctx, account.ID)
+	if err != nil {
+		return webAuthnUser{}, err
+	}
+
+	return webAuthnUser{account: account, credentials: credentials}, nil
+}
+
+func (u AuthUseCase) webAuthnUserByID(ctx context.Context, userID int) (webAuthnUser, error) {
+	account, err := u.ur.GetUserById(ctx, userID)
+	if err != nil {
+		return webAuthnUser{}, err
+	}
+
+	return u.webAuthnUser(ctx, account)
+}
+
+// startWebAuthnCeremony - stores session data and returns options for the client
+func (u AuthUseCase) startWebAuthnCeremony(ctx context.Context, userID int, ceremony string, options any, sessionData *webauthn.SessionData) (gen.WebAuthnCeremony, error) {
+	data, err := json.Marshal(sessionData)
+	if err != nil {
+		return gen.WebAuthnCeremony{}, err
+	}
+
+	session := entity.WebAuthnSession{
+		ID:        uuid.NewString(),
+		UserID:    userID,
+		Ceremony:  ceremony,
+		Data:      data,
+		ExpiresAt: time.Now().Add(webAuthnSessionTTL),
+	}
+	if err = u.ur.SaveWebAuthnSession(ctx, session); err != nil {
+		return gen.WebAuthnCeremony{}, err
+	}
+
+	raw, err := json.Marshal(options)
+	if err != nil {
+		return gen.WebAuthnCeremony{}, err
+	}
+
+	var opts map[string]interface{}
+	if err = json.Unmarshal(raw, &opts); err != nil {
+		return gen.WebAuthnCeremony{}, err
+	}
+
+	return gen.WebAuthnCeremony{
+		SessionId: session.ID,
+		Options:   opts,
+	}, nil
+}
+
+// takeWebAuthnSession - consumes the session, it can't be used twice
+func (u AuthUseCase) takeWebAuthnSession(ctx context.Context, ID, ceremony string) (entity.WebAuthnSession, webauthn.SessionData, error) {
+	session, err := u.ur.TakeWebAuthnSession(ctx, ID)
+	if err != nil {
+		return entity.WebAuthnSession{}, webauthn.SessionData{}, err
+	}
+
+	if session.Ceremony != ceremony || time.Now().After(session.ExpiresAt) {
+		return entity.WebAuthnSession{}, webauthn.SessionData{}, fmt.Errorf("webauthn session not found")
+	}
+
+	var sessionData webauthn.SessionData
+	if err = json.Unmarshal(session.Data, &sessionData); err != nil {
+		return entity.WebAuthnSession{}, webauthn.SessionData{}, err
+	}
+
+	return session, sessionData, nil
+}
+
+// webAuthnErrorMessage - protocol errors keep the useful part in details
+func webAuthnErrorMessage(err error) string {
+	if perr, ok := err.(*protocol.Error); ok && perr.Details != "" {
+		return fmt.Sprintf("%s: %s", perr.Error(), perr.Details)
+	}
+
+	return err.Error()
+}
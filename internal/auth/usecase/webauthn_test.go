@@ -0,0 +1,232 @@
+package usecase_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/bogatyr285/auth-go/internal/auth/entity"
+	"github.com/bogatyr285/auth-go/internal/auth/events"
+	"github.com/bogatyr285/auth-go/internal/gateway/http/gen"
+	"github.com/fxamacker/cbor/v2"
+	"github.com/stretchr/testify/require"
+)
+
+// softAuthenticator - software stand-in of a platform authenticator with a single resident P-256 key
+type softAuthenticator struct {
+	key          *ecdsa.PrivateKey
+	credentialID []byte
+	userHandle   []byte
+	signCount    uint32
+}
+
+func newSoftAuthenticator(t *testing.T) *softAuthenticator {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	credentialID := make([]byte, 16)
+	_, err = rand.Read(credentialID)
+	require.NoError(t, err)
+
+	return &softAuthenticator{key: key, credentialID: credentialID}
+}
+
+// create - navigator.credentials.create() with "none" attestation
+func (a *softAuthenticator) create(t *testing.T, options map[string]interface{}) map[string]interface{} {
+	publicKey := options["publicKey"].(map[string]interface{})
+	user := publicKey["user"].(map[string]interface{})
+
+	userHandle, err := base64.RawURLEncoding.DecodeString(user["id"].(string))
+	require.NoError(t, err)
+	a.userHandle = userHandle
+
+	clientData := a.clientData(t, "webauthn.create", publicKey["challenge"].(string))
+
+	coseKey, err := cbor.Marshal(map[int]interface{}{
+		1:  2,  // kty: EC2
+		3:  -7, // alg: ES256
+		-1: 1,  // crv: P-256
+		-2: a.key.X.FillBytes(make([]byte, 32)),
+		-3: a.key.Y.FillBytes(make([]byte, 32)),
+	})
+	require.NoError(t, err)
+
+	// user present, user verified, attested credential data included
+	authData := a.authData(0x01 | 0x04 | 0x40)
+	authData = append(authData, make([]byte, 16)...) // AAGUID
+	authData = binary.BigEndian.AppendUint16(authData, uint16(len(a.credentialID)))
+	authData = append(authData, a.credentialID...)
+	authData = append(authData, coseKey...)
+
+	attestationObject, err := cbor.Marshal(map[string]interface{}{
+		"fmt":      "none",
+		"attStmt":  map[string]interface{}{},
+		"authData": authData,
+	})
+	require.NoError(t, err)
+
+	return map[string]interface{}{
+		"id":    base64.RawURLEncoding.EncodeToString(a.credentialID),
+		"rawId": base64.RawURLEncoding.EncodeToString(a.credentialID),
+		"type":  "public-key",
+		"response": map[string]interface{}{
+			"clientDataJSON":    base64.RawURLEncoding.EncodeToString(clientData),
+			"attestationObject": base64.RawURLEncoding.EncodeToString(attestationObject),
+			"transports":        []string{"internal"},
+		},
+	}
+}
+
+// get - navigator.credentials.get()
+func (a *softAuthenticator) get(t *testing.T, options map[string]interface{}) map[string]interface{} {
+	publicKey := options["publicKey"].(map[string]interface{})
+	clientData := a.clientData(t, "webauthn.get", publicKey["challenge"].(string))
+
+	a.signCount++
+	authData := a.authData(0x01 | 0x04)
+
+	clientDataHash := sha256.Sum256(clientData)
+	digest := sha256.Sum256(append(append([]byte{}, authData...), clientDataHash[:]...))
+	signature, err := ecdsa.SignASN1(rand.Reader, a.key, digest[:])
+	require.NoError(t, err)
+
+	return map[string]interface{}{
+		"id":    base64.RawURLEncoding.EncodeToString(a.credentialID),
+		"rawId": base64.RawURLEncoding.EncodeToString(a.credentialID),
+		"type":  "public-key",
+		"response": map[string]interface{}{
+			"clientDataJSON":    base64.RawURLEncoding.EncodeToString(clientData),
+			"authenticatorData": base64.RawURLEncoding.EncodeToString(authData),
+			"signature":         base64.RawURLEncoding.EncodeToString(signature),
+			"userHandle":        base64.RawURLEncoding.EncodeToString(a.userHandle),
+		},
+	}
+}
+
+func (a *softAuthenticator) clientData(t *testing.T, typ, challenge string) []byte {
+	clientData, err := json.Marshal(map[string]string{
+		"type":      typ,
+		"challenge": challenge,
+		"origin":    testOrigin,
+	})
+	require.NoError(t, err)
+
+	return clientData
+}
+
+func (a *softAuthenticator) authData(flags byte) []byte {
+	rpIDHash := sha256.Sum256([]byte(testRPID))
+	authData := append(rpIDHash[:], flags)
+	return binary.BigEndian.AppendUint32(authData, a.signCount)
+}
+
+func TestWebAuthnPasskeys(t *testing.T) {
+	bus := events.NewBus(8)
+	srv := newTestServerWithEvents(t, bus)
+	sub := bus.Subscribe(func(e entity.SecurityEvent) bool { return e.Type != entity.SecurityEventLogin })
+	authenticator := newSoftAuthenticator(t)
+
+	credentials := gen.LoginUserRequest{Username: "alice@example.com", Password: "rLy_5tr0nG!"}
+	require.Equal(t, http.StatusCreated, doJSON(t, srv, "/register", "", credentials, nil))
+
+	var tokens gen.LoginUserResponse
+	require.Equal(t, http.StatusOK, doJSON(t, srv, "/login", "", credentials, &tokens))
+
+	// passkey registration is available for authenticated users only
+	require.Equal(t, http.StatusUnauthorized, doJSON(t, srv, "/webauthn/register/begin", "", nil, nil))
+
+	// refreshed tokens need to sign in again
+	var refreshed gen.TokenResponse
+	require.Equal(t, http.StatusOK, doJSON(t, srv, "/refresh", "", gen.TokenRequest{RefreshToken: tokens.RefreshToken}, &refreshed))
+	require.Equal(t, http.StatusForbidden, doJSON(t, srv, "/webauthn/register/begin", refreshed.AccessToken, nil, nil))
+	require.Equal(t, http.StatusForbidden, doJSON(t, srv, "/webauthn/register/finish", refreshed.AccessToken, gen.WebAuthnFinishRequest{
+		SessionId:  "unknown",
+		Credential: map[string]interface{}{},
+	}, nil))
+
+	var ceremony gen.WebAuthnCeremony
+	require.Equal(t, http.StatusOK, doJSON(t, srv, "/webauthn/register/begin", tokens.AccessToken, nil, &ceremony))
+
+	var registered gen.WebAuthnCredential
+	require.Equal(t, http.StatusCreated, doJSON(t, srv, "/webauthn/register/finish", tokens.AccessToken, gen.WebAuthnFinishRequest{
+		SessionId:  ceremony.SessionId,
+		Credential: authenticator.create(t, ceremony.Options),
+	}, &registered))
+	require.Equal(t, base64.RawURLEncoding.EncodeToString(authenticator.credentialID), registered.CredentialId)
+	require.Equal(t, []string{"internal"}, registered.Transports)
+
+	e := <-sub.Events()
+	require.Equal(t, entity.SecurityEventPasskeyRegistered, e.Type)
+	require.Equal(t, credentials.Username, e.Username)
+
+	t.Run("second factor", func(t *testing.T) {
+		var mfa gen.MFARequiredResponse
+		require.Equal(t, http.StatusAccepted, doJSON(t, srv, "/login", "", credentials, &mfa))
+		require.Equal(t, []string{"webauthn"}, mfa.Methods)
+
+		// mfa token must not work as access token
+		require.Equal(t, http.StatusUnauthorized, doJSON(t, srv, "/webauthn/register/begin", mfa.MfaToken, nil, nil))
+
+		var login gen.WebAuthnCeremony
+		require.Equal(t, http.StatusOK, doJSON(t, srv, "/webauthn/login/begin", "", gen.WebAuthnLoginBeginRequest{MfaToken: &mfa.MfaToken}, &login))
+
+		finish := gen.WebAuthnFinishRequest{SessionId: login.SessionId, Credential: authenticator.get(t, login.Options)}
+		var tokens gen.LoginUserResponse
+		require.Equal(t, http.StatusOK, doJSON(t, srv, "/webauthn/login/finish", "", finish, &tokens))
+		require.NotEmpty(t, tokens.AccessToken)
+		require.NotEmpty(t, tokens.RefreshToken)
+
+		// ceremony can't be replayed
+		require.Equal(t, http.StatusBadRequest, doJSON(t, srv, "/webauthn/login/finish", "", finish, nil))
+	})
+
+	t.Run("primary discoverable login", func(t *testing.T) {
+		var login gen.WebAuthnCeremony
+		require.Equal(t, http.StatusOK, doJSON(t, srv, "/webauthn/login/begin", "", gen.WebAuthnLoginBeginRequest{}, &login))
+
+		var tokens gen.LoginUserResponse
+		require.Equal(t, http.StatusOK, doJSON(t, srv, "/webauthn/login/finish", "", gen.WebAuthnFinishRequest{
+			SessionId:  login.SessionId,
+			Credential: authenticator.get(t, login.Options),
+		}, &tokens))
+		require.NotEmpty(t, tokens.AccessToken)
+	})
+
+	t.Run("users aren't enumerated", func(t *testing.T) {
+		bob := gen.LoginUserRequest{Username: "bob@example.com", Password: "rLy_5tr0nG!"}
+		require.Equal(t, http.StatusCreated, doJSON(t, srv, "/register", "", bob, nil))
+
+		// with passkeys, without passkeys and unknown
+		for _, username := range []string{credentials.Username, bob.Username, "nobody@example.com"} {
+			var login gen.WebAuthnCeremony
+			require.Equal(t, http.StatusOK, doJSON(t, srv, "/webauthn/login/begin", "", gen.WebAuthnLoginBeginRequest{Username: &username}, &login))
+			require.NotContains(t, login.Options["publicKey"], "allowCredentials", username)
+		}
+	})
+
+	t.Run("cloned authenticator", func(t *testing.T) {
+		var login gen.WebAuthnCeremony
+		require.Equal(t, http.StatusOK, doJSON(t, srv, "/webauthn/login/begin", "", gen.WebAuthnLoginBeginRequest{Username: &credentials.Username}, &login))
+
+		authenticator.signCount = 0
+		require.Equal(t, http.StatusUnauthorized, doJSON(t, srv, "/webauthn/login/finish", "", gen.WebAuthnFinishRequest{
+			SessionId:  login.SessionId,
+			Credential: authenticator.get(t, login.Options),
+		}, nil))
+	})
+
+	t.Run("password is removed", func(t *testing.T) {
+		require.Equal(t, http.StatusNoContent, doRequest(t, srv, http.MethodDelete, "/identities/password/"+credentials.Username, tokens.AccessToken, nil, nil))
+		require.Equal(t, http.StatusUnauthorized, doJSON(t, srv, "/login", "", credentials, nil))
+
+		e := <-sub.Events()
+		require.Equal(t, entity.SecurityEventPasswordRemoved, e.Type)
+		require.Equal(t, credentials.Username, e.Username)
+	})
+}
@@ -0,0 +1,255 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+	"time"
+
+	"connectrpc.com/connect"
+	authpb "github.com/bogatyr285/auth-go/pkg/server/grpc/auth"
+	"github.com/bogatyr285/auth-go/pkg/server/grpc/auth/authconnect"
+	"github.com/rs/cors"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type connectConfig struct {
+	cors *cors.Cors
+}
+
+type ConnectOption func(*connectConfig)
+
+// WithConnectCORS - browsers may call the handlers from the origins, "*" allows any.
+// Preflight responses are cached for maxAge
+func WithConnectCORS(allowedOrigins []string, maxAge time.Duration) ConnectOption {
+	return func(c *connectConfig) {
+		c.cors = cors.New(cors.Options{
+			AllowedOrigins: allowedOrigins,
+			AllowedMethods: []string{http.MethodGet, http.MethodPost},
+			AllowedHeaders: []string{
+				"Authorization",
+				"X-Request-ID",
+				"Content-Type",
+				"Connect-Protocol-Version",
+				"Connect-Timeout-Ms",
+				"Connect-Accept-Encoding",
+				"Connect-Content-Encoding",
+				"Grpc-Timeout",
+				"X-Grpc-Web",
+				"X-User-Agent",
+			},
+			ExposedHeaders: []string{
+				"Grpc-Status",
+				"Grpc-Message",
+				"Grpc-Status-Details-Bin",
+				"Connect-Content-Encoding",
+				"Content-Encoding",
+			},
+			MaxAge: int(maxAge.Seconds()),
+		})
+	}
+}
+
+// NewConnectHandler - AuthService for browsers over gRPC-Web and Connect protocols,
+// served under the returned path. Calls go to srv in process and pass the same
+// method policies as calls of the gRPC server
+func NewConnectHandler(srv authpb.AuthServiceServer, jm JWTManager, tokens AccessTokenRepository, logger *slog.Logger, opts ...ConnectOption) (string, http.Handler) {
+	logger = logger.With("module", "connect")
+
+	cfg := &connectConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	path, handler := authconnect.NewAuthServiceHandler(connectAuthService{srv: srv},
+		connect.WithInterceptors(connectAuthInterceptor{jm: jm, tokens: tokens}),
+		connect.WithRecover(func(_ context.Context, spec connect.Spec, _ http.Header, p any) error {
+			logger.Error("recovered from panic", slog.String("procedure", spec.Procedure), slog.Any("stack", string(debug.Stack())))
+			return connect.NewError(connect.CodeInternal, fmt.Errorf("%s", p))
+		}),
+	)
+	if cfg.cors != nil {
+		handler = cfg.cors.Handler(handler)
+	}
+
+	return path, withoutWriteDeadline(handler)
+}
+
+// connectAuthService - authconnect.AuthServiceHandler calling the gRPC handlers
+type connectAuthService struct {
+	srv authpb.AuthServiceServer
+}
+
+func (s connectAuthService) RegisterUser(ctx context.Context, req *connect.Request[authpb.RegisterUserRequest]) (*connect.Response[authpb.RegisterUserResponse], error) {
+	return callUnary(ctx, req, s.srv.RegisterUser)
+}
+
+func (s connectAuthService) LoginUser(ctx context.Context, req *connect.Request[authpb.LoginUserRequest]) (*connect.Response[authpb.LoginUserResponse], error) {
+	return callUnary(ctx, req, s.srv.LoginUser)
+}
+
+func (s connectAuthService) Refresh(ctx context.Context, req *connect.Request[authpb.RefreshRequest]) (*connect.Response[authpb.RefreshResponse], error) {
+	return callUnary(ctx, req, s.srv.Refresh)
+}
+
+func (s connectAuthService) GetUser(ctx context.Context, req *connect.Request[authpb.GetUserRequest]) (*connect.Response[authpb.GetUserResponse], error) {
+	return callUnary(ctx, req, s.srv.GetUser)
+}
+
+func (s connectAuthService) BuildInfo(ctx context.Context, req *connect.Request[authpb.BuildInfoRequest]) (*connect.Response[authpb.BuildInfoResponse], error) {
+	return callUnary(ctx, req, s.srv.BuildInfo)
+}
+
+func (s connectAuthService) UserInfo(ctx context.Context, req *connect.Request[authpb.UserInfoRequest]) (*connect.Response[authpb.UserInfoResponse], error) {
+	return callUnary(ctx, req, s.srv.UserInfo)
+}
+
+func (s connectAuthService) SendPhoneCode(ctx context.Context, req *connect.Request[authpb.SendPhoneCodeRequest]) (*connect.Response[authpb.SendPhoneCodeResponse], error) {
+	return callUnary(ctx, req, s.srv.SendPhoneCode)
+}
+
+func (s connectAuthService) VerifyPhone(ctx context.Context, req *connect.Request[authpb.VerifyPhoneRequest]) (*connect.Response[authpb.VerifyPhoneResponse], error) {
+	return callUnary(ctx, req, s.srv.VerifyPhone)
+}
+
+func (s connectAuthService) GrantRole(ctx context.Context, req *connect.Request[authpb.GrantRoleRequest]) (*connect.Response[authpb.UserRolesResponse], error) {
+	return callUnary(ctx, req, s.srv.GrantRole)
+}
+
+func (s connectAuthService) RevokeRole(ctx context.Context, req *connect.Request[authpb.RevokeRoleRequest]) (*connect.Response[authpb.UserRolesResponse], error) {
+	return callUnary(ctx, req, s.srv.RevokeRole)
+}
+
+func (s connectAuthService) WatchSecurityEvents(ctx context.Context, req *connect.Request[authpb.WatchSecurityEventsRequest], stream *connect.ServerStream[authpb.SecurityEvent]) error {
+	return connectError(s.srv.WatchSecurityEvents(req.Msg, &connectServerStream[authpb.SecurityEvent]{ctx: ctx, stream: stream}))
+}
+
+func (s connectAuthService) WatchAllSecurityEvents(ctx context.Context, req *connect.Request[authpb.WatchAllSecurityEventsRequest], stream *connect.ServerStream[authpb.SecurityEvent]) error {
+	return connectError(s.srv.WatchAllSecurityEvents(req.Msg, &connectServerStream[authpb.SecurityEvent]{ctx: ctx, stream: stream}))
+}
+
+func callUnary[Req, Res any](ctx context.Context, req *connect.Request[Req], call func(context.Context, *Req) (*Res, error)) (*connect.Response[Res], error) {
+	res, err := call(ctx, req.Msg)
+	if err != nil {
+		return nil, connectError(err)
+	}
+
+	return connect.NewResponse(res), nil
+}
+
+// connectServerStream - grpc.ServerStreamingServer of gRPC handlers sending to a Connect stream
+type connectServerStream[Res any] struct {
+	ctx    context.Context
+	stream *connect.ServerStream[Res]
+}
+
+func (s *connectServerStream[Res]) Send(m *Res) error {
+	return s.stream.Send(m)
+}
+
+func (s *connectServerStream[Res]) SetHeader(md metadata.MD) error {
+	for key, values := range md {
+		for _, v := range values {
+			s.stream.ResponseHeader().Add(key, v)
+		}
+	}
+
+	return nil
+}
+
+// SendHeader - Send of nil message sends headers only
+func (s *connectServerStream[Res]) SendHeader(md metadata.MD) error {
+	if err := s.SetHeader(md); err != nil {
+		return err
+	}
+
+	return s.stream.Send(nil)
+}
+
+func (s *connectServerStream[Res]) SetTrailer(md metadata.MD) {
+	for key, values := range md {
+		for _, v := range values {
+			s.stream.ResponseTrailer().Add(key, v)
+		}
+	}
+}
+
+func (s *connectServerStream[Res]) Context() context.Context {
+	return s.ctx
+}
+
+func (s *connectServerStream[Res]) SendMsg(m any) error {
+	return s.stream.Conn().Send(m)
+}
+
+// RecvMsg - the request of server streaming RPCs is received by Connect already
+func (s *connectServerStream[Res]) RecvMsg(any) error {
+	return io.EOF
+}
+
+// connectError - status of the gRPC handlers as a Connect error, codes of both are
+// the same. nil stays nil
+func connectError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	st, ok := status.FromError(err)
+	if !ok {
+		return connect.NewError(connect.CodeUnknown, err)
+	}
+
+	return connect.NewError(connect.Code(st.Code()), errors.New(st.Message()))
+}
+
+// connectAuthInterceptor - AuthUnaryInterceptor and AuthStreamInterceptor for Connect
+// handlers, request headers are the metadata
+type connectAuthInterceptor struct {
+	jm     JWTManager
+	tokens AccessTokenRepository
+}
+
+func (i connectAuthInterceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		ctx, err := i.authenticate(ctx, req.Spec().Procedure, req.Header())
+		if err != nil {
+			return nil, err
+		}
+
+		return next(ctx, req)
+	}
+}
+
+func (i connectAuthInterceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+func (i connectAuthInterceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		ctx, err := i.authenticate(ctx, conn.Spec().Procedure, conn.RequestHeader())
+		if err != nil {
+			return err
+		}
+
+		return next(ctx, conn)
+	}
+}
+
+func (i connectAuthInterceptor) authenticate(ctx context.Context, procedure string, header http.Header) (context.Context, error) {
+	md := metadata.MD{}
+	for key, values := range header {
+		md.Append(key, values...)
+	}
+
+	ctx, err := authenticate(metadata.NewIncomingContext(ctx, md), procedure, i.jm, i.tokens)
+	if err != nil {
+		return nil, connectError(err)
+	}
+
+	return ctx, nil
+}
@@ -0,0 +1,194 @@
+package auth_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/textproto"
+	"strconv"
+	"strings"
+
+	authpb "github.com/bogatyr285/auth-go/pkg/server/grpc/auth"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// connectCall - unary call of the Connect protocol with the JSON codec, status code
+// of the response and the Connect error code, empty on success
+func (s *grpcGatewaySuite) connectCall(method string, req, res proto.Message, token string) (int, string) {
+	body, err := protojson.Marshal(req)
+	s.Require().NoError(err)
+
+	httpReq, err := http.NewRequest(http.MethodPost, s.connectServer.URL+"/auth.v1.AuthService/"+method, bytes.NewReader(body))
+	s.Require().NoError(err)
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Connect-Protocol-Version", "1")
+	if token != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	httpRes, err := s.connectServer.Client().Do(httpReq)
+	s.Require().NoError(err)
+	defer httpRes.Body.Close()
+
+	body, err = io.ReadAll(httpRes.Body)
+	s.Require().NoError(err)
+
+	if httpRes.StatusCode != http.StatusOK {
+		var connectErr struct {
+			Code string `json:"code"`
+		}
+		s.Require().NoError(json.Unmarshal(body, &connectErr))
+		return httpRes.StatusCode, connectErr.Code
+	}
+
+	s.Require().NoError(protojson.Unmarshal(body, res))
+	return httpRes.StatusCode, ""
+}
+
+// grpcWebCall - unary call of the gRPC-Web protocol with binary frames, grpc-status
+// of the trailers
+func (s *grpcGatewaySuite) grpcWebCall(method string, req, res proto.Message, token string) codes.Code {
+	msg, err := proto.Marshal(req)
+	s.Require().NoError(err)
+
+	// frame: flags, big endian length, message
+	frame := make([]byte, 5, 5+len(msg))
+	binary.BigEndian.PutUint32(frame[1:], uint32(len(msg)))
+	frame = append(frame, msg...)
+
+	httpReq, err := http.NewRequest(http.MethodPost, s.connectServer.URL+"/auth.v1.AuthService/"+method, bytes.NewReader(frame))
+	s.Require().NoError(err)
+	httpReq.Header.Set("Content-Type", "application/grpc-web+proto")
+	httpReq.Header.Set("X-Grpc-Web", "1")
+	if token != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	httpRes, err := s.connectServer.Client().Do(httpReq)
+	s.Require().NoError(err)
+	defer httpRes.Body.Close()
+	s.Require().Equal(http.StatusOK, httpRes.StatusCode)
+
+	// errors without messages are trailers-only responses
+	if grpcStatus := httpRes.Header.Get("Grpc-Status"); grpcStatus != "" {
+		code, err := strconv.Atoi(grpcStatus)
+		s.Require().NoError(err)
+		return codes.Code(code)
+	}
+
+	body, err := io.ReadAll(httpRes.Body)
+	s.Require().NoError(err)
+
+	var trailers textproto.MIMEHeader
+	for len(body) > 0 {
+		s.Require().GreaterOrEqual(len(body), 5)
+		flags, size := body[0], binary.BigEndian.Uint32(body[1:5])
+		payload := body[5 : 5+size]
+		body = body[5+size:]
+
+		if flags&0x80 != 0 {
+			trailers, err = textproto.NewReader(bufio.NewReader(io.MultiReader(bytes.NewReader(payload), strings.NewReader("\r\n")))).ReadMIMEHeader()
+			s.Require().NoError(err)
+			continue
+		}
+
+		s.Require().NoError(proto.Unmarshal(payload, res))
+	}
+
+	code, err := strconv.Atoi(trailers.Get("Grpc-Status"))
+	s.Require().NoError(err)
+	return codes.Code(code)
+}
+
+func (s *grpcGatewaySuite) TestConnectProtocol() {
+	username := "connect-" + uuid.NewString()
+
+	status, code := s.connectCall("UserInfo", &authpb.UserInfoRequest{}, &authpb.UserInfoResponse{}, "")
+	s.Equal(http.StatusUnauthorized, status)
+	s.Equal("unauthenticated", code)
+
+	registerRes := &authpb.RegisterUserResponse{}
+	status, _ = s.connectCall("RegisterUser", &authpb.RegisterUserRequest{
+		User:     &authpb.User{Name: username, Email: username + "@example.com"},
+		Password: "rLy_5tr0nG!",
+	}, registerRes, "")
+	s.Require().Equal(http.StatusOK, status)
+	s.Equal(username, registerRes.GetUserId())
+
+	loginRes := &authpb.LoginUserResponse{}
+	status, _ = s.connectCall("LoginUser", &authpb.LoginUserRequest{
+		LoginMethod: &authpb.LoginUserRequest_Email{Email: username},
+		Password:    "rLy_5tr0nG!",
+	}, loginRes, "")
+	s.Require().Equal(http.StatusOK, status)
+	s.NotEmpty(loginRes.GetToken())
+
+	userInfoRes := &authpb.UserInfoResponse{}
+	status, _ = s.connectCall("UserInfo", &authpb.UserInfoRequest{}, userInfoRes, loginRes.GetToken())
+	s.Require().Equal(http.StatusOK, status)
+	s.Equal(username, userInfoRes.GetUser().GetUserId())
+
+	// method policies apply, users can't grant roles
+	status, code = s.connectCall("GrantRole", &authpb.GrantRoleRequest{Username: username, Role: authpb.UserRole_USER_ROLE_ADMIN}, &authpb.UserRolesResponse{}, loginRes.GetToken())
+	s.Equal(http.StatusForbidden, status)
+	s.Equal("permission_denied", code)
+}
+
+func (s *grpcGatewaySuite) TestGRPCWebProtocol() {
+	username := "grpc-web-" + uuid.NewString()
+
+	s.Equal(codes.Unauthenticated, s.grpcWebCall("UserInfo", &authpb.UserInfoRequest{}, &authpb.UserInfoResponse{}, ""))
+
+	registerRes := &authpb.RegisterUserResponse{}
+	s.Require().Equal(codes.OK, s.grpcWebCall("RegisterUser", &authpb.RegisterUserRequest{
+		User:     &authpb.User{Name: username, Email: username + "@example.com"},
+		Password: "rLy_5tr0nG!",
+	}, registerRes, ""))
+	s.Equal(username, registerRes.GetUserId())
+
+	// the username is taken
+	s.Equal(codes.AlreadyExists, s.grpcWebCall("RegisterUser", &authpb.RegisterUserRequest{
+		User:     &authpb.User{Name: username},
+		Password: "rLy_5tr0nG!",
+	}, &authpb.RegisterUserResponse{}, ""))
+
+	loginRes := &authpb.LoginUserResponse{}
+	s.Require().Equal(codes.OK, s.grpcWebCall("LoginUser", &authpb.LoginUserRequest{
+		LoginMethod: &authpb.LoginUserRequest_Email{Email: username},
+		Password:    "rLy_5tr0nG!",
+	}, loginRes, ""))
+
+	userInfoRes := &authpb.UserInfoResponse{}
+	s.Require().Equal(codes.OK, s.grpcWebCall("UserInfo", &authpb.UserInfoRequest{}, userInfoRes, loginRes.GetToken()))
+	s.Equal(username, userInfoRes.GetUser().GetUserId())
+}
+
+func (s *grpcGatewaySuite) TestConnectCORS() {
+	preflight := func(origin string) *http.Response {
+		req, err := http.NewRequest(http.MethodOptions, s.connectServer.URL+"/auth.v1.AuthService/UserInfo", nil)
+		s.Require().NoError(err)
+		req.Header.Set("Origin", origin)
+		req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+		req.Header.Set("Access-Control-Request-Headers", "authorization,content-type,x-grpc-web")
+
+		res, err := s.connectServer.Client().Do(req)
+		s.Require().NoError(err)
+		res.Body.Close()
+
+		return res
+	}
+
+	res := preflight(connectOrigin)
+	s.Equal(http.StatusNoContent, res.StatusCode)
+	s.Equal(connectOrigin, res.Header.Get("Access-Control-Allow-Origin"))
+	s.Equal("3600", res.Header.Get("Access-Control-Max-Age"))
+
+	res = preflight("https://evil.example.com")
+	s.Empty(res.Header.Get("Access-Control-Allow-Origin"))
+}
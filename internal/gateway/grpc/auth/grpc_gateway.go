@@ -2,75 +2,241 @@ package auth
 
 import (
 	"context"
+	"crypto/tls"
 	"log/slog"
 	"net"
 	"net/http"
+	"net/textproto"
+	"strings"
+	"time"
 
 	authpb "github.com/bogatyr285/auth-go/pkg/server/grpc/auth"
+	"github.com/bogatyr285/auth-go/pkg/service"
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/encoding/protojson"
 )
 
-// Gateway - proxy which tranforms HTTP requests to GRPC
+// GatewayPathPrefix - paths of the gateway routes, see google.api.http options of the protos
+const GatewayPathPrefix = "/api/v1/"
+
+// Gateway - proxy which tranforms HTTP requests to GRPC, a service.Service
 type Gateway struct {
 	mux        http.Handler
 	httpGwAddr string
-	logger     *slog.Logger
+	// tlsConfig - TLS of the listener, plaintext when nil
+	tlsConfig *tls.Config
+	// grpcCreds - credentials of connections to the gRPC server
+	grpcCreds credentials.TransportCredentials
+	// forwardHeaders - canonical names of HTTP headers passed as metadata
+	forwardHeaders map[string]bool
+	// errorStatuses - HTTP status overriding the default one of the gRPC code
+	errorStatuses map[codes.Code]int
+	timeouts      GatewayTimeouts
+	conn          *grpc.ClientConn
+	server        *service.HTTPServer
+	logger        *slog.Logger
+}
+
+type GatewayTimeouts struct {
+	Read  time.Duration
+	Write time.Duration
+	Idle  time.Duration
+	// Shutdown - how long Stop waits for requests in flight
+	Shutdown time.Duration
+}
+
+type GatewayOption func(*Gateway)
+
+// WithGatewayTLS - serves the gateway over TLS
+func WithGatewayTLS(cfg *tls.Config) GatewayOption {
+	return func(g *Gateway) {
+		g.tlsConfig = cfg
+	}
 }
 
-func NewGateway(ctx context.Context, grpcAddr, httpGwAddr string, logger *slog.Logger) (*Gateway, error) {
-	gwMux := runtime.NewServeMux(runtime.WithMarshalerOption(runtime.MIMEWildcard,
-		&runtime.JSONPb{
-			MarshalOptions: protojson.MarshalOptions{
-				UseProtoNames: true,
-			},
-		}),
+// WithGRPCCredentials - credentials of connections to the gRPC server, e.g. TLS when
+// the server has it. Connections are plaintext by default
+func WithGRPCCredentials(creds credentials.TransportCredentials) GatewayOption {
+	return func(g *Gateway) {
+		g.grpcCreds = creds
+	}
+}
+
+// WithForwardedHeaders - HTTP headers passed to the gRPC server as metadata with
+// lowercased names, e.g. X-Request-ID. Authorization is forwarded always
+func WithForwardedHeaders(headers ...string) GatewayOption {
+	return func(g *Gateway) {
+		for _, h := range headers {
+			g.forwardHeaders[textproto.CanonicalMIMEHeaderKey(h)] = true
+		}
+	}
+}
+
+// WithErrorStatuses - HTTP statuses of gRPC codes, codes missing from the map keep
+// the standard mapping
+func WithErrorStatuses(statuses map[codes.Code]int) GatewayOption {
+	return func(g *Gateway) {
+		g.errorStatuses = statuses
+	}
+}
+
+func WithGatewayTimeouts(timeouts GatewayTimeouts) GatewayOption {
+	return func(g *Gateway) {
+		g.timeouts = timeouts
+	}
+}
+
+func NewGateway(ctx context.Context, grpcAddr, httpGwAddr string, logger *slog.Logger, opts ...GatewayOption) (*Gateway, error) {
+	g := &Gateway{
+		httpGwAddr:     httpGwAddr,
+		grpcCreds:      insecure.NewCredentials(),
+		forwardHeaders: map[string]bool{},
+		timeouts:       GatewayTimeouts{Shutdown: 5 * time.Second},
+		logger:         logger.With("module", "grpc/http-gateway"),
+	}
+	for _, opt := range opts {
+		opt(g)
+	}
+
+	gwMux := runtime.NewServeMux(
+		runtime.WithMarshalerOption(runtime.MIMEWildcard,
+			&runtime.JSONPb{
+				MarshalOptions: protojson.MarshalOptions{
+					UseProtoNames: true,
+				},
+			}),
+		runtime.WithIncomingHeaderMatcher(g.headerMatcher),
+		runtime.WithErrorHandler(g.errorHandler),
 	)
 
-	err := authpb.RegisterAuthServiceHandlerFromEndpoint(
-		context.Background(),
-		gwMux,
-		grpcAddr,
-		[]grpc.DialOption{
-			grpc.WithTransportCredentials(insecure.NewCredentials()),
-			// grpc.WithStatsHandler(new(ocgrpc.ClientHandler)),
-		},
+	// the connection is owned by the gateway, so it outlives requests in flight on Stop
+	conn, err := grpc.NewClient(grpcAddr,
+		grpc.WithTransportCredentials(g.grpcCreds),
+		// grpc.WithStatsHandler(new(ocgrpc.ClientHandler)),
 	)
 	if err != nil {
 		return nil, err
 	}
 
-	return &Gateway{
-		mux:        gwMux,
-		httpGwAddr: httpGwAddr,
-		logger:     logger.With("module", "grpc/http-gateway"),
-	}, nil
+	if err = authpb.RegisterAuthServiceHandler(ctx, gwMux, conn); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	if err = authpb.RegisterRelationServiceHandler(ctx, gwMux, conn); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	g.mux = withoutWriteDeadline(gwMux)
+	g.conn = conn
+	g.server = service.NewHTTPServer(&http.Server{
+		Addr:         httpGwAddr,
+		Handler:      g.mux,
+		TLSConfig:    g.tlsConfig,
+		ReadTimeout:  g.timeouts.Read,
+		WriteTimeout: g.timeouts.Write,
+		IdleTimeout:  g.timeouts.Idle,
+	}, g.timeouts.Shutdown, g.logger)
+
+	return g, nil
+}
+
+// ServeHTTP - the gateway handles requests without its own listener too
+func (g *Gateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	g.mux.ServeHTTP(w, r)
+}
+
+func (g *Gateway) Init(ctx context.Context) error {
+	return g.server.Init(ctx)
+}
+
+// Addr - address the gateway listens on, known once it is initialized
+func (g *Gateway) Addr() net.Addr {
+	return g.server.Addr()
 }
 
-func (g *Gateway) Start() (func() error, error) {
-	hserver := http.Server{
-		Handler: g.mux,
+func (g *Gateway) Run(ctx context.Context) error {
+	return g.server.Run(ctx)
+}
+
+// Stop - waits for requests in flight, then closes the connection to the gRPC server
+func (g *Gateway) Stop() {
+	g.logger.Info("shutting down")
+	g.server.Stop()
+
+	if err := g.Close(); err != nil {
+		g.logger.Error("failed to close grpc connection", slog.Any("err", err))
 	}
+}
 
-	g.logger.Info("starting", slog.String("addr", g.httpGwAddr))
-	l, err := net.Listen("tcp", g.httpGwAddr)
-	if err != nil {
-		return nil, err
+// Close - closes the connection to the gRPC server, when the gateway is served by
+// ServeHTTP it's called once the HTTP server is drained
+func (g *Gateway) Close() error {
+	return g.conn.Close()
+}
+
+// streamingPaths - HTTP paths of server streaming RPCs, the same for the gateway and
+// Connect handlers
+var streamingPaths = func() map[string]bool {
+	paths := map[string]bool{}
+	services := authpb.File_auth_proto.Services()
+	for i := 0; i < services.Len(); i++ {
+		methods := services.Get(i).Methods()
+		for j := 0; j < methods.Len(); j++ {
+			if m := methods.Get(j); m.IsStreamingServer() {
+				paths["/"+string(services.Get(i).FullName())+"/"+string(m.Name())] = true
+			}
+		}
 	}
 
-	go func() {
-		err = hserver.Serve(l)
-		if err != nil {
-			g.logger.Error("http/grpc gateway server", slog.Any("err", err))
+	return paths
+}()
+
+// withoutWriteDeadline - streams of server streaming RPCs outlive write timeouts of
+// the HTTP server, which are meant for unary calls
+func withoutWriteDeadline(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if streamingPaths[r.URL.Path] {
+			// fails for writers which don't support deadlines, they have none to clear
+			_ = http.NewResponseController(w).SetWriteDeadline(time.Time{})
 		}
-	}()
 
-	return func() error {
-		g.logger.Info("shutting down")
-		return hserver.Close()
-	}, nil
+		h.ServeHTTP(w, r)
+	})
+}
+
+// headerMatcher - configured headers are forwarded with lowercased names, the rest
+// as the runtime does by default
+func (g *Gateway) headerMatcher(key string) (string, bool) {
+	if g.forwardHeaders[textproto.CanonicalMIMEHeaderKey(key)] && key != "Authorization" {
+		return strings.ToLower(key), true
+	}
+
+	return runtime.DefaultHeaderMatcher(key)
+}
+
+func (g *Gateway) errorHandler(ctx context.Context, mux *runtime.ServeMux, m runtime.Marshaler, w http.ResponseWriter, r *http.Request, err error) {
+	if code, ok := g.errorStatuses[status.Code(err)]; ok {
+		w = statusWriter{ResponseWriter: w, status: code}
+	}
+
+	runtime.DefaultHTTPErrorHandler(ctx, mux, m, w, r, err)
+}
+
+// statusWriter - replaces the status written by runtime.DefaultHTTPErrorHandler
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w statusWriter) WriteHeader(int) {
+	w.ResponseWriter.WriteHeader(w.status)
 }
 
 type Closer func() error
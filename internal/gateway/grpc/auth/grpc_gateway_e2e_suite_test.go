@@ -7,19 +7,28 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
+	"net"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/bogatyr285/auth-go/config"
+	"github.com/bogatyr285/auth-go/internal/auth/account"
+	"github.com/bogatyr285/auth-go/internal/auth/authz"
+	"github.com/bogatyr285/auth-go/internal/auth/events"
 	"github.com/bogatyr285/auth-go/internal/auth/repository"
 	"github.com/bogatyr285/auth-go/internal/buildinfo"
 	"github.com/bogatyr285/auth-go/internal/gateway/grpc/auth"
 	"github.com/bogatyr285/auth-go/pkg/crypto"
+	"github.com/bogatyr285/auth-go/pkg/health"
 	"github.com/bogatyr285/auth-go/pkg/jwt"
 	authpb "github.com/bogatyr285/auth-go/pkg/server/grpc/auth"
 	"github.com/bogatyr285/auth-go/playground"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/suite"
+	"google.golang.org/protobuf/encoding/protojson"
 )
 
 type grpcGatewaySuite struct {
@@ -30,10 +39,15 @@ type grpcGatewaySuite struct {
 	jwtManager    auth.JWTManager
 	grpcServer    *auth.Server
 	httpGwAddress string
-	grpcCloser    func() error
-	grpcGwCloser  func() error
+	grpcGw        *auth.Gateway
+	// connectServer - gRPC-Web and Connect handlers, CORS allows connectOrigin
+	connectServer *httptest.Server
+	events        *events.Bus
+	health        *health.Checker
 }
 
+const connectOrigin = "https://app.example.com"
+
 func TestGRPCGatewaySuite(t *testing.T) {
 	suite.Run(t, new(grpcGatewaySuite))
 }
@@ -62,36 +76,56 @@ func (s *grpcGatewaySuite) SetupSuite() {
 	s.Require().NoError(err)
 
 	// Set up GRPC server and Gateway
-	grpcAddress := ":9090"
-	s.httpGwAddress = ":9091"
-	authGRPCHandlers := auth.NewAuthHandlers(&s.storage, passwordHasher, s.jwtManager, buildinfo.New())
-	s.grpcServer, err = auth.NewGRPCServer(grpcAddress, authGRPCHandlers, s.log)
+	s.events = events.NewBus(16)
+	accounts := account.New(&s.storage, passwordHasher, s.jwtManager, buildinfo.New(), account.WithEvents(s.events))
+	authGRPCHandlers := auth.NewAuthHandlers(&s.storage, passwordHasher, accounts, auth.WithEventBus(s.events))
+	relations, err := authz.NewEngine(&s.storage, nil)
 	s.Require().NoError(err)
 
-	s.grpcCloser, err = s.grpcServer.Run()
+	ctx := context.Background()
+	s.health = health.NewChecker(health.Config{Interval: time.Hour, Timeout: time.Second}, s.log)
+	s.health.AddProbe("database", s.storage.Ping)
+	s.health.AddService(authpb.AuthService_ServiceDesc.ServiceName, "database")
+	s.Require().NoError(s.health.Init(ctx))
+
+	s.grpcServer, err = auth.NewGRPCServer("127.0.0.1:0", authGRPCHandlers, auth.NewRelationHandlers(relations), s.health, s.jwtManager, &s.storage, s.log)
 	s.Require().NoError(err)
+	s.Require().NoError(s.grpcServer.Init(ctx))
+	go s.grpcServer.Run(ctx)
 
-	grpcGw, err := auth.NewGateway(context.Background(), grpcAddress, s.httpGwAddress, s.log)
+	s.grpcGw, err = auth.NewGateway(ctx, s.grpcServer.Addr().String(), "127.0.0.1:0", s.log)
 	s.Require().NoError(err)
+	s.Require().NoError(s.grpcGw.Init(ctx))
+	go s.grpcGw.Run(ctx)
 
-	s.grpcGwCloser, err = grpcGw.Start()
+	_, port, err := net.SplitHostPort(s.grpcGw.Addr().String())
 	s.Require().NoError(err)
+	s.httpGwAddress = ":" + port
+
+	connectPath, connectHandler := auth.NewConnectHandler(authGRPCHandlers, s.jwtManager, &s.storage, s.log,
+		auth.WithConnectCORS([]string{connectOrigin}, time.Hour))
+	connectMux := http.NewServeMux()
+	connectMux.Handle(connectPath, connectHandler)
+	s.connectServer = httptest.NewServer(connectMux)
 }
 
 func (s *grpcGatewaySuite) TearDownSuite() {
-	// Clean up resources
-	if s.grpcCloser != nil {
-		s.grpcCloser()
+	if s.connectServer != nil {
+		s.connectServer.Close()
+	}
+	// the gateway is stopped before the server behind it
+	if s.grpcGw != nil {
+		s.grpcGw.Stop()
 	}
-	if s.grpcGwCloser != nil {
-		s.grpcGwCloser()
+	if s.grpcServer != nil {
+		s.grpcServer.Stop()
 	}
 }
 
 func (s *grpcGatewaySuite) TestRegisterUser() {
 	registerUserReq := &authpb.RegisterUserRequest{
 		User: &authpb.User{
-			Name: "user1",
+			Name: "user-" + uuid.NewString(),
 		},
 		Password: "rLy_5tr0nG!",
 	}
@@ -115,3 +149,58 @@ func (s *grpcGatewaySuite) TestRegisterUser() {
 
 	s.Equal(registerResModel.UserId, registerUserReq.User.Name)
 }
+
+func (s *grpcGatewaySuite) TestUserInfo() {
+	userInfoURL := fmt.Sprintf("http://localhost%s/api/v1/userinfo", s.httpGwAddress)
+
+	res, err := http.Get(userInfoURL)
+	s.Require().NoError(err)
+	res.Body.Close()
+	s.Equal(http.StatusUnauthorized, res.StatusCode)
+
+	username := "userinfo-" + uuid.NewString()
+	registerUserReqBytes, _ := playground.ProtobufToJSON(&authpb.RegisterUserRequest{
+		User: &authpb.User{
+			Name:   username,
+			Gender: authpb.Gender_GENDER_OTHER,
+			Email:  username + "@example.com",
+		},
+		Password: "rLy_5tr0nG!",
+	})
+	res, err = http.Post(fmt.Sprintf("http://localhost%s/api/v1/register", s.httpGwAddress), "application/json", bytes.NewReader(registerUserReqBytes))
+	s.Require().NoError(err)
+	res.Body.Close()
+	s.Require().Equal(http.StatusOK, res.StatusCode)
+
+	loginReqBytes, _ := playground.ProtobufToJSON(&authpb.LoginUserRequest{
+		LoginMethod: &authpb.LoginUserRequest_Email{Email: username},
+		Password:    "rLy_5tr0nG!",
+	})
+	res, err = http.Post(fmt.Sprintf("http://localhost%s/api/v1/login", s.httpGwAddress), "application/json", bytes.NewReader(loginReqBytes))
+	s.Require().NoError(err)
+	defer res.Body.Close()
+	s.Require().Equal(http.StatusOK, res.StatusCode)
+
+	loginRes := &authpb.LoginUserResponse{}
+	s.Require().NoError(json.NewDecoder(res.Body).Decode(loginRes))
+
+	req, err := http.NewRequest(http.MethodGet, userInfoURL, nil)
+	s.Require().NoError(err)
+	req.Header.Set("Authorization", "Bearer "+loginRes.Token)
+
+	res, err = http.DefaultClient.Do(req)
+	s.Require().NoError(err)
+	defer res.Body.Close()
+	s.Require().Equal(http.StatusOK, res.StatusCode)
+
+	bodyRes, err := io.ReadAll(res.Body)
+	s.Require().NoError(err)
+
+	userInfoRes := &authpb.UserInfoResponse{}
+	s.Require().NoError(protojson.Unmarshal(bodyRes, userInfoRes))
+
+	s.Equal(username, userInfoRes.User.GetUserId())
+	s.Equal(authpb.Gender_GENDER_OTHER, userInfoRes.User.GetGender())
+	s.Equal(authpb.UserRole_USER_ROLE_USER, userInfoRes.User.GetRole())
+	s.Equal(username+"@example.com", userInfoRes.User.GetEmailContact())
+}
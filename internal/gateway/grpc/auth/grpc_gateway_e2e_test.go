@@ -12,14 +12,19 @@ import (
 	"testing"
 
 	"github.com/bogatyr285/auth-go/config"
+	"github.com/bogatyr285/auth-go/internal/auth/account"
+	"github.com/bogatyr285/auth-go/internal/auth/authz"
 	"github.com/bogatyr285/auth-go/internal/auth/repository"
 	"github.com/bogatyr285/auth-go/internal/buildinfo"
 	"github.com/bogatyr285/auth-go/internal/gateway/grpc/auth"
 	"github.com/bogatyr285/auth-go/pkg/crypto"
+	"github.com/bogatyr285/auth-go/pkg/health"
 	"github.com/bogatyr285/auth-go/pkg/jwt"
 	authpb "github.com/bogatyr285/auth-go/pkg/server/grpc/auth"
 	"github.com/bogatyr285/auth-go/playground"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestGRPCGateway(t *testing.T) {
@@ -39,27 +44,29 @@ func TestGRPCGateway(t *testing.T) {
 		[]byte(cfg.JWT.PrivateKey))
 	assert.NoError(t, err)
 
-	grpcAddress := ":9090"
-	httpGwAddress := ":9091"
-	authGRPCHandlers := auth.NewAuthHandlers(&storage, passwordHasher, jwtManager, buildinfo.New())
-	grpcServer, err := auth.NewGRPCServer(grpcAddress, authGRPCHandlers, log)
+	authGRPCHandlers := auth.NewAuthHandlers(&storage, passwordHasher, account.New(&storage, passwordHasher, jwtManager, buildinfo.New()))
+	relations, err := authz.NewEngine(&storage, nil)
 	assert.NoError(t, err)
 
-	grpcCloser, err := grpcServer.Run()
+	ctx := context.Background()
+	grpcServer, err := auth.NewGRPCServer("127.0.0.1:0", authGRPCHandlers, auth.NewRelationHandlers(relations), health.NewChecker(health.Config{}, log), jwtManager, &storage, log)
 	assert.NoError(t, err)
-	defer grpcCloser()
+	require.NoError(t, grpcServer.Init(ctx))
+	go grpcServer.Run(ctx)
+	defer grpcServer.Stop()
 
-	grpcGw, err := auth.NewGateway(context.Background(), grpcAddress, httpGwAddress, log)
+	grpcGw, err := auth.NewGateway(ctx, grpcServer.Addr().String(), "127.0.0.1:0", log)
 	assert.NoError(t, err)
+	require.NoError(t, grpcGw.Init(ctx))
+	go grpcGw.Run(ctx)
+	defer grpcGw.Stop()
 
-	grpcGwCloser, err := grpcGw.Start()
-	assert.NoError(t, err)
-	defer grpcGwCloser()
+	httpGwAddress := grpcGw.Addr().String()
 
 	// prepare
 	registerUserReq := &authpb.RegisterUserRequest{
 		User: &authpb.User{
-			Name: "user1",
+			Name: "user-" + uuid.NewString(),
 		},
 		Password: "rLy_5tr0nG!",
 	}
@@ -67,7 +74,7 @@ func TestGRPCGateway(t *testing.T) {
 	registerUserReqBytes, _ := playground.ProtobufToJSON(registerUserReq)
 
 	// act
-	res, err := http.Post(fmt.Sprintf("http://localhost%s/api/v1/register", httpGwAddress),
+	res, err := http.Post(fmt.Sprintf("http://%s/api/v1/register", httpGwAddress),
 		"application/json",
 		bytes.NewReader(registerUserReqBytes))
 	assert.NoError(t, err)
@@ -0,0 +1,137 @@
+package auth_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bogatyr285/auth-go/internal/gateway/grpc/auth"
+	authpb "github.com/bogatyr285/auth-go/pkg/server/grpc/auth"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// recordingAuthServer - GetUser records metadata of calls, user 42 doesn't exist
+// and user 7 takes a while
+type recordingAuthServer struct {
+	authpb.UnimplementedAuthServiceServer
+
+	mu sync.Mutex
+	md metadata.MD
+}
+
+func (s *recordingAuthServer) GetUser(ctx context.Context, req *authpb.GetUserRequest) (*authpb.GetUserResponse, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	s.mu.Lock()
+	s.md = md
+	s.mu.Unlock()
+
+	switch req.GetId() {
+	case 42:
+		return nil, status.Error(codes.NotFound, "user not found")
+	case 7:
+		time.Sleep(200 * time.Millisecond)
+	}
+
+	return &authpb.GetUserResponse{Id: req.GetId(), Username: "user"}, nil
+}
+
+func (s *recordingAuthServer) metadata() metadata.MD {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.md
+}
+
+func newRecordingServer(t *testing.T) (*recordingAuthServer, string) {
+	srv := &recordingAuthServer{}
+	grpcSrv := grpc.NewServer()
+	authpb.RegisterAuthServiceServer(grpcSrv, srv)
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go grpcSrv.Serve(l)
+	t.Cleanup(grpcSrv.Stop)
+
+	return srv, l.Addr().String()
+}
+
+func TestGatewayConfig(t *testing.T) {
+	backend, grpcAddr := newRecordingServer(t)
+
+	gw, err := auth.NewGateway(context.Background(), grpcAddr, "127.0.0.1:0", slog.New(slog.NewTextHandler(io.Discard, nil)),
+		auth.WithForwardedHeaders("Authorization", "x-request-id"),
+		auth.WithErrorStatuses(map[codes.Code]int{codes.NotFound: http.StatusGone}),
+	)
+	require.NoError(t, err)
+
+	srv := httptest.NewServer(gw)
+	t.Cleanup(srv.Close)
+
+	get := func(t *testing.T, path string) int {
+		req, err := http.NewRequest(http.MethodGet, srv.URL+path, nil)
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer token")
+		req.Header.Set("X-Request-ID", "req-1")
+		req.Header.Set("X-Other", "other")
+
+		res, err := srv.Client().Do(req)
+		require.NoError(t, err)
+		res.Body.Close()
+
+		return res.StatusCode
+	}
+
+	t.Run("headers are forwarded", func(t *testing.T) {
+		require.Equal(t, http.StatusOK, get(t, "/api/v1/users/1"))
+
+		md := backend.metadata()
+		require.Equal(t, []string{"Bearer token"}, md.Get("authorization"))
+		require.Equal(t, []string{"req-1"}, md.Get("x-request-id"))
+		require.Empty(t, md.Get("x-other"))
+	})
+
+	t.Run("error statuses", func(t *testing.T) {
+		require.Equal(t, http.StatusGone, get(t, "/api/v1/users/42"))
+		// codes which aren't configured keep the standard mapping
+		require.Equal(t, http.StatusNotImplemented, get(t, "/api/v1/buildinfo"))
+	})
+}
+
+func TestGatewayGracefulStop(t *testing.T) {
+	_, grpcAddr := newRecordingServer(t)
+
+	ctx := context.Background()
+	gw, err := auth.NewGateway(ctx, grpcAddr, "127.0.0.1:0", slog.New(slog.NewTextHandler(io.Discard, nil)))
+	require.NoError(t, err)
+	require.NoError(t, gw.Init(ctx))
+
+	ran := make(chan error)
+	go func() { ran <- gw.Run(ctx) }()
+
+	slow := make(chan int)
+	go func() {
+		res, err := http.Get("http://" + gw.Addr().String() + "/api/v1/users/7")
+		if err != nil {
+			slow <- 0
+			return
+		}
+		res.Body.Close()
+		slow <- res.StatusCode
+	}()
+
+	// the slow request is in flight when the gateway is stopped
+	time.Sleep(50 * time.Millisecond)
+	gw.Stop()
+
+	require.Equal(t, http.StatusOK, <-slow)
+	require.NoError(t, <-ran)
+}
@@ -2,22 +2,33 @@ package auth
 
 import (
 	"context"
-
 	"errors"
+	"time"
 
+	"github.com/bogatyr285/auth-go/internal/auth/account"
 	"github.com/bogatyr285/auth-go/internal/auth/entity"
-	"github.com/bogatyr285/auth-go/internal/buildinfo"
+	"github.com/bogatyr285/auth-go/internal/auth/events"
 	authpb "github.com/bogatyr285/auth-go/pkg/server/grpc/auth"
 	"github.com/golang-jwt/jwt/v5"
-	"github.com/google/uuid"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
 //go:generate mockgen -source=handlers.go -destination=../../../mocks/handlers_mock.go -package mock
 type UserRepository interface {
-	RegisterUser(ctx context.Context, u entity.UserAccount) error
 	FindUserByEmail(ctx context.Context, username string) (entity.UserAccount, error)
+	GetUserProfile(ctx context.Context, userID int) (entity.UserAccount, error)
+	FindUserByPhone(ctx context.Context, phone string) (entity.UserAccount, error)
+	FindUserByPendingPhone(ctx context.Context, phone string) (entity.UserAccount, error)
+	SetPhoneVerified(ctx context.Context, phone string) error
+	SaveOTP(ctx context.Context, otp entity.OTP) error
+	CountOTPsSince(ctx context.Context, phone string, since time.Time) (int, error)
+	FindActiveOTP(ctx context.Context, phone, purpose string) (entity.OTP, error)
+	RegisterOTPAttempt(ctx context.Context, ID, maxAttempts int) (bool, error)
+	ConsumeOTP(ctx context.Context, ID int) error
+	ListUserRoles(ctx context.Context, userID int) ([]string, error)
+	GrantUserRole(ctx context.Context, userID int, role string) error
+	RevokeUserRole(ctx context.Context, userID int, role string) error
 }
 
 //go:generate mockgen -source=handlers.go -destination=../../../mocks/handlers_mock.go -package mock
@@ -29,49 +40,105 @@ type CryptoPassword interface {
 //go:generate mockgen -source=handlers.go -destination=../../../mocks/handlers_mock.go -package mock
 type JWTManager interface {
 	IssueToken(userID string) (string, error)
+	IssueTokenWithClaims(userID string, extra jwt.MapClaims) (string, error)
 	VerifyToken(tokenString string) (*jwt.Token, error)
 }
 
-var ErrAccessDenied = errors.New("access_denied")
+//go:generate mockgen -source=handlers.go -destination=../../../mocks/handlers_mock.go -package mock
+type AccessTokenRepository interface {
+	GetPersonalAccessToken(ctx context.Context, tokenHash string) (entity.PersonalAccessToken, error)
+	TouchPersonalAccessToken(ctx context.Context, ID string, usedAt time.Time) error
+	GetUserById(ctx context.Context, ID int) (entity.UserAccount, error)
+	ListUserRoles(ctx context.Context, userID int) ([]string, error)
+	ExistsUserByUsername(ctx context.Context, username string) (bool, error)
+}
+
+//go:generate mockgen -source=handlers.go -destination=../../../mocks/handlers_mock.go -package mock
+type SMSSender interface {
+	SendSMS(ctx context.Context, phone, text string) error
+}
+
+var (
+	ErrAccessDenied = errors.New("access_denied")
+	ErrMissingToken = errors.New("bearer token is missing")
+)
 
 type AuthHandlers struct {
 	ur UserRepository
 	cp CryptoPassword
-	jm JWTManager
-	bi buildinfo.BuildInfo
+	ss SMSSender
+	// accounts - registration, login and tokens, shared with the REST API
+	accounts *account.Service
+	// events - security events are watched from it
+	events *events.Bus
 
 	authpb.UnimplementedAuthServiceServer
 }
 
-func NewAuthHandlers(
-	ur UserRepository,
-	cp CryptoPassword,
-	jm JWTManager,
-	bi buildinfo.BuildInfo,
-) *AuthHandlers {
-	return &AuthHandlers{
-		ur: ur,
-		cp: cp,
-		jm: jm,
-		bi: bi,
+type Option func(*AuthHandlers)
+
+// WithSMSSender - enables phone verification and one-time code login
+func WithSMSSender(sms SMSSender) Option {
+	return func(h *AuthHandlers) {
+		h.ss = sms
+	}
+}
+
+// WithEventBus - enables the WatchSecurityEvents RPCs
+func WithEventBus(bus *events.Bus) Option {
+	return func(h *AuthHandlers) {
+		h.events = bus
 	}
 }
 
+// NewAuthHandlers - accounts are configured with the login directories and events, the
+// same service backs the REST API
+func NewAuthHandlers(ur UserRepository, cp CryptoPassword, accounts *account.Service, opts ...Option) *AuthHandlers {
+	h := &AuthHandlers{
+		ur:       ur,
+		cp:       cp,
+		accounts: accounts,
+	}
+
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	return h
+}
+
 func (h *AuthHandlers) RegisterUser(ctx context.Context, req *authpb.RegisterUserRequest) (*authpb.RegisterUserResponse, error) {
-	hashedPassword, err := h.cp.HashPassword(req.Password)
-	if err != nil {
-		// TODO dont show errs to end user. just log it
-		return nil, err
+	var phone string
+	if req.User.GetPhoneNumber() != "" {
+		var err error
+		if phone, err = normalizePhone(req.User.GetPhoneNumber()); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
 	}
-	// TODO with New method
-	user := entity.UserAccount{
-		Username: req.User.Name,
-		Password: string(hashedPassword),
+
+	// throttled numbers are refused before the account exists
+	if phone != "" && h.ss != nil {
+		if err := h.checkSendLimit(ctx, phone); err != nil {
+			return nil, err
+		}
+	}
+
+	user := profile(req.User)
+	user.Username = req.User.GetName()
+	user.Phone = phone
+	if user.Username == "" {
+		user.Username = phone
 	}
 
-	err = h.ur.RegisterUser(ctx, user)
+	user, err := h.accounts.Register(ctx, user, req.Password)
 	if err != nil {
-		return nil, err
+		return nil, accountError(err)
+	}
+
+	if phone != "" && h.ss != nil {
+		if err = h.sendPhoneCode(ctx, phone, entity.OTPPurposeVerify); err != nil {
+			return nil, err
+		}
 	}
 
 	return &authpb.RegisterUserResponse{
@@ -81,32 +148,103 @@ func (h *AuthHandlers) RegisterUser(ctx context.Context, req *authpb.RegisterUse
 }
 
 func (h *AuthHandlers) LoginUser(ctx context.Context, req *authpb.LoginUserRequest) (*authpb.LoginUserResponse, error) {
-	user, err := h.ur.FindUserByEmail(ctx, req.GetEmail())
+	if req.GetPhoneNumber() != "" {
+		return h.loginByPhone(ctx, req)
+	}
+
+	session, err := h.accounts.Login(ctx, req.GetEmail(), req.Password)
 	if err != nil {
-		return nil, err
+		return nil, accountError(err)
 	}
 
-	if !h.cp.ComparePasswords(user.Password, req.Password) {
-		return nil, status.Error(codes.Unauthenticated, ErrAccessDenied.Error())
+	return loginResponse(session), nil
+}
+
+func loginResponse(session account.Session) *authpb.LoginUserResponse {
+	return &authpb.LoginUserResponse{
+		Token:        session.AccessToken,
+		RefreshToken: session.RefreshToken,
+		MfaToken:     session.MFAToken,
+		MfaMethods:   session.MFAMethods,
 	}
+}
 
-	token, err := h.jm.IssueToken(user.Username)
+func (h *AuthHandlers) Refresh(ctx context.Context, req *authpb.RefreshRequest) (*authpb.RefreshResponse, error) {
+	session, err := h.accounts.Refresh(ctx, req.GetRefreshToken())
 	if err != nil {
-		return nil, err
+		return nil, accountError(err)
 	}
 
-	return &authpb.LoginUserResponse{
-		Token: token,
+	return &authpb.RefreshResponse{
+		Token:        session.AccessToken,
+		RefreshToken: session.RefreshToken,
+	}, nil
+}
+
+func (h *AuthHandlers) GetUser(ctx context.Context, req *authpb.GetUserRequest) (*authpb.GetUserResponse, error) {
+	user, err := h.accounts.GetUser(ctx, int(req.GetId()))
+	if err != nil {
+		return nil, accountError(err)
+	}
+
+	return &authpb.GetUserResponse{
+		Id:       int64(user.ID),
+		Username: user.Username,
 	}, nil
 }
 
-func (h *AuthHandlers) UserInfo(context.Context, *authpb.UserInfoRequest) (*authpb.UserInfoResponse, error) {
-	return &authpb.UserInfoResponse{
-		User: &authpb.User{
-			UserId: uuid.New().String(),
-			ContactMethod: &authpb.User_PhoneNumber{
-				PhoneNumber: "7-800-555-3535",
-			},
-		},
+func (h *AuthHandlers) BuildInfo(_ context.Context, _ *authpb.BuildInfoRequest) (*authpb.BuildInfoResponse, error) {
+	bi := h.accounts.BuildInfo()
+
+	return &authpb.BuildInfoResponse{
+		Version:    bi.Version,
+		CommitHash: bi.CommitHash,
+		BuildDate:  bi.BuildDate,
+		GoVersion:  bi.GoVersion,
+		Os:         bi.OS,
+		Arch:       bi.Arch,
+		Compiler:   bi.Compiler,
 	}, nil
 }
+
+// accountError - status of errors of the account service, the same errors get
+// the same HTTP statuses from the REST API
+func accountError(err error) error {
+	switch {
+	case errors.Is(err, account.ErrInvalidCredentials), errors.Is(err, account.ErrInvalidRefreshToken):
+		return status.Error(codes.Unauthenticated, ErrAccessDenied.Error())
+	case errors.Is(err, account.ErrUsernameRequired):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, account.ErrUsernameTaken):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, entity.ErrUserNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	}
+
+	return err
+}
+
+// UserInfo - profile of the caller
+func (h *AuthHandlers) UserInfo(ctx context.Context, _ *authpb.UserInfoRequest) (*authpb.UserInfoResponse, error) {
+	claims, ok := ClaimsFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, ErrMissingToken.Error())
+	}
+
+	caller, err := h.ur.FindUserByEmail(ctx, claims.Subject)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, ErrAccessDenied.Error())
+	}
+
+	user, err := h.ur.GetUserProfile(ctx, caller.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	roles, err := h.ur.ListUserRoles(ctx, caller.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &authpb.UserInfoResponse{User: protoUser(user, roles)}, nil
+}
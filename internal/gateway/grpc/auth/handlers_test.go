@@ -4,16 +4,21 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
+	"github.com/bogatyr285/auth-go/internal/auth/account"
 	"github.com/bogatyr285/auth-go/internal/auth/entity"
 	"github.com/bogatyr285/auth-go/internal/buildinfo"
 	"github.com/bogatyr285/auth-go/internal/gateway/grpc/auth"
 	"github.com/bogatyr285/auth-go/internal/mocks"
+	pkgjwt "github.com/bogatyr285/auth-go/pkg/jwt"
 	authpb "github.com/bogatyr285/auth-go/pkg/server/grpc/auth"
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 )
 
 func TestLoginUser(t *testing.T) {
@@ -22,6 +27,7 @@ func TestLoginUser(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	mockAccountRepo := mocks.NewMockAccountRepository(ctrl)
 	mockCryptoPassword := mocks.NewMockCryptoPassword(ctrl)
 	mockJWTManager := mocks.NewMockJWTManager(ctrl)
 
@@ -41,25 +47,38 @@ func TestLoginUser(t *testing.T) {
 			args: args{
 				ctx: context.Background(),
 				req: &authpb.LoginUserRequest{
-					LoginMethod: &authpb.LoginUserRequest_Email{Email: "test@example.com11"},
+					LoginMethod: &authpb.LoginUserRequest_Email{Email: "test@example.com"},
 					Password:    "validpassword",
 				},
 			},
 			setupMocks: func() {
-				mockUserRepo.EXPECT().
+				mockAccountRepo.EXPECT().
 					FindUserByEmail(gomock.Any(), "test@example.com").
-					Return(entity.UserAccount{Username: "user1", Password: "hashedpassword"}, nil)
+					Return(entity.UserAccount{ID: 1, Username: "user1", Password: "hashedpassword"}, nil)
 
 				mockCryptoPassword.EXPECT().
 					ComparePasswords("hashedpassword", "validpassword").
 					Return(true)
 
+				mockAccountRepo.EXPECT().
+					ListWebAuthnCredentials(gomock.Any(), 1).
+					Return(nil, nil)
+
+				mockAccountRepo.EXPECT().
+					ListUserRoles(gomock.Any(), 1).
+					Return([]string{entity.RoleUser}, nil)
+
 				mockJWTManager.EXPECT().
-					IssueToken("user1").
+					IssueTokenWithClaims("user1", signInClaims(entity.RoleUser)).
 					Return("validtoken", nil)
+
+				mockAccountRepo.EXPECT().
+					ExistsTokenByUserID(gomock.Any(), 1).
+					Return("refreshtoken", nil)
 			},
 			expectedResponse: &authpb.LoginUserResponse{
-				Token: "validtoken",
+				Token:        "validtoken",
+				RefreshToken: "refreshtoken",
 			},
 			expectedError: nil,
 		},
@@ -73,14 +92,14 @@ func TestLoginUser(t *testing.T) {
 				},
 			},
 			setupMocks: func() {
-				mockUserRepo.EXPECT().
+				mockAccountRepo.EXPECT().
 					FindUserByEmail(gomock.Any(), "nonexistent@example.com").
 					Return(entity.UserAccount{}, errors.New("not found"))
 
 				// Other mocks won't get called
 			},
 			expectedResponse: nil,
-			expectedError:    errors.New("not found"),
+			expectedError:    status.Error(codes.Unauthenticated, auth.ErrAccessDenied.Error()),
 		},
 		{
 			name: "incorrect password",
@@ -92,9 +111,9 @@ func TestLoginUser(t *testing.T) {
 				},
 			},
 			setupMocks: func() {
-				mockUserRepo.EXPECT().
+				mockAccountRepo.EXPECT().
 					FindUserByEmail(gomock.Any(), "test@example.com").
-					Return(entity.UserAccount{Username: "user1", Password: "hashedpassword"}, nil)
+					Return(entity.UserAccount{ID: 1, Username: "user1", Password: "hashedpassword"}, nil)
 
 				mockCryptoPassword.EXPECT().
 					ComparePasswords("hashedpassword", "wrongpassword").
@@ -115,21 +134,144 @@ func TestLoginUser(t *testing.T) {
 				},
 			},
 			setupMocks: func() {
-				mockUserRepo.EXPECT().
+				mockAccountRepo.EXPECT().
 					FindUserByEmail(gomock.Any(), "test@example.com").
-					Return(entity.UserAccount{Username: "user1", Password: "hashedpassword"}, nil)
+					Return(entity.UserAccount{ID: 1, Username: "user1", Password: "hashedpassword"}, nil)
 
 				mockCryptoPassword.EXPECT().
 					ComparePasswords("hashedpassword", "validpassword").
 					Return(true)
 
+				mockAccountRepo.EXPECT().
+					ListWebAuthnCredentials(gomock.Any(), 1).
+					Return(nil, nil)
+
+				mockAccountRepo.EXPECT().
+					ListUserRoles(gomock.Any(), 1).
+					Return([]string{entity.RoleUser}, nil)
+
 				mockJWTManager.EXPECT().
-					IssueToken("user1").
+					IssueTokenWithClaims("user1", signInClaims(entity.RoleUser)).
 					Return("", errors.New("token issuance error"))
 			},
 			expectedResponse: nil,
 			expectedError:    errors.New("token issuance error"),
 		},
+		{
+			name: "successful phone login with code",
+			args: args{
+				ctx: context.Background(),
+				req: &authpb.LoginUserRequest{
+					LoginMethod: &authpb.LoginUserRequest_PhoneNumber{PhoneNumber: "+1 (555) 123-4567"},
+					OtpCode:     "123456",
+				},
+			},
+			setupMocks: func() {
+				mockUserRepo.EXPECT().
+					FindUserByPhone(gomock.Any(), "+15551234567").
+					Return(entity.UserAccount{ID: 1, Username: "user1", Phone: "+15551234567", PhoneVerified: true}, nil)
+
+				mockUserRepo.EXPECT().
+					FindActiveOTP(gomock.Any(), "+15551234567", entity.OTPPurposeLogin).
+					Return(entity.OTP{ID: 7, CodeHash: "hashedcode", ExpiresAt: time.Now().Add(time.Minute)}, nil)
+
+				mockUserRepo.EXPECT().
+					RegisterOTPAttempt(gomock.Any(), 7, 5).
+					Return(true, nil)
+
+				mockCryptoPassword.EXPECT().
+					ComparePasswords("hashedcode", "123456").
+					Return(true)
+
+				mockUserRepo.EXPECT().
+					ConsumeOTP(gomock.Any(), 7).
+					Return(nil)
+
+				mockAccountRepo.EXPECT().
+					ListWebAuthnCredentials(gomock.Any(), 1).
+					Return(nil, nil)
+
+				mockAccountRepo.EXPECT().
+					ListUserRoles(gomock.Any(), 1).
+					Return([]string{entity.RoleUser}, nil)
+
+				mockJWTManager.EXPECT().
+					IssueTokenWithClaims("user1", signInClaims(entity.RoleUser)).
+					Return("validtoken", nil)
+
+				mockAccountRepo.EXPECT().
+					ExistsTokenByUserID(gomock.Any(), 1).
+					Return("refreshtoken", nil)
+			},
+			expectedResponse: &authpb.LoginUserResponse{
+				Token:        "validtoken",
+				RefreshToken: "refreshtoken",
+			},
+			expectedError: nil,
+		},
+		{
+			name: "phone login with unverified phone",
+			args: args{
+				ctx: context.Background(),
+				req: &authpb.LoginUserRequest{
+					LoginMethod: &authpb.LoginUserRequest_PhoneNumber{PhoneNumber: "+15551234567"},
+					Password:    "validpassword",
+				},
+			},
+			setupMocks: func() {
+				mockUserRepo.EXPECT().
+					FindUserByPhone(gomock.Any(), "+15551234567").
+					Return(entity.UserAccount{ID: 1, Username: "user1", Password: "hashedpassword", Phone: "+15551234567"}, nil)
+			},
+			expectedResponse: nil,
+			expectedError:    status.Error(codes.Unauthenticated, auth.ErrAccessDenied.Error()),
+		},
+		{
+			name: "phone login with expired code",
+			args: args{
+				ctx: context.Background(),
+				req: &authpb.LoginUserRequest{
+					LoginMethod: &authpb.LoginUserRequest_PhoneNumber{PhoneNumber: "+15551234567"},
+					OtpCode:     "123456",
+				},
+			},
+			setupMocks: func() {
+				mockUserRepo.EXPECT().
+					FindUserByPhone(gomock.Any(), "+15551234567").
+					Return(entity.UserAccount{ID: 1, Username: "user1", Phone: "+15551234567", PhoneVerified: true}, nil)
+
+				mockUserRepo.EXPECT().
+					FindActiveOTP(gomock.Any(), "+15551234567", entity.OTPPurposeLogin).
+					Return(entity.OTP{ID: 7, CodeHash: "hashedcode", ExpiresAt: time.Now().Add(-time.Second)}, nil)
+			},
+			expectedResponse: nil,
+			expectedError:    status.Error(codes.Unauthenticated, auth.ErrAccessDenied.Error()),
+		},
+		{
+			name: "phone login with exhausted attempts",
+			args: args{
+				ctx: context.Background(),
+				req: &authpb.LoginUserRequest{
+					LoginMethod: &authpb.LoginUserRequest_PhoneNumber{PhoneNumber: "+15551234567"},
+					OtpCode:     "123456",
+				},
+			},
+			setupMocks: func() {
+				mockUserRepo.EXPECT().
+					FindUserByPhone(gomock.Any(), "+15551234567").
+					Return(entity.UserAccount{ID: 1, Username: "user1", Phone: "+15551234567", PhoneVerified: true}, nil)
+
+				mockUserRepo.EXPECT().
+					FindActiveOTP(gomock.Any(), "+15551234567", entity.OTPPurposeLogin).
+					Return(entity.OTP{ID: 7, CodeHash: "hashedcode", Attempts: 5, ExpiresAt: time.Now().Add(time.Minute)}, nil)
+
+				mockUserRepo.EXPECT().
+					RegisterOTPAttempt(gomock.Any(), 7, 5).
+					Return(false, nil)
+			},
+			expectedResponse: nil,
+			expectedError:    status.Error(codes.ResourceExhausted, "too many attempts, request a new code"),
+		},
 	}
 
 	for _, tt := range tests {
@@ -137,8 +279,7 @@ func TestLoginUser(t *testing.T) {
 			h := auth.NewAuthHandlers(
 				mockUserRepo,
 				mockCryptoPassword,
-				mockJWTManager,
-				buildinfo.BuildInfo{},
+				account.New(mockAccountRepo, mockCryptoPassword, mockJWTManager, buildinfo.BuildInfo{}),
 			)
 			tt.setupMocks()
 			resp, err := h.LoginUser(tt.args.ctx, tt.args.req)
@@ -148,3 +289,107 @@ func TestLoginUser(t *testing.T) {
 		})
 	}
 }
+
+// signInClaims - claims of access tokens issued by login, auth_time is the time of the call
+func signInClaims(roles ...string) gomock.Matcher {
+	return gomock.Cond(func(x any) bool {
+		claims, ok := x.(jwt.MapClaims)
+		if !ok {
+			return false
+		}
+
+		authTime, ok := claims[pkgjwt.AuthTimeClaim].(int64)
+		return ok && time.Since(time.Unix(authTime, 0)) < time.Minute &&
+			assert.ObjectsAreEqual(roles, claims[pkgjwt.RolesClaim])
+	})
+}
+
+func TestUserInfo(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	withCaller := func(username string) context.Context {
+		return auth.ContextWithClaims(context.Background(), auth.Claims{Subject: username, Roles: []string{entity.RoleUser}})
+	}
+
+	alice := entity.UserAccount{
+		ID:       1,
+		Username: "alice@example.com",
+		Phone:    "+15551234567",
+		Name:     "Alice",
+		Email:    "alice@example.com",
+		Gender:   entity.GenderFemale,
+		Address:  entity.Address{Street: "1 Main St", City: "Springfield", State: "IL", Zipcode: "62701"},
+	}
+
+	tests := []struct {
+		name             string
+		ctx              context.Context
+		setupMocks       func()
+		expectedResponse *authpb.UserInfoResponse
+		expectedError    error
+	}{
+		{
+			name: "caller's profile",
+			ctx:  withCaller("alice@example.com"),
+			setupMocks: func() {
+				mockUserRepo.EXPECT().
+					FindUserByEmail(gomock.Any(), "alice@example.com").
+					Return(entity.UserAccount{ID: 1, Username: "alice@example.com"}, nil)
+
+				mockUserRepo.EXPECT().
+					GetUserProfile(gomock.Any(), 1).
+					Return(alice, nil)
+
+				mockUserRepo.EXPECT().
+					ListUserRoles(gomock.Any(), 1).
+					Return([]string{entity.RoleUser, entity.RoleModerator}, nil)
+			},
+			expectedResponse: &authpb.UserInfoResponse{User: &authpb.User{
+				UserId: "alice@example.com",
+				Name:   "Alice",
+				Gender: authpb.Gender_GENDER_FEMALE,
+				Role:   authpb.UserRole_USER_ROLE_MODERATOR,
+				Email:  "alice@example.com",
+				Address: &authpb.User_Address{
+					Street:  "1 Main St",
+					City:    "Springfield",
+					State:   "IL",
+					Zipcode: "62701",
+				},
+				ContactMethod: &authpb.User_PhoneNumber{PhoneNumber: "+15551234567"},
+			}},
+		},
+		{
+			name:          "call without caller",
+			ctx:           context.Background(),
+			setupMocks:    func() {},
+			expectedError: status.Error(codes.Unauthenticated, auth.ErrMissingToken.Error()),
+		},
+		{
+			name: "deleted user",
+			ctx:  withCaller("bob@example.com"),
+			setupMocks: func() {
+				mockUserRepo.EXPECT().
+					FindUserByEmail(gomock.Any(), "bob@example.com").
+					Return(entity.UserAccount{}, errors.New("not found"))
+			},
+			expectedError: status.Error(codes.Unauthenticated, auth.ErrAccessDenied.Error()),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := auth.NewAuthHandlers(mockUserRepo, mocks.NewMockCryptoPassword(ctrl), nil)
+			tt.setupMocks()
+			resp, err := h.UserInfo(tt.ctx, &authpb.UserInfoRequest{})
+
+			assert.Equal(t, tt.expectedError, err)
+			if tt.expectedResponse != nil {
+				assert.True(t, proto.Equal(tt.expectedResponse, resp), "got %v", resp)
+			}
+		})
+	}
+}
@@ -4,27 +4,69 @@ import (
 	"context"
 	"log/slog"
 
+	"github.com/bogatyr285/auth-go/pkg/health"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
 )
 
+// HealthStatus - serving status of the services, health.Checker
+type HealthStatus interface {
+	Status(service string) health.Status
+	Watch(service string) (<-chan health.Status, func())
+}
+
+// HealthChecker - grpc.health.v1.Health reporting statuses of HealthStatus
 type HealthChecker struct {
+	health HealthStatus
 	logger *slog.Logger
 }
 
-func NewHealthChecker(logger *slog.Logger) *HealthChecker {
-	return &HealthChecker{logger: logger.With("module", "health-checker")}
+func NewHealthChecker(hs HealthStatus, logger *slog.Logger) *HealthChecker {
+	return &HealthChecker{health: hs, logger: logger.With("module", "health-checker")}
 }
 
 func (s *HealthChecker) Check(ctx context.Context, req *grpc_health_v1.HealthCheckRequest) (*grpc_health_v1.HealthCheckResponse, error) {
-	s.logger.Debug("Serving the Check request for health check")
-	return &grpc_health_v1.HealthCheckResponse{
-		Status: grpc_health_v1.HealthCheckResponse_SERVING,
-	}, nil
+	st := s.health.Status(req.GetService())
+	if st == health.StatusServiceUnknown {
+		return nil, status.Error(codes.NotFound, "unknown service")
+	}
+
+	return &grpc_health_v1.HealthCheckResponse{Status: servingStatus(st)}, nil
 }
 
+// Watch - sends the status, then its changes until the client goes away. Unknown
+// services are watched too, they may be registered later. The stream ends with
+// Unavailable when the server shuts down, after NOT_SERVING was sent
 func (s *HealthChecker) Watch(req *grpc_health_v1.HealthCheckRequest, server grpc_health_v1.Health_WatchServer) error {
-	s.logger.Debug("Serving the Watch request for health check")
-	return server.Send(&grpc_health_v1.HealthCheckResponse{
-		Status: grpc_health_v1.HealthCheckResponse_SERVING,
-	})
+	updates, cancel := s.health.Watch(req.GetService())
+	defer cancel()
+
+	for {
+		select {
+		case <-server.Context().Done():
+			return status.FromContextError(server.Context().Err()).Err()
+		case st, ok := <-updates:
+			if !ok {
+				return status.Error(codes.Unavailable, "server is shutting down")
+			}
+
+			if err := server.Send(&grpc_health_v1.HealthCheckResponse{Status: servingStatus(st)}); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func servingStatus(st health.Status) grpc_health_v1.HealthCheckResponse_ServingStatus {
+	switch st {
+	case health.StatusServing:
+		return grpc_health_v1.HealthCheckResponse_SERVING
+	case health.StatusNotServing:
+		return grpc_health_v1.HealthCheckResponse_NOT_SERVING
+	case health.StatusServiceUnknown:
+		return grpc_health_v1.HealthCheckResponse_SERVICE_UNKNOWN
+	default:
+		return grpc_health_v1.HealthCheckResponse_UNKNOWN
+	}
 }
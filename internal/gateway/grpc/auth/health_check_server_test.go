@@ -0,0 +1,91 @@
+package auth_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/bogatyr285/auth-go/internal/gateway/grpc/auth"
+	"github.com/bogatyr285/auth-go/pkg/health"
+	authpb "github.com/bogatyr285/auth-go/pkg/server/grpc/auth"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+// TestHealthCheck - health checks are public and reflect the probes
+func (s *grpcGatewaySuite) TestHealthCheck() {
+	conn, err := grpc.NewClient(s.grpcServer.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	s.Require().NoError(err)
+	defer conn.Close()
+	client := grpc_health_v1.NewHealthClient(conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	for _, service := range []string{"", authpb.AuthService_ServiceDesc.ServiceName} {
+		res, err := client.Check(ctx, &grpc_health_v1.HealthCheckRequest{Service: service})
+		s.Require().NoError(err)
+		s.Equal(grpc_health_v1.HealthCheckResponse_SERVING, res.GetStatus(), service)
+	}
+
+	_, err = client.Check(ctx, &grpc_health_v1.HealthCheckRequest{Service: "billing.v1.BillingService"})
+	s.Equal(codes.NotFound, status.Code(err))
+}
+
+func TestHealthWatch(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	var failing error
+	checker := health.NewChecker(health.Config{Interval: time.Hour, Timeout: time.Second}, log)
+	checker.AddProbe("database", func(context.Context) error { return failing })
+	checker.AddService(authpb.AuthService_ServiceDesc.ServiceName, "database")
+	require.NoError(t, checker.Init(context.Background()))
+
+	srv := grpc.NewServer()
+	grpc_health_v1.RegisterHealthServer(srv, auth.NewHealthChecker(checker, log))
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go srv.Serve(l)
+	defer srv.Stop()
+
+	conn, err := grpc.NewClient(l.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stream, err := grpc_health_v1.NewHealthClient(conn).Watch(ctx, &grpc_health_v1.HealthCheckRequest{
+		Service: authpb.AuthService_ServiceDesc.ServiceName,
+	})
+	require.NoError(t, err)
+
+	recv := func() grpc_health_v1.HealthCheckResponse_ServingStatus {
+		res, err := stream.Recv()
+		require.NoError(t, err)
+		return res.GetStatus()
+	}
+	require.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, recv())
+
+	// changes are pushed, the stream stays open
+	failing = io.ErrUnexpectedEOF
+	require.NoError(t, checker.Init(context.Background()))
+	require.Equal(t, grpc_health_v1.HealthCheckResponse_NOT_SERVING, recv())
+
+	failing = nil
+	require.NoError(t, checker.Init(context.Background()))
+	require.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, recv())
+
+	// graceful shutdown is announced before the stream ends
+	checker.Stop()
+	require.Equal(t, grpc_health_v1.HealthCheckResponse_NOT_SERVING, recv())
+	_, err = stream.Recv()
+	require.Equal(t, codes.Unavailable, status.Code(err))
+}
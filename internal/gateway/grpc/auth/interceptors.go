@@ -0,0 +1,229 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/bogatyr285/auth-go/internal/auth/entity"
+	"github.com/bogatyr285/auth-go/internal/auth/pat"
+	pkgjwt "github.com/bogatyr285/auth-go/pkg/jwt"
+	authpb "github.com/bogatyr285/auth-go/pkg/server/grpc/auth"
+	"github.com/golang-jwt/jwt/v5"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// publicServices - services registered along with ours, they have no policy option
+var publicServices = []string{
+	grpc_health_v1.Health_ServiceDesc.ServiceName,
+	"grpc.reflection.v1.ServerReflection",
+	"grpc.reflection.v1alpha.ServerReflection",
+}
+
+// methodPolicies - policy option of every RPC of auth.proto and relations.proto, keyed by full method name
+var methodPolicies = policiesOf(authpb.File_auth_proto, authpb.File_relations_proto)
+
+func policiesOf(files ...protoreflect.FileDescriptor) map[string]*authpb.MethodPolicy {
+	policies := map[string]*authpb.MethodPolicy{}
+	for _, file := range files {
+		services := file.Services()
+		for i := 0; i < services.Len(); i++ {
+			methods := services.Get(i).Methods()
+			for j := 0; j < methods.Len(); j++ {
+				m := methods.Get(j)
+				policy, _ := proto.GetExtension(m.Options(), authpb.E_Policy).(*authpb.MethodPolicy)
+				policies["/"+string(services.Get(i).FullName())+"/"+string(m.Name())] = policy
+			}
+		}
+	}
+
+	return policies
+}
+
+// methodPolicy - policy of the RPC, unknown RPCs need an access token
+func methodPolicy(fullMethod string) *authpb.MethodPolicy {
+	service, _, _ := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+	if slices.Contains(publicServices, service) {
+		return &authpb.MethodPolicy{Public: true}
+	}
+
+	return methodPolicies[fullMethod]
+}
+
+// Claims - caller of the RPC, verified by the auth interceptors
+type Claims struct {
+	// Subject - username of the caller
+	Subject string
+	Roles   []string
+	// AuthTime - when the caller signed in, zero for refreshed and personal access tokens
+	AuthTime time.Time
+	// TokenID - ID of the personal access token, empty for JWTs
+	TokenID string
+	// Scopes - limit permissions of personal access tokens
+	Scopes []entity.Permission
+}
+
+// HasPermission - roles grant the permission, and scopes too for personal access tokens
+func (c Claims) HasPermission(p entity.Permission) bool {
+	if c.TokenID != "" && !slices.Contains(c.Scopes, p) {
+		return false
+	}
+
+	return entity.HasPermission(c.Roles, p)
+}
+
+type claimsCtxKey struct{}
+
+// ContextWithClaims - context of a call made by the caller
+func ContextWithClaims(ctx context.Context, claims Claims) context.Context {
+	return context.WithValue(ctx, claimsCtxKey{}, claims)
+}
+
+// ClaimsFromContext - caller of the RPC, missing for public RPCs
+func ClaimsFromContext(ctx context.Context) (Claims, bool) {
+	claims, ok := ctx.Value(claimsCtxKey{}).(Claims)
+	return claims, ok
+}
+
+// AuthUnaryInterceptor - verifies the bearer token unless the RPC is public and puts
+// the Claims of the caller into the context
+func AuthUnaryInterceptor(jm JWTManager, tokens AccessTokenRepository) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := authenticate(ctx, info.FullMethod, jm, tokens)
+		if err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// AuthStreamInterceptor - AuthUnaryInterceptor for streaming RPCs
+func AuthStreamInterceptor(jm JWTManager, tokens AccessTokenRepository) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authenticate(ss.Context(), info.FullMethod, jm, tokens)
+		if err != nil {
+			return err
+		}
+
+		return handler(srv, &authenticatedStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// authenticatedStream - stream with Claims of the caller in its context
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
+
+// authenticate - context with Claims of the caller, checked against the policy of the RPC
+func authenticate(ctx context.Context, fullMethod string, jm JWTManager, tokens AccessTokenRepository) (context.Context, error) {
+	policy := methodPolicy(fullMethod)
+	if policy.GetPublic() {
+		return ctx, nil
+	}
+
+	tokenString, err := bearerToken(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	claims, err := verifyBearer(ctx, tokenString, jm, tokens)
+	if err != nil {
+		return nil, err
+	}
+
+	p := entity.Permission(policy.GetPermission())
+	// personal access tokens are limited to RPCs of their scopes
+	if p == "" && claims.TokenID != "" {
+		return nil, status.Error(codes.PermissionDenied, ErrAccessDenied.Error())
+	}
+
+	if p != "" && !claims.HasPermission(p) {
+		return nil, status.Error(codes.PermissionDenied, ErrAccessDenied.Error())
+	}
+
+	return ContextWithClaims(ctx, claims), nil
+}
+
+// verifyBearer - Claims of the access token or the personal access token, both only for
+// users which still exist
+func verifyBearer(ctx context.Context, tokenString string, jm JWTManager, tokens AccessTokenRepository) (Claims, error) {
+	if pat.IsToken(tokenString) {
+		principal, err := pat.NewVerifier(tokens).Verify(ctx, tokenString)
+		if err != nil {
+			if errors.Is(err, pat.ErrInvalidToken) {
+				return Claims{}, status.Error(codes.Unauthenticated, ErrAccessDenied.Error())
+			}
+
+			return Claims{}, status.Error(codes.Internal, err.Error())
+		}
+
+		return Claims{
+			Subject: principal.Username,
+			Roles:   principal.Roles,
+			TokenID: principal.TokenID,
+			Scopes:  principal.Scopes,
+		}, nil
+	}
+
+	token, err := jm.VerifyToken(tokenString)
+	if err != nil {
+		return Claims{}, status.Error(codes.Unauthenticated, ErrAccessDenied.Error())
+	}
+
+	mapClaims := token.Claims.(jwt.MapClaims)
+	if !pkgjwt.FirstParty(mapClaims) {
+		return Claims{}, status.Error(codes.Unauthenticated, ErrAccessDenied.Error())
+	}
+
+	sub, err := mapClaims.GetSubject()
+	if err != nil || sub == "" {
+		return Claims{}, status.Error(codes.Unauthenticated, ErrAccessDenied.Error())
+	}
+
+	// tokens of deleted users stay valid until they expire
+	exists, err := tokens.ExistsUserByUsername(ctx, sub)
+	if err != nil {
+		return Claims{}, status.Error(codes.Internal, err.Error())
+	}
+
+	if !exists {
+		return Claims{}, status.Error(codes.Unauthenticated, ErrAccessDenied.Error())
+	}
+
+	claims := Claims{Subject: sub, Roles: pkgjwt.RolesFromClaims(mapClaims)}
+	if authTime, ok := mapClaims[pkgjwt.AuthTimeClaim].(float64); ok {
+		claims.AuthTime = time.Unix(int64(authTime), 0)
+	}
+
+	return claims, nil
+}
+
+// bearerToken - token from "authorization" metadata
+func bearerToken(ctx context.Context) (string, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return "", ErrMissingToken
+	}
+
+	tokenString, ok := strings.CutPrefix(values[0], "Bearer ")
+	if !ok {
+		return "", ErrMissingToken
+	}
+
+	return tokenString, nil
+}
@@ -0,0 +1,322 @@
+package auth_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bogatyr285/auth-go/internal/auth/entity"
+	"github.com/bogatyr285/auth-go/internal/auth/pat"
+	"github.com/bogatyr285/auth-go/internal/gateway/grpc/auth"
+	"github.com/bogatyr285/auth-go/internal/mocks"
+	authpb "github.com/bogatyr285/auth-go/pkg/server/grpc/auth"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestAuthUnaryInterceptor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	defer ctrl.Finish()
+
+	mockJWTManager := mocks.NewMockJWTManager(ctrl)
+	mockTokens := mocks.NewMockAccessTokenRepository(ctrl)
+
+	accessToken := entity.PersonalAccessToken{
+		ID:        "pat-1",
+		UserID:    7,
+		Scopes:    []entity.Permission{entity.PermissionRelationsRead},
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+
+	withToken := func(token string) context.Context {
+		return metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+token))
+	}
+
+	tests := []struct {
+		name          string
+		ctx           context.Context
+		method        string
+		setupMocks    func()
+		expectedError error
+		// expectedSubject - caller the handler gets, empty for public methods
+		expectedSubject string
+	}{
+		{
+			name:          "public method",
+			ctx:           context.Background(),
+			method:        authpb.AuthService_LoginUser_FullMethodName,
+			setupMocks:    func() {},
+			expectedError: nil,
+		},
+		{
+			name:          "health check",
+			ctx:           context.Background(),
+			method:        grpc_health_v1.Health_Check_FullMethodName,
+			setupMocks:    func() {},
+			expectedError: nil,
+		},
+		{
+			name:          "method without policy",
+			ctx:           context.Background(),
+			method:        "/auth.v1.AuthService/Unknown",
+			setupMocks:    func() {},
+			expectedError: status.Error(codes.Unauthenticated, auth.ErrMissingToken.Error()),
+		},
+		{
+			name:          "missing token",
+			ctx:           context.Background(),
+			method:        authpb.AuthService_UserInfo_FullMethodName,
+			setupMocks:    func() {},
+			expectedError: status.Error(codes.Unauthenticated, auth.ErrMissingToken.Error()),
+		},
+		{
+			name:   "invalid token",
+			ctx:    withToken("invalid"),
+			method: authpb.AuthService_UserInfo_FullMethodName,
+			setupMocks: func() {
+				mockJWTManager.EXPECT().
+					VerifyToken("invalid").
+					Return(nil, errors.New("token validation errror"))
+			},
+			expectedError: status.Error(codes.Unauthenticated, auth.ErrAccessDenied.Error()),
+		},
+		{
+			name:   "mfa token",
+			ctx:    withToken("mfa"),
+			method: authpb.AuthService_UserInfo_FullMethodName,
+			setupMocks: func() {
+				mockJWTManager.EXPECT().
+					VerifyToken("mfa").
+					Return(&jwt.Token{Claims: jwt.MapClaims{"sub": "user1", "token_use": "mfa"}}, nil)
+			},
+			expectedError: status.Error(codes.Unauthenticated, auth.ErrAccessDenied.Error()),
+		},
+		{
+			name:   "token of oauth client",
+			ctx:    withToken("client"),
+			method: authpb.AuthService_UserInfo_FullMethodName,
+			setupMocks: func() {
+				mockJWTManager.EXPECT().
+					VerifyToken("client").
+					Return(&jwt.Token{Claims: jwt.MapClaims{"sub": "user1", "client_id": "app", "scope": "profile"}}, nil)
+			},
+			expectedError: status.Error(codes.Unauthenticated, auth.ErrAccessDenied.Error()),
+		},
+		{
+			name:   "token for another audience",
+			ctx:    withToken("exchanged"),
+			method: authpb.AuthService_UserInfo_FullMethodName,
+			setupMocks: func() {
+				mockJWTManager.EXPECT().
+					VerifyToken("exchanged").
+					Return(&jwt.Token{Claims: jwt.MapClaims{"sub": "user1", "iss": "auth-service", "aud": "orders-api"}}, nil)
+			},
+			expectedError: status.Error(codes.Unauthenticated, auth.ErrAccessDenied.Error()),
+		},
+		{
+			name:   "impersonation token",
+			ctx:    withToken("impersonation"),
+			method: authpb.AuthService_UserInfo_FullMethodName,
+			setupMocks: func() {
+				mockJWTManager.EXPECT().
+					VerifyToken("impersonation").
+					Return(&jwt.Token{Claims: jwt.MapClaims{"sub": "user1", "act": map[string]interface{}{"sub": "admin1"}}}, nil)
+			},
+			expectedError: status.Error(codes.Unauthenticated, auth.ErrAccessDenied.Error()),
+		},
+		{
+			name:   "user has permission",
+			ctx:    withToken("user"),
+			method: authpb.AuthService_UserInfo_FullMethodName,
+			setupMocks: func() {
+				mockJWTManager.EXPECT().
+					VerifyToken("user").
+					Return(&jwt.Token{Claims: jwt.MapClaims{"sub": "user1", "roles": []interface{}{"user"}}}, nil)
+				mockTokens.EXPECT().ExistsUserByUsername(gomock.Any(), "user1").Return(true, nil)
+			},
+			expectedError:   nil,
+			expectedSubject: "user1",
+		},
+		{
+			name:   "user lacks permission",
+			ctx:    withToken("user"),
+			method: authpb.AuthService_GrantRole_FullMethodName,
+			setupMocks: func() {
+				mockJWTManager.EXPECT().
+					VerifyToken("user").
+					Return(&jwt.Token{Claims: jwt.MapClaims{"sub": "user1", "roles": []interface{}{"user"}}}, nil)
+				mockTokens.EXPECT().ExistsUserByUsername(gomock.Any(), "user1").Return(true, nil)
+			},
+			expectedError: status.Error(codes.PermissionDenied, auth.ErrAccessDenied.Error()),
+		},
+		{
+			name:   "admin",
+			ctx:    withToken("admin"),
+			method: authpb.AuthService_GrantRole_FullMethodName,
+			setupMocks: func() {
+				mockJWTManager.EXPECT().
+					VerifyToken("admin").
+					Return(&jwt.Token{Claims: jwt.MapClaims{"sub": "admin1", "roles": []interface{}{"user", "admin"}}}, nil)
+				mockTokens.EXPECT().ExistsUserByUsername(gomock.Any(), "admin1").Return(true, nil)
+			},
+			expectedError:   nil,
+			expectedSubject: "admin1",
+		},
+		{
+			name:   "deleted user",
+			ctx:    withToken("deleted"),
+			method: authpb.AuthService_UserInfo_FullMethodName,
+			setupMocks: func() {
+				mockJWTManager.EXPECT().
+					VerifyToken("deleted").
+					Return(&jwt.Token{Claims: jwt.MapClaims{"sub": "user2", "roles": []interface{}{"user"}}}, nil)
+				mockTokens.EXPECT().ExistsUserByUsername(gomock.Any(), "user2").Return(false, nil)
+			},
+			expectedError: status.Error(codes.Unauthenticated, auth.ErrAccessDenied.Error()),
+		},
+		{
+			name:   "access token within scopes",
+			ctx:    withToken(pat.Prefix + "relations"),
+			method: authpb.RelationService_Check_FullMethodName,
+			setupMocks: func() {
+				mockTokens.EXPECT().
+					GetPersonalAccessToken(gomock.Any(), pat.Hash(pat.Prefix+"relations")).
+					Return(accessToken, nil)
+				mockTokens.EXPECT().GetUserById(gomock.Any(), 7).Return(entity.UserAccount{ID: 7, Username: "ci"}, nil)
+				mockTokens.EXPECT().ListUserRoles(gomock.Any(), 7).Return([]string{"user", "admin"}, nil)
+				mockTokens.EXPECT().TouchPersonalAccessToken(gomock.Any(), "pat-1", gomock.Any()).Return(nil)
+			},
+			expectedError:   nil,
+			expectedSubject: "ci",
+		},
+		{
+			name:   "access token out of scopes",
+			ctx:    withToken(pat.Prefix + "relations"),
+			method: authpb.RelationService_Write_FullMethodName,
+			setupMocks: func() {
+				used := time.Now()
+				token := accessToken
+				token.LastUsedAt = &used
+
+				mockTokens.EXPECT().
+					GetPersonalAccessToken(gomock.Any(), pat.Hash(pat.Prefix+"relations")).
+					Return(token, nil)
+				mockTokens.EXPECT().GetUserById(gomock.Any(), 7).Return(entity.UserAccount{ID: 7, Username: "ci"}, nil)
+				mockTokens.EXPECT().ListUserRoles(gomock.Any(), 7).Return([]string{"user", "admin"}, nil)
+			},
+			expectedError: status.Error(codes.PermissionDenied, auth.ErrAccessDenied.Error()),
+		},
+		{
+			name:   "access token on method without permission",
+			ctx:    withToken(pat.Prefix + "relations"),
+			method: "/auth.v1.AuthService/Unknown",
+			setupMocks: func() {
+				used := time.Now()
+				token := accessToken
+				token.LastUsedAt = &used
+
+				mockTokens.EXPECT().
+					GetPersonalAccessToken(gomock.Any(), pat.Hash(pat.Prefix+"relations")).
+					Return(token, nil)
+				mockTokens.EXPECT().GetUserById(gomock.Any(), 7).Return(entity.UserAccount{ID: 7, Username: "ci"}, nil)
+				mockTokens.EXPECT().ListUserRoles(gomock.Any(), 7).Return([]string{"user", "admin"}, nil)
+			},
+			expectedError: status.Error(codes.PermissionDenied, auth.ErrAccessDenied.Error()),
+		},
+		{
+			name:   "expired access token",
+			ctx:    withToken(pat.Prefix + "expired"),
+			method: authpb.RelationService_Check_FullMethodName,
+			setupMocks: func() {
+				token := accessToken
+				token.ExpiresAt = time.Now().Add(-time.Minute)
+
+				mockTokens.EXPECT().
+					GetPersonalAccessToken(gomock.Any(), pat.Hash(pat.Prefix+"expired")).
+					Return(token, nil)
+			},
+			expectedError: status.Error(codes.Unauthenticated, auth.ErrAccessDenied.Error()),
+		},
+		{
+			name:   "unknown access token",
+			ctx:    withToken(pat.Prefix + "unknown"),
+			method: authpb.RelationService_Check_FullMethodName,
+			setupMocks: func() {
+				mockTokens.EXPECT().
+					GetPersonalAccessToken(gomock.Any(), pat.Hash(pat.Prefix+"unknown")).
+					Return(entity.PersonalAccessToken{}, entity.ErrAccessTokenNotFound)
+			},
+			expectedError: status.Error(codes.Unauthenticated, auth.ErrAccessDenied.Error()),
+		},
+	}
+
+	interceptor := auth.AuthUnaryInterceptor(mockJWTManager, mockTokens)
+	handler := func(ctx context.Context, req any) (any, error) {
+		claims, _ := auth.ClaimsFromContext(ctx)
+		return claims.Subject, nil
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMocks()
+			resp, err := interceptor(tt.ctx, nil, &grpc.UnaryServerInfo{FullMethod: tt.method}, handler)
+
+			assert.Equal(t, tt.expectedError, err)
+			if tt.expectedError == nil {
+				assert.Equal(t, tt.expectedSubject, resp)
+			}
+		})
+	}
+}
+
+// serverStream - stream of a call with the context
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s serverStream) Context() context.Context {
+	return s.ctx
+}
+
+func TestAuthStreamInterceptor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	defer ctrl.Finish()
+
+	mockJWTManager := mocks.NewMockJWTManager(ctrl)
+	mockTokens := mocks.NewMockAccessTokenRepository(ctrl)
+	interceptor := auth.AuthStreamInterceptor(mockJWTManager, mockTokens)
+
+	var subject string
+	handler := func(srv any, stream grpc.ServerStream) error {
+		claims, _ := auth.ClaimsFromContext(stream.Context())
+		subject = claims.Subject
+		return nil
+	}
+	info := &grpc.StreamServerInfo{FullMethod: "/auth.v1.AuthService/Watch", IsServerStream: true}
+
+	err := interceptor(nil, serverStream{ctx: context.Background()}, info, handler)
+	assert.Equal(t, status.Error(codes.Unauthenticated, auth.ErrMissingToken.Error()), err)
+
+	mockJWTManager.EXPECT().
+		VerifyToken("user").
+		Return(&jwt.Token{Claims: jwt.MapClaims{"sub": "user1", "roles": []interface{}{"user"}, "auth_time": float64(1700000000)}}, nil)
+	mockTokens.EXPECT().ExistsUserByUsername(gomock.Any(), "user1").Return(true, nil)
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer user"))
+	assert.NoError(t, interceptor(nil, serverStream{ctx: ctx}, info, handler))
+	assert.Equal(t, "user1", subject)
+
+	// health checks are watched without a token
+	info.FullMethod = grpc_health_v1.Health_Watch_FullMethodName
+	assert.NoError(t, interceptor(nil, serverStream{ctx: context.Background()}, info, handler))
+}
@@ -0,0 +1,223 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/bogatyr285/auth-go/internal/auth/entity"
+	authpb "github.com/bogatyr285/auth-go/pkg/server/grpc/auth"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	otpDigits      = 6
+	otpTTL         = 5 * time.Minute
+	otpMaxAttempts = 5
+	// otpSendLimit - codes per phone within otpSendWindow
+	otpSendLimit  = 3
+	otpSendWindow = 15 * time.Minute
+)
+
+var (
+	ErrInvalidPhone = errors.New("phone number must be in E.164 format, e.g. +15551234567")
+	ErrSMSDisabled  = errors.New("sms is not configured")
+
+	e164 = regexp.MustCompile(`^\+[1-9]\d{6,14}$`)
+)
+
+// normalizePhone - drops formatting characters and checks the number is E.164
+func normalizePhone(phone string) (string, error) {
+	phone = strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '-', '(', ')', '.':
+			return -1
+		}
+		return r
+	}, phone)
+
+	if !e164.MatchString(phone) {
+		return "", ErrInvalidPhone
+	}
+
+	return phone, nil
+}
+
+func (h *AuthHandlers) SendPhoneCode(ctx context.Context, req *authpb.SendPhoneCodeRequest) (*authpb.SendPhoneCodeResponse, error) {
+	if h.ss == nil {
+		return nil, status.Error(codes.FailedPrecondition, ErrSMSDisabled.Error())
+	}
+
+	phone, err := normalizePhone(req.GetPhoneNumber())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	var purpose string
+	switch req.GetPurpose() {
+	case authpb.PhoneCodePurpose_PHONE_CODE_PURPOSE_VERIFY:
+		purpose = entity.OTPPurposeVerify
+	case authpb.PhoneCodePurpose_PHONE_CODE_PURPOSE_LOGIN:
+		purpose = entity.OTPPurposeLogin
+	default:
+		return nil, status.Error(codes.InvalidArgument, "purpose is required")
+	}
+
+	if err = h.sendPhoneCode(ctx, phone, purpose); err != nil {
+		return nil, err
+	}
+
+	return &authpb.SendPhoneCodeResponse{
+		ExpiresIn: int32(otpTTL.Seconds()),
+	}, nil
+}
+
+func (h *AuthHandlers) VerifyPhone(ctx context.Context, req *authpb.VerifyPhoneRequest) (*authpb.VerifyPhoneResponse, error) {
+	phone, err := normalizePhone(req.GetPhoneNumber())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if err = h.checkPhoneCode(ctx, phone, entity.OTPPurposeVerify, req.GetCode()); err != nil {
+		return nil, err
+	}
+
+	if err = h.ur.SetPhoneVerified(ctx, phone); err != nil {
+		return nil, err
+	}
+
+	return &authpb.VerifyPhoneResponse{
+		Message: "ok",
+	}, nil
+}
+
+func (h *AuthHandlers) loginByPhone(ctx context.Context, req *authpb.LoginUserRequest) (*authpb.LoginUserResponse, error) {
+	phone, err := normalizePhone(req.GetPhoneNumber())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	user, err := h.ur.FindUserByPhone(ctx, phone)
+	if err != nil || !user.PhoneVerified {
+		return nil, status.Error(codes.Unauthenticated, ErrAccessDenied.Error())
+	}
+
+	if req.GetOtpCode() != "" {
+		if err = h.checkPhoneCode(ctx, phone, entity.OTPPurposeLogin, req.GetOtpCode()); err != nil {
+			return nil, err
+		}
+	} else if !h.cp.ComparePasswords(user.Password, req.Password) {
+		return nil, status.Error(codes.Unauthenticated, ErrAccessDenied.Error())
+	}
+
+	session, err := h.accounts.SignIn(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	return loginResponse(session), nil
+}
+
+// checkSendLimit - codes are throttled per number, whoever requests them
+func (h *AuthHandlers) checkSendLimit(ctx context.Context, phone string) error {
+	count, err := h.ur.CountOTPsSince(ctx, phone, time.Now().Add(-otpSendWindow))
+	if err != nil {
+		return err
+	}
+
+	if count >= otpSendLimit {
+		return status.Error(codes.ResourceExhausted, "too many codes requested, try again later")
+	}
+
+	return nil
+}
+
+// sendPhoneCode - codes for unknown numbers are recorded but not sent,
+// so the response doesn't reveal registered numbers and they are throttled the same way
+func (h *AuthHandlers) sendPhoneCode(ctx context.Context, phone, purpose string) error {
+	if err := h.checkSendLimit(ctx, phone); err != nil {
+		return err
+	}
+
+	code, err := generateCode()
+	if err != nil {
+		return err
+	}
+
+	hash, err := h.cp.HashPassword(code)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	err = h.ur.SaveOTP(ctx, entity.OTP{
+		Phone:     phone,
+		Purpose:   purpose,
+		CodeHash:  string(hash),
+		CreatedAt: now,
+		ExpiresAt: now.Add(otpTTL),
+	})
+	if err != nil {
+		return err
+	}
+
+	// verification codes go to numbers waiting for verification, login codes to verified ones
+	if purpose == entity.OTPPurposeLogin {
+		user, err := h.ur.FindUserByPhone(ctx, phone)
+		if err != nil || !user.PhoneVerified {
+			return nil
+		}
+	} else if _, err = h.ur.FindUserByPendingPhone(ctx, phone); err != nil {
+		return nil
+	}
+
+	text := fmt.Sprintf("Your code is %s. It expires in %s.", code, otpTTL)
+	return h.ss.SendSMS(ctx, phone, text)
+}
+
+// checkPhoneCode - every check counts as an attempt, a matched code can't be reused
+func (h *AuthHandlers) checkPhoneCode(ctx context.Context, phone, purpose, code string) error {
+	otp, err := h.ur.FindActiveOTP(ctx, phone, purpose)
+	if err != nil || time.Now().After(otp.ExpiresAt) {
+		return status.Error(codes.Unauthenticated, ErrAccessDenied.Error())
+	}
+
+	ok, err := h.ur.RegisterOTPAttempt(ctx, otp.ID, otpMaxAttempts)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return status.Error(codes.ResourceExhausted, "too many attempts, request a new code")
+	}
+
+	if !h.cp.ComparePasswords(otp.CodeHash, code) {
+		return status.Error(codes.Unauthenticated, ErrAccessDenied.Error())
+	}
+
+	if err = h.ur.ConsumeOTP(ctx, otp.ID); err != nil {
+		return status.Error(codes.Unauthenticated, ErrAccessDenied.Error())
+	}
+
+	return nil
+}
+
+func generateCode() (string, error) {
+	max := big.NewInt(1)
+	for i := 0; i < otpDigits; i++ {
+		max.Mul(max, big.NewInt(10))
+	}
+
+	n, err := rand.Int(rand.Reader, max)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%0*d", otpDigits, n), nil
+}
@@ -0,0 +1,271 @@
+package auth_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bogatyr285/auth-go/internal/auth/account"
+	"github.com/bogatyr285/auth-go/internal/auth/entity"
+	"github.com/bogatyr285/auth-go/internal/auth/repository"
+	"github.com/bogatyr285/auth-go/internal/buildinfo"
+	"github.com/bogatyr285/auth-go/internal/gateway/grpc/auth"
+	"github.com/bogatyr285/auth-go/internal/mocks"
+	"github.com/bogatyr285/auth-go/pkg/crypto"
+	authpb "github.com/bogatyr285/auth-go/pkg/server/grpc/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestSendPhoneCode(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	mockCryptoPassword := mocks.NewMockCryptoPassword(ctrl)
+	mockSMSSender := mocks.NewMockSMSSender(ctrl)
+
+	tests := []struct {
+		name             string
+		req              *authpb.SendPhoneCodeRequest
+		setupMocks       func()
+		expectedResponse *authpb.SendPhoneCodeResponse
+		expectedError    error
+	}{
+		{
+			name: "login code sent to verified phone",
+			req: &authpb.SendPhoneCodeRequest{
+				PhoneNumber: "+15551234567",
+				Purpose:     authpb.PhoneCodePurpose_PHONE_CODE_PURPOSE_LOGIN,
+			},
+			setupMocks: func() {
+				mockUserRepo.EXPECT().
+					CountOTPsSince(gomock.Any(), "+15551234567", gomock.Any()).
+					Return(0, nil)
+
+				mockCryptoPassword.EXPECT().
+					HashPassword(gomock.Any()).
+					Return([]byte("hashedcode"), nil)
+
+				mockUserRepo.EXPECT().
+					SaveOTP(gomock.Any(), gomock.Cond(func(x any) bool {
+						otp := x.(entity.OTP)
+						return otp.Phone == "+15551234567" && otp.Purpose == entity.OTPPurposeLogin && otp.CodeHash == "hashedcode"
+					})).
+					Return(nil)
+
+				mockUserRepo.EXPECT().
+					FindUserByPhone(gomock.Any(), "+15551234567").
+					Return(entity.UserAccount{Username: "user1", PhoneVerified: true}, nil)
+
+				mockSMSSender.EXPECT().
+					SendSMS(gomock.Any(), "+15551234567", gomock.Any()).
+					Return(nil)
+			},
+			expectedResponse: &authpb.SendPhoneCodeResponse{ExpiresIn: 300},
+			expectedError:    nil,
+		},
+		{
+			name: "unknown phone gets the same response without sms",
+			req: &authpb.SendPhoneCodeRequest{
+				PhoneNumber: "+15550000000",
+				Purpose:     authpb.PhoneCodePurpose_PHONE_CODE_PURPOSE_LOGIN,
+			},
+			setupMocks: func() {
+				mockUserRepo.EXPECT().
+					CountOTPsSince(gomock.Any(), "+15550000000", gomock.Any()).
+					Return(0, nil)
+
+				mockCryptoPassword.EXPECT().
+					HashPassword(gomock.Any()).
+					Return([]byte("hashedcode"), nil)
+
+				mockUserRepo.EXPECT().
+					SaveOTP(gomock.Any(), gomock.Any()).
+					Return(nil)
+
+				mockUserRepo.EXPECT().
+					FindUserByPhone(gomock.Any(), "+15550000000").
+					Return(entity.UserAccount{}, errors.New("not found"))
+			},
+			expectedResponse: &authpb.SendPhoneCodeResponse{ExpiresIn: 300},
+			expectedError:    nil,
+		},
+		{
+			name: "too many codes",
+			req: &authpb.SendPhoneCodeRequest{
+				PhoneNumber: "+15551234567",
+				Purpose:     authpb.PhoneCodePurpose_PHONE_CODE_PURPOSE_VERIFY,
+			},
+			setupMocks: func() {
+				mockUserRepo.EXPECT().
+					CountOTPsSince(gomock.Any(), "+15551234567", gomock.Any()).
+					Return(3, nil)
+			},
+			expectedResponse: nil,
+			expectedError:    status.Error(codes.ResourceExhausted, "too many codes requested, try again later"),
+		},
+		{
+			name: "invalid phone",
+			req: &authpb.SendPhoneCodeRequest{
+				PhoneNumber: "555-1234",
+				Purpose:     authpb.PhoneCodePurpose_PHONE_CODE_PURPOSE_VERIFY,
+			},
+			setupMocks:       func() {},
+			expectedResponse: nil,
+			expectedError:    status.Error(codes.InvalidArgument, auth.ErrInvalidPhone.Error()),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := auth.NewAuthHandlers(
+				mockUserRepo,
+				mockCryptoPassword,
+				nil,
+				auth.WithSMSSender(mockSMSSender),
+			)
+			tt.setupMocks()
+			resp, err := h.SendPhoneCode(context.Background(), tt.req)
+
+			assert.Equal(t, tt.expectedResponse, resp)
+			assert.Equal(t, tt.expectedError, err)
+		})
+	}
+}
+
+func TestVerifyPhone(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	mockCryptoPassword := mocks.NewMockCryptoPassword(ctrl)
+
+	tests := []struct {
+		name             string
+		req              *authpb.VerifyPhoneRequest
+		setupMocks       func()
+		expectedResponse *authpb.VerifyPhoneResponse
+		expectedError    error
+	}{
+		{
+			name: "successful verification",
+			req:  &authpb.VerifyPhoneRequest{PhoneNumber: "+15551234567", Code: "123456"},
+			setupMocks: func() {
+				mockUserRepo.EXPECT().
+					FindActiveOTP(gomock.Any(), "+15551234567", entity.OTPPurposeVerify).
+					Return(entity.OTP{ID: 1, CodeHash: "hashedcode", ExpiresAt: time.Now().Add(time.Minute)}, nil)
+
+				mockUserRepo.EXPECT().
+					RegisterOTPAttempt(gomock.Any(), 1, 5).
+					Return(true, nil)
+
+				mockCryptoPassword.EXPECT().
+					ComparePasswords("hashedcode", "123456").
+					Return(true)
+
+				mockUserRepo.EXPECT().
+					ConsumeOTP(gomock.Any(), 1).
+					Return(nil)
+
+				mockUserRepo.EXPECT().
+					SetPhoneVerified(gomock.Any(), "+15551234567").
+					Return(nil)
+			},
+			expectedResponse: &authpb.VerifyPhoneResponse{Message: "ok"},
+			expectedError:    nil,
+		},
+		{
+			name: "wrong code",
+			req:  &authpb.VerifyPhoneRequest{PhoneNumber: "+15551234567", Code: "000000"},
+			setupMocks: func() {
+				mockUserRepo.EXPECT().
+					FindActiveOTP(gomock.Any(), "+15551234567", entity.OTPPurposeVerify).
+					Return(entity.OTP{ID: 1, CodeHash: "hashedcode", ExpiresAt: time.Now().Add(time.Minute)}, nil)
+
+				mockUserRepo.EXPECT().
+					RegisterOTPAttempt(gomock.Any(), 1, 5).
+					Return(true, nil)
+
+				mockCryptoPassword.EXPECT().
+					ComparePasswords("hashedcode", "000000").
+					Return(false)
+			},
+			expectedResponse: nil,
+			expectedError:    status.Error(codes.Unauthenticated, auth.ErrAccessDenied.Error()),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := auth.NewAuthHandlers(
+				mockUserRepo,
+				mockCryptoPassword,
+				nil,
+			)
+			tt.setupMocks()
+			resp, err := h.VerifyPhone(context.Background(), tt.req)
+
+			assert.Equal(t, tt.expectedResponse, resp)
+			assert.Equal(t, tt.expectedError, err)
+		})
+	}
+}
+
+// smsInbox - the last code sent to every number
+type smsInbox map[string]string
+
+func (i smsInbox) SendSMS(_ context.Context, phone, text string) error {
+	i[phone] = strings.TrimSuffix(strings.Fields(text)[3], ".")
+	return nil
+}
+
+func TestPhoneOwnership(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	storage, err := repository.New(filepath.Join(t.TempDir(), "db.sqlite"))
+	require.NoError(t, err)
+
+	inbox := smsInbox{}
+	hasher := crypto.NewPasswordHasher()
+	h := auth.NewAuthHandlers(&storage, hasher, account.New(&storage, hasher, mocks.NewMockJWTManager(ctrl), buildinfo.BuildInfo{}),
+		auth.WithSMSSender(inbox))
+
+	const phone = "+15551234567"
+	register := func(name string) error {
+		_, err := h.RegisterUser(context.Background(), &authpb.RegisterUserRequest{
+			User:     &authpb.User{Name: name, ContactMethod: &authpb.User_PhoneNumber{PhoneNumber: phone}},
+			Password: "rLy_5tr0nG!",
+		})
+		return err
+	}
+
+	// unverified numbers don't block the owner
+	require.NoError(t, register("mallory"))
+	require.NoError(t, register("alice"))
+	_, err = storage.FindUserByPhone(context.Background(), phone)
+	require.Error(t, err)
+
+	_, err = h.VerifyPhone(context.Background(), &authpb.VerifyPhoneRequest{PhoneNumber: phone, Code: inbox[phone]})
+	require.NoError(t, err)
+
+	user, err := storage.FindUserByPhone(context.Background(), phone)
+	require.NoError(t, err)
+	require.Equal(t, "alice", user.Username)
+	require.True(t, user.PhoneVerified)
+
+	// throttled registrations don't create the account
+	require.NoError(t, register("bob"))
+	require.Equal(t, status.Error(codes.ResourceExhausted, "too many codes requested, try again later"), register("eve"))
+	exists, err := storage.ExistsUserByUsername(context.Background(), "eve")
+	require.NoError(t, err)
+	require.False(t, exists)
+}
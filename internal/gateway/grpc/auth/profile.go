@@ -0,0 +1,76 @@
+package auth
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/bogatyr285/auth-go/internal/auth/entity"
+	authpb "github.com/bogatyr285/auth-go/pkg/server/grpc/auth"
+)
+
+const genderPrefix = "GENDER_"
+
+// genderName - entity gender for the proto enum value, empty when unspecified
+func genderName(g authpb.Gender) string {
+	if g == authpb.Gender_GENDER_UNSPECIFIED {
+		return ""
+	}
+
+	return strings.ToLower(strings.TrimPrefix(g.String(), genderPrefix))
+}
+
+func gender(name string) authpb.Gender {
+	return authpb.Gender(authpb.Gender_value[genderPrefix+strings.ToUpper(name)])
+}
+
+// profile - profile fields of the proto user, phone is handled by registration
+func profile(u *authpb.User) entity.UserAccount {
+	user := entity.UserAccount{
+		Name:   u.GetName(),
+		Email:  u.GetEmail(),
+		Gender: genderName(u.GetGender()),
+		Address: entity.Address{
+			Street:  u.GetAddress().GetStreet(),
+			City:    u.GetAddress().GetCity(),
+			State:   u.GetAddress().GetState(),
+			Zipcode: u.GetAddress().GetZipcode(),
+		},
+	}
+	if user.Email == "" {
+		user.Email = u.GetEmailContact()
+	}
+
+	return user
+}
+
+// protoUser - the user as User message, role is the most privileged of the roles
+func protoUser(u entity.UserAccount, roles []string) *authpb.User {
+	user := &authpb.User{
+		UserId: u.Username,
+		Name:   u.Name,
+		Gender: gender(u.Gender),
+		Email:  u.Email,
+		Address: &authpb.User_Address{
+			Street:  u.Address.Street,
+			City:    u.Address.City,
+			State:   u.Address.State,
+			Zipcode: u.Address.Zipcode,
+		},
+	}
+
+	for _, role := range []string{entity.RoleAdmin, entity.RoleModerator, entity.RoleUser} {
+		if slices.Contains(roles, role) {
+			user.Role = userRole(role)
+			break
+		}
+	}
+
+	switch {
+	case u.Phone != "":
+		user.ContactMethod = &authpb.User_PhoneNumber{PhoneNumber: u.Phone}
+	case u.Email != "":
+		user.ContactMethod = &authpb.User_EmailContact{EmailContact: u.Email}
+	}
+
+	return user
+}
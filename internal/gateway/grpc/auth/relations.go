@@ -0,0 +1,145 @@
+package auth
+
+import (
+	"context"
+	"errors"
+
+	"github.com/bogatyr285/auth-go/internal/auth/authz"
+	"github.com/bogatyr285/auth-go/internal/auth/entity"
+	authpb "github.com/bogatyr285/auth-go/pkg/server/grpc/auth"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type RelationEngine interface {
+	Check(ctx context.Context, tuple entity.RelationTuple, token string) (bool, string, error)
+	Expand(ctx context.Context, object entity.Object, relation, token string) (entity.UsersetTree, string, error)
+	Write(ctx context.Context, updates []entity.TupleUpdate) (string, error)
+	ListObjects(ctx context.Context, namespace, relation string, subject entity.Subject, token string) ([]string, string, error)
+}
+
+type RelationHandlers struct {
+	re RelationEngine
+
+	authpb.UnimplementedRelationServiceServer
+}
+
+func NewRelationHandlers(re RelationEngine) *RelationHandlers {
+	return &RelationHandlers{
+		re: re,
+	}
+}
+
+func (h *RelationHandlers) Check(ctx context.Context, req *authpb.CheckRequest) (*authpb.CheckResponse, error) {
+	tuple, err := parseTuple(req.GetTuple())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	allowed, token, err := h.re.Check(ctx, tuple, req.GetConsistencyToken())
+	if err != nil {
+		return nil, relationError(err)
+	}
+
+	return &authpb.CheckResponse{
+		Allowed:          allowed,
+		ConsistencyToken: token,
+	}, nil
+}
+
+func (h *RelationHandlers) Expand(ctx context.Context, req *authpb.ExpandRequest) (*authpb.ExpandResponse, error) {
+	object, err := entity.ParseObject(req.GetObject())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	tree, token, err := h.re.Expand(ctx, object, req.GetRelation(), req.GetConsistencyToken())
+	if err != nil {
+		return nil, relationError(err)
+	}
+
+	return &authpb.ExpandResponse{
+		Tree:             usersetTreeToProto(tree),
+		ConsistencyToken: token,
+	}, nil
+}
+
+func (h *RelationHandlers) Write(ctx context.Context, req *authpb.WriteRequest) (*authpb.WriteResponse, error) {
+	updates := make([]entity.TupleUpdate, 0, len(req.GetUpdates()))
+	for _, u := range req.GetUpdates() {
+		tuple, err := parseTuple(u.GetTuple())
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+
+		update := entity.TupleUpdate{Tuple: tuple}
+		switch u.GetOperation() {
+		case authpb.RelationTupleOperation_RELATION_TUPLE_OPERATION_TOUCH:
+			update.Operation = entity.TupleTouch
+		case authpb.RelationTupleOperation_RELATION_TUPLE_OPERATION_DELETE:
+			update.Operation = entity.TupleDelete
+		default:
+			return nil, status.Error(codes.InvalidArgument, "operation is required")
+		}
+
+		updates = append(updates, update)
+	}
+
+	token, err := h.re.Write(ctx, updates)
+	if err != nil {
+		return nil, relationError(err)
+	}
+
+	return &authpb.WriteResponse{
+		ConsistencyToken: token,
+	}, nil
+}
+
+func (h *RelationHandlers) ListObjects(ctx context.Context, req *authpb.ListObjectsRequest) (*authpb.ListObjectsResponse, error) {
+	subject, err := entity.ParseSubject(req.GetSubject())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	objects, token, err := h.re.ListObjects(ctx, req.GetNamespace(), req.GetRelation(), subject, req.GetConsistencyToken())
+	if err != nil {
+		return nil, relationError(err)
+	}
+
+	return &authpb.ListObjectsResponse{
+		Objects:          objects,
+		ConsistencyToken: token,
+	}, nil
+}
+
+func parseTuple(t *authpb.RelationTuple) (entity.RelationTuple, error) {
+	return entity.ParseTuple(t.GetObject(), t.GetRelation(), t.GetSubject())
+}
+
+func usersetTreeToProto(tree entity.UsersetTree) *authpb.UsersetTree {
+	res := &authpb.UsersetTree{
+		Object:   tree.Object.String(),
+		Relation: tree.Relation,
+	}
+
+	for _, s := range tree.Subjects {
+		res.Subjects = append(res.Subjects, s.String())
+	}
+
+	for _, child := range tree.Children {
+		res.Children = append(res.Children, usersetTreeToProto(child))
+	}
+
+	return res
+}
+
+func relationError(err error) error {
+	switch {
+	case errors.Is(err, authz.ErrInvalidRequest):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, authz.ErrInvalidConsistencyToken):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	default:
+		return err
+	}
+}
@@ -0,0 +1,73 @@
+package auth
+
+import (
+	"context"
+	"strings"
+
+	"github.com/bogatyr285/auth-go/internal/auth/entity"
+	authpb "github.com/bogatyr285/auth-go/pkg/server/grpc/auth"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const userRolePrefix = "USER_ROLE_"
+
+// roleName - entity role for the proto enum value, empty for unknown values
+func roleName(role authpb.UserRole) string {
+	name := strings.ToLower(strings.TrimPrefix(role.String(), userRolePrefix))
+	if !entity.IsRole(name) {
+		return ""
+	}
+
+	return name
+}
+
+func userRole(name string) authpb.UserRole {
+	return authpb.UserRole(authpb.UserRole_value[userRolePrefix+strings.ToUpper(name)])
+}
+
+func (h *AuthHandlers) GrantRole(ctx context.Context, req *authpb.GrantRoleRequest) (*authpb.UserRolesResponse, error) {
+	return h.changeRole(ctx, req.GetUsername(), req.GetRole(), h.ur.GrantUserRole)
+}
+
+func (h *AuthHandlers) RevokeRole(ctx context.Context, req *authpb.RevokeRoleRequest) (*authpb.UserRolesResponse, error) {
+	return h.changeRole(ctx, req.GetUsername(), req.GetRole(), h.ur.RevokeUserRole)
+}
+
+func (h *AuthHandlers) changeRole(
+	ctx context.Context,
+	username string,
+	role authpb.UserRole,
+	change func(ctx context.Context, userID int, role string) error,
+) (*authpb.UserRolesResponse, error) {
+	name := roleName(role)
+	if name == "" {
+		return nil, status.Error(codes.InvalidArgument, "unknown role")
+	}
+
+	// every user has USER_ROLE_USER, it can't be granted or revoked
+	if name == entity.RoleUser {
+		return nil, status.Error(codes.InvalidArgument, "role is implied for every user")
+	}
+
+	user, err := h.ur.FindUserByEmail(ctx, username)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, "user not found")
+	}
+
+	if err = change(ctx, user.ID, name); err != nil {
+		return nil, err
+	}
+
+	roles, err := h.ur.ListUserRoles(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &authpb.UserRolesResponse{Username: user.Username}
+	for _, r := range roles {
+		res.Roles = append(res.Roles, userRole(r))
+	}
+
+	return res, nil
+}
@@ -0,0 +1,96 @@
+package auth_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/bogatyr285/auth-go/internal/auth/entity"
+	"github.com/bogatyr285/auth-go/internal/gateway/grpc/auth"
+	"github.com/bogatyr285/auth-go/internal/mocks"
+	authpb "github.com/bogatyr285/auth-go/pkg/server/grpc/auth"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGrantRole(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	mockCryptoPassword := mocks.NewMockCryptoPassword(ctrl)
+
+	tests := []struct {
+		name             string
+		req              *authpb.GrantRoleRequest
+		setupMocks       func()
+		expectedResponse *authpb.UserRolesResponse
+		expectedError    error
+	}{
+		{
+			name: "successful grant",
+			req:  &authpb.GrantRoleRequest{Username: "user1", Role: authpb.UserRole_USER_ROLE_MODERATOR},
+			setupMocks: func() {
+				mockUserRepo.EXPECT().
+					FindUserByEmail(gomock.Any(), "user1").
+					Return(entity.UserAccount{ID: 1, Username: "user1"}, nil)
+
+				mockUserRepo.EXPECT().
+					GrantUserRole(gomock.Any(), 1, entity.RoleModerator).
+					Return(nil)
+
+				mockUserRepo.EXPECT().
+					ListUserRoles(gomock.Any(), 1).
+					Return([]string{entity.RoleUser, entity.RoleModerator}, nil)
+			},
+			expectedResponse: &authpb.UserRolesResponse{
+				Username: "user1",
+				Roles:    []authpb.UserRole{authpb.UserRole_USER_ROLE_USER, authpb.UserRole_USER_ROLE_MODERATOR},
+			},
+			expectedError: nil,
+		},
+		{
+			name:             "unspecified role",
+			req:              &authpb.GrantRoleRequest{Username: "user1"},
+			setupMocks:       func() {},
+			expectedResponse: nil,
+			expectedError:    status.Error(codes.InvalidArgument, "unknown role"),
+		},
+		{
+			name:             "implied role",
+			req:              &authpb.GrantRoleRequest{Username: "user1", Role: authpb.UserRole_USER_ROLE_USER},
+			setupMocks:       func() {},
+			expectedResponse: nil,
+			expectedError:    status.Error(codes.InvalidArgument, "role is implied for every user"),
+		},
+		{
+			name: "user not found",
+			req:  &authpb.GrantRoleRequest{Username: "nonexistent", Role: authpb.UserRole_USER_ROLE_ADMIN},
+			setupMocks: func() {
+				mockUserRepo.EXPECT().
+					FindUserByEmail(gomock.Any(), "nonexistent").
+					Return(entity.UserAccount{}, errors.New("not found"))
+			},
+			expectedResponse: nil,
+			expectedError:    status.Error(codes.NotFound, "user not found"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := auth.NewAuthHandlers(
+				mockUserRepo,
+				mockCryptoPassword,
+				nil,
+			)
+			tt.setupMocks()
+			resp, err := h.GrantRole(context.Background(), tt.req)
+
+			assert.Equal(t, tt.expectedResponse, resp)
+			assert.Equal(t, tt.expectedError, err)
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package auth
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/bogatyr285/auth-go/internal/auth/entity"
+	"github.com/bogatyr285/auth-go/internal/auth/events"
+	authpb "github.com/bogatyr285/auth-go/pkg/server/grpc/auth"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var ErrEventsDisabled = errors.New("security events are not configured")
+
+const securityEventTypePrefix = "SECURITY_EVENT_TYPE_"
+
+func (h *AuthHandlers) WatchSecurityEvents(req *authpb.WatchSecurityEventsRequest, stream grpc.ServerStreamingServer[authpb.SecurityEvent]) error {
+	claims, ok := ClaimsFromContext(stream.Context())
+	if !ok {
+		return status.Error(codes.Unauthenticated, ErrAccessDenied.Error())
+	}
+
+	return h.watchSecurityEvents(stream, func(e entity.SecurityEvent) bool {
+		return e.Username == claims.Subject
+	})
+}
+
+func (h *AuthHandlers) WatchAllSecurityEvents(req *authpb.WatchAllSecurityEventsRequest, stream grpc.ServerStreamingServer[authpb.SecurityEvent]) error {
+	var filter func(entity.SecurityEvent) bool
+	if username := req.GetUsername(); username != "" {
+		filter = func(e entity.SecurityEvent) bool {
+			return e.Username == username
+		}
+	}
+
+	return h.watchSecurityEvents(stream, filter)
+}
+
+// watchSecurityEvents - sends events until the client goes away or the subscription ends
+func (h *AuthHandlers) watchSecurityEvents(stream grpc.ServerStreamingServer[authpb.SecurityEvent], filter func(entity.SecurityEvent) bool) error {
+	if h.events == nil {
+		return status.Error(codes.Unimplemented, ErrEventsDisabled.Error())
+	}
+
+	sub := h.events.Subscribe(filter)
+	defer sub.Close()
+
+	// headers tell the client it's subscribed, events published from now on are sent
+	if err := stream.SendHeader(metadata.MD{}); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case e, ok := <-sub.Events():
+			if !ok {
+				return subscriptionError(sub.Err())
+			}
+
+			if err := stream.Send(securityEvent(e)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func subscriptionError(err error) error {
+	switch {
+	case errors.Is(err, events.ErrSlowSubscriber):
+		return status.Error(codes.ResourceExhausted, err.Error())
+	case errors.Is(err, events.ErrBusClosed):
+		return status.Error(codes.Unavailable, err.Error())
+	default:
+		return nil
+	}
+}
+
+func securityEvent(e entity.SecurityEvent) *authpb.SecurityEvent {
+	return &authpb.SecurityEvent{
+		Id:       e.ID,
+		Type:     authpb.SecurityEventType(authpb.SecurityEventType_value[securityEventTypePrefix+strings.ToUpper(string(e.Type))]),
+		Username: e.Username,
+		Time:     timestamppb.New(e.Time),
+		TokenId:  e.TokenID,
+		Provider: e.Provider,
+		ClientId: e.ClientID,
+	}
+}
@@ -0,0 +1,190 @@
+package auth_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/bogatyr285/auth-go/internal/auth/entity"
+	authpb "github.com/bogatyr285/auth-go/pkg/server/grpc/auth"
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func (s *grpcGatewaySuite) authClient() authpb.AuthServiceClient {
+	conn, err := grpc.NewClient(s.grpcServer.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	s.Require().NoError(err)
+	s.T().Cleanup(func() { conn.Close() })
+
+	return authpb.NewAuthServiceClient(conn)
+}
+
+// registerAndLogin - access token of a new user
+func (s *grpcGatewaySuite) registerAndLogin(client authpb.AuthServiceClient, username string) string {
+	ctx := context.Background()
+
+	_, err := client.RegisterUser(ctx, &authpb.RegisterUserRequest{
+		User:     &authpb.User{Name: username},
+		Password: "rLy_5tr0nG!",
+	})
+	s.Require().NoError(err)
+
+	return s.login(client, username)
+}
+
+func (s *grpcGatewaySuite) login(client authpb.AuthServiceClient, username string) string {
+	res, err := client.LoginUser(context.Background(), &authpb.LoginUserRequest{
+		LoginMethod: &authpb.LoginUserRequest_Email{Email: username},
+		Password:    "rLy_5tr0nG!",
+	})
+	s.Require().NoError(err)
+
+	return res.GetToken()
+}
+
+// withToken - context of calls made with the access token, cancelled with the test
+func (s *grpcGatewaySuite) withToken(token string) context.Context {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	s.T().Cleanup(cancel)
+
+	return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token)
+}
+
+func (s *grpcGatewaySuite) TestWatchSecurityEvents() {
+	client := s.authClient()
+	alice, bob := "alice-"+uuid.NewString(), "bob-"+uuid.NewString()
+	aliceToken := s.registerAndLogin(client, alice)
+	s.registerAndLogin(client, bob)
+
+	stream, err := client.WatchSecurityEvents(s.withToken(aliceToken), &authpb.WatchSecurityEventsRequest{})
+	s.Require().NoError(err)
+	// headers are sent once the stream is subscribed
+	_, err = stream.Header()
+	s.Require().NoError(err)
+
+	s.login(client, bob)
+	s.login(client, alice)
+
+	// logins of other users aren't watched
+	e, err := stream.Recv()
+	s.Require().NoError(err)
+	s.Equal(authpb.SecurityEventType_SECURITY_EVENT_TYPE_LOGIN, e.GetType())
+	s.Equal(alice, e.GetUsername())
+	s.NotEmpty(e.GetId())
+	s.WithinDuration(time.Now(), e.GetTime().AsTime(), time.Minute)
+}
+
+// TestWatchSecurityEventTypes - events of the use cases are sent with their details
+func (s *grpcGatewaySuite) TestWatchSecurityEventTypes() {
+	client := s.authClient()
+	username := "types-watcher-" + uuid.NewString()
+	token := s.registerAndLogin(client, username)
+
+	stream, err := client.WatchSecurityEvents(s.withToken(token), &authpb.WatchSecurityEventsRequest{})
+	s.Require().NoError(err)
+	_, err = stream.Header()
+	s.Require().NoError(err)
+
+	s.events.Publish(entity.SecurityEvent{Type: entity.SecurityEventIdentityLinked, Username: username, Provider: "google"})
+	s.events.Publish(entity.SecurityEvent{Type: entity.SecurityEventOAuthSignIn, Username: username, ClientID: "app"})
+
+	e, err := stream.Recv()
+	s.Require().NoError(err)
+	s.Equal(authpb.SecurityEventType_SECURITY_EVENT_TYPE_IDENTITY_LINKED, e.GetType())
+	s.Equal("google", e.GetProvider())
+
+	e, err = stream.Recv()
+	s.Require().NoError(err)
+	s.Equal(authpb.SecurityEventType_SECURITY_EVENT_TYPE_OAUTH_SIGN_IN, e.GetType())
+	s.Equal("app", e.GetClientId())
+}
+
+func (s *grpcGatewaySuite) TestWatchSecurityEventsAuth() {
+	client := s.authClient()
+	username := "watcher-" + uuid.NewString()
+	token := s.registerAndLogin(client, username)
+
+	// streams pass the auth interceptor chain
+	stream, err := client.WatchSecurityEvents(context.Background(), &authpb.WatchSecurityEventsRequest{})
+	s.Require().NoError(err)
+	_, err = stream.Recv()
+	s.Equal(codes.Unauthenticated, status.Code(err))
+
+	all, err := client.WatchAllSecurityEvents(s.withToken(token), &authpb.WatchAllSecurityEventsRequest{})
+	s.Require().NoError(err)
+	_, err = all.Recv()
+	s.Equal(codes.PermissionDenied, status.Code(err))
+
+	// admins watch events of everyone
+	user, err := s.storage.FindUserByEmail(context.Background(), username)
+	s.Require().NoError(err)
+	s.Require().NoError(s.storage.GrantUserRole(context.Background(), user.ID, entity.RoleAdmin))
+	adminToken := s.login(client, username)
+
+	other := "other-" + uuid.NewString()
+	all, err = client.WatchAllSecurityEvents(s.withToken(adminToken), &authpb.WatchAllSecurityEventsRequest{Username: other})
+	s.Require().NoError(err)
+	_, err = all.Header()
+	s.Require().NoError(err)
+
+	s.registerAndLogin(client, other)
+
+	e, err := all.Recv()
+	s.Require().NoError(err)
+	s.Equal(other, e.GetUsername())
+}
+
+// TestWatchSecurityEventsConnect - the stream over the Connect protocol, enveloped JSON
+// messages followed by the end of stream message
+func (s *grpcGatewaySuite) TestWatchSecurityEventsConnect() {
+	client := s.authClient()
+	username := "connect-watcher-" + uuid.NewString()
+	token := s.registerAndLogin(client, username)
+
+	body := []byte("{}")
+	envelope := make([]byte, 5, 5+len(body))
+	binary.BigEndian.PutUint32(envelope[1:], uint32(len(body)))
+	envelope = append(envelope, body...)
+
+	req, err := http.NewRequestWithContext(s.withToken(token), http.MethodPost,
+		s.connectServer.URL+"/auth.v1.AuthService/WatchSecurityEvents", bytes.NewReader(envelope))
+	s.Require().NoError(err)
+	req.Header.Set("Content-Type", "application/connect+json")
+	req.Header.Set("Connect-Protocol-Version", "1")
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	// the response starts once the stream is subscribed
+	res, err := s.connectServer.Client().Do(req)
+	s.Require().NoError(err)
+	defer res.Body.Close()
+	s.Require().Equal(http.StatusOK, res.StatusCode)
+
+	s.login(client, username)
+
+	r := bufio.NewReader(res.Body)
+	prefix := make([]byte, 5)
+	_, err = io.ReadFull(r, prefix)
+	s.Require().NoError(err)
+	s.Zero(prefix[0], "end of stream instead of an event")
+
+	msg := make([]byte, binary.BigEndian.Uint32(prefix[1:]))
+	_, err = io.ReadFull(r, msg)
+	s.Require().NoError(err)
+
+	var event struct {
+		Type     string `json:"type"`
+		Username string `json:"username"`
+	}
+	s.Require().NoError(json.Unmarshal(msg, &event))
+	s.Equal("SECURITY_EVENT_TYPE_LOGIN", event.Type)
+	s.Equal(username, event.Username)
+}
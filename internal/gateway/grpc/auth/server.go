@@ -1,8 +1,10 @@
 package auth
 
 import (
+	"context"
 	"log/slog"
 	"net"
+	"net/http"
 	"runtime/debug"
 	"time"
 
@@ -34,38 +36,40 @@ type Server struct {
 func NewGRPCServer(
 	grpcAddr string,
 	authHadndlers authpb.AuthServiceServer,
+	relationHandlers authpb.RelationServiceServer,
+	healthStatus HealthStatus,
+	jm JWTManager,
+	tokens AccessTokenRepository,
 	logger *slog.Logger,
+	opts ...grpc.ServerOption,
 ) (*Server, error) {
 	logger = logger.With("module", "grpc-server")
-	netListener, err := net.Listen("tcp", grpcAddr)
-	if err != nil {
-		return nil, err
-	}
 	grpcPanicRecoveryHandler := func(p any) (err error) {
 		logger.Error("recovered from panic", slog.Any("stack", string(debug.Stack())))
 		return status.Errorf(codes.Internal, "%s", p)
 	}
 
-	grpcSrv := grpc.NewServer(
+	grpcSrv := grpc.NewServer(append([]grpc.ServerOption{
 		grpc.ChainUnaryInterceptor(
 			recovery.UnaryServerInterceptor(recovery.WithRecoveryHandler(grpcPanicRecoveryHandler)),
+			AuthUnaryInterceptor(jm, tokens),
 		),
 		grpc.ChainStreamInterceptor(
 			recovery.StreamServerInterceptor(recovery.WithRecoveryHandler(grpcPanicRecoveryHandler)),
+			AuthStreamInterceptor(jm, tokens),
 		),
-	)
+	}, opts...)...)
 	authpb.RegisterAuthServiceServer(grpcSrv, authHadndlers)
+	authpb.RegisterRelationServiceServer(grpcSrv, relationHandlers)
 
 	// register health check service
-	healthService := NewHealthChecker(logger)
-	grpc_health_v1.RegisterHealthServer(grpcSrv, healthService)
+	grpc_health_v1.RegisterHealthServer(grpcSrv, NewHealthChecker(healthStatus, logger))
 
 	// Register reflection service on gRPC server. can be a flag
 	reflection.Register(grpcSrv)
 
 	server := &Server{
 		grpcAddr:            grpcAddr,
-		listener:            netListener,
 		grpcSrv:             grpcSrv,
 		gracefulStopTimeout: GRPCDefaultGracefulStopTimeout,
 		logger:              logger,
@@ -74,21 +78,45 @@ func NewGRPCServer(
 	return server, nil
 }
 
-func (s *Server) Run() (func() error, error) {
+// Init - binds the address, so busy ports fail before anything is served
+func (s *Server) Init(ctx context.Context) error {
+	l, err := net.Listen("tcp", s.grpcAddr)
+	if err != nil {
+		return err
+	}
+	s.listener = l
+
+	return nil
+}
+
+// Addr - address the server listens on, known once it is initialized
+func (s *Server) Addr() net.Addr {
+	return s.listener.Addr()
+}
+
+func (s *Server) Run(ctx context.Context) error {
 	s.logger.Info("starting", slog.String("grpcAddr", s.grpcAddr))
 
-	go func() {
-		err := s.grpcSrv.Serve(s.listener)
-		if err == grpc.ErrServerStopped {
-			s.logger.Error("grpc server", slog.Any("err", err))
-		}
-	}()
+	// Serve returns nil once the server is stopped
+	return s.grpcSrv.Serve(s.listener)
+}
 
-	return s.close, nil
+// ServeHTTP - serves gRPC requests of an HTTP/2 server the port is shared with,
+// instead of the server's own listener
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.grpcSrv.ServeHTTP(w, r)
 }
 
-// stop - gracefully stop server & listeners
-func (s *Server) close() error {
+// Close - cancels calls served by ServeHTTP, which can't be stopped gracefully.
+// The HTTP server is expected to be drained first
+func (s *Server) Close() error {
+	s.grpcSrv.Stop()
+	return nil
+}
+
+// Stop - gracefully stop server & listeners, calls in flight are cancelled after
+// the graceful stop timeout
+func (s *Server) Stop() {
 	s.logger.Info("gracefully stopping....", slog.String("grpcAddr", s.grpcAddr))
 
 	stopped := make(chan struct{})
@@ -105,8 +133,11 @@ func (s *Server) close() error {
 		s.logger.Info("ungracefully stopping....", slog.String("grpcAddr", s.grpcAddr))
 		s.grpcSrv.Stop()
 	case <-stopped:
-		t.Stop()
+	}
+
+	// the listener is not tracked by the server when Run wasn't called
+	if s.listener != nil {
+		s.listener.Close()
 	}
 	s.logger.Info("stopped", slog.String("grpcAddr", s.grpcAddr))
-	return nil
 }
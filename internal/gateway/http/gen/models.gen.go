@@ -3,6 +3,23 @@
 // Code generated by github.com/oapi-codegen/oapi-codegen/v2 version v2.3.0 DO NOT EDIT.
 package gen
 
+import (
+	"time"
+)
+
+// Defines values for RelationTupleUpdateOperation.
+const (
+	Delete RelationTupleUpdateOperation = "delete"
+	Touch  RelationTupleUpdateOperation = "touch"
+)
+
+// Defines values for RoleRequestRole.
+const (
+	Admin     RoleRequestRole = "admin"
+	Moderator RoleRequestRole = "moderator"
+	User      RoleRequestRole = "user"
+)
+
 // BuildInfo defines model for BuildInfo.
 type BuildInfo struct {
 	// Arch Architecture of the machine used for the build
@@ -27,10 +44,94 @@ type BuildInfo struct {
 	Version string `json:"version"`
 }
 
+// CheckRequest defines model for CheckRequest.
+type CheckRequest struct {
+	// ConsistencyToken Token of a previous call, the check sees everything written before it
+	ConsistencyToken *string       `json:"consistency_token,omitempty"`
+	Tuple            RelationTuple `json:"tuple"`
+}
+
+// CheckResponse defines model for CheckResponse.
+type CheckResponse struct {
+	Allowed          bool   `json:"allowed"`
+	ConsistencyToken string `json:"consistency_token"`
+}
+
+// ClientSecret defines model for ClientSecret.
+type ClientSecret struct {
+	ClientId     string `json:"client_id"`
+	ClientSecret string `json:"client_secret"`
+
+	// PreviousExpireAt When previous secrets stop being valid
+	PreviousExpireAt time.Time `json:"previous_expire_at"`
+}
+
 // ErrorResponse defines model for ErrorResponse.
 type ErrorResponse struct {
 	// Error Description of the error
 	Error string `json:"error"`
+
+	// Fields Offending fields of requests not matching this spec
+	Fields *[]FieldError `json:"fields,omitempty"`
+}
+
+// ExpandRequest defines model for ExpandRequest.
+type ExpandRequest struct {
+	ConsistencyToken *string `json:"consistency_token,omitempty"`
+	Object           string  `json:"object"`
+	Relation         string  `json:"relation"`
+}
+
+// ExpandResponse defines model for ExpandResponse.
+type ExpandResponse struct {
+	ConsistencyToken string      `json:"consistency_token"`
+	Tree             UsersetTree `json:"tree"`
+}
+
+// FederationLinkResponse defines model for FederationLinkResponse.
+type FederationLinkResponse struct {
+	// Location Authorization URL of the provider
+	Location string `json:"location"`
+}
+
+// FieldError defines model for FieldError.
+type FieldError struct {
+	// Field Parameter name or dot separated path of the body field, "body" for the body itself
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// IdentitiesResponse defines model for IdentitiesResponse.
+type IdentitiesResponse struct {
+	Identities []Identity `json:"identities"`
+}
+
+// Identity defines model for Identity.
+type Identity struct {
+	CreatedAt time.Time `json:"created_at"`
+	Email     *string   `json:"email,omitempty"`
+
+	// Provider password, phone, webauthn or name of an external provider
+	Provider string `json:"provider"`
+
+	// Subject Username, phone number, passkey ID or the provider's ID of the user
+	Subject string `json:"subject"`
+}
+
+// ListObjectsRequest defines model for ListObjectsRequest.
+type ListObjectsRequest struct {
+	ConsistencyToken *string `json:"consistency_token,omitempty"`
+	Namespace        string  `json:"namespace"`
+	Relation         string  `json:"relation"`
+	Subject          string  `json:"subject"`
+}
+
+// ListObjectsResponse defines model for ListObjectsResponse.
+type ListObjectsResponse struct {
+	ConsistencyToken string `json:"consistency_token"`
+
+	// Objects IDs of the objects
+	Objects []string `json:"objects"`
 }
 
 // LoginUserRequest defines model for LoginUserRequest.
@@ -48,6 +149,84 @@ type LoginUserResponse struct {
 	RefreshToken string `json:"refresh_token"`
 }
 
+// MFARequiredResponse defines model for MFARequiredResponse.
+type MFARequiredResponse struct {
+	// Methods Second factor methods available for the user
+	Methods []string `json:"methods"`
+
+	// MfaToken Short-lived token proving the first factor, to be passed to the second factor ceremony
+	MfaToken string `json:"mfa_token"`
+}
+
+// MagicLinkConsumeRequest defines model for MagicLinkConsumeRequest.
+type MagicLinkConsumeRequest struct {
+	// Token Token from the emailed link
+	Token string `json:"token"`
+}
+
+// MagicLinkRequest defines model for MagicLinkRequest.
+type MagicLinkRequest struct {
+	// Email Verified email of the user
+	Email string `json:"email"`
+}
+
+// OAuthClient defines model for OAuthClient.
+type OAuthClient struct {
+	ClientId string `json:"client_id"`
+
+	// ClientSecret Returned only when a confidential client is registered
+	ClientSecret *string  `json:"client_secret,omitempty"`
+	Confidential bool     `json:"confidential"`
+	Name         string   `json:"name"`
+	RedirectUris []string `json:"redirect_uris"`
+	Scopes       []string `json:"scopes"`
+}
+
+// OAuthClientRequest defines model for OAuthClientRequest.
+type OAuthClientRequest struct {
+	// Confidential Confidential clients get a secret and may use client_credentials grant
+	Confidential *bool `json:"confidential,omitempty"`
+
+	// Name Name shown to users on the consent page
+	Name string `json:"name"`
+
+	// RedirectUris Exact redirect URIs, https or loopback http, or custom schemes of native apps
+	RedirectUris []string `json:"redirect_uris"`
+
+	// Scopes Scopes the client may request, on behalf of users and with client_credentials grant
+	Scopes *[]string `json:"scopes,omitempty"`
+}
+
+// OAuthClientUpdate defines model for OAuthClientUpdate.
+type OAuthClientUpdate struct {
+	Name         string   `json:"name"`
+	RedirectUris []string `json:"redirect_uris"`
+	Scopes       []string `json:"scopes"`
+}
+
+// PersonalAccessToken defines model for PersonalAccessToken.
+type PersonalAccessToken struct {
+	CreatedAt  time.Time  `json:"created_at"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	Id         string     `json:"id"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	Name       string     `json:"name"`
+	Scopes     []string   `json:"scopes"`
+
+	// Token Returned only when the token is created
+	Token *string `json:"token,omitempty"`
+}
+
+// PersonalAccessTokenRequest defines model for PersonalAccessTokenRequest.
+type PersonalAccessTokenRequest struct {
+	// ExpiresAt Defaults to 30 days from now, at most a year from now
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	Name      string     `json:"name"`
+
+	// Scopes Permissions of the token, e.g. relations:read. They must be granted by roles of the account
+	Scopes []string `json:"scopes"`
+}
+
 // RegisterUserRequest defines model for RegisterUserRequest.
 type RegisterUserRequest struct {
 	Age *int `json:"age,omitempty"`
@@ -68,6 +247,50 @@ type RegisterUserResponse struct {
 	Username string `json:"username"`
 }
 
+// RelationTuple defines model for RelationTuple.
+type RelationTuple struct {
+	// Object namespace:id, e.g. document:readme
+	Object   string `json:"object"`
+	Relation string `json:"relation"`
+
+	// Subject namespace:id or namespace:id#relation, e.g. user:alice or group:eng#member
+	Subject string `json:"subject"`
+}
+
+// RelationTupleUpdate defines model for RelationTupleUpdate.
+type RelationTupleUpdate struct {
+	// Operation touch writes the tuple if it doesn't exist yet
+	Operation RelationTupleUpdateOperation `json:"operation"`
+	Tuple     RelationTuple                `json:"tuple"`
+}
+
+// RelationTupleUpdateOperation touch writes the tuple if it doesn't exist yet
+type RelationTupleUpdateOperation string
+
+// RoleRequest defines model for RoleRequest.
+type RoleRequest struct {
+	// Role Role to grant
+	Role RoleRequestRole `json:"role"`
+}
+
+// RoleRequestRole Role to grant
+type RoleRequestRole string
+
+// RotateClientSecretRequest defines model for RotateClientSecretRequest.
+type RotateClientSecretRequest struct {
+	// OverlapSeconds How long previous secrets stay valid, 24 hours by default
+	OverlapSeconds *int `json:"overlap_seconds,omitempty"`
+}
+
+// SAMLResponseForm defines model for SAMLResponseForm.
+type SAMLResponseForm struct {
+	// RelayState State of the login started by /saml/login
+	RelayState string `json:"RelayState"`
+
+	// SAMLResponse Base64 encoded response of the identity provider
+	SAMLResponse string `json:"SAMLResponse"`
+}
+
 // TokenRequest defines model for TokenRequest.
 type TokenRequest struct {
 	RefreshToken string `json:"refresh_token"`
@@ -88,11 +311,143 @@ type UserInfo struct {
 	Username string `json:"username"`
 }
 
+// UserRoles defines model for UserRoles.
+type UserRoles struct {
+	// Id Unique identifier for the user
+	Id int `json:"id"`
+
+	// Roles Roles of the user, "user" is implied for everyone
+	Roles []string `json:"roles"`
+}
+
+// UsersetTree defines model for UsersetTree.
+type UsersetTree struct {
+	// Children Usersets the relation is derived from
+	Children []UsersetTree `json:"children"`
+	Object   string        `json:"object"`
+	Relation string        `json:"relation"`
+
+	// Subjects Subjects of the tuples of the userset
+	Subjects []string `json:"subjects"`
+}
+
+// WebAuthnCeremony defines model for WebAuthnCeremony.
+type WebAuthnCeremony struct {
+	// Options PublicKeyCredentialCreationOptions or PublicKeyCredentialRequestOptions
+	Options map[string]interface{} `json:"options"`
+
+	// SessionId Identifier of the ceremony, to be passed to the finish step
+	SessionId string `json:"session_id"`
+}
+
+// WebAuthnCredential defines model for WebAuthnCredential.
+type WebAuthnCredential struct {
+	// CredentialId Base64url encoded credential ID
+	CredentialId string   `json:"credential_id"`
+	Transports   []string `json:"transports"`
+}
+
+// WebAuthnFinishRequest defines model for WebAuthnFinishRequest.
+type WebAuthnFinishRequest struct {
+	// Credential PublicKeyCredential serialized as JSON by the client
+	Credential map[string]interface{} `json:"credential"`
+	SessionId  string                 `json:"session_id"`
+}
+
+// WebAuthnLoginBeginRequest defines model for WebAuthnLoginBeginRequest.
+type WebAuthnLoginBeginRequest struct {
+	// MfaToken Token returned by /login when the second factor is required
+	MfaToken *string `json:"mfa_token,omitempty"`
+
+	// Username Ignored, passkeys of primary logins name their user
+	Username *string `json:"username,omitempty"`
+}
+
+// WriteRequest defines model for WriteRequest.
+type WriteRequest struct {
+	Updates []RelationTupleUpdate `json:"updates"`
+}
+
+// WriteResponse defines model for WriteResponse.
+type WriteResponse struct {
+	ConsistencyToken string `json:"consistency_token"`
+}
+
+// GetFederationCallbackParams defines parameters for GetFederationCallback.
+type GetFederationCallbackParams struct {
+	State string  `form:"state" json:"state"`
+	Code  *string `form:"code,omitempty" json:"code,omitempty"`
+	Error *string `form:"error,omitempty" json:"error,omitempty"`
+
+	// FederationLink Set by /federation/link, identities are linked only in the browser which started linking
+	FederationLink *string `form:"federation_link,omitempty" json:"federation_link,omitempty"`
+}
+
+// PostFederationLinkParams defines parameters for PostFederationLink.
+type PostFederationLinkParams struct {
+	Provider string `form:"provider" json:"provider"`
+}
+
+// GetFederationLoginParams defines parameters for GetFederationLogin.
+type GetFederationLoginParams struct {
+	Provider string `form:"provider" json:"provider"`
+}
+
+// GetSamlLoginParams defines parameters for GetSamlLogin.
+type GetSamlLoginParams struct {
+	Provider string `form:"provider" json:"provider"`
+}
+
+// PostAdminOauthClientsJSONRequestBody defines body for PostAdminOauthClients for application/json ContentType.
+type PostAdminOauthClientsJSONRequestBody = OAuthClientRequest
+
+// PutAdminOauthClientsClientIdJSONRequestBody defines body for PutAdminOauthClientsClientId for application/json ContentType.
+type PutAdminOauthClientsClientIdJSONRequestBody = OAuthClientUpdate
+
+// PostAdminOauthClientsClientIdSecretsJSONRequestBody defines body for PostAdminOauthClientsClientIdSecrets for application/json ContentType.
+type PostAdminOauthClientsClientIdSecretsJSONRequestBody = RotateClientSecretRequest
+
+// PostAdminUsersIdRolesJSONRequestBody defines body for PostAdminUsersIdRoles for application/json ContentType.
+type PostAdminUsersIdRolesJSONRequestBody = RoleRequest
+
 // PostLoginJSONRequestBody defines body for PostLogin for application/json ContentType.
 type PostLoginJSONRequestBody = LoginUserRequest
 
+// PostMagicLinkJSONRequestBody defines body for PostMagicLink for application/json ContentType.
+type PostMagicLinkJSONRequestBody = MagicLinkRequest
+
+// PostMagicLinkConsumeJSONRequestBody defines body for PostMagicLinkConsume for application/json ContentType.
+type PostMagicLinkConsumeJSONRequestBody = MagicLinkConsumeRequest
+
+// PostPersonalAccessTokensJSONRequestBody defines body for PostPersonalAccessTokens for application/json ContentType.
+type PostPersonalAccessTokensJSONRequestBody = PersonalAccessTokenRequest
+
 // PostRefreshJSONRequestBody defines body for PostRefresh for application/json ContentType.
 type PostRefreshJSONRequestBody = TokenRequest
 
 // PostRegisterJSONRequestBody defines body for PostRegister for application/json ContentType.
 type PostRegisterJSONRequestBody = RegisterUserRequest
+
+// PostRelationsCheckJSONRequestBody defines body for PostRelationsCheck for application/json ContentType.
+type PostRelationsCheckJSONRequestBody = CheckRequest
+
+// PostRelationsExpandJSONRequestBody defines body for PostRelationsExpand for application/json ContentType.
+type PostRelationsExpandJSONRequestBody = ExpandRequest
+
+// PostRelationsObjectsJSONRequestBody defines body for PostRelationsObjects for application/json ContentType.
+type PostRelationsObjectsJSONRequestBody = ListObjectsRequest
+
+// PostRelationsWriteJSONRequestBody defines body for PostRelationsWrite for application/json ContentType.
+type PostRelationsWriteJSONRequestBody = WriteRequest
+
+// PostSamlAcsFormdataRequestBody defines body for PostSamlAcs for application/x-www-form-urlencoded ContentType.
+type PostSamlAcsFormdataRequestBody = SAMLResponseForm
+
+// PostWebauthnLoginBeginJSONRequestBody defines body for PostWebauthnLoginBegin for application/json ContentType.
+type PostWebauthnLoginBeginJSONRequestBody = WebAuthnLoginBeginRequest
+
+// PostWebauthnLoginFinishJSONRequestBody defines body for PostWebauthnLoginFinish for application/json ContentType.
+type PostWebauthnLoginFinishJSONRequestBody = WebAuthnFinishRequest
+
+// PostWebauthnRegisterFinishJSONRequestBody defines body for PostWebauthnRegisterFinish for application/json ContentType.
+type PostWebauthnRegisterFinishJSONRequestBody = WebAuthnFinishRequest
@@ -10,6 +10,7 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"path"
@@ -23,39 +24,243 @@ import (
 
 // ServerInterface represents all server handlers.
 type ServerInterface interface {
+	// List OAuth clients
+	// (GET /admin/oauth/clients)
+	GetAdminOauthClients(w http.ResponseWriter, r *http.Request)
+	// Register OAuth client for the authorization code flow
+	// (POST /admin/oauth/clients)
+	PostAdminOauthClients(w http.ResponseWriter, r *http.Request)
+	// Delete OAuth client with its secrets and grants
+	// (DELETE /admin/oauth/clients/{client_id})
+	DeleteAdminOauthClientsClientId(w http.ResponseWriter, r *http.Request, clientId string)
+	// Get OAuth client
+	// (GET /admin/oauth/clients/{client_id})
+	GetAdminOauthClientsClientId(w http.ResponseWriter, r *http.Request, clientId string)
+	// Update OAuth client
+	// (PUT /admin/oauth/clients/{client_id})
+	PutAdminOauthClientsClientId(w http.ResponseWriter, r *http.Request, clientId string)
+	// Rotate secret of confidential client
+	// (POST /admin/oauth/clients/{client_id}/secrets)
+	PostAdminOauthClientsClientIdSecrets(w http.ResponseWriter, r *http.Request, clientId string)
+	// List roles of the user
+	// (GET /admin/users/{id}/roles)
+	GetAdminUsersIdRoles(w http.ResponseWriter, r *http.Request, id int)
+	// Grant role to the user
+	// (POST /admin/users/{id}/roles)
+	PostAdminUsersIdRoles(w http.ResponseWriter, r *http.Request, id int)
+	// Revoke role from the user
+	// (DELETE /admin/users/{id}/roles/{role})
+	DeleteAdminUsersIdRolesRole(w http.ResponseWriter, r *http.Request, id int, role string)
 	// Get build information
 	// (GET /buildinfo)
 	GetBuildinfo(w http.ResponseWriter, r *http.Request)
+	// Finish sign in at an external provider and exchange it for token pair
+	// (GET /federation/callback)
+	GetFederationCallback(w http.ResponseWriter, r *http.Request, params GetFederationCallbackParams)
+	// Start linking an identity at an external provider to the current account
+	// (POST /federation/link)
+	PostFederationLink(w http.ResponseWriter, r *http.Request, params PostFederationLinkParams)
+	// Redirect to sign in at an external OpenID Connect provider
+	// (GET /federation/login)
+	GetFederationLogin(w http.ResponseWriter, r *http.Request, params GetFederationLoginParams)
+	// Login methods of the current account
+	// (GET /identities)
+	GetIdentities(w http.ResponseWriter, r *http.Request)
+	// Unlink login method from the current account
+	// (DELETE /identities/{provider}/{subject})
+	DeleteIdentitiesProviderSubject(w http.ResponseWriter, r *http.Request, provider string, subject string)
 	// Login a user
 	// (POST /login)
 	PostLogin(w http.ResponseWriter, r *http.Request)
+	// Email a single-use login link
+	// (POST /magic-link)
+	PostMagicLink(w http.ResponseWriter, r *http.Request)
+	// Exchange the link token for token pair
+	// (POST /magic-link/consume)
+	PostMagicLinkConsume(w http.ResponseWriter, r *http.Request)
+	// Personal access tokens of the current account
+	// (GET /personal-access-tokens)
+	GetPersonalAccessTokens(w http.ResponseWriter, r *http.Request)
+	// Create personal access token for scripts and CI
+	// (POST /personal-access-tokens)
+	PostPersonalAccessTokens(w http.ResponseWriter, r *http.Request)
+	// Revoke personal access token
+	// (DELETE /personal-access-tokens/{id})
+	DeletePersonalAccessTokensId(w http.ResponseWriter, r *http.Request, id string)
 	// Generate new token pair
 	// (POST /refresh)
 	PostRefresh(w http.ResponseWriter, r *http.Request)
 	// Register a new user
 	// (POST /register)
 	PostRegister(w http.ResponseWriter, r *http.Request)
-	// Get build information
+	// Check the subject has the relation with the object
+	// (POST /relations/check)
+	PostRelationsCheck(w http.ResponseWriter, r *http.Request)
+	// Expand usersets having the relation with the object
+	// (POST /relations/expand)
+	PostRelationsExpand(w http.ResponseWriter, r *http.Request)
+	// List objects the subject has the relation with
+	// (POST /relations/objects)
+	PostRelationsObjects(w http.ResponseWriter, r *http.Request)
+	// Write or delete relation tuples atomically
+	// (POST /relations/write)
+	PostRelationsWrite(w http.ResponseWriter, r *http.Request)
+	// Assertion consumer service, exchanges signed response for token pair
+	// (POST /saml/acs)
+	PostSamlAcs(w http.ResponseWriter, r *http.Request)
+	// Redirect to sign in at a SAML identity provider
+	// (GET /saml/login)
+	GetSamlLogin(w http.ResponseWriter, r *http.Request, params GetSamlLoginParams)
+	// SAML metadata of the service provider
+	// (GET /saml/metadata)
+	GetSamlMetadata(w http.ResponseWriter, r *http.Request)
+	// Get user
 	// (GET /users/{id})
 	GetUsersId(w http.ResponseWriter, r *http.Request, id int)
+	// Start passkey login
+	// (POST /webauthn/login/begin)
+	PostWebauthnLoginBegin(w http.ResponseWriter, r *http.Request)
+	// Verify the assertion and issue token pair
+	// (POST /webauthn/login/finish)
+	PostWebauthnLoginFinish(w http.ResponseWriter, r *http.Request)
+	// Start passkey registration for the authenticated user
+	// (POST /webauthn/register/begin)
+	PostWebauthnRegisterBegin(w http.ResponseWriter, r *http.Request)
+	// Verify the attestation and store the passkey
+	// (POST /webauthn/register/finish)
+	PostWebauthnRegisterFinish(w http.ResponseWriter, r *http.Request)
 }
 
 // Unimplemented server implementation that returns http.StatusNotImplemented for each endpoint.
 
 type Unimplemented struct{}
 
+// List OAuth clients
+// (GET /admin/oauth/clients)
+func (_ Unimplemented) GetAdminOauthClients(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Register OAuth client for the authorization code flow
+// (POST /admin/oauth/clients)
+func (_ Unimplemented) PostAdminOauthClients(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Delete OAuth client with its secrets and grants
+// (DELETE /admin/oauth/clients/{client_id})
+func (_ Unimplemented) DeleteAdminOauthClientsClientId(w http.ResponseWriter, r *http.Request, clientId string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Get OAuth client
+// (GET /admin/oauth/clients/{client_id})
+func (_ Unimplemented) GetAdminOauthClientsClientId(w http.ResponseWriter, r *http.Request, clientId string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Update OAuth client
+// (PUT /admin/oauth/clients/{client_id})
+func (_ Unimplemented) PutAdminOauthClientsClientId(w http.ResponseWriter, r *http.Request, clientId string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Rotate secret of confidential client
+// (POST /admin/oauth/clients/{client_id}/secrets)
+func (_ Unimplemented) PostAdminOauthClientsClientIdSecrets(w http.ResponseWriter, r *http.Request, clientId string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// List roles of the user
+// (GET /admin/users/{id}/roles)
+func (_ Unimplemented) GetAdminUsersIdRoles(w http.ResponseWriter, r *http.Request, id int) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Grant role to the user
+// (POST /admin/users/{id}/roles)
+func (_ Unimplemented) PostAdminUsersIdRoles(w http.ResponseWriter, r *http.Request, id int) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Revoke role from the user
+// (DELETE /admin/users/{id}/roles/{role})
+func (_ Unimplemented) DeleteAdminUsersIdRolesRole(w http.ResponseWriter, r *http.Request, id int, role string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
 // Get build information
 // (GET /buildinfo)
 func (_ Unimplemented) GetBuildinfo(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNotImplemented)
 }
 
+// Finish sign in at an external provider and exchange it for token pair
+// (GET /federation/callback)
+func (_ Unimplemented) GetFederationCallback(w http.ResponseWriter, r *http.Request, params GetFederationCallbackParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Start linking an identity at an external provider to the current account
+// (POST /federation/link)
+func (_ Unimplemented) PostFederationLink(w http.ResponseWriter, r *http.Request, params PostFederationLinkParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Redirect to sign in at an external OpenID Connect provider
+// (GET /federation/login)
+func (_ Unimplemented) GetFederationLogin(w http.ResponseWriter, r *http.Request, params GetFederationLoginParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Login methods of the current account
+// (GET /identities)
+func (_ Unimplemented) GetIdentities(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Unlink login method from the current account
+// (DELETE /identities/{provider}/{subject})
+func (_ Unimplemented) DeleteIdentitiesProviderSubject(w http.ResponseWriter, r *http.Request, provider string, subject string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
 // Login a user
 // (POST /login)
 func (_ Unimplemented) PostLogin(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNotImplemented)
 }
 
+// Email a single-use login link
+// (POST /magic-link)
+func (_ Unimplemented) PostMagicLink(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Exchange the link token for token pair
+// (POST /magic-link/consume)
+func (_ Unimplemented) PostMagicLinkConsume(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Personal access tokens of the current account
+// (GET /personal-access-tokens)
+func (_ Unimplemented) GetPersonalAccessTokens(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Create personal access token for scripts and CI
+// (POST /personal-access-tokens)
+func (_ Unimplemented) PostPersonalAccessTokens(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Revoke personal access token
+// (DELETE /personal-access-tokens/{id})
+func (_ Unimplemented) DeletePersonalAccessTokensId(w http.ResponseWriter, r *http.Request, id string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
 // Generate new token pair
 // (POST /refresh)
 func (_ Unimplemented) PostRefresh(w http.ResponseWriter, r *http.Request) {
@@ -68,12 +273,78 @@ func (_ Unimplemented) PostRegister(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNotImplemented)
 }
 
-// Get build information
+// Check the subject has the relation with the object
+// (POST /relations/check)
+func (_ Unimplemented) PostRelationsCheck(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Expand usersets having the relation with the object
+// (POST /relations/expand)
+func (_ Unimplemented) PostRelationsExpand(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// List objects the subject has the relation with
+// (POST /relations/objects)
+func (_ Unimplemented) PostRelationsObjects(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Write or delete relation tuples atomically
+// (POST /relations/write)
+func (_ Unimplemented) PostRelationsWrite(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Assertion consumer service, exchanges signed response for token pair
+// (POST /saml/acs)
+func (_ Unimplemented) PostSamlAcs(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Redirect to sign in at a SAML identity provider
+// (GET /saml/login)
+func (_ Unimplemented) GetSamlLogin(w http.ResponseWriter, r *http.Request, params GetSamlLoginParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// SAML metadata of the service provider
+// (GET /saml/metadata)
+func (_ Unimplemented) GetSamlMetadata(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Get user
 // (GET /users/{id})
 func (_ Unimplemented) GetUsersId(w http.ResponseWriter, r *http.Request, id int) {
 	w.WriteHeader(http.StatusNotImplemented)
 }
 
+// Start passkey login
+// (POST /webauthn/login/begin)
+func (_ Unimplemented) PostWebauthnLoginBegin(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Verify the assertion and issue token pair
+// (POST /webauthn/login/finish)
+func (_ Unimplemented) PostWebauthnLoginFinish(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Start passkey registration for the authenticated user
+// (POST /webauthn/register/begin)
+func (_ Unimplemented) PostWebauthnRegisterBegin(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Verify the attestation and store the passkey
+// (POST /webauthn/register/finish)
+func (_ Unimplemented) PostWebauthnRegisterFinish(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
 // ServerInterfaceWrapper converts contexts to parameters.
 type ServerInterfaceWrapper struct {
 	Handler            ServerInterface
@@ -83,12 +354,12 @@ type ServerInterfaceWrapper struct {
 
 type MiddlewareFunc func(http.Handler) http.Handler
 
-// GetBuildinfo operation middleware
-func (siw *ServerInterfaceWrapper) GetBuildinfo(w http.ResponseWriter, r *http.Request) {
+// GetAdminOauthClients operation middleware
+func (siw *ServerInterfaceWrapper) GetAdminOauthClients(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.GetBuildinfo(w, r)
+		siw.Handler.GetAdminOauthClients(w, r)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -98,12 +369,12 @@ func (siw *ServerInterfaceWrapper) GetBuildinfo(w http.ResponseWriter, r *http.R
 	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-// PostLogin operation middleware
-func (siw *ServerInterfaceWrapper) PostLogin(w http.ResponseWriter, r *http.Request) {
+// PostAdminOauthClients operation middleware
+func (siw *ServerInterfaceWrapper) PostAdminOauthClients(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.PostLogin(w, r)
+		siw.Handler.PostAdminOauthClients(w, r)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -113,12 +384,23 @@ func (siw *ServerInterfaceWrapper) PostLogin(w http.ResponseWriter, r *http.Requ
 	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-// PostRefresh operation middleware
-func (siw *ServerInterfaceWrapper) PostRefresh(w http.ResponseWriter, r *http.Request) {
+// DeleteAdminOauthClientsClientId operation middleware
+func (siw *ServerInterfaceWrapper) DeleteAdminOauthClientsClientId(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
+	var err error
+
+	// ------------- Path parameter "client_id" -------------
+	var clientId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "client_id", chi.URLParam(r, "client_id"), &clientId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "client_id", Err: err})
+		return
+	}
+
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.PostRefresh(w, r)
+		siw.Handler.DeleteAdminOauthClientsClientId(w, r, clientId)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -128,12 +410,23 @@ func (siw *ServerInterfaceWrapper) PostRefresh(w http.ResponseWriter, r *http.Re
 	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-// PostRegister operation middleware
-func (siw *ServerInterfaceWrapper) PostRegister(w http.ResponseWriter, r *http.Request) {
+// GetAdminOauthClientsClientId operation middleware
+func (siw *ServerInterfaceWrapper) GetAdminOauthClientsClientId(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
+	var err error
+
+	// ------------- Path parameter "client_id" -------------
+	var clientId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "client_id", chi.URLParam(r, "client_id"), &clientId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "client_id", Err: err})
+		return
+	}
+
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.PostRegister(w, r)
+		siw.Handler.GetAdminOauthClientsClientId(w, r, clientId)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -143,23 +436,23 @@ func (siw *ServerInterfaceWrapper) PostRegister(w http.ResponseWriter, r *http.R
 	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-// GetUsersId operation middleware
-func (siw *ServerInterfaceWrapper) GetUsersId(w http.ResponseWriter, r *http.Request) {
+// PutAdminOauthClientsClientId operation middleware
+func (siw *ServerInterfaceWrapper) PutAdminOauthClientsClientId(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	var err error
 
-	// ------------- Path parameter "id" -------------
-	var id int
+	// ------------- Path parameter "client_id" -------------
+	var clientId string
 
-	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	err = runtime.BindStyledParameterWithOptions("simple", "client_id", chi.URLParam(r, "client_id"), &clientId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
 	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "client_id", Err: err})
 		return
 	}
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.GetUsersId(w, r, id)
+		siw.Handler.PutAdminOauthClientsClientId(w, r, clientId)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -169,368 +462,3377 @@ func (siw *ServerInterfaceWrapper) GetUsersId(w http.ResponseWriter, r *http.Req
 	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-type UnescapedCookieParamError struct {
-	ParamName string
-	Err       error
-}
+// PostAdminOauthClientsClientIdSecrets operation middleware
+func (siw *ServerInterfaceWrapper) PostAdminOauthClientsClientIdSecrets(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 
-func (e *UnescapedCookieParamError) Error() string {
-	return fmt.Sprintf("error unescaping cookie parameter '%s'", e.ParamName)
-}
+	var err error
 
-func (e *UnescapedCookieParamError) Unwrap() error {
-	return e.Err
-}
+	// ------------- Path parameter "client_id" -------------
+	var clientId string
 
-type UnmarshalingParamError struct {
-	ParamName string
-	Err       error
-}
+	err = runtime.BindStyledParameterWithOptions("simple", "client_id", chi.URLParam(r, "client_id"), &clientId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "client_id", Err: err})
+		return
+	}
 
-func (e *UnmarshalingParamError) Error() string {
-	return fmt.Sprintf("Error unmarshaling parameter %s as JSON: %s", e.ParamName, e.Err.Error())
-}
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostAdminOauthClientsClientIdSecrets(w, r, clientId)
+	}))
 
-func (e *UnmarshalingParamError) Unwrap() error {
-	return e.Err
-}
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
 
-type RequiredParamError struct {
-	ParamName string
+	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-func (e *RequiredParamError) Error() string {
-	return fmt.Sprintf("Query argument %s is required, but not found", e.ParamName)
-}
+// GetAdminUsersIdRoles operation middleware
+func (siw *ServerInterfaceWrapper) GetAdminUsersIdRoles(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 
-type RequiredHeaderError struct {
-	ParamName string
-	Err       error
-}
+	var err error
 
-func (e *RequiredHeaderError) Error() string {
-	return fmt.Sprintf("Header parameter %s is required, but not found", e.ParamName)
-}
+	// ------------- Path parameter "id" -------------
+	var id int
 
-func (e *RequiredHeaderError) Unwrap() error {
-	return e.Err
-}
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
 
-type InvalidParamFormatError struct {
-	ParamName string
-	Err       error
-}
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetAdminUsersIdRoles(w, r, id)
+	}))
 
-func (e *InvalidParamFormatError) Error() string {
-	return fmt.Sprintf("Invalid format for parameter %s: %s", e.ParamName, e.Err.Error())
-}
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
 
-func (e *InvalidParamFormatError) Unwrap() error {
-	return e.Err
+	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-type TooManyValuesForParamError struct {
-	ParamName string
-	Count     int
-}
+// PostAdminUsersIdRoles operation middleware
+func (siw *ServerInterfaceWrapper) PostAdminUsersIdRoles(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 
-func (e *TooManyValuesForParamError) Error() string {
-	return fmt.Sprintf("Expected one value for %s, got %d", e.ParamName, e.Count)
-}
+	var err error
 
-// Handler creates http.Handler with routing matching OpenAPI spec.
-func Handler(si ServerInterface) http.Handler {
-	return HandlerWithOptions(si, ChiServerOptions{})
-}
+	// ------------- Path parameter "id" -------------
+	var id int
 
-type ChiServerOptions struct {
-	BaseURL          string
-	BaseRouter       chi.Router
-	Middlewares      []MiddlewareFunc
-	ErrorHandlerFunc func(w http.ResponseWriter, r *http.Request, err error)
-}
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
 
-// HandlerFromMux creates http.Handler with routing matching OpenAPI spec based on the provided mux.
-func HandlerFromMux(si ServerInterface, r chi.Router) http.Handler {
-	return HandlerWithOptions(si, ChiServerOptions{
-		BaseRouter: r,
-	})
-}
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostAdminUsersIdRoles(w, r, id)
+	}))
 
-func HandlerFromMuxWithBaseURL(si ServerInterface, r chi.Router, baseURL string) http.Handler {
-	return HandlerWithOptions(si, ChiServerOptions{
-		BaseURL:    baseURL,
-		BaseRouter: r,
-	})
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-// HandlerWithOptions creates http.Handler with additional options
-func HandlerWithOptions(si ServerInterface, options ChiServerOptions) http.Handler {
-	r := options.BaseRouter
+// DeleteAdminUsersIdRolesRole operation middleware
+func (siw *ServerInterfaceWrapper) DeleteAdminUsersIdRolesRole(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 
-	if r == nil {
-		r = chi.NewRouter()
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id int
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
 	}
-	if options.ErrorHandlerFunc == nil {
-		options.ErrorHandlerFunc = func(w http.ResponseWriter, r *http.Request, err error) {
-			http.Error(w, err.Error(), http.StatusBadRequest)
-		}
-	}
-	wrapper := ServerInterfaceWrapper{
-		Handler:            si,
-		HandlerMiddlewares: options.Middlewares,
-		ErrorHandlerFunc:   options.ErrorHandlerFunc,
+
+	// ------------- Path parameter "role" -------------
+	var role string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "role", chi.URLParam(r, "role"), &role, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "role", Err: err})
+		return
 	}
 
-	r.Group(func(r chi.Router) {
-		r.Get(options.BaseURL+"/buildinfo", wrapper.GetBuildinfo)
-	})
-	r.Group(func(r chi.Router) {
-		r.Post(options.BaseURL+"/login", wrapper.PostLogin)
-	})
-	r.Group(func(r chi.Router) {
-		r.Post(options.BaseURL+"/refresh", wrapper.PostRefresh)
-	})
-	r.Group(func(r chi.Router) {
-		r.Post(options.BaseURL+"/register", wrapper.PostRegister)
-	})
-	r.Group(func(r chi.Router) {
-		r.Get(options.BaseURL+"/users/{id}", wrapper.GetUsersId)
-	})
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeleteAdminUsersIdRolesRole(w, r, id, role)
+	}))
 
-	return r
-}
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
 
-type GetBuildinfoRequestObject struct {
+	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-type GetBuildinfoResponseObject interface {
-	VisitGetBuildinfoResponse(w http.ResponseWriter) error
+// GetBuildinfo operation middleware
+func (siw *ServerInterfaceWrapper) GetBuildinfo(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetBuildinfo(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-type GetBuildinfo200JSONResponse BuildInfo
+// GetFederationCallback operation middleware
+func (siw *ServerInterfaceWrapper) GetFederationCallback(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 
-func (response GetBuildinfo200JSONResponse) VisitGetBuildinfoResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(200)
+	var err error
 
-	return json.NewEncoder(w).Encode(response)
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetFederationCallbackParams
+
+	// ------------- Required query parameter "state" -------------
+
+	if paramValue := r.URL.Query().Get("state"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "state"})
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "state", r.URL.Query(), &params.State)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "state", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "code" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "code", r.URL.Query(), &params.Code)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "code", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "error" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "error", r.URL.Query(), &params.Error)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "error", Err: err})
+		return
+	}
+
+	var cookie *http.Cookie
+
+	if cookie, err = r.Cookie("federation_link"); err == nil {
+		var value string
+		err = runtime.BindStyledParameterWithOptions("simple", "federation_link", cookie.Value, &value, runtime.BindStyledParameterOptions{Explode: true, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "federation_link", Err: err})
+			return
+		}
+		params.FederationLink = &value
+
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetFederationCallback(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-type GetBuildinfo500JSONResponse ErrorResponse
+// PostFederationLink operation middleware
+func (siw *ServerInterfaceWrapper) PostFederationLink(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 
-func (response GetBuildinfo500JSONResponse) VisitGetBuildinfoResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(500)
+	var err error
 
-	return json.NewEncoder(w).Encode(response)
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PostFederationLinkParams
+
+	// ------------- Required query parameter "provider" -------------
+
+	if paramValue := r.URL.Query().Get("provider"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "provider"})
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "provider", r.URL.Query(), &params.Provider)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "provider", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostFederationLink(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-type PostLoginRequestObject struct {
-	Body *PostLoginJSONRequestBody
+// GetFederationLogin operation middleware
+func (siw *ServerInterfaceWrapper) GetFederationLogin(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetFederationLoginParams
+
+	// ------------- Required query parameter "provider" -------------
+
+	if paramValue := r.URL.Query().Get("provider"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "provider"})
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "provider", r.URL.Query(), &params.Provider)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "provider", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetFederationLogin(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-type PostLoginResponseObject interface {
-	VisitPostLoginResponse(w http.ResponseWriter) error
+// GetIdentities operation middleware
+func (siw *ServerInterfaceWrapper) GetIdentities(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetIdentities(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-type PostLogin200JSONResponse LoginUserResponse
+// DeleteIdentitiesProviderSubject operation middleware
+func (siw *ServerInterfaceWrapper) DeleteIdentitiesProviderSubject(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 
-func (response PostLogin200JSONResponse) VisitPostLoginResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(200)
+	var err error
 
-	return json.NewEncoder(w).Encode(response)
+	// ------------- Path parameter "provider" -------------
+	var provider string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "provider", chi.URLParam(r, "provider"), &provider, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "provider", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "subject" -------------
+	var subject string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "subject", chi.URLParam(r, "subject"), &subject, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "subject", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeleteIdentitiesProviderSubject(w, r, provider, subject)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-type PostLogin400JSONResponse ErrorResponse
+// PostLogin operation middleware
+func (siw *ServerInterfaceWrapper) PostLogin(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 
-func (response PostLogin400JSONResponse) VisitPostLoginResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(400)
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostLogin(w, r)
+	}))
 
-	return json.NewEncoder(w).Encode(response)
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-type PostLogin401JSONResponse ErrorResponse
+// PostMagicLink operation middleware
+func (siw *ServerInterfaceWrapper) PostMagicLink(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 
-func (response PostLogin401JSONResponse) VisitPostLoginResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(401)
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostMagicLink(w, r)
+	}))
 
-	return json.NewEncoder(w).Encode(response)
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-type PostLogin500JSONResponse ErrorResponse
+// PostMagicLinkConsume operation middleware
+func (siw *ServerInterfaceWrapper) PostMagicLinkConsume(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 
-func (response PostLogin500JSONResponse) VisitPostLoginResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(500)
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostMagicLinkConsume(w, r)
+	}))
 
-	return json.NewEncoder(w).Encode(response)
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-type PostRefreshRequestObject struct {
-	Body *PostRefreshJSONRequestBody
+// GetPersonalAccessTokens operation middleware
+func (siw *ServerInterfaceWrapper) GetPersonalAccessTokens(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetPersonalAccessTokens(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-type PostRefreshResponseObject interface {
-	VisitPostRefreshResponse(w http.ResponseWriter) error
+// PostPersonalAccessTokens operation middleware
+func (siw *ServerInterfaceWrapper) PostPersonalAccessTokens(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostPersonalAccessTokens(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-type PostRefresh200JSONResponse TokenResponse
+// DeletePersonalAccessTokensId operation middleware
+func (siw *ServerInterfaceWrapper) DeletePersonalAccessTokensId(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeletePersonalAccessTokensId(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// PostRefresh operation middleware
+func (siw *ServerInterfaceWrapper) PostRefresh(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostRefresh(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// PostRegister operation middleware
+func (siw *ServerInterfaceWrapper) PostRegister(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostRegister(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// PostRelationsCheck operation middleware
+func (siw *ServerInterfaceWrapper) PostRelationsCheck(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostRelationsCheck(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// PostRelationsExpand operation middleware
+func (siw *ServerInterfaceWrapper) PostRelationsExpand(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostRelationsExpand(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// PostRelationsObjects operation middleware
+func (siw *ServerInterfaceWrapper) PostRelationsObjects(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostRelationsObjects(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// PostRelationsWrite operation middleware
+func (siw *ServerInterfaceWrapper) PostRelationsWrite(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostRelationsWrite(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// PostSamlAcs operation middleware
+func (siw *ServerInterfaceWrapper) PostSamlAcs(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostSamlAcs(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetSamlLogin operation middleware
+func (siw *ServerInterfaceWrapper) GetSamlLogin(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetSamlLoginParams
+
+	// ------------- Required query parameter "provider" -------------
+
+	if paramValue := r.URL.Query().Get("provider"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "provider"})
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "provider", r.URL.Query(), &params.Provider)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "provider", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetSamlLogin(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetSamlMetadata operation middleware
+func (siw *ServerInterfaceWrapper) GetSamlMetadata(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetSamlMetadata(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetUsersId operation middleware
+func (siw *ServerInterfaceWrapper) GetUsersId(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id int
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetUsersId(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// PostWebauthnLoginBegin operation middleware
+func (siw *ServerInterfaceWrapper) PostWebauthnLoginBegin(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostWebauthnLoginBegin(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// PostWebauthnLoginFinish operation middleware
+func (siw *ServerInterfaceWrapper) PostWebauthnLoginFinish(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostWebauthnLoginFinish(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// PostWebauthnRegisterBegin operation middleware
+func (siw *ServerInterfaceWrapper) PostWebauthnRegisterBegin(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostWebauthnRegisterBegin(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// PostWebauthnRegisterFinish operation middleware
+func (siw *ServerInterfaceWrapper) PostWebauthnRegisterFinish(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostWebauthnRegisterFinish(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+type UnescapedCookieParamError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *UnescapedCookieParamError) Error() string {
+	return fmt.Sprintf("error unescaping cookie parameter '%s'", e.ParamName)
+}
+
+func (e *UnescapedCookieParamError) Unwrap() error {
+	return e.Err
+}
+
+type UnmarshalingParamError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *UnmarshalingParamError) Error() string {
+	return fmt.Sprintf("Error unmarshaling parameter %s as JSON: %s", e.ParamName, e.Err.Error())
+}
+
+func (e *UnmarshalingParamError) Unwrap() error {
+	return e.Err
+}
+
+type RequiredParamError struct {
+	ParamName string
+}
+
+func (e *RequiredParamError) Error() string {
+	return fmt.Sprintf("Query argument %s is required, but not found", e.ParamName)
+}
+
+type RequiredHeaderError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *RequiredHeaderError) Error() string {
+	return fmt.Sprintf("Header parameter %s is required, but not found", e.ParamName)
+}
+
+func (e *RequiredHeaderError) Unwrap() error {
+	return e.Err
+}
+
+type InvalidParamFormatError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *InvalidParamFormatError) Error() string {
+	return fmt.Sprintf("Invalid format for parameter %s: %s", e.ParamName, e.Err.Error())
+}
+
+func (e *InvalidParamFormatError) Unwrap() error {
+	return e.Err
+}
+
+type TooManyValuesForParamError struct {
+	ParamName string
+	Count     int
+}
+
+func (e *TooManyValuesForParamError) Error() string {
+	return fmt.Sprintf("Expected one value for %s, got %d", e.ParamName, e.Count)
+}
+
+// Handler creates http.Handler with routing matching OpenAPI spec.
+func Handler(si ServerInterface) http.Handler {
+	return HandlerWithOptions(si, ChiServerOptions{})
+}
+
+type ChiServerOptions struct {
+	BaseURL          string
+	BaseRouter       chi.Router
+	Middlewares      []MiddlewareFunc
+	ErrorHandlerFunc func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+// HandlerFromMux creates http.Handler with routing matching OpenAPI spec based on the provided mux.
+func HandlerFromMux(si ServerInterface, r chi.Router) http.Handler {
+	return HandlerWithOptions(si, ChiServerOptions{
+		BaseRouter: r,
+	})
+}
+
+func HandlerFromMuxWithBaseURL(si ServerInterface, r chi.Router, baseURL string) http.Handler {
+	return HandlerWithOptions(si, ChiServerOptions{
+		BaseURL:    baseURL,
+		BaseRouter: r,
+	})
+}
+
+// HandlerWithOptions creates http.Handler with additional options
+func HandlerWithOptions(si ServerInterface, options ChiServerOptions) http.Handler {
+	r := options.BaseRouter
+
+	if r == nil {
+		r = chi.NewRouter()
+	}
+	if options.ErrorHandlerFunc == nil {
+		options.ErrorHandlerFunc = func(w http.ResponseWriter, r *http.Request, err error) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		}
+	}
+	wrapper := ServerInterfaceWrapper{
+		Handler:            si,
+		HandlerMiddlewares: options.Middlewares,
+		ErrorHandlerFunc:   options.ErrorHandlerFunc,
+	}
+
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/admin/oauth/clients", wrapper.GetAdminOauthClients)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/admin/oauth/clients", wrapper.PostAdminOauthClients)
+	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/admin/oauth/clients/{client_id}", wrapper.DeleteAdminOauthClientsClientId)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/admin/oauth/clients/{client_id}", wrapper.GetAdminOauthClientsClientId)
+	})
+	r.Group(func(r chi.Router) {
+		r.Put(options.BaseURL+"/admin/oauth/clients/{client_id}", wrapper.PutAdminOauthClientsClientId)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/admin/oauth/clients/{client_id}/secrets", wrapper.PostAdminOauthClientsClientIdSecrets)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/admin/users/{id}/roles", wrapper.GetAdminUsersIdRoles)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/admin/users/{id}/roles", wrapper.PostAdminUsersIdRoles)
+	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/admin/users/{id}/roles/{role}", wrapper.DeleteAdminUsersIdRolesRole)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/buildinfo", wrapper.GetBuildinfo)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/federation/callback", wrapper.GetFederationCallback)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/federation/link", wrapper.PostFederationLink)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/federation/login", wrapper.GetFederationLogin)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/identities", wrapper.GetIdentities)
+	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/identities/{provider}/{subject}", wrapper.DeleteIdentitiesProviderSubject)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/login", wrapper.PostLogin)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/magic-link", wrapper.PostMagicLink)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/magic-link/consume", wrapper.PostMagicLinkConsume)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/personal-access-tokens", wrapper.GetPersonalAccessTokens)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/personal-access-tokens", wrapper.PostPersonalAccessTokens)
+	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/personal-access-tokens/{id}", wrapper.DeletePersonalAccessTokensId)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/refresh", wrapper.PostRefresh)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/register", wrapper.PostRegister)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/relations/check", wrapper.PostRelationsCheck)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/relations/expand", wrapper.PostRelationsExpand)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/relations/objects", wrapper.PostRelationsObjects)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/relations/write", wrapper.PostRelationsWrite)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/saml/acs", wrapper.PostSamlAcs)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/saml/login", wrapper.GetSamlLogin)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/saml/metadata", wrapper.GetSamlMetadata)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/users/{id}", wrapper.GetUsersId)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/webauthn/login/begin", wrapper.PostWebauthnLoginBegin)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/webauthn/login/finish", wrapper.PostWebauthnLoginFinish)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/webauthn/register/begin", wrapper.PostWebauthnRegisterBegin)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/webauthn/register/finish", wrapper.PostWebauthnRegisterFinish)
+	})
+
+	return r
+}
+
+type GetAdminOauthClientsRequestObject struct {
+}
+
+type GetAdminOauthClientsResponseObject interface {
+	VisitGetAdminOauthClientsResponse(w http.ResponseWriter) error
+}
+
+type GetAdminOauthClients200JSONResponse []OAuthClient
+
+func (response GetAdminOauthClients200JSONResponse) VisitGetAdminOauthClientsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetAdminOauthClients500JSONResponse ErrorResponse
+
+func (response GetAdminOauthClients500JSONResponse) VisitGetAdminOauthClientsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostAdminOauthClientsRequestObject struct {
+	Body *PostAdminOauthClientsJSONRequestBody
+}
+
+type PostAdminOauthClientsResponseObject interface {
+	VisitPostAdminOauthClientsResponse(w http.ResponseWriter) error
+}
+
+type PostAdminOauthClients201JSONResponse OAuthClient
+
+func (response PostAdminOauthClients201JSONResponse) VisitPostAdminOauthClientsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(201)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostAdminOauthClients400JSONResponse ErrorResponse
+
+func (response PostAdminOauthClients400JSONResponse) VisitPostAdminOauthClientsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostAdminOauthClients500JSONResponse ErrorResponse
+
+func (response PostAdminOauthClients500JSONResponse) VisitPostAdminOauthClientsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type DeleteAdminOauthClientsClientIdRequestObject struct {
+	ClientId string `json:"client_id"`
+}
+
+type DeleteAdminOauthClientsClientIdResponseObject interface {
+	VisitDeleteAdminOauthClientsClientIdResponse(w http.ResponseWriter) error
+}
+
+type DeleteAdminOauthClientsClientId204Response struct {
+}
+
+func (response DeleteAdminOauthClientsClientId204Response) VisitDeleteAdminOauthClientsClientIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(204)
+	return nil
+}
+
+type DeleteAdminOauthClientsClientId404JSONResponse ErrorResponse
+
+func (response DeleteAdminOauthClientsClientId404JSONResponse) VisitDeleteAdminOauthClientsClientIdResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type DeleteAdminOauthClientsClientId500JSONResponse ErrorResponse
+
+func (response DeleteAdminOauthClientsClientId500JSONResponse) VisitDeleteAdminOauthClientsClientIdResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetAdminOauthClientsClientIdRequestObject struct {
+	ClientId string `json:"client_id"`
+}
+
+type GetAdminOauthClientsClientIdResponseObject interface {
+	VisitGetAdminOauthClientsClientIdResponse(w http.ResponseWriter) error
+}
+
+type GetAdminOauthClientsClientId200JSONResponse OAuthClient
+
+func (response GetAdminOauthClientsClientId200JSONResponse) VisitGetAdminOauthClientsClientIdResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetAdminOauthClientsClientId404JSONResponse ErrorResponse
+
+func (response GetAdminOauthClientsClientId404JSONResponse) VisitGetAdminOauthClientsClientIdResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetAdminOauthClientsClientId500JSONResponse ErrorResponse
+
+func (response GetAdminOauthClientsClientId500JSONResponse) VisitGetAdminOauthClientsClientIdResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PutAdminOauthClientsClientIdRequestObject struct {
+	ClientId string `json:"client_id"`
+	Body     *PutAdminOauthClientsClientIdJSONRequestBody
+}
+
+type PutAdminOauthClientsClientIdResponseObject interface {
+	VisitPutAdminOauthClientsClientIdResponse(w http.ResponseWriter) error
+}
+
+type PutAdminOauthClientsClientId200JSONResponse OAuthClient
+
+func (response PutAdminOauthClientsClientId200JSONResponse) VisitPutAdminOauthClientsClientIdResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PutAdminOauthClientsClientId400JSONResponse ErrorResponse
+
+func (response PutAdminOauthClientsClientId400JSONResponse) VisitPutAdminOauthClientsClientIdResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PutAdminOauthClientsClientId404JSONResponse ErrorResponse
+
+func (response PutAdminOauthClientsClientId404JSONResponse) VisitPutAdminOauthClientsClientIdResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PutAdminOauthClientsClientId500JSONResponse ErrorResponse
+
+func (response PutAdminOauthClientsClientId500JSONResponse) VisitPutAdminOauthClientsClientIdResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostAdminOauthClientsClientIdSecretsRequestObject struct {
+	ClientId string `json:"client_id"`
+	Body     *PostAdminOauthClientsClientIdSecretsJSONRequestBody
+}
+
+type PostAdminOauthClientsClientIdSecretsResponseObject interface {
+	VisitPostAdminOauthClientsClientIdSecretsResponse(w http.ResponseWriter) error
+}
+
+type PostAdminOauthClientsClientIdSecrets201JSONResponse ClientSecret
+
+func (response PostAdminOauthClientsClientIdSecrets201JSONResponse) VisitPostAdminOauthClientsClientIdSecretsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(201)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostAdminOauthClientsClientIdSecrets400JSONResponse ErrorResponse
+
+func (response PostAdminOauthClientsClientIdSecrets400JSONResponse) VisitPostAdminOauthClientsClientIdSecretsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostAdminOauthClientsClientIdSecrets404JSONResponse ErrorResponse
+
+func (response PostAdminOauthClientsClientIdSecrets404JSONResponse) VisitPostAdminOauthClientsClientIdSecretsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostAdminOauthClientsClientIdSecrets500JSONResponse ErrorResponse
+
+func (response PostAdminOauthClientsClientIdSecrets500JSONResponse) VisitPostAdminOauthClientsClientIdSecretsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetAdminUsersIdRolesRequestObject struct {
+	Id int `json:"id"`
+}
+
+type GetAdminUsersIdRolesResponseObject interface {
+	VisitGetAdminUsersIdRolesResponse(w http.ResponseWriter) error
+}
+
+type GetAdminUsersIdRoles200JSONResponse UserRoles
+
+func (response GetAdminUsersIdRoles200JSONResponse) VisitGetAdminUsersIdRolesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetAdminUsersIdRoles404JSONResponse ErrorResponse
+
+func (response GetAdminUsersIdRoles404JSONResponse) VisitGetAdminUsersIdRolesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetAdminUsersIdRoles500JSONResponse ErrorResponse
+
+func (response GetAdminUsersIdRoles500JSONResponse) VisitGetAdminUsersIdRolesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostAdminUsersIdRolesRequestObject struct {
+	Id   int `json:"id"`
+	Body *PostAdminUsersIdRolesJSONRequestBody
+}
+
+type PostAdminUsersIdRolesResponseObject interface {
+	VisitPostAdminUsersIdRolesResponse(w http.ResponseWriter) error
+}
+
+type PostAdminUsersIdRoles200JSONResponse UserRoles
+
+func (response PostAdminUsersIdRoles200JSONResponse) VisitPostAdminUsersIdRolesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostAdminUsersIdRoles400JSONResponse ErrorResponse
+
+func (response PostAdminUsersIdRoles400JSONResponse) VisitPostAdminUsersIdRolesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostAdminUsersIdRoles404JSONResponse ErrorResponse
+
+func (response PostAdminUsersIdRoles404JSONResponse) VisitPostAdminUsersIdRolesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostAdminUsersIdRoles500JSONResponse ErrorResponse
+
+func (response PostAdminUsersIdRoles500JSONResponse) VisitPostAdminUsersIdRolesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type DeleteAdminUsersIdRolesRoleRequestObject struct {
+	Id   int    `json:"id"`
+	Role string `json:"role"`
+}
+
+type DeleteAdminUsersIdRolesRoleResponseObject interface {
+	VisitDeleteAdminUsersIdRolesRoleResponse(w http.ResponseWriter) error
+}
+
+type DeleteAdminUsersIdRolesRole200JSONResponse UserRoles
+
+func (response DeleteAdminUsersIdRolesRole200JSONResponse) VisitDeleteAdminUsersIdRolesRoleResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type DeleteAdminUsersIdRolesRole400JSONResponse ErrorResponse
+
+func (response DeleteAdminUsersIdRolesRole400JSONResponse) VisitDeleteAdminUsersIdRolesRoleResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type DeleteAdminUsersIdRolesRole404JSONResponse ErrorResponse
+
+func (response DeleteAdminUsersIdRolesRole404JSONResponse) VisitDeleteAdminUsersIdRolesRoleResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type DeleteAdminUsersIdRolesRole500JSONResponse ErrorResponse
+
+func (response DeleteAdminUsersIdRolesRole500JSONResponse) VisitDeleteAdminUsersIdRolesRoleResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetBuildinfoRequestObject struct {
+}
+
+type GetBuildinfoResponseObject interface {
+	VisitGetBuildinfoResponse(w http.ResponseWriter) error
+}
+
+type GetBuildinfo200JSONResponse BuildInfo
+
+func (response GetBuildinfo200JSONResponse) VisitGetBuildinfoResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetBuildinfo500JSONResponse ErrorResponse
+
+func (response GetBuildinfo500JSONResponse) VisitGetBuildinfoResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetFederationCallbackRequestObject struct {
+	Params GetFederationCallbackParams
+}
+
+type GetFederationCallbackResponseObject interface {
+	VisitGetFederationCallbackResponse(w http.ResponseWriter) error
+}
+
+type GetFederationCallback200JSONResponse LoginUserResponse
+
+func (response GetFederationCallback200JSONResponse) VisitGetFederationCallbackResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetFederationCallback202JSONResponse MFARequiredResponse
+
+func (response GetFederationCallback202JSONResponse) VisitGetFederationCallbackResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(202)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetFederationCallback204Response struct {
+}
+
+func (response GetFederationCallback204Response) VisitGetFederationCallbackResponse(w http.ResponseWriter) error {
+	w.WriteHeader(204)
+	return nil
+}
+
+type GetFederationCallback401JSONResponse ErrorResponse
+
+func (response GetFederationCallback401JSONResponse) VisitGetFederationCallbackResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetFederationCallback409JSONResponse ErrorResponse
+
+func (response GetFederationCallback409JSONResponse) VisitGetFederationCallbackResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(409)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetFederationCallback500JSONResponse ErrorResponse
+
+func (response GetFederationCallback500JSONResponse) VisitGetFederationCallbackResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostFederationLinkRequestObject struct {
+	Params PostFederationLinkParams
+}
+
+type PostFederationLinkResponseObject interface {
+	VisitPostFederationLinkResponse(w http.ResponseWriter) error
+}
+
+type PostFederationLink200ResponseHeaders struct {
+	SetCookie string
+}
+
+type PostFederationLink200JSONResponse struct {
+	Body    FederationLinkResponse
+	Headers PostFederationLink200ResponseHeaders
+}
+
+func (response PostFederationLink200JSONResponse) VisitPostFederationLinkResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Set-Cookie", fmt.Sprint(response.Headers.SetCookie))
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type PostFederationLink401JSONResponse ErrorResponse
+
+func (response PostFederationLink401JSONResponse) VisitPostFederationLinkResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostFederationLink403JSONResponse ErrorResponse
+
+func (response PostFederationLink403JSONResponse) VisitPostFederationLinkResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(403)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostFederationLink404JSONResponse ErrorResponse
+
+func (response PostFederationLink404JSONResponse) VisitPostFederationLinkResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostFederationLink500JSONResponse ErrorResponse
+
+func (response PostFederationLink500JSONResponse) VisitPostFederationLinkResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetFederationLoginRequestObject struct {
+	Params GetFederationLoginParams
+}
+
+type GetFederationLoginResponseObject interface {
+	VisitGetFederationLoginResponse(w http.ResponseWriter) error
+}
+
+type GetFederationLogin302ResponseHeaders struct {
+	Location string
+}
+
+type GetFederationLogin302Response struct {
+	Headers GetFederationLogin302ResponseHeaders
+}
+
+func (response GetFederationLogin302Response) VisitGetFederationLoginResponse(w http.ResponseWriter) error {
+	w.Header().Set("Location", fmt.Sprint(response.Headers.Location))
+	w.WriteHeader(302)
+	return nil
+}
+
+type GetFederationLogin404JSONResponse ErrorResponse
+
+func (response GetFederationLogin404JSONResponse) VisitGetFederationLoginResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetFederationLogin500JSONResponse ErrorResponse
+
+func (response GetFederationLogin500JSONResponse) VisitGetFederationLoginResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetIdentitiesRequestObject struct {
+}
+
+type GetIdentitiesResponseObject interface {
+	VisitGetIdentitiesResponse(w http.ResponseWriter) error
+}
+
+type GetIdentities200JSONResponse IdentitiesResponse
+
+func (response GetIdentities200JSONResponse) VisitGetIdentitiesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetIdentities401JSONResponse ErrorResponse
+
+func (response GetIdentities401JSONResponse) VisitGetIdentitiesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetIdentities500JSONResponse ErrorResponse
+
+func (response GetIdentities500JSONResponse) VisitGetIdentitiesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type DeleteIdentitiesProviderSubjectRequestObject struct {
+	Provider string `json:"provider"`
+	Subject  string `json:"subject"`
+}
+
+type DeleteIdentitiesProviderSubjectResponseObject interface {
+	VisitDeleteIdentitiesProviderSubjectResponse(w http.ResponseWriter) error
+}
+
+type DeleteIdentitiesProviderSubject204Response struct {
+}
+
+func (response DeleteIdentitiesProviderSubject204Response) VisitDeleteIdentitiesProviderSubjectResponse(w http.ResponseWriter) error {
+	w.WriteHeader(204)
+	return nil
+}
+
+type DeleteIdentitiesProviderSubject401JSONResponse ErrorResponse
+
+func (response DeleteIdentitiesProviderSubject401JSONResponse) VisitDeleteIdentitiesProviderSubjectResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type DeleteIdentitiesProviderSubject403JSONResponse ErrorResponse
+
+func (response DeleteIdentitiesProviderSubject403JSONResponse) VisitDeleteIdentitiesProviderSubjectResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(403)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type DeleteIdentitiesProviderSubject404JSONResponse ErrorResponse
+
+func (response DeleteIdentitiesProviderSubject404JSONResponse) VisitDeleteIdentitiesProviderSubjectResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type DeleteIdentitiesProviderSubject409JSONResponse ErrorResponse
+
+func (response DeleteIdentitiesProviderSubject409JSONResponse) VisitDeleteIdentitiesProviderSubjectResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(409)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type DeleteIdentitiesProviderSubject500JSONResponse ErrorResponse
+
+func (response DeleteIdentitiesProviderSubject500JSONResponse) VisitDeleteIdentitiesProviderSubjectResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostLoginRequestObject struct {
+	Body *PostLoginJSONRequestBody
+}
+
+type PostLoginResponseObject interface {
+	VisitPostLoginResponse(w http.ResponseWriter) error
+}
+
+type PostLogin200JSONResponse LoginUserResponse
+
+func (response PostLogin200JSONResponse) VisitPostLoginResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostLogin202JSONResponse MFARequiredResponse
+
+func (response PostLogin202JSONResponse) VisitPostLoginResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(202)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostLogin400JSONResponse ErrorResponse
+
+func (response PostLogin400JSONResponse) VisitPostLoginResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostLogin401JSONResponse ErrorResponse
+
+func (response PostLogin401JSONResponse) VisitPostLoginResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostLogin500JSONResponse ErrorResponse
+
+func (response PostLogin500JSONResponse) VisitPostLoginResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostMagicLinkRequestObject struct {
+	Body *PostMagicLinkJSONRequestBody
+}
+
+type PostMagicLinkResponseObject interface {
+	VisitPostMagicLinkResponse(w http.ResponseWriter) error
+}
+
+type PostMagicLink202Response struct {
+}
+
+func (response PostMagicLink202Response) VisitPostMagicLinkResponse(w http.ResponseWriter) error {
+	w.WriteHeader(202)
+	return nil
+}
+
+type PostMagicLink400JSONResponse ErrorResponse
+
+func (response PostMagicLink400JSONResponse) VisitPostMagicLinkResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostMagicLink429JSONResponse ErrorResponse
+
+func (response PostMagicLink429JSONResponse) VisitPostMagicLinkResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(429)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostMagicLink500JSONResponse ErrorResponse
+
+func (response PostMagicLink500JSONResponse) VisitPostMagicLinkResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostMagicLinkConsumeRequestObject struct {
+	Body *PostMagicLinkConsumeJSONRequestBody
+}
+
+type PostMagicLinkConsumeResponseObject interface {
+	VisitPostMagicLinkConsumeResponse(w http.ResponseWriter) error
+}
+
+type PostMagicLinkConsume200JSONResponse LoginUserResponse
+
+func (response PostMagicLinkConsume200JSONResponse) VisitPostMagicLinkConsumeResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostMagicLinkConsume202JSONResponse MFARequiredResponse
+
+func (response PostMagicLinkConsume202JSONResponse) VisitPostMagicLinkConsumeResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(202)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostMagicLinkConsume401JSONResponse ErrorResponse
+
+func (response PostMagicLinkConsume401JSONResponse) VisitPostMagicLinkConsumeResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostMagicLinkConsume500JSONResponse ErrorResponse
+
+func (response PostMagicLinkConsume500JSONResponse) VisitPostMagicLinkConsumeResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetPersonalAccessTokensRequestObject struct {
+}
+
+type GetPersonalAccessTokensResponseObject interface {
+	VisitGetPersonalAccessTokensResponse(w http.ResponseWriter) error
+}
+
+type GetPersonalAccessTokens200JSONResponse []PersonalAccessToken
+
+func (response GetPersonalAccessTokens200JSONResponse) VisitGetPersonalAccessTokensResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetPersonalAccessTokens401JSONResponse ErrorResponse
+
+func (response GetPersonalAccessTokens401JSONResponse) VisitGetPersonalAccessTokensResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetPersonalAccessTokens500JSONResponse ErrorResponse
+
+func (response GetPersonalAccessTokens500JSONResponse) VisitGetPersonalAccessTokensResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostPersonalAccessTokensRequestObject struct {
+	Body *PostPersonalAccessTokensJSONRequestBody
+}
+
+type PostPersonalAccessTokensResponseObject interface {
+	VisitPostPersonalAccessTokensResponse(w http.ResponseWriter) error
+}
+
+type PostPersonalAccessTokens201JSONResponse PersonalAccessToken
+
+func (response PostPersonalAccessTokens201JSONResponse) VisitPostPersonalAccessTokensResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(201)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostPersonalAccessTokens400JSONResponse ErrorResponse
+
+func (response PostPersonalAccessTokens400JSONResponse) VisitPostPersonalAccessTokensResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostPersonalAccessTokens401JSONResponse ErrorResponse
+
+func (response PostPersonalAccessTokens401JSONResponse) VisitPostPersonalAccessTokensResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostPersonalAccessTokens403JSONResponse ErrorResponse
+
+func (response PostPersonalAccessTokens403JSONResponse) VisitPostPersonalAccessTokensResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(403)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostPersonalAccessTokens500JSONResponse ErrorResponse
+
+func (response PostPersonalAccessTokens500JSONResponse) VisitPostPersonalAccessTokensResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type DeletePersonalAccessTokensIdRequestObject struct {
+	Id string `json:"id"`
+}
+
+type DeletePersonalAccessTokensIdResponseObject interface {
+	VisitDeletePersonalAccessTokensIdResponse(w http.ResponseWriter) error
+}
+
+type DeletePersonalAccessTokensId204Response struct {
+}
+
+func (response DeletePersonalAccessTokensId204Response) VisitDeletePersonalAccessTokensIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(204)
+	return nil
+}
+
+type DeletePersonalAccessTokensId401JSONResponse ErrorResponse
+
+func (response DeletePersonalAccessTokensId401JSONResponse) VisitDeletePersonalAccessTokensIdResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type DeletePersonalAccessTokensId404JSONResponse ErrorResponse
+
+func (response DeletePersonalAccessTokensId404JSONResponse) VisitDeletePersonalAccessTokensIdResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type DeletePersonalAccessTokensId500JSONResponse ErrorResponse
+
+func (response DeletePersonalAccessTokensId500JSONResponse) VisitDeletePersonalAccessTokensIdResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostRefreshRequestObject struct {
+	Body *PostRefreshJSONRequestBody
+}
+
+type PostRefreshResponseObject interface {
+	VisitPostRefreshResponse(w http.ResponseWriter) error
+}
+
+type PostRefresh200JSONResponse TokenResponse
+
+func (response PostRefresh200JSONResponse) VisitPostRefreshResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostRefresh400JSONResponse ErrorResponse
+
+func (response PostRefresh400JSONResponse) VisitPostRefreshResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostRefresh401JSONResponse ErrorResponse
+
+func (response PostRefresh401JSONResponse) VisitPostRefreshResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostRefresh500JSONResponse ErrorResponse
+
+func (response PostRefresh500JSONResponse) VisitPostRefreshResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostRegisterRequestObject struct {
+	Body *PostRegisterJSONRequestBody
+}
+
+type PostRegisterResponseObject interface {
+	VisitPostRegisterResponse(w http.ResponseWriter) error
+}
+
+type PostRegister201JSONResponse RegisterUserResponse
+
+func (response PostRegister201JSONResponse) VisitPostRegisterResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(201)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostRegister400JSONResponse ErrorResponse
+
+func (response PostRegister400JSONResponse) VisitPostRegisterResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostRegister409JSONResponse ErrorResponse
+
+func (response PostRegister409JSONResponse) VisitPostRegisterResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(409)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostRegister500JSONResponse ErrorResponse
+
+func (response PostRegister500JSONResponse) VisitPostRegisterResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostRelationsCheckRequestObject struct {
+	Body *PostRelationsCheckJSONRequestBody
+}
+
+type PostRelationsCheckResponseObject interface {
+	VisitPostRelationsCheckResponse(w http.ResponseWriter) error
+}
+
+type PostRelationsCheck200JSONResponse CheckResponse
+
+func (response PostRelationsCheck200JSONResponse) VisitPostRelationsCheckResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostRelationsCheck400JSONResponse ErrorResponse
+
+func (response PostRelationsCheck400JSONResponse) VisitPostRelationsCheckResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostRelationsCheck500JSONResponse ErrorResponse
+
+func (response PostRelationsCheck500JSONResponse) VisitPostRelationsCheckResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostRelationsExpandRequestObject struct {
+	Body *PostRelationsExpandJSONRequestBody
+}
+
+type PostRelationsExpandResponseObject interface {
+	VisitPostRelationsExpandResponse(w http.ResponseWriter) error
+}
+
+type PostRelationsExpand200JSONResponse ExpandResponse
+
+func (response PostRelationsExpand200JSONResponse) VisitPostRelationsExpandResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostRelationsExpand400JSONResponse ErrorResponse
+
+func (response PostRelationsExpand400JSONResponse) VisitPostRelationsExpandResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostRelationsExpand500JSONResponse ErrorResponse
+
+func (response PostRelationsExpand500JSONResponse) VisitPostRelationsExpandResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostRelationsObjectsRequestObject struct {
+	Body *PostRelationsObjectsJSONRequestBody
+}
+
+type PostRelationsObjectsResponseObject interface {
+	VisitPostRelationsObjectsResponse(w http.ResponseWriter) error
+}
+
+type PostRelationsObjects200JSONResponse ListObjectsResponse
+
+func (response PostRelationsObjects200JSONResponse) VisitPostRelationsObjectsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostRelationsObjects400JSONResponse ErrorResponse
+
+func (response PostRelationsObjects400JSONResponse) VisitPostRelationsObjectsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostRelationsObjects500JSONResponse ErrorResponse
+
+func (response PostRelationsObjects500JSONResponse) VisitPostRelationsObjectsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostRelationsWriteRequestObject struct {
+	Body *PostRelationsWriteJSONRequestBody
+}
+
+type PostRelationsWriteResponseObject interface {
+	VisitPostRelationsWriteResponse(w http.ResponseWriter) error
+}
+
+type PostRelationsWrite200JSONResponse WriteResponse
+
+func (response PostRelationsWrite200JSONResponse) VisitPostRelationsWriteResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostRelationsWrite400JSONResponse ErrorResponse
+
+func (response PostRelationsWrite400JSONResponse) VisitPostRelationsWriteResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostRelationsWrite500JSONResponse ErrorResponse
+
+func (response PostRelationsWrite500JSONResponse) VisitPostRelationsWriteResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostSamlAcsRequestObject struct {
+	Body *PostSamlAcsFormdataRequestBody
+}
+
+type PostSamlAcsResponseObject interface {
+	VisitPostSamlAcsResponse(w http.ResponseWriter) error
+}
+
+type PostSamlAcs200JSONResponse LoginUserResponse
+
+func (response PostSamlAcs200JSONResponse) VisitPostSamlAcsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostSamlAcs202JSONResponse MFARequiredResponse
+
+func (response PostSamlAcs202JSONResponse) VisitPostSamlAcsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(202)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostSamlAcs401JSONResponse ErrorResponse
+
+func (response PostSamlAcs401JSONResponse) VisitPostSamlAcsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostSamlAcs409JSONResponse ErrorResponse
+
+func (response PostSamlAcs409JSONResponse) VisitPostSamlAcsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(409)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostSamlAcs500JSONResponse ErrorResponse
+
+func (response PostSamlAcs500JSONResponse) VisitPostSamlAcsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetSamlLoginRequestObject struct {
+	Params GetSamlLoginParams
+}
+
+type GetSamlLoginResponseObject interface {
+	VisitGetSamlLoginResponse(w http.ResponseWriter) error
+}
+
+type GetSamlLogin302ResponseHeaders struct {
+	Location string
+}
+
+type GetSamlLogin302Response struct {
+	Headers GetSamlLogin302ResponseHeaders
+}
+
+func (response GetSamlLogin302Response) VisitGetSamlLoginResponse(w http.ResponseWriter) error {
+	w.Header().Set("Location", fmt.Sprint(response.Headers.Location))
+	w.WriteHeader(302)
+	return nil
+}
+
+type GetSamlLogin404JSONResponse ErrorResponse
+
+func (response GetSamlLogin404JSONResponse) VisitGetSamlLoginResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetSamlLogin500JSONResponse ErrorResponse
+
+func (response GetSamlLogin500JSONResponse) VisitGetSamlLoginResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetSamlMetadataRequestObject struct {
+}
+
+type GetSamlMetadataResponseObject interface {
+	VisitGetSamlMetadataResponse(w http.ResponseWriter) error
+}
+
+type GetSamlMetadata200ApplicationsamlmetadataXmlResponse struct {
+	Body          io.Reader
+	ContentLength int64
+}
+
+func (response GetSamlMetadata200ApplicationsamlmetadataXmlResponse) VisitGetSamlMetadataResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/samlmetadata+xml")
+	if response.ContentLength != 0 {
+		w.Header().Set("Content-Length", fmt.Sprint(response.ContentLength))
+	}
+	w.WriteHeader(200)
+
+	if closer, ok := response.Body.(io.ReadCloser); ok {
+		defer closer.Close()
+	}
+	_, err := io.Copy(w, response.Body)
+	return err
+}
+
+type GetSamlMetadata404JSONResponse ErrorResponse
+
+func (response GetSamlMetadata404JSONResponse) VisitGetSamlMetadataResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetSamlMetadata500JSONResponse ErrorResponse
+
+func (response GetSamlMetadata500JSONResponse) VisitGetSamlMetadataResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetUsersIdRequestObject struct {
+	Id int `json:"id"`
+}
+
+type GetUsersIdResponseObject interface {
+	VisitGetUsersIdResponse(w http.ResponseWriter) error
+}
+
+type GetUsersId200JSONResponse UserInfo
+
+func (response GetUsersId200JSONResponse) VisitGetUsersIdResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetUsersId404JSONResponse ErrorResponse
+
+func (response GetUsersId404JSONResponse) VisitGetUsersIdResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetUsersId500JSONResponse ErrorResponse
+
+func (response GetUsersId500JSONResponse) VisitGetUsersIdResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostWebauthnLoginBeginRequestObject struct {
+	Body *PostWebauthnLoginBeginJSONRequestBody
+}
+
+type PostWebauthnLoginBeginResponseObject interface {
+	VisitPostWebauthnLoginBeginResponse(w http.ResponseWriter) error
+}
+
+type PostWebauthnLoginBegin200JSONResponse WebAuthnCeremony
+
+func (response PostWebauthnLoginBegin200JSONResponse) VisitPostWebauthnLoginBeginResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostWebauthnLoginBegin400JSONResponse ErrorResponse
+
+func (response PostWebauthnLoginBegin400JSONResponse) VisitPostWebauthnLoginBeginResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostWebauthnLoginBegin401JSONResponse ErrorResponse
+
+func (response PostWebauthnLoginBegin401JSONResponse) VisitPostWebauthnLoginBeginResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostWebauthnLoginBegin500JSONResponse ErrorResponse
+
+func (response PostWebauthnLoginBegin500JSONResponse) VisitPostWebauthnLoginBeginResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostWebauthnLoginFinishRequestObject struct {
+	Body *PostWebauthnLoginFinishJSONRequestBody
+}
+
+type PostWebauthnLoginFinishResponseObject interface {
+	VisitPostWebauthnLoginFinishResponse(w http.ResponseWriter) error
+}
+
+type PostWebauthnLoginFinish200JSONResponse LoginUserResponse
+
+func (response PostWebauthnLoginFinish200JSONResponse) VisitPostWebauthnLoginFinishResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostWebauthnLoginFinish400JSONResponse ErrorResponse
+
+func (response PostWebauthnLoginFinish400JSONResponse) VisitPostWebauthnLoginFinishResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostWebauthnLoginFinish401JSONResponse ErrorResponse
+
+func (response PostWebauthnLoginFinish401JSONResponse) VisitPostWebauthnLoginFinishResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostWebauthnLoginFinish500JSONResponse ErrorResponse
+
+func (response PostWebauthnLoginFinish500JSONResponse) VisitPostWebauthnLoginFinishResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostWebauthnRegisterBeginRequestObject struct {
+}
+
+type PostWebauthnRegisterBeginResponseObject interface {
+	VisitPostWebauthnRegisterBeginResponse(w http.ResponseWriter) error
+}
+
+type PostWebauthnRegisterBegin200JSONResponse WebAuthnCeremony
+
+func (response PostWebauthnRegisterBegin200JSONResponse) VisitPostWebauthnRegisterBeginResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostWebauthnRegisterBegin401JSONResponse ErrorResponse
+
+func (response PostWebauthnRegisterBegin401JSONResponse) VisitPostWebauthnRegisterBeginResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostWebauthnRegisterBegin403JSONResponse ErrorResponse
+
+func (response PostWebauthnRegisterBegin403JSONResponse) VisitPostWebauthnRegisterBeginResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(403)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostWebauthnRegisterBegin500JSONResponse ErrorResponse
+
+func (response PostWebauthnRegisterBegin500JSONResponse) VisitPostWebauthnRegisterBeginResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostWebauthnRegisterFinishRequestObject struct {
+	Body *PostWebauthnRegisterFinishJSONRequestBody
+}
+
+type PostWebauthnRegisterFinishResponseObject interface {
+	VisitPostWebauthnRegisterFinishResponse(w http.ResponseWriter) error
+}
+
+type PostWebauthnRegisterFinish201JSONResponse WebAuthnCredential
+
+func (response PostWebauthnRegisterFinish201JSONResponse) VisitPostWebauthnRegisterFinishResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(201)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostWebauthnRegisterFinish400JSONResponse ErrorResponse
+
+func (response PostWebauthnRegisterFinish400JSONResponse) VisitPostWebauthnRegisterFinishResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostWebauthnRegisterFinish401JSONResponse ErrorResponse
+
+func (response PostWebauthnRegisterFinish401JSONResponse) VisitPostWebauthnRegisterFinishResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostWebauthnRegisterFinish403JSONResponse ErrorResponse
+
+func (response PostWebauthnRegisterFinish403JSONResponse) VisitPostWebauthnRegisterFinishResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(403)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostWebauthnRegisterFinish500JSONResponse ErrorResponse
+
+func (response PostWebauthnRegisterFinish500JSONResponse) VisitPostWebauthnRegisterFinishResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+// StrictServerInterface represents all server handlers.
+type StrictServerInterface interface {
+	// List OAuth clients
+	// (GET /admin/oauth/clients)
+	GetAdminOauthClients(ctx context.Context, request GetAdminOauthClientsRequestObject) (GetAdminOauthClientsResponseObject, error)
+	// Register OAuth client for the authorization code flow
+	// (POST /admin/oauth/clients)
+	PostAdminOauthClients(ctx context.Context, request PostAdminOauthClientsRequestObject) (PostAdminOauthClientsResponseObject, error)
+	// Delete OAuth client with its secrets and grants
+	// (DELETE /admin/oauth/clients/{client_id})
+	DeleteAdminOauthClientsClientId(ctx context.Context, request DeleteAdminOauthClientsClientIdRequestObject) (DeleteAdminOauthClientsClientIdResponseObject, error)
+	// Get OAuth client
+	// (GET /admin/oauth/clients/{client_id})
+	GetAdminOauthClientsClientId(ctx context.Context, request GetAdminOauthClientsClientIdRequestObject) (GetAdminOauthClientsClientIdResponseObject, error)
+	// Update OAuth client
+	// (PUT /admin/oauth/clients/{client_id})
+	PutAdminOauthClientsClientId(ctx context.Context, request PutAdminOauthClientsClientIdRequestObject) (PutAdminOauthClientsClientIdResponseObject, error)
+	// Rotate secret of confidential client
+	// (POST /admin/oauth/clients/{client_id}/secrets)
+	PostAdminOauthClientsClientIdSecrets(ctx context.Context, request PostAdminOauthClientsClientIdSecretsRequestObject) (PostAdminOauthClientsClientIdSecretsResponseObject, error)
+	// List roles of the user
+	// (GET /admin/users/{id}/roles)
+	GetAdminUsersIdRoles(ctx context.Context, request GetAdminUsersIdRolesRequestObject) (GetAdminUsersIdRolesResponseObject, error)
+	// Grant role to the user
+	// (POST /admin/users/{id}/roles)
+	PostAdminUsersIdRoles(ctx context.Context, request PostAdminUsersIdRolesRequestObject) (PostAdminUsersIdRolesResponseObject, error)
+	// Revoke role from the user
+	// (DELETE /admin/users/{id}/roles/{role})
+	DeleteAdminUsersIdRolesRole(ctx context.Context, request DeleteAdminUsersIdRolesRoleRequestObject) (DeleteAdminUsersIdRolesRoleResponseObject, error)
+	// Get build information
+	// (GET /buildinfo)
+	GetBuildinfo(ctx context.Context, request GetBuildinfoRequestObject) (GetBuildinfoResponseObject, error)
+	// Finish sign in at an external provider and exchange it for token pair
+	// (GET /federation/callback)
+	GetFederationCallback(ctx context.Context, request GetFederationCallbackRequestObject) (GetFederationCallbackResponseObject, error)
+	// Start linking an identity at an external provider to the current account
+	// (POST /federation/link)
+	PostFederationLink(ctx context.Context, request PostFederationLinkRequestObject) (PostFederationLinkResponseObject, error)
+	// Redirect to sign in at an external OpenID Connect provider
+	// (GET /federation/login)
+	GetFederationLogin(ctx context.Context, request GetFederationLoginRequestObject) (GetFederationLoginResponseObject, error)
+	// Login methods of the current account
+	// (GET /identities)
+	GetIdentities(ctx context.Context, request GetIdentitiesRequestObject) (GetIdentitiesResponseObject, error)
+	// Unlink login method from the current account
+	// (DELETE /identities/{provider}/{subject})
+	DeleteIdentitiesProviderSubject(ctx context.Context, request DeleteIdentitiesProviderSubjectRequestObject) (DeleteIdentitiesProviderSubjectResponseObject, error)
+	// Login a user
+	// (POST /login)
+	PostLogin(ctx context.Context, request PostLoginRequestObject) (PostLoginResponseObject, error)
+	// Email a single-use login link
+	// (POST /magic-link)
+	PostMagicLink(ctx context.Context, request PostMagicLinkRequestObject) (PostMagicLinkResponseObject, error)
+	// Exchange the link token for token pair
+	// (POST /magic-link/consume)
+	PostMagicLinkConsume(ctx context.Context, request PostMagicLinkConsumeRequestObject) (PostMagicLinkConsumeResponseObject, error)
+	// Personal access tokens of the current account
+	// (GET /personal-access-tokens)
+	GetPersonalAccessTokens(ctx context.Context, request GetPersonalAccessTokensRequestObject) (GetPersonalAccessTokensResponseObject, error)
+	// Create personal access token for scripts and CI
+	// (POST /personal-access-tokens)
+	PostPersonalAccessTokens(ctx context.Context, request PostPersonalAccessTokensRequestObject) (PostPersonalAccessTokensResponseObject, error)
+	// Revoke personal access token
+	// (DELETE /personal-access-tokens/{id})
+	DeletePersonalAccessTokensId(ctx context.Context, request DeletePersonalAccessTokensIdRequestObject) (DeletePersonalAccessTokensIdResponseObject, error)
+	// Generate new token pair
+	// (POST /refresh)
+	PostRefresh(ctx context.Context, request PostRefreshRequestObject) (PostRefreshResponseObject, error)
+	// Register a new user
+	// (POST /register)
+	PostRegister(ctx context.Context, request PostRegisterRequestObject) (PostRegisterResponseObject, error)
+	// Check the subject has the relation with the object
+	// (POST /relations/check)
+	PostRelationsCheck(ctx context.Context, request PostRelationsCheckRequestObject) (PostRelationsCheckResponseObject, error)
+	// Expand usersets having the relation with the object
+	// (POST /relations/expand)
+	PostRelationsExpand(ctx context.Context, request PostRelationsExpandRequestObject) (PostRelationsExpandResponseObject, error)
+	// List objects the subject has the relation with
+	// (POST /relations/objects)
+	PostRelationsObjects(ctx context.Context, request PostRelationsObjectsRequestObject) (PostRelationsObjectsResponseObject, error)
+	// Write or delete relation tuples atomically
+	// (POST /relations/write)
+	PostRelationsWrite(ctx context.Context, request PostRelationsWriteRequestObject) (PostRelationsWriteResponseObject, error)
+	// Assertion consumer service, exchanges signed response for token pair
+	// (POST /saml/acs)
+	PostSamlAcs(ctx context.Context, request PostSamlAcsRequestObject) (PostSamlAcsResponseObject, error)
+	// Redirect to sign in at a SAML identity provider
+	// (GET /saml/login)
+	GetSamlLogin(ctx context.Context, request GetSamlLoginRequestObject) (GetSamlLoginResponseObject, error)
+	// SAML metadata of the service provider
+	// (GET /saml/metadata)
+	GetSamlMetadata(ctx context.Context, request GetSamlMetadataRequestObject) (GetSamlMetadataResponseObject, error)
+	// Get user
+	// (GET /users/{id})
+	GetUsersId(ctx context.Context, request GetUsersIdRequestObject) (GetUsersIdResponseObject, error)
+	// Start passkey login
+	// (POST /webauthn/login/begin)
+	PostWebauthnLoginBegin(ctx context.Context, request PostWebauthnLoginBeginRequestObject) (PostWebauthnLoginBeginResponseObject, error)
+	// Verify the assertion and issue token pair
+	// (POST /webauthn/login/finish)
+	PostWebauthnLoginFinish(ctx context.Context, request PostWebauthnLoginFinishRequestObject) (PostWebauthnLoginFinishResponseObject, error)
+	// Start passkey registration for the authenticated user
+	// (POST /webauthn/register/begin)
+	PostWebauthnRegisterBegin(ctx context.Context, request PostWebauthnRegisterBeginRequestObject) (PostWebauthnRegisterBeginResponseObject, error)
+	// Verify the attestation and store the passkey
+	// (POST /webauthn/register/finish)
+	PostWebauthnRegisterFinish(ctx context.Context, request PostWebauthnRegisterFinishRequestObject) (PostWebauthnRegisterFinishResponseObject, error)
+}
+
+type StrictHandlerFunc = strictnethttp.StrictHTTPHandlerFunc
+type StrictMiddlewareFunc = strictnethttp.StrictHTTPMiddlewareFunc
+
+type StrictHTTPServerOptions struct {
+	RequestErrorHandlerFunc  func(w http.ResponseWriter, r *http.Request, err error)
+	ResponseErrorHandlerFunc func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+func NewStrictHandler(ssi StrictServerInterface, middlewares []StrictMiddlewareFunc) ServerInterface {
+	return &strictHandler{ssi: ssi, middlewares: middlewares, options: StrictHTTPServerOptions{
+		RequestErrorHandlerFunc: func(w http.ResponseWriter, r *http.Request, err error) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		},
+		ResponseErrorHandlerFunc: func(w http.ResponseWriter, r *http.Request, err error) {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		},
+	}}
+}
+
+func NewStrictHandlerWithOptions(ssi StrictServerInterface, middlewares []StrictMiddlewareFunc, options StrictHTTPServerOptions) ServerInterface {
+	return &strictHandler{ssi: ssi, middlewares: middlewares, options: options}
+}
+
+type strictHandler struct {
+	ssi         StrictServerInterface
+	middlewares []StrictMiddlewareFunc
+	options     StrictHTTPServerOptions
+}
+
+// GetAdminOauthClients operation middleware
+func (sh *strictHandler) GetAdminOauthClients(w http.ResponseWriter, r *http.Request) {
+	var request GetAdminOauthClientsRequestObject
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetAdminOauthClients(ctx, request.(GetAdminOauthClientsRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetAdminOauthClients")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetAdminOauthClientsResponseObject); ok {
+		if err := validResponse.VisitGetAdminOauthClientsResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// PostAdminOauthClients operation middleware
+func (sh *strictHandler) PostAdminOauthClients(w http.ResponseWriter, r *http.Request) {
+	var request PostAdminOauthClientsRequestObject
+
+	var body PostAdminOauthClientsJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.PostAdminOauthClients(ctx, request.(PostAdminOauthClientsRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "PostAdminOauthClients")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(PostAdminOauthClientsResponseObject); ok {
+		if err := validResponse.VisitPostAdminOauthClientsResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// DeleteAdminOauthClientsClientId operation middleware
+func (sh *strictHandler) DeleteAdminOauthClientsClientId(w http.ResponseWriter, r *http.Request, clientId string) {
+	var request DeleteAdminOauthClientsClientIdRequestObject
+
+	request.ClientId = clientId
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.DeleteAdminOauthClientsClientId(ctx, request.(DeleteAdminOauthClientsClientIdRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "DeleteAdminOauthClientsClientId")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(DeleteAdminOauthClientsClientIdResponseObject); ok {
+		if err := validResponse.VisitDeleteAdminOauthClientsClientIdResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// GetAdminOauthClientsClientId operation middleware
+func (sh *strictHandler) GetAdminOauthClientsClientId(w http.ResponseWriter, r *http.Request, clientId string) {
+	var request GetAdminOauthClientsClientIdRequestObject
+
+	request.ClientId = clientId
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetAdminOauthClientsClientId(ctx, request.(GetAdminOauthClientsClientIdRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetAdminOauthClientsClientId")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetAdminOauthClientsClientIdResponseObject); ok {
+		if err := validResponse.VisitGetAdminOauthClientsClientIdResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// PutAdminOauthClientsClientId operation middleware
+func (sh *strictHandler) PutAdminOauthClientsClientId(w http.ResponseWriter, r *http.Request, clientId string) {
+	var request PutAdminOauthClientsClientIdRequestObject
+
+	request.ClientId = clientId
+
+	var body PutAdminOauthClientsClientIdJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.PutAdminOauthClientsClientId(ctx, request.(PutAdminOauthClientsClientIdRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "PutAdminOauthClientsClientId")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(PutAdminOauthClientsClientIdResponseObject); ok {
+		if err := validResponse.VisitPutAdminOauthClientsClientIdResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// PostAdminOauthClientsClientIdSecrets operation middleware
+func (sh *strictHandler) PostAdminOauthClientsClientIdSecrets(w http.ResponseWriter, r *http.Request, clientId string) {
+	var request PostAdminOauthClientsClientIdSecretsRequestObject
+
+	request.ClientId = clientId
+
+	var body PostAdminOauthClientsClientIdSecretsJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.PostAdminOauthClientsClientIdSecrets(ctx, request.(PostAdminOauthClientsClientIdSecretsRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "PostAdminOauthClientsClientIdSecrets")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(PostAdminOauthClientsClientIdSecretsResponseObject); ok {
+		if err := validResponse.VisitPostAdminOauthClientsClientIdSecretsResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// GetAdminUsersIdRoles operation middleware
+func (sh *strictHandler) GetAdminUsersIdRoles(w http.ResponseWriter, r *http.Request, id int) {
+	var request GetAdminUsersIdRolesRequestObject
+
+	request.Id = id
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetAdminUsersIdRoles(ctx, request.(GetAdminUsersIdRolesRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetAdminUsersIdRoles")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetAdminUsersIdRolesResponseObject); ok {
+		if err := validResponse.VisitGetAdminUsersIdRolesResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// PostAdminUsersIdRoles operation middleware
+func (sh *strictHandler) PostAdminUsersIdRoles(w http.ResponseWriter, r *http.Request, id int) {
+	var request PostAdminUsersIdRolesRequestObject
+
+	request.Id = id
+
+	var body PostAdminUsersIdRolesJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.PostAdminUsersIdRoles(ctx, request.(PostAdminUsersIdRolesRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "PostAdminUsersIdRoles")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(PostAdminUsersIdRolesResponseObject); ok {
+		if err := validResponse.VisitPostAdminUsersIdRolesResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// DeleteAdminUsersIdRolesRole operation middleware
+func (sh *strictHandler) DeleteAdminUsersIdRolesRole(w http.ResponseWriter, r *http.Request, id int, role string) {
+	var request DeleteAdminUsersIdRolesRoleRequestObject
+
+	request.Id = id
+	request.Role = role
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.DeleteAdminUsersIdRolesRole(ctx, request.(DeleteAdminUsersIdRolesRoleRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "DeleteAdminUsersIdRolesRole")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(DeleteAdminUsersIdRolesRoleResponseObject); ok {
+		if err := validResponse.VisitDeleteAdminUsersIdRolesRoleResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// GetBuildinfo operation middleware
+func (sh *strictHandler) GetBuildinfo(w http.ResponseWriter, r *http.Request) {
+	var request GetBuildinfoRequestObject
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetBuildinfo(ctx, request.(GetBuildinfoRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetBuildinfo")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetBuildinfoResponseObject); ok {
+		if err := validResponse.VisitGetBuildinfoResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// GetFederationCallback operation middleware
+func (sh *strictHandler) GetFederationCallback(w http.ResponseWriter, r *http.Request, params GetFederationCallbackParams) {
+	var request GetFederationCallbackRequestObject
+
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetFederationCallback(ctx, request.(GetFederationCallbackRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetFederationCallback")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetFederationCallbackResponseObject); ok {
+		if err := validResponse.VisitGetFederationCallbackResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// PostFederationLink operation middleware
+func (sh *strictHandler) PostFederationLink(w http.ResponseWriter, r *http.Request, params PostFederationLinkParams) {
+	var request PostFederationLinkRequestObject
+
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.PostFederationLink(ctx, request.(PostFederationLinkRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "PostFederationLink")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(PostFederationLinkResponseObject); ok {
+		if err := validResponse.VisitPostFederationLinkResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// GetFederationLogin operation middleware
+func (sh *strictHandler) GetFederationLogin(w http.ResponseWriter, r *http.Request, params GetFederationLoginParams) {
+	var request GetFederationLoginRequestObject
+
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetFederationLogin(ctx, request.(GetFederationLoginRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetFederationLogin")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetFederationLoginResponseObject); ok {
+		if err := validResponse.VisitGetFederationLoginResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// GetIdentities operation middleware
+func (sh *strictHandler) GetIdentities(w http.ResponseWriter, r *http.Request) {
+	var request GetIdentitiesRequestObject
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetIdentities(ctx, request.(GetIdentitiesRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetIdentities")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetIdentitiesResponseObject); ok {
+		if err := validResponse.VisitGetIdentitiesResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// DeleteIdentitiesProviderSubject operation middleware
+func (sh *strictHandler) DeleteIdentitiesProviderSubject(w http.ResponseWriter, r *http.Request, provider string, subject string) {
+	var request DeleteIdentitiesProviderSubjectRequestObject
+
+	request.Provider = provider
+	request.Subject = subject
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.DeleteIdentitiesProviderSubject(ctx, request.(DeleteIdentitiesProviderSubjectRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "DeleteIdentitiesProviderSubject")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(DeleteIdentitiesProviderSubjectResponseObject); ok {
+		if err := validResponse.VisitDeleteIdentitiesProviderSubjectResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// PostLogin operation middleware
+func (sh *strictHandler) PostLogin(w http.ResponseWriter, r *http.Request) {
+	var request PostLoginRequestObject
+
+	var body PostLoginJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.PostLogin(ctx, request.(PostLoginRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "PostLogin")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(PostLoginResponseObject); ok {
+		if err := validResponse.VisitPostLoginResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// PostMagicLink operation middleware
+func (sh *strictHandler) PostMagicLink(w http.ResponseWriter, r *http.Request) {
+	var request PostMagicLinkRequestObject
+
+	var body PostMagicLinkJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.PostMagicLink(ctx, request.(PostMagicLinkRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "PostMagicLink")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(PostMagicLinkResponseObject); ok {
+		if err := validResponse.VisitPostMagicLinkResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// PostMagicLinkConsume operation middleware
+func (sh *strictHandler) PostMagicLinkConsume(w http.ResponseWriter, r *http.Request) {
+	var request PostMagicLinkConsumeRequestObject
+
+	var body PostMagicLinkConsumeJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.PostMagicLinkConsume(ctx, request.(PostMagicLinkConsumeRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "PostMagicLinkConsume")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(PostMagicLinkConsumeResponseObject); ok {
+		if err := validResponse.VisitPostMagicLinkConsumeResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// GetPersonalAccessTokens operation middleware
+func (sh *strictHandler) GetPersonalAccessTokens(w http.ResponseWriter, r *http.Request) {
+	var request GetPersonalAccessTokensRequestObject
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetPersonalAccessTokens(ctx, request.(GetPersonalAccessTokensRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetPersonalAccessTokens")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetPersonalAccessTokensResponseObject); ok {
+		if err := validResponse.VisitGetPersonalAccessTokensResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// PostPersonalAccessTokens operation middleware
+func (sh *strictHandler) PostPersonalAccessTokens(w http.ResponseWriter, r *http.Request) {
+	var request PostPersonalAccessTokensRequestObject
+
+	var body PostPersonalAccessTokensJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.PostPersonalAccessTokens(ctx, request.(PostPersonalAccessTokensRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "PostPersonalAccessTokens")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(PostPersonalAccessTokensResponseObject); ok {
+		if err := validResponse.VisitPostPersonalAccessTokensResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// DeletePersonalAccessTokensId operation middleware
+func (sh *strictHandler) DeletePersonalAccessTokensId(w http.ResponseWriter, r *http.Request, id string) {
+	var request DeletePersonalAccessTokensIdRequestObject
+
+	request.Id = id
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.DeletePersonalAccessTokensId(ctx, request.(DeletePersonalAccessTokensIdRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "DeletePersonalAccessTokensId")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(DeletePersonalAccessTokensIdResponseObject); ok {
+		if err := validResponse.VisitDeletePersonalAccessTokensIdResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// PostRefresh operation middleware
+func (sh *strictHandler) PostRefresh(w http.ResponseWriter, r *http.Request) {
+	var request PostRefreshRequestObject
+
+	var body PostRefreshJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.PostRefresh(ctx, request.(PostRefreshRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "PostRefresh")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(PostRefreshResponseObject); ok {
+		if err := validResponse.VisitPostRefreshResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// PostRegister operation middleware
+func (sh *strictHandler) PostRegister(w http.ResponseWriter, r *http.Request) {
+	var request PostRegisterRequestObject
+
+	var body PostRegisterJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.PostRegister(ctx, request.(PostRegisterRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "PostRegister")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(PostRegisterResponseObject); ok {
+		if err := validResponse.VisitPostRegisterResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// PostRelationsCheck operation middleware
+func (sh *strictHandler) PostRelationsCheck(w http.ResponseWriter, r *http.Request) {
+	var request PostRelationsCheckRequestObject
+
+	var body PostRelationsCheckJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.PostRelationsCheck(ctx, request.(PostRelationsCheckRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "PostRelationsCheck")
+	}
 
-func (response PostRefresh200JSONResponse) VisitPostRefreshResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(200)
+	response, err := handler(r.Context(), w, r, request)
 
-	return json.NewEncoder(w).Encode(response)
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(PostRelationsCheckResponseObject); ok {
+		if err := validResponse.VisitPostRelationsCheckResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
 }
 
-type PostRefresh400JSONResponse ErrorResponse
-
-func (response PostRefresh400JSONResponse) VisitPostRefreshResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(400)
+// PostRelationsExpand operation middleware
+func (sh *strictHandler) PostRelationsExpand(w http.ResponseWriter, r *http.Request) {
+	var request PostRelationsExpandRequestObject
 
-	return json.NewEncoder(w).Encode(response)
-}
+	var body PostRelationsExpandJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
 
-type PostRefresh500JSONResponse ErrorResponse
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.PostRelationsExpand(ctx, request.(PostRelationsExpandRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "PostRelationsExpand")
+	}
 
-func (response PostRefresh500JSONResponse) VisitPostRefreshResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(500)
+	response, err := handler(r.Context(), w, r, request)
 
-	return json.NewEncoder(w).Encode(response)
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(PostRelationsExpandResponseObject); ok {
+		if err := validResponse.VisitPostRelationsExpandResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
 }
 
-type PostRegisterRequestObject struct {
-	Body *PostRegisterJSONRequestBody
-}
+// PostRelationsObjects operation middleware
+func (sh *strictHandler) PostRelationsObjects(w http.ResponseWriter, r *http.Request) {
+	var request PostRelationsObjectsRequestObject
 
-type PostRegisterResponseObject interface {
-	VisitPostRegisterResponse(w http.ResponseWriter) error
-}
+	var body PostRelationsObjectsJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
 
-type PostRegister201JSONResponse RegisterUserResponse
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.PostRelationsObjects(ctx, request.(PostRelationsObjectsRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "PostRelationsObjects")
+	}
 
-func (response PostRegister201JSONResponse) VisitPostRegisterResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(201)
+	response, err := handler(r.Context(), w, r, request)
 
-	return json.NewEncoder(w).Encode(response)
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(PostRelationsObjectsResponseObject); ok {
+		if err := validResponse.VisitPostRelationsObjectsResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
 }
 
-type PostRegister400JSONResponse ErrorResponse
-
-func (response PostRegister400JSONResponse) VisitPostRegisterResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(400)
+// PostRelationsWrite operation middleware
+func (sh *strictHandler) PostRelationsWrite(w http.ResponseWriter, r *http.Request) {
+	var request PostRelationsWriteRequestObject
 
-	return json.NewEncoder(w).Encode(response)
-}
+	var body PostRelationsWriteJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
 
-type PostRegister500JSONResponse ErrorResponse
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.PostRelationsWrite(ctx, request.(PostRelationsWriteRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "PostRelationsWrite")
+	}
 
-func (response PostRegister500JSONResponse) VisitPostRegisterResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(500)
+	response, err := handler(r.Context(), w, r, request)
 
-	return json.NewEncoder(w).Encode(response)
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(PostRelationsWriteResponseObject); ok {
+		if err := validResponse.VisitPostRelationsWriteResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
 }
 
-type GetUsersIdRequestObject struct {
-	Id int `json:"id"`
-}
+// PostSamlAcs operation middleware
+func (sh *strictHandler) PostSamlAcs(w http.ResponseWriter, r *http.Request) {
+	var request PostSamlAcsRequestObject
 
-type GetUsersIdResponseObject interface {
-	VisitGetUsersIdResponse(w http.ResponseWriter) error
-}
+	if err := r.ParseForm(); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode formdata: %w", err))
+		return
+	}
+	var body PostSamlAcsFormdataRequestBody
+	if err := runtime.BindForm(&body, r.Form, nil, nil); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't bind formdata: %w", err))
+		return
+	}
+	request.Body = &body
 
-type GetUsersId200JSONResponse UserInfo
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.PostSamlAcs(ctx, request.(PostSamlAcsRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "PostSamlAcs")
+	}
 
-func (response GetUsersId200JSONResponse) VisitGetUsersIdResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(200)
+	response, err := handler(r.Context(), w, r, request)
 
-	return json.NewEncoder(w).Encode(response)
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(PostSamlAcsResponseObject); ok {
+		if err := validResponse.VisitPostSamlAcsResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
 }
 
-type GetUsersId500JSONResponse ErrorResponse
+// GetSamlLogin operation middleware
+func (sh *strictHandler) GetSamlLogin(w http.ResponseWriter, r *http.Request, params GetSamlLoginParams) {
+	var request GetSamlLoginRequestObject
 
-func (response GetUsersId500JSONResponse) VisitGetUsersIdResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(500)
+	request.Params = params
 
-	return json.NewEncoder(w).Encode(response)
-}
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetSamlLogin(ctx, request.(GetSamlLoginRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetSamlLogin")
+	}
 
-// StrictServerInterface represents all server handlers.
-type StrictServerInterface interface {
-	// Get build information
-	// (GET /buildinfo)
-	GetBuildinfo(ctx context.Context, request GetBuildinfoRequestObject) (GetBuildinfoResponseObject, error)
-	// Login a user
-	// (POST /login)
-	PostLogin(ctx context.Context, request PostLoginRequestObject) (PostLoginResponseObject, error)
-	// Generate new token pair
-	// (POST /refresh)
-	PostRefresh(ctx context.Context, request PostRefreshRequestObject) (PostRefreshResponseObject, error)
-	// Register a new user
-	// (POST /register)
-	PostRegister(ctx context.Context, request PostRegisterRequestObject) (PostRegisterResponseObject, error)
-	// Get build information
-	// (GET /users/{id})
-	GetUsersId(ctx context.Context, request GetUsersIdRequestObject) (GetUsersIdResponseObject, error)
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetSamlLoginResponseObject); ok {
+		if err := validResponse.VisitGetSamlLoginResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
 }
 
-type StrictHandlerFunc = strictnethttp.StrictHTTPHandlerFunc
-type StrictMiddlewareFunc = strictnethttp.StrictHTTPMiddlewareFunc
+// GetSamlMetadata operation middleware
+func (sh *strictHandler) GetSamlMetadata(w http.ResponseWriter, r *http.Request) {
+	var request GetSamlMetadataRequestObject
 
-type StrictHTTPServerOptions struct {
-	RequestErrorHandlerFunc  func(w http.ResponseWriter, r *http.Request, err error)
-	ResponseErrorHandlerFunc func(w http.ResponseWriter, r *http.Request, err error)
-}
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetSamlMetadata(ctx, request.(GetSamlMetadataRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetSamlMetadata")
+	}
 
-func NewStrictHandler(ssi StrictServerInterface, middlewares []StrictMiddlewareFunc) ServerInterface {
-	return &strictHandler{ssi: ssi, middlewares: middlewares, options: StrictHTTPServerOptions{
-		RequestErrorHandlerFunc: func(w http.ResponseWriter, r *http.Request, err error) {
-			http.Error(w, err.Error(), http.StatusBadRequest)
-		},
-		ResponseErrorHandlerFunc: func(w http.ResponseWriter, r *http.Request, err error) {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-		},
-	}}
-}
+	response, err := handler(r.Context(), w, r, request)
 
-func NewStrictHandlerWithOptions(ssi StrictServerInterface, middlewares []StrictMiddlewareFunc, options StrictHTTPServerOptions) ServerInterface {
-	return &strictHandler{ssi: ssi, middlewares: middlewares, options: options}
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetSamlMetadataResponseObject); ok {
+		if err := validResponse.VisitGetSamlMetadataResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
 }
 
-type strictHandler struct {
-	ssi         StrictServerInterface
-	middlewares []StrictMiddlewareFunc
-	options     StrictHTTPServerOptions
-}
+// GetUsersId operation middleware
+func (sh *strictHandler) GetUsersId(w http.ResponseWriter, r *http.Request, id int) {
+	var request GetUsersIdRequestObject
 
-// GetBuildinfo operation middleware
-func (sh *strictHandler) GetBuildinfo(w http.ResponseWriter, r *http.Request) {
-	var request GetBuildinfoRequestObject
+	request.Id = id
 
 	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
-		return sh.ssi.GetBuildinfo(ctx, request.(GetBuildinfoRequestObject))
+		return sh.ssi.GetUsersId(ctx, request.(GetUsersIdRequestObject))
 	}
 	for _, middleware := range sh.middlewares {
-		handler = middleware(handler, "GetBuildinfo")
+		handler = middleware(handler, "GetUsersId")
 	}
 
 	response, err := handler(r.Context(), w, r, request)
 
 	if err != nil {
 		sh.options.ResponseErrorHandlerFunc(w, r, err)
-	} else if validResponse, ok := response.(GetBuildinfoResponseObject); ok {
-		if err := validResponse.VisitGetBuildinfoResponse(w); err != nil {
+	} else if validResponse, ok := response.(GetUsersIdResponseObject); ok {
+		if err := validResponse.VisitGetUsersIdResponse(w); err != nil {
 			sh.options.ResponseErrorHandlerFunc(w, r, err)
 		}
 	} else if response != nil {
@@ -538,11 +3840,11 @@ func (sh *strictHandler) GetBuildinfo(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// PostLogin operation middleware
-func (sh *strictHandler) PostLogin(w http.ResponseWriter, r *http.Request) {
-	var request PostLoginRequestObject
+// PostWebauthnLoginBegin operation middleware
+func (sh *strictHandler) PostWebauthnLoginBegin(w http.ResponseWriter, r *http.Request) {
+	var request PostWebauthnLoginBeginRequestObject
 
-	var body PostLoginJSONRequestBody
+	var body PostWebauthnLoginBeginJSONRequestBody
 	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
 		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
 		return
@@ -550,18 +3852,18 @@ func (sh *strictHandler) PostLogin(w http.ResponseWriter, r *http.Request) {
 	request.Body = &body
 
 	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
-		return sh.ssi.PostLogin(ctx, request.(PostLoginRequestObject))
+		return sh.ssi.PostWebauthnLoginBegin(ctx, request.(PostWebauthnLoginBeginRequestObject))
 	}
 	for _, middleware := range sh.middlewares {
-		handler = middleware(handler, "PostLogin")
+		handler = middleware(handler, "PostWebauthnLoginBegin")
 	}
 
 	response, err := handler(r.Context(), w, r, request)
 
 	if err != nil {
 		sh.options.ResponseErrorHandlerFunc(w, r, err)
-	} else if validResponse, ok := response.(PostLoginResponseObject); ok {
-		if err := validResponse.VisitPostLoginResponse(w); err != nil {
+	} else if validResponse, ok := response.(PostWebauthnLoginBeginResponseObject); ok {
+		if err := validResponse.VisitPostWebauthnLoginBeginResponse(w); err != nil {
 			sh.options.ResponseErrorHandlerFunc(w, r, err)
 		}
 	} else if response != nil {
@@ -569,11 +3871,11 @@ func (sh *strictHandler) PostLogin(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// PostRefresh operation middleware
-func (sh *strictHandler) PostRefresh(w http.ResponseWriter, r *http.Request) {
-	var request PostRefreshRequestObject
+// PostWebauthnLoginFinish operation middleware
+func (sh *strictHandler) PostWebauthnLoginFinish(w http.ResponseWriter, r *http.Request) {
+	var request PostWebauthnLoginFinishRequestObject
 
-	var body PostRefreshJSONRequestBody
+	var body PostWebauthnLoginFinishJSONRequestBody
 	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
 		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
 		return
@@ -581,18 +3883,18 @@ func (sh *strictHandler) PostRefresh(w http.ResponseWriter, r *http.Request) {
 	request.Body = &body
 
 	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
-		return sh.ssi.PostRefresh(ctx, request.(PostRefreshRequestObject))
+		return sh.ssi.PostWebauthnLoginFinish(ctx, request.(PostWebauthnLoginFinishRequestObject))
 	}
 	for _, middleware := range sh.middlewares {
-		handler = middleware(handler, "PostRefresh")
+		handler = middleware(handler, "PostWebauthnLoginFinish")
 	}
 
 	response, err := handler(r.Context(), w, r, request)
 
 	if err != nil {
 		sh.options.ResponseErrorHandlerFunc(w, r, err)
-	} else if validResponse, ok := response.(PostRefreshResponseObject); ok {
-		if err := validResponse.VisitPostRefreshResponse(w); err != nil {
+	} else if validResponse, ok := response.(PostWebauthnLoginFinishResponseObject); ok {
+		if err := validResponse.VisitPostWebauthnLoginFinishResponse(w); err != nil {
 			sh.options.ResponseErrorHandlerFunc(w, r, err)
 		}
 	} else if response != nil {
@@ -600,30 +3902,23 @@ func (sh *strictHandler) PostRefresh(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// PostRegister operation middleware
-func (sh *strictHandler) PostRegister(w http.ResponseWriter, r *http.Request) {
-	var request PostRegisterRequestObject
-
-	var body PostRegisterJSONRequestBody
-	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
-		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
-		return
-	}
-	request.Body = &body
+// PostWebauthnRegisterBegin operation middleware
+func (sh *strictHandler) PostWebauthnRegisterBegin(w http.ResponseWriter, r *http.Request) {
+	var request PostWebauthnRegisterBeginRequestObject
 
 	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
-		return sh.ssi.PostRegister(ctx, request.(PostRegisterRequestObject))
+		return sh.ssi.PostWebauthnRegisterBegin(ctx, request.(PostWebauthnRegisterBeginRequestObject))
 	}
 	for _, middleware := range sh.middlewares {
-		handler = middleware(handler, "PostRegister")
+		handler = middleware(handler, "PostWebauthnRegisterBegin")
 	}
 
 	response, err := handler(r.Context(), w, r, request)
 
 	if err != nil {
 		sh.options.ResponseErrorHandlerFunc(w, r, err)
-	} else if validResponse, ok := response.(PostRegisterResponseObject); ok {
-		if err := validResponse.VisitPostRegisterResponse(w); err != nil {
+	} else if validResponse, ok := response.(PostWebauthnRegisterBeginResponseObject); ok {
+		if err := validResponse.VisitPostWebauthnRegisterBeginResponse(w); err != nil {
 			sh.options.ResponseErrorHandlerFunc(w, r, err)
 		}
 	} else if response != nil {
@@ -631,25 +3926,30 @@ func (sh *strictHandler) PostRegister(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// GetUsersId operation middleware
-func (sh *strictHandler) GetUsersId(w http.ResponseWriter, r *http.Request, id int) {
-	var request GetUsersIdRequestObject
+// PostWebauthnRegisterFinish operation middleware
+func (sh *strictHandler) PostWebauthnRegisterFinish(w http.ResponseWriter, r *http.Request) {
+	var request PostWebauthnRegisterFinishRequestObject
 
-	request.Id = id
+	var body PostWebauthnRegisterFinishJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
 
 	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
-		return sh.ssi.GetUsersId(ctx, request.(GetUsersIdRequestObject))
+		return sh.ssi.PostWebauthnRegisterFinish(ctx, request.(PostWebauthnRegisterFinishRequestObject))
 	}
 	for _, middleware := range sh.middlewares {
-		handler = middleware(handler, "GetUsersId")
+		handler = middleware(handler, "PostWebauthnRegisterFinish")
 	}
 
 	response, err := handler(r.Context(), w, r, request)
 
 	if err != nil {
 		sh.options.ResponseErrorHandlerFunc(w, r, err)
-	} else if validResponse, ok := response.(GetUsersIdResponseObject); ok {
-		if err := validResponse.VisitGetUsersIdResponse(w); err != nil {
+	} else if validResponse, ok := response.(PostWebauthnRegisterFinishResponseObject); ok {
+		if err := validResponse.VisitPostWebauthnRegisterFinishResponse(w); err != nil {
 			sh.options.ResponseErrorHandlerFunc(w, r, err)
 		}
 	} else if response != nil {
@@ -660,22 +3960,90 @@ func (sh *strictHandler) GetUsersId(w http.ResponseWriter, r *http.Request, id i
 // Base64 encoded, gzipped, json marshaled Swagger object
 var swaggerSpec = []string{
 
-	"H4sIAAAAAAAC/+xXTW/jNhD9KwTboxE7/bj4lrRFYWDRBmnTy2IRcMWxxFQilSGV1A3834sZyrJlUYlT",
-	"2GmA7smySQ7fm/c0M36SmatqZ8EGL+dP0mcFVIofLxtT6oVdOvpSo6sBgwFeUpgV9KnBZ2jqYJyVc3mB",
-	"WWECZKFBEG4pQgGiUllhLIjGgxZLh/zjZ4osJzKsapBz6QMam8v1RPLCrVYBhtF/VKGLOhogc1Vlwm2h",
-	"fALfD7woaHETyLsGMxCZ0zASrjYlYDIWrxxILHe3D4Cez+6H+tmJGl2OqqqMzUWpbN6oHER74MAbnB9G",
-	"/rUGVIGC+pUPUB0YahTpHy2i51VYTyTCfWMQtJx/7KL1xelp3csPU5lEi+0o8Km7x32+gywQ0J8QHV6D",
-	"r531MHQp0HLCSNtvGyZx50tM4q4UkA8uN/bGA17DfQM+DLHUyvtHh3oI56pd6bD8ZTxr1njAlDz0u1VV",
-	"4hW5aVcODLVHr4s72aJ9gexY5lWWgfe3wf0JbKMBB4Qlgi9Gd+xB68XbP53CeA258QHwWU1UDjtXGxsg",
-	"B6TTh4tl4fEIOo1H+ZcS9emPqWQSBG+suW9AGA02mKUB7MoFtjFB72HdSdyrKJer8Zgj/A2Vm+6SFPPf",
-	"yRKjir/Sdi/7rL3u3b4HlPZ0C3+d+EdQ/CgC037T8tkbPq4WjJYOt76i3uesUFaLkmoW3W5CCS04cdGE",
-	"gphmcd/F1ULuND95fjY7m3FrrcGq2si5/PZsdnbOL18oOItT7mIbRDmw51xsu84uNLV3CJfdJmIbzcLH",
-	"v5nN6CNzNoDls6quyxbQ9M7HJhxnMnr6GmEp5/Kr6XZom7YT23Q7rnGW+tnhRUEQsIpsEQIaeAAtfMOu",
-	"WjZluSK63x8RVL9DJ4AtbCCxS/Eb4AOg4ANsC99UlcJVzGCcNXYJ8J5p1JXM7Xwi91fOhw+t9BiLwqXT",
-	"q6PRG/T9dd/QARtYn1DzYStOpJi9visyvQ05aGNJ7e/eUu1LpUWXKrr7/O3uvrGqCYVD8zfod2lzFlOo",
-	"WCjZ3W19f97f1+2m0zi810/f2N395prI6i/wKLj3iVoZFDlYSk6ipP2XJn+H5TSmicfObfo2losT2Uue",
-	"a3edxnSp6f0g752fCMJriut2pv3ivD3nbZIq1PYvD7uOnvz0yej1c2MU5dovNM9fqCoIgF7OPz5JGgF4",
-	"JpMTGYfROEj27TLZobo/yK4/nbCOdUP4/2UwW6//CQAA///vuk2MVxQAAA==",
+	"H4sIAAAAAAAC/+w9bW/cNpN/hVAPaItTbCfNHXD+ljhNz3dpE9jp9cOTwuBKsys+kUiVpOzsY/i/Hzgk",
+	"9UpptYl3s0H3S1OvJHI4nPcZDu+jRBSl4MC1is7vI5VkUFD835cVy9NLvhTmj1KKEqRmgI+oTDLzbwoq",
+	"kazUTPDoPHohk4xpSHQlgYgl0RmQgiYZ40AqBSlZCok/LszIURzpdQnReaS0ZHwVPcQRPrhJqYbh6K+o",
+	"rkcdHSARRcH0TUZVAL4LfEjMQz+QEpVMgCQihZHhSpaDDI6FT2YubCVubkEq/LY/1C+ClFKsJC0Kxlck",
+	"p3xV0RUQ98HMGYQajvy2BEm1GVStlYZi5lCjkP6fg2h6Fx7iSMJfFZOQRuf/qEfrbk5nrzv4waXElsRa",
+	"O/BnPY9Y/BMSbQC9yCD5eAV/VaD0kEgTwRVTGniyvtHiIwQW9N78bJZDSSnhlolKkYTmeYzrS8zwRAEo",
+	"Arcg1zozqLyTTGvgZAFLIYEwHUKhrsocafjfJCyj8+i704bNTh2PnV5BTg0g7/HlPt7sEBPLVqXgCgLM",
+	"mefizoxx7z9dCJED5ZaiA0iZ3kA/XujjIHg5A66vIZEQ2hV8esPSwMSxf6rqjwdv+H26gU8lk3BD9XBb",
+	"/8iANxtqB1NEaVGSBZg9vKU5MwtaClmYASJDhk80K2AjOTfw96ENwhZC0M9SCjm+f2AeB+Rf85dnQPtm",
+	"gPyWDPI0JBCWS+CpwYB9wwwkLf8owoUmBdUJUrnOmCKqhCSKI6ahUJuI+bUZEFcWPdQQUSnpeoBCC3YQ",
+	"M59KytPtOHooCe1woUfScdxmoneDtD6ZgndsK+cBrCVslBW/K5AK9Hvz6kBSmB/ncudrSFEpCP6G8Qkp",
+	"koukRlVPzVc6E5L9Cx+T36/eeHIspbhlKciNPFSPHQSwIaQBUEi1Q4jeUUkL0CAJpwUQIUkqNFFQUkk1",
+	"pKSkutb4C5GuLfXH5ENk/voQNSrRPGRaQb4MsVUBStEVbKYeC2fzQWidlylwzczKxjeB1e/gX3P40A27",
+	"3siFrbEnwFsHiFqCwaqTvHNEaBxBQVk+Is8d0Qx2taRK3QmZxqTMBIeY3MGCVjrjRPiNXhLKCXzSIDnN",
+	"J+gvjlRVS4XuLIaxzGBuFsKrYgEyJmb2j7Aml6+IIw4//PcKf7TkVKkZ9N4CzMMRt9EYwv8bpvRb/Et9",
+	"oTw0q1MlTWBbkdjB2vQKmzlaIzbfb1zglwlQO2hA312+Un6j/DstdTYYZ5JfmgHmCdo3YsW4oa7R7fME",
+	"HhJo9okHHj4xhXa8I7eC8TfAVzqLzp8GiL1yND1O7Z81cA8h9Sxxs5INiBg1WZMElJrYYQlLCSqbbbK2",
+	"x+t/HYLx19cvrtz341AWoDMRMquuIRE8JUuaaCGJe43QW8pyusihVi8OyXMJMI6KJR1zXa4zIfWTnN1C",
+	"SvAVK5/QdAOyZFJpB1BMtCALQImGL+MbqgNzAhIKwdcbRVkDUVzjI4hQumKJsTAuBFdVAaNMMOmZLaUo",
+	"LJ0a/QEpyRn/uBHGiW32UI2CUyuqgefLlgxSC8hWwt+OGILmrbGkrLf0xX5SF9wr0JXkkBLB8zW5M+4Q",
+	"JYngS6v0aU7sAIQpImHFlAYJIwGV5qOwR+kFTYBnUyYh0TeVZF37ZSPdq0SUsNU3E16ak1FdcHorq6fc",
+	"sE9TqriDp360aIB6RVagCXXeKaE8JQVdG5JyL9wkEtwniqwk5a1AQwD93Ql/MzJeZeKOG343dKqI4Daq",
+	"IbgyW18awzSesW3dkX/+RBNN/Evk96tLFZNM61IZOykXolzQ5CP+EptfkkppURA0TwG1Maea3QKhZam2",
+	"EoYNUfQkIf5u12ap2iDSubWxWfYCMpovzdwWEwbZd0xnU5j+TMILEtsGovq99BHPLk0dJGeNcNME+7wD",
+	"qQSn+QtUyu+9vH8EpwKjLGqrb0bkaU6VvjHR0a0GG92g7bEcjynCgCg3lI5vG/HtELdRDbVFoQOvg8GN",
+	"DklgG8e1aGdn+nGsJa1yrYxg+umMpHStrJ7n4i4mVJNCKCMY10Bl/WBmrG7WlvRMbZAFU4oJXrsKiNuY",
+	"wMnqhHhnRp1LoOkJeZ/BmhSV0saiQlkBKVmsiRQ51APQJBHVI0iRCaa6clp70sHoxioY17ACDM/N9zw4",
+	"3D260zF3zM/0N7qoGQ+tBADl7K8KiNXUSwayttwbI6ln9LWQuhUC8vX4mFMcXE8SXnk7oTBYshgJgdTO",
+	"+zlLHdmnIqkK4BqpvhixE+YFDsbn8nEc//d3fkgHhFnrOc1ZgiG9lRRVeQ589V0BJkSzEV/DEO50QKKD",
+	"vTGdLEoXPR0uTYsqyTA95KwRzOAQtiRMk1SA4t9r63CTNaYLgFeF9VcqzHWlkINu7+yj5pMayOOJ3NKV",
+	"yMd9NSPkAhpK5GCEubec/LJoWjB0EwVGnDFTgYT+56adw3nC0GmqoZ1gGoVV3ILMaXlj/dyA4P9vcUdy",
+	"wVehRBFd2xRRTJ49J5mopDIyPrWay4otVphVng0FwUMA7usXv77xoui1kMUQXLN362sdzHzjz1525Caa",
+	"YkCUTvOcKlrkp/hziE3bUw+HfkkV/OdzAjwRKaREuhf9ZC5GvJ4f2u9MF7eXFdrPaStiy6DP5iiPm+5g",
+	"o1BGT4QLLrbTVo+goh5JI6EOFjmonS1J+tGHQkm1F2OyPebfD5GxmllR5szVQmBuX3D4fHMN8WABGUOC",
+	"z90NHZ+M5akMWf7uM+UMECvbDfApSAz7GeN4bnq2kz8cOh+flzOt9WnIK3dPaqPaqJzOjijQXxqNDyp3",
+	"DO54rIb24w9YGOebX/igZ0DLmyHxf2maMvMHzd+1XtGygrhvOleLnCX/C+uLOpxwIQGBe2vHM2ZM4C0n",
+	"AN1LUQBiBeih3IR45rJhFodcH8wNB36XjDOVEaWh3MjgrXnjGimTKK0XFXTx3bPgOqwqqmRea6PmA3L5",
+	"KlhlIylXpZD6i0KFHbA6g06t9DWicTwe2EHEFxERUSAZzdm/ICVUkf+5fvub0ftNvGszxWyxyy3Ap5aP",
+	"SZ2XsGLj6nsieWGj+9KHNowVY+2aOr7RTU9gmNoBvJXrebniQkJap3NRAJWSFVSurSmlbCpZZ8DkuNYb",
+	"4kEyPW4oV+g9zE/ah1yPTXTr5/hzHLovyar2uWRGztN8w5z50isZeXeJqtbg1/m91hfBUGxtuzKdg1N8",
+	"xFCZoUNbKUJevLuMWpWJ0dOTs5Mz1FwlcFqy6Dz66eTs5CkGB3SGyzxFD+RUmKKBUxd1N7+vbMqkdogu",
+	"U1OFCfqFef0treOyCtWLRSJ++OzszKFPu5wNLcvcgXj6T2WVpN3U2XvfzgIN9/whHsQD66iBX9JDHP3H",
+	"lpBNAdQtTwuAcMldwcU1yFuQxFV9oTlQGM6KzjG7T3BpbTBLoQKofyfUCO6RvV6KdP1oiwvkch66tG6k",
+	"8sNg45/uAoIQcu2TdlbuIY6e73N7X9KU1Lg5QNLyHNAhr9pRoJ3qNGNIkGUu7nCMkEA4va9zhQ9WcGEQ",
+	"ZkCkr/D3AZnafy5TlDuuCk1F5/+4j5itYdKZj7ufd7KSXYKLWwjsC+I/B8T4PJBhtFiw0Duaeb6/fXPT",
+	"c2E2ouLpQRKO3cIu2WAakOkm+GMUEkayUGDNVhVfhQ7O9iuUjlQVoqpfoKvoUM9VITVXfT2y2aki9Qbr",
+	"HD26N5K1QKWtTfmaSvTINn22sfvT45wZOvrUCWr0apw52XOhx+P5tZXgsgPkjvHUpJ2VaNePJNTUjBB1",
+	"x3SSNZETk7e0Y6LaEJVJ69xxl4qeYdN6br92S/jmmH48/7JnI7oNQogCf6u3KjbZN4a5N1sPRVeU8aM8",
+	"ODirHknL85dYhsoV2wICI8in90Yi1CmASWsNI+CXqc1GzOG8eSzX5P12aZ81eZRQSKCf69g7hRnwDpy+",
+	"MCAhQ5jaEJTYD93sQlY3mfw9m2YbqdVXTsWoU2WYfv8m0vkb4J1fzG7hNnlTyO7SuDQ+vTf/zA2otFnM",
+	"/GcnbBYHR5F2usPwwjfzjYRb8fHIN98I31zhblnGqY+yNKyDHRB8wmTMdnlZv7RDwmtafYQ22TwkjNsC",
+	"XCYwcScZmBIEVWGNy7LK8/XBRmcW/QVY9C/rk9CnpueDObbQ2ohewtJtnMlEYi2UYsIWZbujVi59aaKJ",
+	"7bOiJwPH8BfQzRHsCz9vWOD9VYFcN7JKaaq3E1ZxeCDX7mTr73y/gckP+0fjNKZ3W8g2Z7hi0hw/JlQC",
+	"HuzyRe7MpoAXUtwZlN9lLMnqijfzopkrthAmQnxk0IDYzHPjzop9HVE+PPQ4JsLaLGTIaAWp9U+fnT17",
+	"NHBC5xsDAL1zZGsq2KFEA62biK9JD+F7PlaNotd+Q7VoV8T39rJPFVaHPN2jDuE+XeRzJv+1XwWGtQdM",
+	"EU1NQcRiTShvcOViTO1CzNgfRvc/mI8bVFMudAbSD3GQEvm1qz9iK244nergGX7Mw8CnJKN8BSaEg5E7",
+	"e86VMjmQ4Eg+oyFBR/uKSEiAa0wSNsUFeKqjFjdMETwf5yi3LlLxTSvMgxbwbWnv2gY5oU4oV3cgFXl2",
+	"9pwInsDIxp0Eg4fdPh3zFESrSvcwDNqRZiMByrkOYzSKowxoiou+N8rkyYUV+IMt7gl+YhWD7eHkSoxC",
+	"Cn8KMw9fXxz9tL/JewU3zDYEcgwDXFSrLG4Ivwmi7tPk5x+5CeHW1HGI8u3aKDdvphjZVnP8mKhzkiap",
+	"pETh5IV3X8RhmdSEq9DiNldRtS+Z8ZM1VfpC150RdusL8/SbVp+hTax4JLW+f9lgeESfvi2BX74iF4Jz",
+	"82KzHENc3fZCY2TVNCrapQsaaIcUMlDrnkC3viODaw5UF3hiRaG1iFrr+9qi/PDi4eiw+l4lYjkughos",
+	"nt57+nk4vXfV9r0Y3/Z2V05r99lC0ztFa7Kx3+Nx24qPGUw2ltiQkPdjrutuS5vjiZ8lCMNBxabJ0yNX",
+	"ebU3rUZHdDRTDstM6exSJ0K5V/9yDnMdZmkIEnYX7Dp8GhRRtWk0nsvzJtEuUm2DVl97zrd9o8Em32pg",
+	"TrDp66Y1joZD33CgrTxGYbpqPZmOwLzHUIqduT4ML+EWaG4O/WDUCuVSmkpQiizAnM3GJiF2qnCUpG7o",
+	"tSPOHjQMm8XZAWfIjFGHltiys1Zmj3jeDnqM0cPI6j3bp9ISghSU2/ix8l2cWl3LHdIOkjV+xo2jRDG+",
+	"yuFJpfxxfRvc7vLKaWI7402rrX4fvV3Tea9d31GRzVBkyNvzlNhRkbS4xUf3sa2FwaGN74ci/aVrPvXE",
+	"9lR4gs8noxaBdlX7Oc8XmHjOuT4LYdzO+jBpqparY/RiSDseycTSg6WX8TBG/AV5obrTmudwQo15QiV4",
+	"Ml2sydXP1+/t0aGrdxfmsKpydcdN/ghT24InELZkRun18YX9RCe3PZdxB3llhDfqTnd/Z2/gGwi1HJys",
+	"wCYcQMqQyEBlYwe0keOLyymFgxWOm4saQ7x8mT5iVeN2UUPfbQFLBw+AhJ/v050wS/8mqgSD9GmJ0XWO",
+	"mvYUrtxLu9EZ22uJs8eeexzL5rRPYzGSFXCwF3/0yxP/Nnrjktvzbo5yPDEdZIGm3SysaO6b/b4LwibK",
+	"d2/t6CBFoNXqnu2kYEvTWa7x4fSR+Lq1dYfdyYI2PXEd3buuw6dYv7SJ/N3LeC/cjpigc9XenuV/9767",
+	"0AFH8wKRoKpcH9ul9M1fRA721LJpaZLRXk/Bumy97uTUoUHAW85mEqG9Em1HVNi9H27PZNi77G1E6ijQ",
+	"RLsui0c67IT7DPp850dFMlrfVjOXEFv3PM2gRHe51K5yvsP7ufYdLA9coBXYm7fdPpzNTV1HAg2cEHYk",
+	"tlle9mkT+37PpExsz7cjuuw0JtwzRXbbDobccdsE1l2he6TAHgUi/vD6StupqiY41zyXalEwU7puu0Ha",
+	"zt80UdNp9886t0becvQeHKUQLZqLYguaQq/zOJ7h8gHycID7mhb5i2S+OP705O7u7ok5s/ekkrnrCTt/",
+	"QwYt14+pzMc+AHY8qjV1VOsgJcwLpUC6zoiY6JdEgbxlCcT1aSuFMf32bQChjGzD+1NZWMP0h3UYAUUd",
+	"tjB2msAfTwjddXA8p7DTcwrECOkA4hsCK0DTlGq6icZ+9e9tJdHNFH6Gf/9U5Bv2tr90P+lwBfZMM3Yw",
+	"WlUSUmcx7pk2LHYVSZky15AeZvYDgfSb4J0UJ5N6FNE0GZkiB9dM5Jts8TTWguF32zd7rOvCse/GsOlD",
+	"E0z114ZbbXW6gF6teHf8P5wqr7vXE2oYKBG3IA0XkR98t/kclPrRGdBM+UPt9gywooVVm3i5yAfuv/GN",
+	"BsdLYc35ePO2cldFCWkiJVCfbzr5wA2ILfh0Vj81cFTKlqgMu+jTpY01+9vTLOwnH3jQXv/Doa3p9L8r",
+	"j3X0SoF9u6/9e0FCoebmWgaHC+LuxcDt5vSWragW8qR1p+jJCvQPPx6r2A/vlK5nG2vHhqSFvS1lOq7T",
+	"4RTb3mDHrNK9fOTb82yPfHAofIAXm9vLZGjtG5o4PVOqgoHXV3OHzysP1ek4f/h0Z6NMDkOQJ+6WphmS",
+	"HF+FH3786oR0LAT8UpnfuYamfXuEW6m/lnWE7rdRDJ7wD1U3PH18zqt5Zuzk30c4rOKUIy9/U7zc1lta",
+	"g9LNdVJKCwltr8jM8fD/AwCfwkYH6ZQAAA==",
 }
 
 // GetSwagger returns the content of the embedded swagger specification file
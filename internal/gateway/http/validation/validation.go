@@ -0,0 +1,206 @@
+// Package validation - chi middleware checking REST requests, and in development
+// responses too, against the OpenAPI spec the handlers are generated from
+package validation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/bogatyr285/auth-go/internal/gateway/http/gen"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/legacy"
+)
+
+const (
+	// BodyField - field of errors about the request body as a whole, e.g. malformed JSON
+	BodyField = "body"
+
+	requestFailed  = "request doesn't match the API spec"
+	responseFailed = "response doesn't match the API spec"
+)
+
+func init() {
+	// SAML metadata is XML the spec describes as a string
+	openapi3filter.RegisterBodyDecoder("application/samlmetadata+xml", openapi3filter.FileBodyDecoder)
+}
+
+type Validator struct {
+	router            routers.Router
+	validateResponses bool
+	logger            *slog.Logger
+}
+
+type Option func(*Validator)
+
+// WithResponseValidation - responses not matching the spec are replaced with 500, a
+// development aid to catch handlers drifting from the spec
+func WithResponseValidation() Option {
+	return func(v *Validator) {
+		v.validateResponses = true
+	}
+}
+
+// New - spec is usually gen.GetSwagger(). Servers of the spec are ignored, paths are
+// matched wherever the API is served
+func New(spec *openapi3.T, logger *slog.Logger, opts ...Option) (*Validator, error) {
+	spec.Servers = nil
+
+	router, err := legacy.NewRouter(spec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load openapi spec: %s", err)
+	}
+
+	v := &Validator{
+		router: router,
+		logger: logger.With("module", "openapi-validator"),
+	}
+	for _, opt := range opts {
+		opt(v)
+	}
+
+	return v, nil
+}
+
+// Middleware - requests not matching the spec get 400 listing every offending field.
+// Paths the spec doesn't describe, e.g. of the OAuth provider, are passed through
+func (v *Validator) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route, pathParams, err := v.router.FindRoute(r)
+		if err != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		input := &openapi3filter.RequestValidationInput{
+			Request:    r,
+			PathParams: pathParams,
+			Route:      route,
+			Options: &openapi3filter.Options{
+				MultiError: true,
+				// callers are authenticated by the auth middleware
+				AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+			},
+		}
+		if err := openapi3filter.ValidateRequest(r.Context(), input); err != nil {
+			writeError(w, http.StatusBadRequest, requestFailed, fieldErrors(err, ""))
+			return
+		}
+
+		if !v.validateResponses {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		rec := &recorder{header: http.Header{}, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+
+		if err := v.validateResponse(r.Context(), input, rec); err != nil {
+			v.logger.Error("invalid response",
+				slog.String("method", r.Method),
+				slog.String("path", r.URL.Path),
+				slog.Int("status", rec.status),
+				slog.Any("err", err))
+			writeError(w, http.StatusInternalServerError, responseFailed, fieldErrors(err, ""))
+			return
+		}
+
+		for k, values := range rec.header {
+			w.Header()[k] = values
+		}
+		w.WriteHeader(rec.status)
+		w.Write(rec.body.Bytes())
+	})
+}
+
+func (v *Validator) validateResponse(ctx context.Context, req *openapi3filter.RequestValidationInput, rec *recorder) error {
+	input := &openapi3filter.ResponseValidationInput{
+		RequestValidationInput: req,
+		Status:                 rec.status,
+		Header:                 rec.header,
+		Options:                &openapi3filter.Options{MultiError: true},
+	}
+	input.SetBodyBytes(rec.body.Bytes())
+
+	return openapi3filter.ValidateResponse(ctx, input)
+}
+
+// fieldErrors - offending fields of the validation error, field is the parameter or
+// body the nested errors are about
+func fieldErrors(err error, field string) []gen.FieldError {
+	switch e := err.(type) {
+	case openapi3.MultiError:
+		var res []gen.FieldError
+		for _, err := range e {
+			res = append(res, fieldErrors(err, field)...)
+		}
+		return res
+	case *openapi3filter.RequestError:
+		switch {
+		case e.Parameter != nil:
+			field = e.Parameter.Name
+		case e.RequestBody != nil:
+			field = BodyField
+		}
+
+		if e.Err == nil {
+			return []gen.FieldError{{Field: field, Message: e.Reason}}
+		}
+		return fieldErrors(e.Err, field)
+	case *openapi3filter.ResponseError:
+		if e.Err == nil {
+			return []gen.FieldError{{Field: BodyField, Message: e.Reason}}
+		}
+		return fieldErrors(e.Err, BodyField)
+	case *openapi3.SchemaError:
+		// body fields are named by their path, parameters by their name
+		if path := e.JSONPointer(); len(path) > 0 && (field == BodyField || field == "") {
+			field = strings.Join(path, ".")
+		}
+		if field == "" {
+			field = BodyField
+		}
+		return []gen.FieldError{{Field: field, Message: e.Reason}}
+	default:
+		if field == "" {
+			field = BodyField
+		}
+		return []gen.FieldError{{Field: field, Message: err.Error()}}
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, msg string, fields []gen.FieldError) {
+	res := gen.ErrorResponse{Error: msg}
+	if len(fields) > 0 {
+		res.Fields = &fields
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(res)
+}
+
+// recorder - response held back until it's validated
+type recorder struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (r *recorder) Header() http.Header {
+	return r.header
+}
+
+func (r *recorder) WriteHeader(status int) {
+	r.status = status
+}
+
+func (r *recorder) Write(b []byte) (int, error) {
+	return r.body.Write(b)
+}
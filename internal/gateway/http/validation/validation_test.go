@@ -0,0 +1,182 @@
+package validation_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bogatyr285/auth-go/internal/gateway/http/gen"
+	"github.com/bogatyr285/auth-go/internal/gateway/http/validation"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+)
+
+// newServer - the handler answers every request with the response
+func newServer(t *testing.T, response string, opts ...validation.Option) (*httptest.Server, *bool) {
+	spec, err := gen.GetSwagger()
+	require.NoError(t, err)
+
+	v, err := validation.New(spec, slog.New(slog.NewTextHandler(io.Discard, nil)), opts...)
+	require.NoError(t, err)
+
+	called := new(bool)
+	router := chi.NewRouter()
+	router.Use(v.Middleware)
+	router.HandleFunc("/*", func(w http.ResponseWriter, r *http.Request) {
+		*called = true
+
+		// the body is still readable by the handler
+		_, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(response))
+	})
+
+	srv := httptest.NewServer(router)
+	t.Cleanup(srv.Close)
+
+	return srv, called
+}
+
+func do(t *testing.T, srv *httptest.Server, method, path, body string) (int, gen.ErrorResponse) {
+	req, err := http.NewRequest(method, srv.URL+path, strings.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := srv.Client().Do(req)
+	require.NoError(t, err)
+	defer res.Body.Close()
+
+	var errRes gen.ErrorResponse
+	if res.StatusCode >= http.StatusBadRequest {
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&errRes))
+	}
+
+	return res.StatusCode, errRes
+}
+
+func fields(res gen.ErrorResponse) []string {
+	if res.Fields == nil {
+		return nil
+	}
+
+	var names []string
+	for _, f := range *res.Fields {
+		names = append(names, f.Field)
+	}
+	return names
+}
+
+func TestRequestValidation(t *testing.T) {
+	srv, called := newServer(t, `{}`)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		// fields - offending fields, nil when the request is valid
+		fields []string
+	}{
+		{
+			name:   "valid",
+			method: http.MethodPost,
+			path:   "/register",
+			body:   `{"username": "alice@example.com", "password": "rLy_5tr0nG!"}`,
+		},
+		{
+			name:   "empty credentials",
+			method: http.MethodPost,
+			path:   "/register",
+			body:   `{"username": "", "password": ""}`,
+			fields: []string{"username", "password"},
+		},
+		{
+			name:   "missing credentials",
+			method: http.MethodPost,
+			path:   "/login",
+			body:   `{"username": "alice@example.com"}`,
+			fields: []string{"password"},
+		},
+		{
+			name:   "wrong type",
+			method: http.MethodPost,
+			path:   "/register",
+			body:   `{"username": "alice@example.com", "password": "rLy_5tr0nG!", "age": "ten"}`,
+			fields: []string{"age"},
+		},
+		{
+			name:   "malformed body",
+			method: http.MethodPost,
+			path:   "/register",
+			body:   `{"username":`,
+			fields: []string{validation.BodyField},
+		},
+		{
+			name:   "path parameter",
+			method: http.MethodGet,
+			path:   "/users/alice",
+			fields: []string{"id"},
+		},
+		{
+			name:   "not in the spec",
+			method: http.MethodPost,
+			path:   "/oauth/token",
+			body:   `grant_type=client_credentials`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			*called = false
+			status, res := do(t, srv, tt.method, tt.path, tt.body)
+
+			if tt.fields == nil {
+				require.Equal(t, http.StatusOK, status)
+				require.True(t, *called)
+				return
+			}
+
+			require.Equal(t, http.StatusBadRequest, status)
+			require.False(t, *called)
+			require.NotEmpty(t, res.Error)
+			require.ElementsMatch(t, tt.fields, fields(res))
+			for _, f := range *res.Fields {
+				require.NotEmpty(t, f.Message)
+			}
+		})
+	}
+}
+
+const buildInfo = `{"version": %s, "commit_hash": "abc", "build_date": "2024-01-01", "go_version": "go1.22",
+	"os": "linux", "arch": "amd64", "compiler": "gc"}`
+
+func TestResponseValidation(t *testing.T) {
+	t.Run("invalid responses are replaced", func(t *testing.T) {
+		srv, _ := newServer(t, fmt.Sprintf(buildInfo, `1`), validation.WithResponseValidation())
+
+		status, res := do(t, srv, http.MethodGet, "/buildinfo", "")
+		require.Equal(t, http.StatusInternalServerError, status)
+		require.Equal(t, []string{"version"}, fields(res))
+	})
+
+	t.Run("valid responses are passed", func(t *testing.T) {
+		srv, _ := newServer(t, fmt.Sprintf(buildInfo, `"v1.0.0"`), validation.WithResponseValidation())
+
+		status, _ := do(t, srv, http.MethodGet, "/buildinfo", "")
+		require.Equal(t, http.StatusOK, status)
+	})
+
+	t.Run("off by default", func(t *testing.T) {
+		srv, _ := newServer(t, fmt.Sprintf(buildInfo, `1`))
+
+		status, _ := do(t, srv, http.MethodGet, "/buildinfo", "")
+		require.Equal(t, http.StatusOK, status)
+	})
+}
@@ -0,0 +1,122 @@
+// Package mux serves REST, the grpc-gateway and native gRPC on a single port
+package mux
+
+import (
+	"context"
+	"crypto/tls"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/bogatyr285/auth-go/pkg/service"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+)
+
+// Handler - routes gRPC requests to grpc, requests under gatewayPrefix to gateway
+// and the rest to rest
+func Handler(grpc, gateway http.Handler, gatewayPrefix string, rest http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case isGRPC(r):
+			grpc.ServeHTTP(w, r)
+		case strings.HasPrefix(r.URL.Path, gatewayPrefix):
+			gateway.ServeHTTP(w, r)
+		default:
+			rest.ServeHTTP(w, r)
+		}
+	})
+}
+
+// isGRPC - gRPC calls are HTTP/2 requests of application/grpc content type with an
+// optional codec suffix, e.g. application/grpc+proto. gRPC-Web isn't one of them
+func isGRPC(r *http.Request) bool {
+	if r.ProtoMajor != 2 {
+		return false
+	}
+
+	contentType := r.Header.Get("Content-Type")
+	if !strings.HasPrefix(contentType, "application/grpc") {
+		return false
+	}
+
+	rest := contentType[len("application/grpc"):]
+	return rest == "" || rest[0] == '+' || rest[0] == ';'
+}
+
+type Config struct {
+	Address string
+	// TLS - HTTP/2 is negotiated with ALPN, plaintext connections speak HTTP/1.1 or
+	// HTTP/2 with prior knowledge (h2c) when nil
+	TLS               *tls.Config
+	ReadHeaderTimeout time.Duration
+	IdleTimeout       time.Duration
+	// ShutdownTimeout - how long Stop waits for requests in flight
+	ShutdownTimeout time.Duration
+}
+
+// Server - service.Service serving a Handler. Closers, e.g. the gRPC server, are
+// closed on Stop once the requests in flight are done
+type Server struct {
+	server  *service.HTTPServer
+	closers []io.Closer
+	logger  *slog.Logger
+}
+
+func NewServer(cfg Config, handler http.Handler, logger *slog.Logger, closers ...io.Closer) (*Server, error) {
+	logger = logger.With("module", "mux")
+
+	srv := &http.Server{
+		Addr:              cfg.Address,
+		TLSConfig:         cfg.TLS,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+	}
+
+	// the same HTTP/2 server handles h2c connections, so they get GOAWAY on shutdown too
+	h2s := &http2.Server{IdleTimeout: cfg.IdleTimeout}
+	if err := http2.ConfigureServer(srv, h2s); err != nil {
+		return nil, err
+	}
+
+	if cfg.TLS == nil {
+		// ConfigureServer sets an empty TLS config, which would make the server serve TLS
+		srv.TLSConfig = nil
+		handler = h2c.NewHandler(handler, h2s)
+	}
+	srv.Handler = handler
+
+	return &Server{
+		server:  service.NewHTTPServer(srv, cfg.ShutdownTimeout, logger),
+		closers: closers,
+		logger:  logger,
+	}, nil
+}
+
+func (s *Server) Init(ctx context.Context) error {
+	return s.server.Init(ctx)
+}
+
+// Addr - address the server listens on, known once it is initialized
+func (s *Server) Addr() net.Addr {
+	return s.server.Addr()
+}
+
+func (s *Server) Run(ctx context.Context) error {
+	return s.server.Run(ctx)
+}
+
+// Stop - waits for requests in flight up to the shutdown timeout, then closes the
+// closers. Calls on h2c connections aren't waited for, they're hijacked from the server
+func (s *Server) Stop() {
+	s.server.Stop()
+
+	for _, c := range s.closers {
+		if err := c.Close(); err != nil {
+			s.logger.Error("failed to close", slog.Any("err", err))
+		}
+	}
+}
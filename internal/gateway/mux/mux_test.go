@@ -0,0 +1,184 @@
+package mux_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io"
+	"log/slog"
+	"math/big"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bogatyr285/auth-go/internal/gateway/mux"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// named - responds with its name and the protocol of the request
+func named(name string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, name+" "+r.Proto)
+	})
+}
+
+type closerFunc func() error
+
+func (f closerFunc) Close() error { return f() }
+
+// selfSigned - certificate of localhost
+func selfSigned(t *testing.T) tls.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		DNSNames:     []string{"localhost"},
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
+// serve - address of a running server sharing the port between a gRPC health
+// server, a gateway under /api/v1/ and REST
+func serve(t *testing.T, tlsConfig *tls.Config) string {
+	grpcSrv := grpc.NewServer()
+	grpc_health_v1.RegisterHealthServer(grpcSrv, health.NewServer())
+
+	srv, err := mux.NewServer(mux.Config{
+		Address:         "127.0.0.1:0",
+		TLS:             tlsConfig,
+		ShutdownTimeout: time.Second,
+	},
+		mux.Handler(grpcSrv, named("gateway"), "/api/v1/", named("rest")),
+		slog.New(slog.NewTextHandler(io.Discard, nil)),
+		closerFunc(func() error {
+			grpcSrv.Stop()
+			return nil
+		}),
+	)
+	require.NoError(t, err)
+	require.NoError(t, srv.Init(context.Background()))
+
+	go srv.Run(context.Background())
+	t.Cleanup(srv.Stop)
+
+	return srv.Addr().String()
+}
+
+func checkHealth(t *testing.T, addr string, creds credentials.TransportCredentials) {
+	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(creds))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	res, err := grpc_health_v1.NewHealthClient(conn).Check(ctx, &grpc_health_v1.HealthCheckRequest{})
+	require.NoError(t, err)
+	require.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, res.GetStatus())
+}
+
+func get(t *testing.T, client *http.Client, url, contentType string) string {
+	req, err := http.NewRequest(http.MethodPost, url, strings.NewReader(""))
+	require.NoError(t, err)
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	res, err := client.Do(req)
+	require.NoError(t, err)
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+
+	return string(body)
+}
+
+func TestPlaintext(t *testing.T) {
+	addr := serve(t, nil)
+
+	t.Run("grpc over h2c", func(t *testing.T) {
+		checkHealth(t, addr, insecure.NewCredentials())
+	})
+
+	t.Run("http", func(t *testing.T) {
+		client := &http.Client{}
+		require.Equal(t, "rest HTTP/1.1", get(t, client, "http://"+addr+"/login", ""))
+		require.Equal(t, "gateway HTTP/1.1", get(t, client, "http://"+addr+"/api/v1/login", ""))
+		// gRPC-Web isn't native gRPC
+		require.Equal(t, "rest HTTP/1.1", get(t, client, "http://"+addr+"/login", "application/grpc-web"))
+	})
+}
+
+func TestTLS(t *testing.T) {
+	cert := selfSigned(t)
+	addr := serve(t, &tls.Config{Certificates: []tls.Certificate{cert}})
+
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	require.NoError(t, err)
+	roots := x509.NewCertPool()
+	roots.AddCert(leaf)
+	clientTLS := &tls.Config{RootCAs: roots, ServerName: "localhost"}
+
+	t.Run("grpc", func(t *testing.T) {
+		checkHealth(t, addr, credentials.NewTLS(clientTLS))
+	})
+
+	t.Run("http/2 negotiated with alpn", func(t *testing.T) {
+		// the transport adds h2 to NextProtos of the config
+		client := &http.Client{Transport: &http.Transport{TLSClientConfig: clientTLS.Clone(), ForceAttemptHTTP2: true}}
+		require.Equal(t, "rest HTTP/2.0", get(t, client, "https://"+addr+"/login", ""))
+		require.Equal(t, "gateway HTTP/2.0", get(t, client, "https://"+addr+"/api/v1/login", ""))
+	})
+
+	t.Run("http/1.1", func(t *testing.T) {
+		client := &http.Client{Transport: &http.Transport{TLSClientConfig: clientTLS.Clone()}}
+		require.Equal(t, "rest HTTP/1.1", get(t, client, "https://"+addr+"/login", ""))
+	})
+}
+
+func TestStopClosesClosers(t *testing.T) {
+	grpcSrv := grpc.NewServer()
+	closed := false
+
+	srv, err := mux.NewServer(mux.Config{Address: "127.0.0.1:0", ShutdownTimeout: time.Second},
+		mux.Handler(grpcSrv, named("gateway"), "/api/v1/", named("rest")),
+		slog.New(slog.NewTextHandler(io.Discard, nil)),
+		closerFunc(func() error {
+			closed = true
+			return nil
+		}),
+	)
+	require.NoError(t, err)
+	require.NoError(t, srv.Init(context.Background()))
+
+	ran := make(chan error)
+	go func() { ran <- srv.Run(context.Background()) }()
+
+	srv.Stop()
+	require.NoError(t, <-ran)
+	require.True(t, closed)
+
+	_, err = net.Dial("tcp", srv.Addr().String())
+	require.Error(t, err)
+}
@@ -0,0 +1,234 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: account.go
+//
+// Generated by this command:
+//
+//	mockgen -source=account.go -destination=../../mocks/account_mock.go -package mocks -exclude_interfaces=CryptoPassword,JWTManager,Publisher -mock_names=Repository=MockAccountRepository
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/bogatyr285/auth-go/internal/auth/entity"
+	uuid "github.com/google/uuid"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockAccountRepository is a mock of Repository interface.
+type MockAccountRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAccountRepositoryMockRecorder
+}
+
+// MockAccountRepositoryMockRecorder is the mock recorder for MockAccountRepository.
+type MockAccountRepositoryMockRecorder struct {
+	mock *MockAccountRepository
+}
+
+// NewMockAccountRepository creates a new mock instance.
+func NewMockAccountRepository(ctrl *gomock.Controller) *MockAccountRepository {
+	mock := &MockAccountRepository{ctrl: ctrl}
+	mock.recorder = &MockAccountRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAccountRepository) EXPECT() *MockAccountRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ExistsTokenByUserID mocks base method.
+func (m *MockAccountRepository) ExistsTokenByUserID(ctx context.Context, userID int) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExistsTokenByUserID", ctx, userID)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExistsTokenByUserID indicates an expected call of ExistsTokenByUserID.
+func (mr *MockAccountRepositoryMockRecorder) ExistsTokenByUserID(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExistsTokenByUserID", reflect.TypeOf((*MockAccountRepository)(nil).ExistsTokenByUserID), ctx, userID)
+}
+
+// ExistsUserByUsername mocks base method.
+func (m *MockAccountRepository) ExistsUserByUsername(ctx context.Context, username string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExistsUserByUsername", ctx, username)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExistsUserByUsername indicates an expected call of ExistsUserByUsername.
+func (mr *MockAccountRepositoryMockRecorder) ExistsUserByUsername(ctx, username any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExistsUserByUsername", reflect.TypeOf((*MockAccountRepository)(nil).ExistsUserByUsername), ctx, username)
+}
+
+// FindUserByEmail mocks base method.
+func (m *MockAccountRepository) FindUserByEmail(ctx context.Context, username string) (entity.UserAccount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindUserByEmail", ctx, username)
+	ret0, _ := ret[0].(entity.UserAccount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindUserByEmail indicates an expected call of FindUserByEmail.
+func (mr *MockAccountRepositoryMockRecorder) FindUserByEmail(ctx, username any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindUserByEmail", reflect.TypeOf((*MockAccountRepository)(nil).FindUserByEmail), ctx, username)
+}
+
+// GenerateUserToken mocks base method.
+func (m *MockAccountRepository) GenerateUserToken(ctx context.Context, userID int) (uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GenerateUserToken", ctx, userID)
+	ret0, _ := ret[0].(uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GenerateUserToken indicates an expected call of GenerateUserToken.
+func (mr *MockAccountRepositoryMockRecorder) GenerateUserToken(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateUserToken", reflect.TypeOf((*MockAccountRepository)(nil).GenerateUserToken), ctx, userID)
+}
+
+// GetIdentity mocks base method.
+func (m *MockAccountRepository) GetIdentity(ctx context.Context, provider, subject string) (entity.Identity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIdentity", ctx, provider, subject)
+	ret0, _ := ret[0].(entity.Identity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIdentity indicates an expected call of GetIdentity.
+func (mr *MockAccountRepositoryMockRecorder) GetIdentity(ctx, provider, subject any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIdentity", reflect.TypeOf((*MockAccountRepository)(nil).GetIdentity), ctx, provider, subject)
+}
+
+// GetUserById mocks base method.
+func (m *MockAccountRepository) GetUserById(ctx context.Context, ID int) (entity.UserAccount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserById", ctx, ID)
+	ret0, _ := ret[0].(entity.UserAccount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserById indicates an expected call of GetUserById.
+func (mr *MockAccountRepositoryMockRecorder) GetUserById(ctx, ID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserById", reflect.TypeOf((*MockAccountRepository)(nil).GetUserById), ctx, ID)
+}
+
+// GrantUserRole mocks base method.
+func (m *MockAccountRepository) GrantUserRole(ctx context.Context, userID int, role string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GrantUserRole", ctx, userID, role)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// GrantUserRole indicates an expected call of GrantUserRole.
+func (mr *MockAccountRepositoryMockRecorder) GrantUserRole(ctx, userID, role any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GrantUserRole", reflect.TypeOf((*MockAccountRepository)(nil).GrantUserRole), ctx, userID, role)
+}
+
+// ListUserRoles mocks base method.
+func (m *MockAccountRepository) ListUserRoles(ctx context.Context, userID int) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUserRoles", ctx, userID)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUserRoles indicates an expected call of ListUserRoles.
+func (mr *MockAccountRepositoryMockRecorder) ListUserRoles(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUserRoles", reflect.TypeOf((*MockAccountRepository)(nil).ListUserRoles), ctx, userID)
+}
+
+// ListWebAuthnCredentials mocks base method.
+func (m *MockAccountRepository) ListWebAuthnCredentials(ctx context.Context, userID int) ([]entity.WebAuthnCredential, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWebAuthnCredentials", ctx, userID)
+	ret0, _ := ret[0].([]entity.WebAuthnCredential)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWebAuthnCredentials indicates an expected call of ListWebAuthnCredentials.
+func (mr *MockAccountRepositoryMockRecorder) ListWebAuthnCredentials(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebAuthnCredentials", reflect.TypeOf((*MockAccountRepository)(nil).ListWebAuthnCredentials), ctx, userID)
+}
+
+// ProvisionFederatedUser mocks base method.
+func (m *MockAccountRepository) ProvisionFederatedUser(ctx context.Context, u entity.UserAccount, identity entity.Identity) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProvisionFederatedUser", ctx, u, identity)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ProvisionFederatedUser indicates an expected call of ProvisionFederatedUser.
+func (mr *MockAccountRepositoryMockRecorder) ProvisionFederatedUser(ctx, u, identity any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProvisionFederatedUser", reflect.TypeOf((*MockAccountRepository)(nil).ProvisionFederatedUser), ctx, u, identity)
+}
+
+// RegisterUser mocks base method.
+func (m *MockAccountRepository) RegisterUser(ctx context.Context, u entity.UserAccount) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegisterUser", ctx, u)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RegisterUser indicates an expected call of RegisterUser.
+func (mr *MockAccountRepositoryMockRecorder) RegisterUser(ctx, u any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterUser", reflect.TypeOf((*MockAccountRepository)(nil).RegisterUser), ctx, u)
+}
+
+// RevokeUserRole mocks base method.
+func (m *MockAccountRepository) RevokeUserRole(ctx context.Context, userID int, role string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeUserRole", ctx, userID, role)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeUserRole indicates an expected call of RevokeUserRole.
+func (mr *MockAccountRepositoryMockRecorder) RevokeUserRole(ctx, userID, role any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeUserRole", reflect.TypeOf((*MockAccountRepository)(nil).RevokeUserRole), ctx, userID, role)
+}
+
+// SelectUserByToken mocks base method.
+func (m *MockAccountRepository) SelectUserByToken(ctx context.Context, token string) (entity.UserAccount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectUserByToken", ctx, token)
+	ret0, _ := ret[0].(entity.UserAccount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectUserByToken indicates an expected call of SelectUserByToken.
+func (mr *MockAccountRepositoryMockRecorder) SelectUserByToken(ctx, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectUserByToken", reflect.TypeOf((*MockAccountRepository)(nil).SelectUserByToken), ctx, token)
+}
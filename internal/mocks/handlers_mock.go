@@ -3,15 +3,16 @@
 //
 // Generated by this command:
 //
-//	mockgen -source=handlers.go -destination=../../../mocks/handlers_mock.go -package mock
+//	mockgen -source=handlers.go -destination=../../../mocks/handlers_mock.go -package mocks
 //
 
-// Package mock is a generated GoMock package.
+// Package mocks is a generated GoMock package.
 package mocks
 
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	entity "github.com/bogatyr285/auth-go/internal/auth/entity"
 	jwt "github.com/golang-jwt/jwt/v5"
@@ -41,6 +42,50 @@ func (m *MockUserRepository) EXPECT() *MockUserRepositoryMockRecorder {
 	return m.recorder
 }
 
+// ConsumeOTP mocks base method.
+func (m *MockUserRepository) ConsumeOTP(ctx context.Context, ID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConsumeOTP", ctx, ID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ConsumeOTP indicates an expected call of ConsumeOTP.
+func (mr *MockUserRepositoryMockRecorder) ConsumeOTP(ctx, ID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConsumeOTP", reflect.TypeOf((*MockUserRepository)(nil).ConsumeOTP), ctx, ID)
+}
+
+// CountOTPsSince mocks base method.
+func (m *MockUserRepository) CountOTPsSince(ctx context.Context, phone string, since time.Time) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountOTPsSince", ctx, phone, since)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountOTPsSince indicates an expected call of CountOTPsSince.
+func (mr *MockUserRepositoryMockRecorder) CountOTPsSince(ctx, phone, since any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountOTPsSince", reflect.TypeOf((*MockUserRepository)(nil).CountOTPsSince), ctx, phone, since)
+}
+
+// FindActiveOTP mocks base method.
+func (m *MockUserRepository) FindActiveOTP(ctx context.Context, phone, purpose string) (entity.OTP, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindActiveOTP", ctx, phone, purpose)
+	ret0, _ := ret[0].(entity.OTP)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindActiveOTP indicates an expected call of FindActiveOTP.
+func (mr *MockUserRepositoryMockRecorder) FindActiveOTP(ctx, phone, purpose any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindActiveOTP", reflect.TypeOf((*MockUserRepository)(nil).FindActiveOTP), ctx, phone, purpose)
+}
+
 // FindUserByEmail mocks base method.
 func (m *MockUserRepository) FindUserByEmail(ctx context.Context, username string) (entity.UserAccount, error) {
 	m.ctrl.T.Helper()
@@ -56,18 +101,135 @@ func (mr *MockUserRepositoryMockRecorder) FindUserByEmail(ctx, username any) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindUserByEmail", reflect.TypeOf((*MockUserRepository)(nil).FindUserByEmail), ctx, username)
 }
 
-// RegisterUser mocks base method.
-func (m *MockUserRepository) RegisterUser(ctx context.Context, u entity.UserAccount) error {
+// FindUserByPendingPhone mocks base method.
+func (m *MockUserRepository) FindUserByPendingPhone(ctx context.Context, phone string) (entity.UserAccount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindUserByPendingPhone", ctx, phone)
+	ret0, _ := ret[0].(entity.UserAccount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindUserByPendingPhone indicates an expected call of FindUserByPendingPhone.
+func (mr *MockUserRepositoryMockRecorder) FindUserByPendingPhone(ctx, phone any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindUserByPendingPhone", reflect.TypeOf((*MockUserRepository)(nil).FindUserByPendingPhone), ctx, phone)
+}
+
+// FindUserByPhone mocks base method.
+func (m *MockUserRepository) FindUserByPhone(ctx context.Context, phone string) (entity.UserAccount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindUserByPhone", ctx, phone)
+	ret0, _ := ret[0].(entity.UserAccount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindUserByPhone indicates an expected call of FindUserByPhone.
+func (mr *MockUserRepositoryMockRecorder) FindUserByPhone(ctx, phone any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindUserByPhone", reflect.TypeOf((*MockUserRepository)(nil).FindUserByPhone), ctx, phone)
+}
+
+// GetUserProfile mocks base method.
+func (m *MockUserRepository) GetUserProfile(ctx context.Context, userID int) (entity.UserAccount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserProfile", ctx, userID)
+	ret0, _ := ret[0].(entity.UserAccount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserProfile indicates an expected call of GetUserProfile.
+func (mr *MockUserRepositoryMockRecorder) GetUserProfile(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserProfile", reflect.TypeOf((*MockUserRepository)(nil).GetUserProfile), ctx, userID)
+}
+
+// GrantUserRole mocks base method.
+func (m *MockUserRepository) GrantUserRole(ctx context.Context, userID int, role string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GrantUserRole", ctx, userID, role)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// GrantUserRole indicates an expected call of GrantUserRole.
+func (mr *MockUserRepositoryMockRecorder) GrantUserRole(ctx, userID, role any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GrantUserRole", reflect.TypeOf((*MockUserRepository)(nil).GrantUserRole), ctx, userID, role)
+}
+
+// ListUserRoles mocks base method.
+func (m *MockUserRepository) ListUserRoles(ctx context.Context, userID int) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUserRoles", ctx, userID)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUserRoles indicates an expected call of ListUserRoles.
+func (mr *MockUserRepositoryMockRecorder) ListUserRoles(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUserRoles", reflect.TypeOf((*MockUserRepository)(nil).ListUserRoles), ctx, userID)
+}
+
+// RegisterOTPAttempt mocks base method.
+func (m *MockUserRepository) RegisterOTPAttempt(ctx context.Context, ID, maxAttempts int) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegisterOTPAttempt", ctx, ID, maxAttempts)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RegisterOTPAttempt indicates an expected call of RegisterOTPAttempt.
+func (mr *MockUserRepositoryMockRecorder) RegisterOTPAttempt(ctx, ID, maxAttempts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterOTPAttempt", reflect.TypeOf((*MockUserRepository)(nil).RegisterOTPAttempt), ctx, ID, maxAttempts)
+}
+
+// RevokeUserRole mocks base method.
+func (m *MockUserRepository) RevokeUserRole(ctx context.Context, userID int, role string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeUserRole", ctx, userID, role)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeUserRole indicates an expected call of RevokeUserRole.
+func (mr *MockUserRepositoryMockRecorder) RevokeUserRole(ctx, userID, role any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeUserRole", reflect.TypeOf((*MockUserRepository)(nil).RevokeUserRole), ctx, userID, role)
+}
+
+// SaveOTP mocks base method.
+func (m *MockUserRepository) SaveOTP(ctx context.Context, otp entity.OTP) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "RegisterUser", ctx, u)
+	ret := m.ctrl.Call(m, "SaveOTP", ctx, otp)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// RegisterUser indicates an expected call of RegisterUser.
-func (mr *MockUserRepositoryMockRecorder) RegisterUser(ctx, u any) *gomock.Call {
+// SaveOTP indicates an expected call of SaveOTP.
+func (mr *MockUserRepositoryMockRecorder) SaveOTP(ctx, otp any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterUser", reflect.TypeOf((*MockUserRepository)(nil).RegisterUser), ctx, u)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveOTP", reflect.TypeOf((*MockUserRepository)(nil).SaveOTP), ctx, otp)
+}
+
+// SetPhoneVerified mocks base method.
+func (m *MockUserRepository) SetPhoneVerified(ctx context.Context, phone string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetPhoneVerified", ctx, phone)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetPhoneVerified indicates an expected call of SetPhoneVerified.
+func (mr *MockUserRepositoryMockRecorder) SetPhoneVerified(ctx, phone any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPhoneVerified", reflect.TypeOf((*MockUserRepository)(nil).SetPhoneVerified), ctx, phone)
 }
 
 // MockCryptoPassword is a mock of CryptoPassword interface.
@@ -160,6 +322,21 @@ func (mr *MockJWTManagerMockRecorder) IssueToken(userID any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IssueToken", reflect.TypeOf((*MockJWTManager)(nil).IssueToken), userID)
 }
 
+// IssueTokenWithClaims mocks base method.
+func (m *MockJWTManager) IssueTokenWithClaims(userID string, extra jwt.MapClaims) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IssueTokenWithClaims", userID, extra)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IssueTokenWithClaims indicates an expected call of IssueTokenWithClaims.
+func (mr *MockJWTManagerMockRecorder) IssueTokenWithClaims(userID, extra any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IssueTokenWithClaims", reflect.TypeOf((*MockJWTManager)(nil).IssueTokenWithClaims), userID, extra)
+}
+
 // VerifyToken mocks base method.
 func (m *MockJWTManager) VerifyToken(tokenString string) (*jwt.Token, error) {
 	m.ctrl.T.Helper()
@@ -174,3 +351,137 @@ func (mr *MockJWTManagerMockRecorder) VerifyToken(tokenString any) *gomock.Call
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyToken", reflect.TypeOf((*MockJWTManager)(nil).VerifyToken), tokenString)
 }
+
+// MockAccessTokenRepository is a mock of AccessTokenRepository interface.
+type MockAccessTokenRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAccessTokenRepositoryMockRecorder
+}
+
+// MockAccessTokenRepositoryMockRecorder is the mock recorder for MockAccessTokenRepository.
+type MockAccessTokenRepositoryMockRecorder struct {
+	mock *MockAccessTokenRepository
+}
+
+// NewMockAccessTokenRepository creates a new mock instance.
+func NewMockAccessTokenRepository(ctrl *gomock.Controller) *MockAccessTokenRepository {
+	mock := &MockAccessTokenRepository{ctrl: ctrl}
+	mock.recorder = &MockAccessTokenRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAccessTokenRepository) EXPECT() *MockAccessTokenRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ExistsUserByUsername mocks base method.
+func (m *MockAccessTokenRepository) ExistsUserByUsername(ctx context.Context, username string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExistsUserByUsername", ctx, username)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExistsUserByUsername indicates an expected call of ExistsUserByUsername.
+func (mr *MockAccessTokenRepositoryMockRecorder) ExistsUserByUsername(ctx, username any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExistsUserByUsername", reflect.TypeOf((*MockAccessTokenRepository)(nil).ExistsUserByUsername), ctx, username)
+}
+
+// GetPersonalAccessToken mocks base method.
+func (m *MockAccessTokenRepository) GetPersonalAccessToken(ctx context.Context, tokenHash string) (entity.PersonalAccessToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPersonalAccessToken", ctx, tokenHash)
+	ret0, _ := ret[0].(entity.PersonalAccessToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPersonalAccessToken indicates an expected call of GetPersonalAccessToken.
+func (mr *MockAccessTokenRepositoryMockRecorder) GetPersonalAccessToken(ctx, tokenHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPersonalAccessToken", reflect.TypeOf((*MockAccessTokenRepository)(nil).GetPersonalAccessToken), ctx, tokenHash)
+}
+
+// GetUserById mocks base method.
+func (m *MockAccessTokenRepository) GetUserById(ctx context.Context, ID int) (entity.UserAccount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserById", ctx, ID)
+	ret0, _ := ret[0].(entity.UserAccount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserById indicates an expected call of GetUserById.
+func (mr *MockAccessTokenRepositoryMockRecorder) GetUserById(ctx, ID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserById", reflect.TypeOf((*MockAccessTokenRepository)(nil).GetUserById), ctx, ID)
+}
+
+// ListUserRoles mocks base method.
+func (m *MockAccessTokenRepository) ListUserRoles(ctx context.Context, userID int) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUserRoles", ctx, userID)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUserRoles indicates an expected call of ListUserRoles.
+func (mr *MockAccessTokenRepositoryMockRecorder) ListUserRoles(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUserRoles", reflect.TypeOf((*MockAccessTokenRepository)(nil).ListUserRoles), ctx, userID)
+}
+
+// TouchPersonalAccessToken mocks base method.
+func (m *MockAccessTokenRepository) TouchPersonalAccessToken(ctx context.Context, ID string, usedAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TouchPersonalAccessToken", ctx, ID, usedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TouchPersonalAccessToken indicates an expected call of TouchPersonalAccessToken.
+func (mr *MockAccessTokenRepositoryMockRecorder) TouchPersonalAccessToken(ctx, ID, usedAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TouchPersonalAccessToken", reflect.TypeOf((*MockAccessTokenRepository)(nil).TouchPersonalAccessToken), ctx, ID, usedAt)
+}
+
+// MockSMSSender is a mock of SMSSender interface.
+type MockSMSSender struct {
+	ctrl     *gomock.Controller
+	recorder *MockSMSSenderMockRecorder
+}
+
+// MockSMSSenderMockRecorder is the mock recorder for MockSMSSender.
+type MockSMSSenderMockRecorder struct {
+	mock *MockSMSSender
+}
+
+// NewMockSMSSender creates a new mock instance.
+func NewMockSMSSender(ctrl *gomock.Controller) *MockSMSSender {
+	mock := &MockSMSSender{ctrl: ctrl}
+	mock.recorder = &MockSMSSenderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSMSSender) EXPECT() *MockSMSSenderMockRecorder {
+	return m.recorder
+}
+
+// SendSMS mocks base method.
+func (m *MockSMSSender) SendSMS(ctx context.Context, phone, text string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendSMS", ctx, phone, text)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendSMS indicates an expected call of SendSMS.
+func (mr *MockSMSSenderMockRecorder) SendSMS(ctx, phone, text any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendSMS", reflect.TypeOf((*MockSMSSender)(nil).SendSMS), ctx, phone, text)
+}
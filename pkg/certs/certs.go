@@ -0,0 +1,207 @@
+// Package certs - TLS configs of servers and clients with certificates read from disk.
+// Rotated files are picked up by the next handshake, without a restart
+package certs
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+)
+
+// Files - PEM files, all of them are optional for clients
+type Files struct {
+	CertFile string
+	KeyFile  string
+	// CAFile - CA bundle the peer certificate must be signed by. Servers require client
+	// certificates when it is set (mutual TLS), clients use system roots when it is empty
+	CAFile string
+}
+
+// Store - key pair and CA bundle of Files. The files are checked for changes at most
+// once per interval, on handshakes. Failed reloads keep the previous certificates
+type Store struct {
+	files    Files
+	interval time.Duration
+	logger   *slog.Logger
+
+	mu       sync.Mutex
+	checked  time.Time
+	modTimes map[string]time.Time
+	cert     *tls.Certificate
+	pool     *x509.CertPool
+}
+
+func New(files Files, interval time.Duration, logger *slog.Logger) (*Store, error) {
+	if (files.CertFile == "") != (files.KeyFile == "") {
+		return nil, errors.New("certificate and key files are required together")
+	}
+
+	s := &Store{
+		files:    files,
+		interval: interval,
+		logger:   logger.With("module", "certs"),
+	}
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+	s.checked = time.Now()
+
+	return s, nil
+}
+
+// ServerConfig - TLS of listeners, client certificates are verified when CAFile is set
+func (s *Store) ServerConfig() *tls.Config {
+	cfg := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			cert, _ := s.current()
+			if cert == nil {
+				return nil, errors.New("no server certificate")
+			}
+
+			return cert, nil
+		},
+	}
+
+	if s.files.CAFile != "" {
+		// the chain is verified by VerifyConnection, against the CA bundle loaded last
+		cfg.ClientAuth = tls.RequireAnyClientCert
+		cfg.VerifyConnection = func(cs tls.ConnectionState) error {
+			_, pool := s.current()
+			return verify(cs.PeerCertificates, pool, "", x509.ExtKeyUsageClientAuth)
+		}
+	}
+
+	return cfg
+}
+
+// ClientConfig - TLS of connections to serverName, the key pair is presented when
+// the server asks for a client certificate
+func (s *Store) ClientConfig(serverName string) *tls.Config {
+	cfg := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: serverName,
+		// the chain is verified by VerifyConnection, against the CA bundle loaded last
+		InsecureSkipVerify: true, //nolint:gosec
+		VerifyConnection: func(cs tls.ConnectionState) error {
+			_, pool := s.current()
+			return verify(cs.PeerCertificates, pool, cs.ServerName, x509.ExtKeyUsageServerAuth)
+		},
+	}
+
+	if s.files.CertFile != "" {
+		cfg.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			cert, _ := s.current()
+			return cert, nil
+		}
+	}
+
+	return cfg
+}
+
+// verify - chain of the peer leads to the pool, nil pool means system roots
+func verify(certs []*x509.Certificate, pool *x509.CertPool, dnsName string, usage x509.ExtKeyUsage) error {
+	if len(certs) == 0 {
+		return errors.New("peer sent no certificate")
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+
+	_, err := certs[0].Verify(x509.VerifyOptions{
+		Roots:         pool,
+		Intermediates: intermediates,
+		DNSName:       dnsName,
+		KeyUsages:     []x509.ExtKeyUsage{usage},
+	})
+
+	return err
+}
+
+// current - certificates, reloaded first when the files changed since the last check
+func (s *Store) current() (*tls.Certificate, *x509.CertPool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if time.Since(s.checked) >= s.interval {
+		s.checked = time.Now()
+		if s.changed() {
+			if err := s.load(); err != nil {
+				s.logger.Error("failed to reload certificates", slog.Any("err", err))
+			} else {
+				s.logger.Info("certificates reloaded")
+			}
+		}
+	}
+
+	return s.cert, s.pool
+}
+
+func (s *Store) paths() []string {
+	var paths []string
+	for _, path := range []string{s.files.CertFile, s.files.KeyFile, s.files.CAFile} {
+		if path != "" {
+			paths = append(paths, path)
+		}
+	}
+
+	return paths
+}
+
+func (s *Store) changed() bool {
+	for _, path := range s.paths() {
+		info, err := os.Stat(path)
+		if err != nil || !info.ModTime().Equal(s.modTimes[path]) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// load - reads all files, the certificates are replaced only when all of them are valid
+func (s *Store) load() error {
+	modTimes := map[string]time.Time{}
+	for _, path := range s.paths() {
+		info, err := os.Stat(path)
+		if err != nil {
+			return fmt.Errorf("failed to stat %s: %s", path, err)
+		}
+
+		modTimes[path] = info.ModTime()
+	}
+
+	var cert *tls.Certificate
+	if s.files.CertFile != "" {
+		pair, err := tls.LoadX509KeyPair(s.files.CertFile, s.files.KeyFile)
+		if err != nil {
+			return fmt.Errorf("failed to load key pair: %s", err)
+		}
+
+		cert = &pair
+	}
+
+	var pool *x509.CertPool
+	if s.files.CAFile != "" {
+		pem, err := os.ReadFile(s.files.CAFile)
+		if err != nil {
+			return fmt.Errorf("failed to read ca bundle: %s", err)
+		}
+
+		pool = x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("no certificates in %s", s.files.CAFile)
+		}
+	}
+
+	s.cert, s.pool, s.modTimes = cert, pool, modTimes
+
+	return nil
+}
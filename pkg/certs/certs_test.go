@@ -0,0 +1,248 @@
+package certs_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"log/slog"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bogatyr285/auth-go/pkg/certs"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// testCA - locally generated certificate authority
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	file string
+}
+
+func newTestCA(t *testing.T, name string) testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Minute),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	file := filepath.Join(t.TempDir(), name+".pem")
+	writeFile(t, file, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+
+	return testCA{cert: cert, key: key, file: file}
+}
+
+// issue - writes key pair signed by the CA into dir, returns paths of the files
+func (ca testCA) issue(t *testing.T, dir, name string, usage x509.ExtKeyUsage) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	writeFile(t, certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+	writeFile(t, keyFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}))
+
+	return certFile, keyFile
+}
+
+// writeFile - the modification time moves forward on every write, so rewrites are noticed
+// on file systems with coarse timestamps
+func writeFile(t *testing.T, path string, data []byte) {
+	var modTime time.Time
+	if info, err := os.Stat(path); err == nil {
+		modTime = info.ModTime()
+	}
+
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+	if !modTime.IsZero() {
+		require.NoError(t, os.Chtimes(path, time.Now(), modTime.Add(time.Second)))
+	}
+}
+
+func newStore(t *testing.T, files certs.Files) *certs.Store {
+	store, err := certs.New(files, 0, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	require.NoError(t, err)
+
+	return store
+}
+
+// serveHTTPS - URL of a server responding with the common name of the client certificate
+func serveHTTPS(t *testing.T, cfg *tls.Config) string {
+	l, err := tls.Listen("tcp", "127.0.0.1:0", cfg)
+	require.NoError(t, err)
+
+	srv := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(r.TLS.PeerCertificates) > 0 {
+			io.WriteString(w, r.TLS.PeerCertificates[0].Subject.CommonName)
+		}
+	})}
+	go srv.Serve(l)
+	t.Cleanup(func() { srv.Close() })
+
+	return "https://" + l.Addr().String()
+}
+
+// get - body and common name of the server certificate
+func get(t *testing.T, url string, cfg *tls.Config) (string, string, error) {
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: cfg, DisableKeepAlives: true}}
+
+	res, err := client.Get(url)
+	if err != nil {
+		return "", "", err
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+
+	return string(body), res.TLS.PeerCertificates[0].Subject.CommonName, nil
+}
+
+func TestMutualTLS(t *testing.T) {
+	serverCA, clientCA, otherCA := newTestCA(t, "server-ca"), newTestCA(t, "client-ca"), newTestCA(t, "other-ca")
+
+	certFile, keyFile := serverCA.issue(t, t.TempDir(), "server", x509.ExtKeyUsageServerAuth)
+	url := serveHTTPS(t, newStore(t, certs.Files{CertFile: certFile, KeyFile: keyFile, CAFile: clientCA.file}).ServerConfig())
+
+	t.Run("client certificate of the CA", func(t *testing.T) {
+		certFile, keyFile := clientCA.issue(t, t.TempDir(), "service", x509.ExtKeyUsageClientAuth)
+		client := newStore(t, certs.Files{CertFile: certFile, KeyFile: keyFile, CAFile: serverCA.file})
+
+		body, server, err := get(t, url, client.ClientConfig("localhost"))
+		require.NoError(t, err)
+		require.Equal(t, "service", body)
+		require.Equal(t, "server", server)
+	})
+
+	t.Run("no client certificate", func(t *testing.T) {
+		client := newStore(t, certs.Files{CAFile: serverCA.file})
+
+		_, _, err := get(t, url, client.ClientConfig("localhost"))
+		require.Error(t, err)
+	})
+
+	t.Run("client certificate of another CA", func(t *testing.T) {
+		certFile, keyFile := otherCA.issue(t, t.TempDir(), "intruder", x509.ExtKeyUsageClientAuth)
+		client := newStore(t, certs.Files{CertFile: certFile, KeyFile: keyFile, CAFile: serverCA.file})
+
+		_, _, err := get(t, url, client.ClientConfig("localhost"))
+		require.Error(t, err)
+	})
+
+	t.Run("server of another CA", func(t *testing.T) {
+		certFile, keyFile := clientCA.issue(t, t.TempDir(), "service", x509.ExtKeyUsageClientAuth)
+		client := newStore(t, certs.Files{CertFile: certFile, KeyFile: keyFile, CAFile: otherCA.file})
+
+		_, _, err := get(t, url, client.ClientConfig("localhost"))
+		require.Error(t, err)
+	})
+
+	t.Run("server name is verified", func(t *testing.T) {
+		certFile, keyFile := clientCA.issue(t, t.TempDir(), "service", x509.ExtKeyUsageClientAuth)
+		client := newStore(t, certs.Files{CertFile: certFile, KeyFile: keyFile, CAFile: serverCA.file})
+
+		_, _, err := get(t, url, client.ClientConfig("auth.example.com"))
+		require.Error(t, err)
+	})
+}
+
+func TestReload(t *testing.T) {
+	ca := newTestCA(t, "ca")
+	dir := t.TempDir()
+
+	certFile, keyFile := ca.issue(t, dir, "server-1", x509.ExtKeyUsageServerAuth)
+	url := serveHTTPS(t, newStore(t, certs.Files{CertFile: certFile, KeyFile: keyFile}).ServerConfig())
+	client := newStore(t, certs.Files{CAFile: ca.file}).ClientConfig("localhost")
+
+	_, server, err := get(t, url, client)
+	require.NoError(t, err)
+	require.Equal(t, "server-1", server)
+
+	// rotated certificate is served without a restart
+	ca.issue(t, dir, "server-2", x509.ExtKeyUsageServerAuth)
+
+	_, server, err = get(t, url, client)
+	require.NoError(t, err)
+	require.Equal(t, "server-2", server)
+
+	// broken files keep the previous certificate
+	writeFile(t, certFile, []byte("not a certificate"))
+
+	_, server, err = get(t, url, client)
+	require.NoError(t, err)
+	require.Equal(t, "server-2", server)
+}
+
+func TestGRPC(t *testing.T) {
+	serverCA, clientCA := newTestCA(t, "server-ca"), newTestCA(t, "client-ca")
+
+	certFile, keyFile := serverCA.issue(t, t.TempDir(), "server", x509.ExtKeyUsageServerAuth)
+	server := newStore(t, certs.Files{CertFile: certFile, KeyFile: keyFile, CAFile: clientCA.file})
+
+	srv := grpc.NewServer(grpc.Creds(credentials.NewTLS(server.ServerConfig())))
+	grpc_health_v1.RegisterHealthServer(srv, health.NewServer())
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go srv.Serve(l)
+	t.Cleanup(srv.Stop)
+
+	check := func(t *testing.T, files certs.Files) error {
+		conn, err := grpc.NewClient(l.Addr().String(),
+			grpc.WithTransportCredentials(credentials.NewTLS(newStore(t, files).ClientConfig("localhost"))))
+		require.NoError(t, err)
+		defer conn.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		_, err = grpc_health_v1.NewHealthClient(conn).Check(ctx, &grpc_health_v1.HealthCheckRequest{})
+		return err
+	}
+
+	certFile, keyFile = clientCA.issue(t, t.TempDir(), "gateway", x509.ExtKeyUsageClientAuth)
+	require.NoError(t, check(t, certs.Files{CertFile: certFile, KeyFile: keyFile, CAFile: serverCA.file}))
+	require.Error(t, check(t, certs.Files{CAFile: serverCA.file}))
+}
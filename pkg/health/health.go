@@ -0,0 +1,315 @@
+// Package health - serving status of services derived from probes of the dependencies
+// they need, reported by the gRPC health service and the HTTP /healthz and /readyz
+package health
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Status - serving status, values match grpc.health.v1.HealthCheckResponse.ServingStatus
+type Status int
+
+const (
+	// StatusUnknown - probes didn't run yet
+	StatusUnknown Status = iota
+	StatusServing
+	StatusNotServing
+	// StatusServiceUnknown - the service isn't registered
+	StatusServiceUnknown
+)
+
+func (s Status) String() string {
+	switch s {
+	case StatusServing:
+		return "SERVING"
+	case StatusNotServing:
+		return "NOT_SERVING"
+	case StatusServiceUnknown:
+		return "SERVICE_UNKNOWN"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// Probe - checks a dependency is usable, e.g. pings the database
+type Probe func(ctx context.Context) error
+
+type Config struct {
+	// Interval - how often the probes run
+	Interval time.Duration
+	// Timeout - how long a single probe may take before it's failed
+	Timeout time.Duration
+}
+
+// Checker - runs the probes periodically. A service is serving while all probes it
+// depends on pass, the overall service "" depends on all probes. It's a service.Service,
+// once stopped every service is not serving, so load balancers stop sending requests
+// while the servers drain
+type Checker struct {
+	cfg Config
+	log *slog.Logger
+
+	mu       sync.Mutex
+	probes   map[string]Probe
+	services map[string][]string
+	// errs - last results of the probes, missing until they run
+	errs     map[string]error
+	watchers map[*watcher]struct{}
+	stopped  bool
+	done     chan struct{}
+}
+
+// watcher - ch holds the latest status only, so slow watchers skip intermediate ones
+type watcher struct {
+	service string
+	last    Status
+	ch      chan Status
+}
+
+func NewChecker(cfg Config, log *slog.Logger) *Checker {
+	return &Checker{
+		cfg:      cfg,
+		log:      log.With("module", "health-checker"),
+		probes:   map[string]Probe{},
+		services: map[string][]string{},
+		errs:     map[string]error{},
+		watchers: map[*watcher]struct{}{},
+		done:     make(chan struct{}),
+	}
+}
+
+// AddProbe - probes are added before the checker is initialized
+func (c *Checker) AddProbe(name string, probe Probe) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.probes[name] = probe
+}
+
+// AddService - the service is serving while the probes pass, it has no dependencies
+// when there are none
+func (c *Checker) AddService(service string, probes ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.services[service] = probes
+}
+
+// Init - runs the probes once, so the status is known before servers start
+func (c *Checker) Init(ctx context.Context) error {
+	c.check(ctx)
+	return nil
+}
+
+func (c *Checker) Run(ctx context.Context) error {
+	t := time.NewTicker(c.cfg.Interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-c.done:
+			return nil
+		case <-t.C:
+			c.check(ctx)
+		}
+	}
+}
+
+// Stop - services become not serving and watches end after being told so
+func (c *Checker) Stop() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.stopped {
+		return
+	}
+	c.stopped = true
+	c.log.Info("shutting down, services are not serving")
+
+	c.notify()
+	for w := range c.watchers {
+		c.unwatch(w)
+	}
+	close(c.done)
+}
+
+// check - runs the probes concurrently and notifies watchers of changed statuses
+func (c *Checker) check(ctx context.Context) {
+	c.mu.Lock()
+	probes := make(map[string]Probe, len(c.probes))
+	for name, p := range c.probes {
+		probes[name] = p
+	}
+	c.mu.Unlock()
+
+	type result struct {
+		probe string
+		err   error
+	}
+	results := make(chan result, len(probes))
+
+	var wg sync.WaitGroup
+	for name, probe := range probes {
+		wg.Add(1)
+		go func(name string, probe Probe) {
+			defer wg.Done()
+
+			ctx, cancel := context.WithTimeout(ctx, c.cfg.Timeout)
+			defer cancel()
+
+			results <- result{probe: name, err: probe(ctx)}
+		}(name, probe)
+	}
+	wg.Wait()
+	close(results)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for r := range results {
+		prev, checked := c.errs[r.probe]
+		switch {
+		case r.err != nil && (prev == nil || r.err.Error() != prev.Error()):
+			c.log.Warn("probe failed", slog.String("probe", r.probe), slog.Any("err", r.err))
+		case r.err == nil && (!checked || prev != nil):
+			c.log.Info("probe passed", slog.String("probe", r.probe))
+		}
+		c.errs[r.probe] = r.err
+	}
+
+	c.notify()
+}
+
+// Status - current status of the service
+func (c *Checker) Status(service string) Status {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.status(service)
+}
+
+// status - c.mu is held
+func (c *Checker) status(service string) Status {
+	probes, ok := c.dependencies(service)
+	switch {
+	case !ok:
+		return StatusServiceUnknown
+	case c.stopped:
+		return StatusNotServing
+	}
+
+	for _, name := range probes {
+		err, checked := c.errs[name]
+		if !checked {
+			return StatusUnknown
+		}
+		if err != nil {
+			return StatusNotServing
+		}
+	}
+
+	return StatusServing
+}
+
+// dependencies - probes of the service, c.mu is held
+func (c *Checker) dependencies(service string) ([]string, bool) {
+	if service != "" {
+		probes, ok := c.services[service]
+		return probes, ok
+	}
+
+	probes := make([]string, 0, len(c.probes))
+	for name := range c.probes {
+		probes = append(probes, name)
+	}
+	sort.Strings(probes)
+
+	return probes, true
+}
+
+// Watch - the current status of the service, then its changes. The channel is closed
+// once the checker is stopped or cancel is called
+func (c *Checker) Watch(service string) (<-chan Status, func()) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	w := &watcher{service: service, last: c.status(service), ch: make(chan Status, 1)}
+	w.ch <- w.last
+	if c.stopped {
+		close(w.ch)
+		return w.ch, func() {}
+	}
+	c.watchers[w] = struct{}{}
+
+	return w.ch, func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		c.unwatch(w)
+	}
+}
+
+// notify - sends changed statuses to watchers, replacing the ones they didn't receive
+// yet. c.mu is held
+func (c *Checker) notify() {
+	for w := range c.watchers {
+		s := c.status(w.service)
+		if s == w.last {
+			continue
+		}
+		w.last = s
+
+		select {
+		case <-w.ch:
+		default:
+		}
+		w.ch <- s
+	}
+}
+
+// unwatch - c.mu is held
+func (c *Checker) unwatch(w *watcher) {
+	if _, ok := c.watchers[w]; !ok {
+		return
+	}
+
+	delete(c.watchers, w)
+	close(w.ch)
+}
+
+// Report - status of a service and of the probes it depends on
+type Report struct {
+	Status string `json:"status"`
+	// Checks - "ok" or "failing" by probe, errors are only logged as they may reveal
+	// internals
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+func (c *Checker) Report(service string) Report {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	r := Report{Status: c.status(service).String()}
+	probes, _ := c.dependencies(service)
+	for _, name := range probes {
+		if r.Checks == nil {
+			r.Checks = make(map[string]string, len(probes))
+		}
+
+		err, checked := c.errs[name]
+		switch {
+		case !checked:
+			r.Checks[name] = "unknown"
+		case err != nil:
+			r.Checks[name] = "failing"
+		default:
+			r.Checks[name] = "ok"
+		}
+	}
+
+	return r
+}
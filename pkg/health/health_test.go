@@ -0,0 +1,174 @@
+package health_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bogatyr285/auth-go/pkg/health"
+	"github.com/stretchr/testify/require"
+)
+
+// probe - fails while err is set
+type probe struct {
+	err atomic.Pointer[error]
+}
+
+func (p *probe) check(context.Context) error {
+	if err := p.err.Load(); err != nil {
+		return *err
+	}
+	return nil
+}
+
+func (p *probe) fail(err error) {
+	p.err.Store(&err)
+}
+
+func (p *probe) pass() {
+	p.err.Store(nil)
+}
+
+func newChecker() (*health.Checker, *probe, *probe) {
+	db, smtp := &probe{}, &probe{}
+
+	c := health.NewChecker(health.Config{Interval: 10 * time.Millisecond, Timeout: time.Second},
+		slog.New(slog.NewTextHandler(io.Discard, nil)))
+	c.AddProbe("database", db.check)
+	c.AddProbe("notifier", smtp.check)
+	c.AddService("auth", "database", "notifier")
+	c.AddService("relations", "database")
+
+	return c, db, smtp
+}
+
+// next - status received from the watch within a second
+func next(t *testing.T, updates <-chan health.Status) health.Status {
+	t.Helper()
+
+	select {
+	case s, ok := <-updates:
+		require.True(t, ok, "watch ended")
+		return s
+	case <-time.After(time.Second):
+		t.Fatal("no status change")
+		return health.StatusUnknown
+	}
+}
+
+func TestChecker(t *testing.T) {
+	t.Run("statuses follow the probes", func(t *testing.T) {
+		c, db, smtp := newChecker()
+		require.Equal(t, health.StatusUnknown, c.Status("auth"))
+
+		smtp.fail(errors.New("connection refused"))
+		require.NoError(t, c.Init(context.Background()))
+
+		require.Equal(t, health.StatusNotServing, c.Status(""))
+		require.Equal(t, health.StatusNotServing, c.Status("auth"))
+		require.Equal(t, health.StatusServing, c.Status("relations"))
+		require.Equal(t, health.StatusServiceUnknown, c.Status("billing"))
+
+		require.Equal(t, health.Report{
+			Status: "NOT_SERVING",
+			Checks: map[string]string{"database": "ok", "notifier": "failing"},
+		}, c.Report("auth"))
+
+		smtp.pass()
+		db.fail(errors.New("disk I/O error"))
+		require.NoError(t, c.Init(context.Background()))
+		require.Equal(t, health.StatusNotServing, c.Status("relations"))
+	})
+
+	t.Run("watch", func(t *testing.T) {
+		c, db, _ := newChecker()
+		require.NoError(t, c.Init(context.Background()))
+		go c.Run(context.Background())
+
+		updates, cancel := c.Watch("relations")
+		defer cancel()
+		require.Equal(t, health.StatusServing, next(t, updates))
+
+		db.fail(errors.New("disk I/O error"))
+		require.Equal(t, health.StatusNotServing, next(t, updates))
+
+		db.pass()
+		require.Equal(t, health.StatusServing, next(t, updates))
+
+		unknown, cancelUnknown := c.Watch("billing")
+		defer cancelUnknown()
+		require.Equal(t, health.StatusServiceUnknown, next(t, unknown))
+
+		// shutting down is the last status
+		c.Stop()
+		require.Equal(t, health.StatusNotServing, next(t, updates))
+		_, ok := <-updates
+		require.False(t, ok)
+		require.Equal(t, health.StatusNotServing, c.Status(""))
+
+		late, _ := c.Watch("relations")
+		require.Equal(t, health.StatusNotServing, next(t, late))
+		_, ok = <-late
+		require.False(t, ok)
+	})
+
+	t.Run("slow probes fail", func(t *testing.T) {
+		c := health.NewChecker(health.Config{Interval: time.Hour, Timeout: 10 * time.Millisecond},
+			slog.New(slog.NewTextHandler(io.Discard, nil)))
+		c.AddProbe("database", func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+
+		require.NoError(t, c.Init(context.Background()))
+		require.Equal(t, health.StatusNotServing, c.Status(""))
+	})
+}
+
+func TestHandlers(t *testing.T) {
+	c, _, smtp := newChecker()
+	smtp.fail(errors.New("connection refused"))
+	require.NoError(t, c.Init(context.Background()))
+
+	mux := http.NewServeMux()
+	mux.Handle(health.LivenessPath, c.LivenessHandler())
+	mux.Handle(health.ReadinessPath, c.ReadinessHandler())
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	get := func(t *testing.T, path string) (int, health.Report) {
+		res, err := srv.Client().Get(srv.URL + path)
+		require.NoError(t, err)
+		defer res.Body.Close()
+
+		var report health.Report
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&report))
+		return res.StatusCode, report
+	}
+
+	status, _ := get(t, health.LivenessPath)
+	require.Equal(t, http.StatusOK, status)
+
+	status, report := get(t, health.ReadinessPath)
+	require.Equal(t, http.StatusServiceUnavailable, status)
+	require.Equal(t, "failing", report.Checks["notifier"])
+
+	status, _ = get(t, health.ReadinessPath+"?service=relations")
+	require.Equal(t, http.StatusOK, status)
+
+	status, _ = get(t, health.ReadinessPath+"?service=billing")
+	require.Equal(t, http.StatusNotFound, status)
+
+	c.Stop()
+	status, _ = get(t, health.LivenessPath)
+	require.Equal(t, http.StatusServiceUnavailable, status)
+	status, _ = get(t, health.ReadinessPath+"?service=relations")
+	require.Equal(t, http.StatusServiceUnavailable, status)
+}
@@ -0,0 +1,50 @@
+package health
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+const (
+	LivenessPath  = "/healthz"
+	ReadinessPath = "/readyz"
+)
+
+// LivenessHandler - the process serves requests, 503 once it's shutting down.
+// Dependencies aren't probed, a failing database shouldn't get the process restarted
+func (c *Checker) LivenessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c.mu.Lock()
+		stopped := c.stopped
+		c.mu.Unlock()
+
+		report := Report{Status: StatusServing.String()}
+		if stopped {
+			report.Status = StatusNotServing.String()
+		}
+		writeReport(w, report)
+	})
+}
+
+// ReadinessHandler - 200 while the service of the "service" query parameter, all of
+// them by default, is serving, 503 with the failing probes otherwise
+func (c *Checker) ReadinessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeReport(w, c.Report(r.URL.Query().Get("service")))
+	})
+}
+
+func writeReport(w http.ResponseWriter, report Report) {
+	status := http.StatusServiceUnavailable
+	switch report.Status {
+	case StatusServing.String():
+		status = http.StatusOK
+	case StatusServiceUnknown.String():
+		status = http.StatusNotFound
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(report)
+}
@@ -2,7 +2,10 @@ package jwt
 
 import (
 	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -17,11 +20,30 @@ var (
 	ErrValidation      = fmt.Errorf("token validation errror")
 )
 
+const (
+	// RolesClaim - roles of the subject embedded in access tokens
+	RolesClaim = "roles"
+	// TokenUseClaim - marks special purpose tokens which must not be accepted as access tokens
+	TokenUseClaim = "token_use"
+	// AuthTimeClaim - when the subject signed in, missing from tokens issued by refresh
+	AuthTimeClaim = "auth_time"
+	// ClientIDClaim - OAuth client the token was issued to, such tokens are for the
+	// client's resource servers rather than the APIs of the issuer
+	ClientIDClaim = "client_id"
+	// SubjectTypeClaim - marks tokens whose sub isn't a user, SubjectTypeClient for tokens
+	// of OAuth clients acting on their own behalf
+	SubjectTypeClaim  = "sub_type"
+	SubjectTypeClient = "client"
+	// ActClaim - RFC 8693 actor acting for the subject, tokens of impersonation
+	ActClaim = "act"
+)
+
 type JWTManager struct {
 	issuer     string
 	expiresIn  time.Duration
 	publicKey  interface{}
 	privateKey interface{}
+	keyID      string
 }
 
 func NewJWTManager(issuer string, expiresIn time.Duration, publicKey, privateKey []byte) (*JWTManager, error) {
@@ -40,18 +62,50 @@ func NewJWTManager(issuer string, expiresIn time.Duration, publicKey, privateKey
 		expiresIn:  expiresIn,
 		publicKey:  pubKey,
 		privateKey: privKey,
+		keyID:      thumbprint(pubKey.(ed25519.PublicKey)),
 	}, nil
 }
 
+func (j *JWTManager) Issuer() string {
+	return j.issuer
+}
+
+// PublicKey - key tokens are verified with, published as JWK Set for OIDC clients
+func (j *JWTManager) PublicKey() ed25519.PublicKey {
+	return j.publicKey.(ed25519.PublicKey)
+}
+
+// KeyID - "kid" header of issued tokens
+func (j *JWTManager) KeyID() string {
+	return j.keyID
+}
+
+// thumbprint - RFC 7638 JWK thumbprint of the Ed25519 key
+func thumbprint(key ed25519.PublicKey) string {
+	jwk := `{"crv":"Ed25519","kty":"OKP","x":"` + base64.RawURLEncoding.EncodeToString(key) + `"}`
+	sum := sha256.Sum256([]byte(jwk))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
 func (j *JWTManager) IssueToken(userID string) (string, error) {
+	return j.IssueTokenWithClaims(userID, nil)
+}
+
+// IssueTokenWithClaims - same as IssueToken, extra claims are added on top
+// of the registered ones and may override them (e.g. shorter "exp")
+func (j *JWTManager) IssueTokenWithClaims(userID string, extra jwt.MapClaims) (string, error) {
 	claims := jwt.MapClaims{
 		"iss": j.issuer,
 		"sub": userID,
 		"iat": time.Now().Unix(),
 		"exp": time.Now().Add(j.expiresIn).Unix(),
 	}
+	for k, v := range extra {
+		claims[k] = v
+	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, claims)
+	token.Header["kid"] = j.keyID
 
 	signed, err := token.SignedString(j.privateKey.(ed25519.PrivateKey))
 	if err != nil {
@@ -76,3 +130,64 @@ func (j *JWTManager) VerifyToken(tokenString string) (*jwt.Token, error) {
 
 	return token, nil
 }
+
+// CheckSigningKey - tokens are signed and verified, probed by health checks
+func (j *JWTManager) CheckSigningKey() error {
+	token, err := j.IssueTokenWithClaims("health-check", jwt.MapClaims{TokenUseClaim: "health_check"})
+	if err != nil {
+		return err
+	}
+
+	_, err = j.VerifyToken(token)
+	return err
+}
+
+// FirstParty - the token is an access token of a user signed in to the issuer itself,
+// not a special purpose token nor one issued to an OAuth client
+func FirstParty(claims jwt.MapClaims) bool {
+	for _, claim := range []string{TokenUseClaim, ClientIDClaim, SubjectTypeClaim, ActClaim} {
+		if _, ok := claims[claim]; ok {
+			return false
+		}
+	}
+
+	return ForIssuer(claims)
+}
+
+// ForIssuer - the token has no audience or the issuer is one of it, tokens for other
+// audiences are signed by the issuer but meant for other services
+func ForIssuer(claims jwt.MapClaims) bool {
+	aud, err := claims.GetAudience()
+	if err != nil {
+		return false
+	}
+
+	if len(aud) == 0 {
+		return true
+	}
+
+	iss, err := claims.GetIssuer()
+	return err == nil && iss != "" && slices.Contains(aud, iss)
+}
+
+// ClientSubject - sub of the token is an OAuth client, not a user
+func ClientSubject(claims jwt.MapClaims) bool {
+	return claims[SubjectTypeClaim] == SubjectTypeClient
+}
+
+// RolesFromClaims - roles embedded in the token, nil when there are none
+func RolesFromClaims(claims jwt.MapClaims) []string {
+	raw, ok := claims[RolesClaim].([]interface{})
+	if !ok {
+		return nil
+	}
+
+	roles := make([]string, 0, len(raw))
+	for _, r := range raw {
+		if role, ok := r.(string); ok {
+			roles = append(roles, role)
+		}
+	}
+
+	return roles
+}
@@ -0,0 +1,97 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/smtp"
+	"strings"
+)
+
+// LogNotifier - writes messages to log instead of sending them, for local usage
+type LogNotifier struct {
+	logger *slog.Logger
+}
+
+func NewLogNotifier(logger *slog.Logger) LogNotifier {
+	return LogNotifier{logger: logger.With("module", "log-notifier")}
+}
+
+func (n LogNotifier) Notify(ctx context.Context, to, subject, body string) error {
+	n.logger.InfoContext(ctx, "notification",
+		slog.String("to", to),
+		slog.String("subject", subject),
+		slog.String("body", body),
+	)
+	return nil
+}
+
+// SMTPNotifier - sends messages as plain text emails
+type SMTPNotifier struct {
+	addr string
+	from string
+	auth smtp.Auth
+}
+
+func NewSMTPNotifier(addr, from, username, password string) (SMTPNotifier, error) {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return SMTPNotifier{}, fmt.Errorf("invalid smtp address: %w", err)
+	}
+
+	var auth smtp.Auth
+	if username != "" {
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+
+	return SMTPNotifier{
+		addr: addr,
+		from: from,
+		auth: auth,
+	}, nil
+}
+
+// Ping - the SMTP server greets and says goodbye, probed by health checks
+func (n SMTPNotifier) Ping(ctx context.Context) error {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", n.addr)
+	if err != nil {
+		return fmt.Errorf("failed to connect to smtp server: %w", err)
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	host, _, _ := net.SplitHostPort(n.addr)
+	c, err := smtp.NewClient(conn, host)
+	if err != nil {
+		return fmt.Errorf("failed to greet smtp server: %w", err)
+	}
+
+	if err := c.Quit(); err != nil {
+		return fmt.Errorf("failed to quit smtp session: %w", err)
+	}
+
+	return nil
+}
+
+func (n SMTPNotifier) Notify(ctx context.Context, to, subject, body string) error {
+	if strings.ContainsAny(to+subject, "\r\n") {
+		return fmt.Errorf("invalid header value")
+	}
+
+	msg := "From: " + n.from + "\r\n" +
+		"To: " + to + "\r\n" +
+		"Subject: " + subject + "\r\n" +
+		"Content-Type: text/plain; charset=UTF-8\r\n" +
+		"\r\n" + body
+
+	if err := smtp.SendMail(n.addr, n.auth, n.from, []string{to}, []byte(msg)); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,173 @@
+// Package saml - SAML 2.0 service provider: metadata, AuthnRequests of the HTTP-Redirect
+// binding and validation of signed responses posted back by identity providers
+package saml
+
+import (
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"time"
+
+	gosaml "github.com/crewjam/saml"
+)
+
+var ErrValidation = fmt.Errorf("saml response validation error")
+
+type Config struct {
+	// EntityID - ID of the service provider, the audience of assertions
+	EntityID string
+	// ACSURL - assertion consumer service the responses are posted to
+	ACSURL string
+	// Key, Certificate - optional, published in metadata for encrypted assertions
+	Key         *rsa.PrivateKey
+	Certificate *x509.Certificate
+}
+
+// Assertion - identity of the user asserted by the identity provider
+type Assertion struct {
+	ID      string
+	Subject string
+	// Attributes - values by attribute name, friendly names are included as well
+	Attributes map[string][]string
+	// ExpiresAt - the assertion isn't accepted after, clock skew included
+	ExpiresAt time.Time
+}
+
+// First - first value of the attribute
+func (a Assertion) First(name string) string {
+	if values := a.Attributes[name]; len(values) > 0 {
+		return values[0]
+	}
+
+	return ""
+}
+
+type ServiceProvider struct {
+	cfg    Config
+	acsURL url.URL
+}
+
+func New(cfg Config) (*ServiceProvider, error) {
+	acsURL, err := url.Parse(cfg.ACSURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid acs url: %s", err)
+	}
+
+	if cfg.EntityID == "" {
+		return nil, fmt.Errorf("saml entity id is required")
+	}
+
+	return &ServiceProvider{cfg: cfg, acsURL: *acsURL}, nil
+}
+
+// Metadata - XML metadata identity providers are configured with
+func (sp *ServiceProvider) Metadata() ([]byte, error) {
+	p := sp.provider(nil)
+	return xml.MarshalIndent(p.Metadata(), "", "  ")
+}
+
+// Connect - identity provider described by the XML metadata
+func (sp *ServiceProvider) Connect(idpMetadata []byte) (*IdentityProvider, error) {
+	metadata := &gosaml.EntityDescriptor{}
+	if err := xml.Unmarshal(idpMetadata, metadata); err != nil {
+		return nil, fmt.Errorf("invalid identity provider metadata: %s", err)
+	}
+
+	if len(metadata.IDPSSODescriptors) == 0 {
+		return nil, fmt.Errorf("metadata of %s has no identity provider descriptor", metadata.EntityID)
+	}
+
+	return &IdentityProvider{sp: sp.provider(metadata)}, nil
+}
+
+func (sp *ServiceProvider) provider(idpMetadata *gosaml.EntityDescriptor) gosaml.ServiceProvider {
+	return gosaml.ServiceProvider{
+		EntityID:          sp.cfg.EntityID,
+		Key:               sp.cfg.Key,
+		Certificate:       sp.cfg.Certificate,
+		AcsURL:            sp.acsURL,
+		IDPMetadata:       idpMetadata,
+		AuthnNameIDFormat: gosaml.PersistentNameIDFormat,
+	}
+}
+
+// IdentityProvider - responses are accepted only for requests made by the service
+// provider, unsolicited ones are rejected
+type IdentityProvider struct {
+	sp gosaml.ServiceProvider
+}
+
+// EntityID - issuer of the provider's assertions
+func (idp *IdentityProvider) EntityID() string {
+	return idp.sp.IDPMetadata.EntityID
+}
+
+// AuthnRequestURL - where the user is redirected to sign in, requestID is what the
+// response must be in response to
+func (idp *IdentityProvider) AuthnRequestURL(relayState string) (location, requestID string, err error) {
+	binding := idp.sp.GetSSOBindingLocation(gosaml.HTTPRedirectBinding)
+	if binding == "" {
+		return "", "", fmt.Errorf("%s has no HTTP-Redirect sign in endpoint", idp.EntityID())
+	}
+
+	req, err := idp.sp.MakeAuthenticationRequest(binding, gosaml.HTTPRedirectBinding, gosaml.HTTPPostBinding)
+	if err != nil {
+		return "", "", err
+	}
+
+	u, err := req.Redirect(url.QueryEscape(relayState), &idp.sp)
+	if err != nil {
+		return "", "", err
+	}
+
+	return u.String(), req.ID, nil
+}
+
+// ParseResponse - assertion of the base64 encoded response, validated against the
+// provider's signing certificates, the audience, time conditions and the request ID
+func (idp *IdentityProvider) ParseResponse(samlResponse, requestID string) (Assertion, error) {
+	raw, err := base64.StdEncoding.DecodeString(samlResponse)
+	if err != nil {
+		return Assertion{}, fmt.Errorf("%w: invalid encoding: %s", ErrValidation, err)
+	}
+
+	assertion, err := idp.sp.ParseXMLResponse(raw, []string{requestID})
+	if err != nil {
+		// the library hides details behind a generic message
+		if invalid, ok := err.(*gosaml.InvalidResponseError); ok {
+			err = invalid.PrivateErr
+		}
+		return Assertion{}, fmt.Errorf("%w: %s", ErrValidation, err)
+	}
+
+	// assertions without audience would be accepted by any service provider of the IdP
+	if assertion.Conditions == nil || len(assertion.Conditions.AudienceRestrictions) == 0 {
+		return Assertion{}, fmt.Errorf("%w: assertion has no audience restriction", ErrValidation)
+	}
+
+	if assertion.Subject == nil || assertion.Subject.NameID == nil || assertion.Subject.NameID.Value == "" {
+		return Assertion{}, fmt.Errorf("%w: assertion has no subject", ErrValidation)
+	}
+
+	attributes := map[string][]string{}
+	for _, statement := range assertion.AttributeStatements {
+		for _, attr := range statement.Attributes {
+			for _, v := range attr.Values {
+				attributes[attr.Name] = append(attributes[attr.Name], v.Value)
+				if attr.FriendlyName != "" && attr.FriendlyName != attr.Name {
+					attributes[attr.FriendlyName] = append(attributes[attr.FriendlyName], v.Value)
+				}
+			}
+		}
+	}
+
+	return Assertion{
+		ID:         assertion.ID,
+		Subject:    assertion.Subject.NameID.Value,
+		Attributes: attributes,
+		ExpiresAt:  assertion.Conditions.NotOnOrAfter.Add(gosaml.MaxClockSkew),
+	}, nil
+}
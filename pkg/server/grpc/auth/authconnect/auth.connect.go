@@ -0,0 +1,451 @@
+// Code generated by protoc-gen-connect-go. DO NOT EDIT.
+//
+// Source: auth.proto
+
+package authconnect
+
+import (
+	connect "connectrpc.com/connect"
+	context "context"
+	errors "errors"
+	auth "github.com/bogatyr285/auth-go/pkg/server/grpc/auth"
+	http "net/http"
+	strings "strings"
+)
+
+// This is a compile-time assertion to ensure that this generated file and the connect package are
+// compatible. If you get a compiler error that this constant is not defined, this code was
+// generated with a version of connect newer than the one compiled into your binary. You can fix the
+// problem by either regenerating this code with an older version of connect or updating the connect
+// version compiled into your binary.
+const _ = connect.IsAtLeastVersion1_13_0
+
+const (
+	// AuthServiceName is the fully-qualified name of the AuthService service.
+	AuthServiceName = "auth.v1.AuthService"
+)
+
+// These constants are the fully-qualified names of the RPCs defined in this package. They're
+// exposed at runtime as Spec.Procedure and as the final two segments of the HTTP route.
+//
+// Note that these are different from the fully-qualified method names used by
+// google.golang.org/protobuf/reflect/protoreflect. To convert from these constants to
+// reflection-formatted method names, remove the leading slash and convert the remaining slash to a
+// period.
+const (
+	// AuthServiceRegisterUserProcedure is the fully-qualified name of the AuthService's RegisterUser
+	// RPC.
+	AuthServiceRegisterUserProcedure = "/auth.v1.AuthService/RegisterUser"
+	// AuthServiceLoginUserProcedure is the fully-qualified name of the AuthService's LoginUser RPC.
+	AuthServiceLoginUserProcedure = "/auth.v1.AuthService/LoginUser"
+	// AuthServiceRefreshProcedure is the fully-qualified name of the AuthService's Refresh RPC.
+	AuthServiceRefreshProcedure = "/auth.v1.AuthService/Refresh"
+	// AuthServiceGetUserProcedure is the fully-qualified name of the AuthService's GetUser RPC.
+	AuthServiceGetUserProcedure = "/auth.v1.AuthService/GetUser"
+	// AuthServiceBuildInfoProcedure is the fully-qualified name of the AuthService's BuildInfo RPC.
+	AuthServiceBuildInfoProcedure = "/auth.v1.AuthService/BuildInfo"
+	// AuthServiceUserInfoProcedure is the fully-qualified name of the AuthService's UserInfo RPC.
+	AuthServiceUserInfoProcedure = "/auth.v1.AuthService/UserInfo"
+	// AuthServiceSendPhoneCodeProcedure is the fully-qualified name of the AuthService's SendPhoneCode
+	// RPC.
+	AuthServiceSendPhoneCodeProcedure = "/auth.v1.AuthService/SendPhoneCode"
+	// AuthServiceVerifyPhoneProcedure is the fully-qualified name of the AuthService's VerifyPhone RPC.
+	AuthServiceVerifyPhoneProcedure = "/auth.v1.AuthService/VerifyPhone"
+	// AuthServiceGrantRoleProcedure is the fully-qualified name of the AuthService's GrantRole RPC.
+	AuthServiceGrantRoleProcedure = "/auth.v1.AuthService/GrantRole"
+	// AuthServiceRevokeRoleProcedure is the fully-qualified name of the AuthService's RevokeRole RPC.
+	AuthServiceRevokeRoleProcedure = "/auth.v1.AuthService/RevokeRole"
+	// AuthServiceWatchSecurityEventsProcedure is the fully-qualified name of the AuthService's
+	// WatchSecurityEvents RPC.
+	AuthServiceWatchSecurityEventsProcedure = "/auth.v1.AuthService/WatchSecurityEvents"
+	// AuthServiceWatchAllSecurityEventsProcedure is the fully-qualified name of the AuthService's
+	// WatchAllSecurityEvents RPC.
+	AuthServiceWatchAllSecurityEventsProcedure = "/auth.v1.AuthService/WatchAllSecurityEvents"
+)
+
+// These variables are the protoreflect.Descriptor objects for the RPCs defined in this package.
+var (
+	authServiceServiceDescriptor                      = auth.File_auth_proto.Services().ByName("AuthService")
+	authServiceRegisterUserMethodDescriptor           = authServiceServiceDescriptor.Methods().ByName("RegisterUser")
+	authServiceLoginUserMethodDescriptor              = authServiceServiceDescriptor.Methods().ByName("LoginUser")
+	authServiceRefreshMethodDescriptor                = authServiceServiceDescriptor.Methods().ByName("Refresh")
+	authServiceGetUserMethodDescriptor                = authServiceServiceDescriptor.Methods().ByName("GetUser")
+	authServiceBuildInfoMethodDescriptor              = authServiceServiceDescriptor.Methods().ByName("BuildInfo")
+	authServiceUserInfoMethodDescriptor               = authServiceServiceDescriptor.Methods().ByName("UserInfo")
+	authServiceSendPhoneCodeMethodDescriptor          = authServiceServiceDescriptor.Methods().ByName("SendPhoneCode")
+	authServiceVerifyPhoneMethodDescriptor            = authServiceServiceDescriptor.Methods().ByName("VerifyPhone")
+	authServiceGrantRoleMethodDescriptor              = authServiceServiceDescriptor.Methods().ByName("GrantRole")
+	authServiceRevokeRoleMethodDescriptor             = authServiceServiceDescriptor.Methods().ByName("RevokeRole")
+	authServiceWatchSecurityEventsMethodDescriptor    = authServiceServiceDescriptor.Methods().ByName("WatchSecurityEvents")
+	authServiceWatchAllSecurityEventsMethodDescriptor = authServiceServiceDescriptor.Methods().ByName("WatchAllSecurityEvents")
+)
+
+// AuthServiceClient is a client for the auth.v1.AuthService service.
+type AuthServiceClient interface {
+	RegisterUser(context.Context, *connect.Request[auth.RegisterUserRequest]) (*connect.Response[auth.RegisterUserResponse], error)
+	LoginUser(context.Context, *connect.Request[auth.LoginUserRequest]) (*connect.Response[auth.LoginUserResponse], error)
+	// Refresh - new access token for the refresh token issued by LoginUser
+	Refresh(context.Context, *connect.Request[auth.RefreshRequest]) (*connect.Response[auth.RefreshResponse], error)
+	GetUser(context.Context, *connect.Request[auth.GetUserRequest]) (*connect.Response[auth.GetUserResponse], error)
+	BuildInfo(context.Context, *connect.Request[auth.BuildInfoRequest]) (*connect.Response[auth.BuildInfoResponse], error)
+	UserInfo(context.Context, *connect.Request[auth.UserInfoRequest]) (*connect.Response[auth.UserInfoResponse], error)
+	// SendPhoneCode - sends one-time code by SMS to verify the phone or to log in
+	SendPhoneCode(context.Context, *connect.Request[auth.SendPhoneCodeRequest]) (*connect.Response[auth.SendPhoneCodeResponse], error)
+	VerifyPhone(context.Context, *connect.Request[auth.VerifyPhoneRequest]) (*connect.Response[auth.VerifyPhoneResponse], error)
+	// GrantRole - admin only
+	GrantRole(context.Context, *connect.Request[auth.GrantRoleRequest]) (*connect.Response[auth.UserRolesResponse], error)
+	// RevokeRole - admin only
+	RevokeRole(context.Context, *connect.Request[auth.RevokeRoleRequest]) (*connect.Response[auth.UserRolesResponse], error)
+	// WatchSecurityEvents - security events of the caller's account as they happen.
+	// The stream ends with RESOURCE_EXHAUSTED when the client doesn't keep up and
+	// with UNAVAILABLE on shutdown, clients are expected to watch again
+	WatchSecurityEvents(context.Context, *connect.Request[auth.WatchSecurityEventsRequest]) (*connect.ServerStreamForClient[auth.SecurityEvent], error)
+	// WatchAllSecurityEvents - WatchSecurityEvents of all accounts, admin only
+	WatchAllSecurityEvents(context.Context, *connect.Request[auth.WatchAllSecurityEventsRequest]) (*connect.ServerStreamForClient[auth.SecurityEvent], error)
+}
+
+// NewAuthServiceClient constructs a client for the auth.v1.AuthService service. By default, it uses
+// the Connect protocol with the binary Protobuf Codec, asks for gzipped responses, and sends
+// uncompressed requests. To use the gRPC or gRPC-Web protocols, supply the connect.WithGRPC() or
+// connect.WithGRPCWeb() options.
+//
+// The URL supplied here should be the base URL for the Connect or gRPC server (for example,
+// http://api.acme.com or https://acme.com/grpc).
+func NewAuthServiceClient(httpClient connect.HTTPClient, baseURL string, opts ...connect.ClientOption) AuthServiceClient {
+	baseURL = strings.TrimRight(baseURL, "/")
+	return &authServiceClient{
+		registerUser: connect.NewClient[auth.RegisterUserRequest, auth.RegisterUserResponse](
+			httpClient,
+			baseURL+AuthServiceRegisterUserProcedure,
+			connect.WithSchema(authServiceRegisterUserMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		loginUser: connect.NewClient[auth.LoginUserRequest, auth.LoginUserResponse](
+			httpClient,
+			baseURL+AuthServiceLoginUserProcedure,
+			connect.WithSchema(authServiceLoginUserMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		refresh: connect.NewClient[auth.RefreshRequest, auth.RefreshResponse](
+			httpClient,
+			baseURL+AuthServiceRefreshProcedure,
+			connect.WithSchema(authServiceRefreshMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		getUser: connect.NewClient[auth.GetUserRequest, auth.GetUserResponse](
+			httpClient,
+			baseURL+AuthServiceGetUserProcedure,
+			connect.WithSchema(authServiceGetUserMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		buildInfo: connect.NewClient[auth.BuildInfoRequest, auth.BuildInfoResponse](
+			httpClient,
+			baseURL+AuthServiceBuildInfoProcedure,
+			connect.WithSchema(authServiceBuildInfoMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		userInfo: connect.NewClient[auth.UserInfoRequest, auth.UserInfoResponse](
+			httpClient,
+			baseURL+AuthServiceUserInfoProcedure,
+			connect.WithSchema(authServiceUserInfoMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		sendPhoneCode: connect.NewClient[auth.SendPhoneCodeRequest, auth.SendPhoneCodeResponse](
+			httpClient,
+			baseURL+AuthServiceSendPhoneCodeProcedure,
+			connect.WithSchema(authServiceSendPhoneCodeMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		verifyPhone: connect.NewClient[auth.VerifyPhoneRequest, auth.VerifyPhoneResponse](
+			httpClient,
+			baseURL+AuthServiceVerifyPhoneProcedure,
+			connect.WithSchema(authServiceVerifyPhoneMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		grantRole: connect.NewClient[auth.GrantRoleRequest, auth.UserRolesResponse](
+			httpClient,
+			baseURL+AuthServiceGrantRoleProcedure,
+			connect.WithSchema(authServiceGrantRoleMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		revokeRole: connect.NewClient[auth.RevokeRoleRequest, auth.UserRolesResponse](
+			httpClient,
+			baseURL+AuthServiceRevokeRoleProcedure,
+			connect.WithSchema(authServiceRevokeRoleMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		watchSecurityEvents: connect.NewClient[auth.WatchSecurityEventsRequest, auth.SecurityEvent](
+			httpClient,
+			baseURL+AuthServiceWatchSecurityEventsProcedure,
+			connect.WithSchema(authServiceWatchSecurityEventsMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		watchAllSecurityEvents: connect.NewClient[auth.WatchAllSecurityEventsRequest, auth.SecurityEvent](
+			httpClient,
+			baseURL+AuthServiceWatchAllSecurityEventsProcedure,
+			connect.WithSchema(authServiceWatchAllSecurityEventsMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+	}
+}
+
+// authServiceClient implements AuthServiceClient.
+type authServiceClient struct {
+	registerUser           *connect.Client[auth.RegisterUserRequest, auth.RegisterUserResponse]
+	loginUser              *connect.Client[auth.LoginUserRequest, auth.LoginUserResponse]
+	refresh                *connect.Client[auth.RefreshRequest, auth.RefreshResponse]
+	getUser                *connect.Client[auth.GetUserRequest, auth.GetUserResponse]
+	buildInfo              *connect.Client[auth.BuildInfoRequest, auth.BuildInfoResponse]
+	userInfo               *connect.Client[auth.UserInfoRequest, auth.UserInfoResponse]
+	sendPhoneCode          *connect.Client[auth.SendPhoneCodeRequest, auth.SendPhoneCodeResponse]
+	verifyPhone            *connect.Client[auth.VerifyPhoneRequest, auth.VerifyPhoneResponse]
+	grantRole              *connect.Client[auth.GrantRoleRequest, auth.UserRolesResponse]
+	revokeRole             *connect.Client[auth.RevokeRoleRequest, auth.UserRolesResponse]
+	watchSecurityEvents    *connect.Client[auth.WatchSecurityEventsRequest, auth.SecurityEvent]
+	watchAllSecurityEvents *connect.Client[auth.WatchAllSecurityEventsRequest, auth.SecurityEvent]
+}
+
+// RegisterUser calls auth.v1.AuthService.RegisterUser.
+func (c *authServiceClient) RegisterUser(ctx context.Context, req *connect.Request[auth.RegisterUserRequest]) (*connect.Response[auth.RegisterUserResponse], error) {
+	return c.registerUser.CallUnary(ctx, req)
+}
+
+// LoginUser calls auth.v1.AuthService.LoginUser.
+func (c *authServiceClient) LoginUser(ctx context.Context, req *connect.Request[auth.LoginUserRequest]) (*connect.Response[auth.LoginUserResponse], error) {
+	return c.loginUser.CallUnary(ctx, req)
+}
+
+// Refresh calls auth.v1.AuthService.Refresh.
+func (c *authServiceClient) Refresh(ctx context.Context, req *connect.Request[auth.RefreshRequest]) (*connect.Response[auth.RefreshResponse], error) {
+	return c.refresh.CallUnary(ctx, req)
+}
+
+// GetUser calls auth.v1.AuthService.GetUser.
+func (c *authServiceClient) GetUser(ctx context.Context, req *connect.Request[auth.GetUserRequest]) (*connect.Response[auth.GetUserResponse], error) {
+	return c.getUser.CallUnary(ctx, req)
+}
+
+// BuildInfo calls auth.v1.AuthService.BuildInfo.
+func (c *authServiceClient) BuildInfo(ctx context.Context, req *connect.Request[auth.BuildInfoRequest]) (*connect.Response[auth.BuildInfoResponse], error) {
+	return c.buildInfo.CallUnary(ctx, req)
+}
+
+// UserInfo calls auth.v1.AuthService.UserInfo.
+func (c *authServiceClient) UserInfo(ctx context.Context, req *connect.Request[auth.UserInfoRequest]) (*connect.Response[auth.UserInfoResponse], error) {
+	return c.userInfo.CallUnary(ctx, req)
+}
+
+// SendPhoneCode calls auth.v1.AuthService.SendPhoneCode.
+func (c *authServiceClient) SendPhoneCode(ctx context.Context, req *connect.Request[auth.SendPhoneCodeRequest]) (*connect.Response[auth.SendPhoneCodeResponse], error) {
+	return c.sendPhoneCode.CallUnary(ctx, req)
+}
+
+// VerifyPhone calls auth.v1.AuthService.VerifyPhone.
+func (c *authServiceClient) VerifyPhone(ctx context.Context, req *connect.Request[auth.VerifyPhoneRequest]) (*connect.Response[auth.VerifyPhoneResponse], error) {
+	return c.verifyPhone.CallUnary(ctx, req)
+}
+
+// GrantRole calls auth.v1.AuthService.GrantRole.
+func (c *authServiceClient) GrantRole(ctx context.Context, req *connect.Request[auth.GrantRoleRequest]) (*connect.Response[auth.UserRolesResponse], error) {
+	return c.grantRole.CallUnary(ctx, req)
+}
+
+// RevokeRole calls auth.v1.AuthService.RevokeRole.
+func (c *authServiceClient) RevokeRole(ctx context.Context, req *connect.Request[auth.RevokeRoleRequest]) (*connect.Response[auth.UserRolesResponse], error) {
+	return c.revokeRole.CallUnary(ctx, req)
+}
+
+// WatchSecurityEvents calls auth.v1.AuthService.WatchSecurityEvents.
+func (c *authServiceClient) WatchSecurityEvents(ctx context.Context, req *connect.Request[auth.WatchSecurityEventsRequest]) (*connect.ServerStreamForClient[auth.SecurityEvent], error) {
+	return c.watchSecurityEvents.CallServerStream(ctx, req)
+}
+
+// WatchAllSecurityEvents calls auth.v1.AuthService.WatchAllSecurityEvents.
+func (c *authServiceClient) WatchAllSecurityEvents(ctx context.Context, req *connect.Request[auth.WatchAllSecurityEventsRequest]) (*connect.ServerStreamForClient[auth.SecurityEvent], error) {
+	return c.watchAllSecurityEvents.CallServerStream(ctx, req)
+}
+
+// AuthServiceHandler is an implementation of the auth.v1.AuthService service.
+type AuthServiceHandler interface {
+	RegisterUser(context.Context, *connect.Request[auth.RegisterUserRequest]) (*connect.Response[auth.RegisterUserResponse], error)
+	LoginUser(context.Context, *connect.Request[auth.LoginUserRequest]) (*connect.Response[auth.LoginUserResponse], error)
+	// Refresh - new access token for the refresh token issued by LoginUser
+	Refresh(context.Context, *connect.Request[auth.RefreshRequest]) (*connect.Response[auth.RefreshResponse], error)
+	GetUser(context.Context, *connect.Request[auth.GetUserRequest]) (*connect.Response[auth.GetUserResponse], error)
+	BuildInfo(context.Context, *connect.Request[auth.BuildInfoRequest]) (*connect.Response[auth.BuildInfoResponse], error)
+	UserInfo(context.Context, *connect.Request[auth.UserInfoRequest]) (*connect.Response[auth.UserInfoResponse], error)
+	// SendPhoneCode - sends one-time code by SMS to verify the phone or to log in
+	SendPhoneCode(context.Context, *connect.Request[auth.SendPhoneCodeRequest]) (*connect.Response[auth.SendPhoneCodeResponse], error)
+	VerifyPhone(context.Context, *connect.Request[auth.VerifyPhoneRequest]) (*connect.Response[auth.VerifyPhoneResponse], error)
+	// GrantRole - admin only
+	GrantRole(context.Context, *connect.Request[auth.GrantRoleRequest]) (*connect.Response[auth.UserRolesResponse], error)
+	// RevokeRole - admin only
+	RevokeRole(context.Context, *connect.Request[auth.RevokeRoleRequest]) (*connect.Response[auth.UserRolesResponse], error)
+	// WatchSecurityEvents - security events of the caller's account as they happen.
+	// The stream ends with RESOURCE_EXHAUSTED when the client doesn't keep up and
+	// with UNAVAILABLE on shutdown, clients are expected to watch again
+	WatchSecurityEvents(context.Context, *connect.Request[auth.WatchSecurityEventsRequest], *connect.ServerStream[auth.SecurityEvent]) error
+	// WatchAllSecurityEvents - WatchSecurityEvents of all accounts, admin only
+	WatchAllSecurityEvents(context.Context, *connect.Request[auth.WatchAllSecurityEventsRequest], *connect.ServerStream[auth.SecurityEvent]) error
+}
+
+// NewAuthServiceHandler builds an HTTP handler from the service implementation. It returns the path
+// on which to mount the handler and the handler itself.
+//
+// By default, handlers support the Connect, gRPC, and gRPC-Web protocols with the binary Protobuf
+// and JSON codecs. They also support gzip compression.
+func NewAuthServiceHandler(svc AuthServiceHandler, opts ...connect.HandlerOption) (string, http.Handler) {
+	authServiceRegisterUserHandler := connect.NewUnaryHandler(
+		AuthServiceRegisterUserProcedure,
+		svc.RegisterUser,
+		connect.WithSchema(authServiceRegisterUserMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	authServiceLoginUserHandler := connect.NewUnaryHandler(
+		AuthServiceLoginUserProcedure,
+		svc.LoginUser,
+		connect.WithSchema(authServiceLoginUserMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	authServiceRefreshHandler := connect.NewUnaryHandler(
+		AuthServiceRefreshProcedure,
+		svc.Refresh,
+		connect.WithSchema(authServiceRefreshMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	authServiceGetUserHandler := connect.NewUnaryHandler(
+		AuthServiceGetUserProcedure,
+		svc.GetUser,
+		connect.WithSchema(authServiceGetUserMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	authServiceBuildInfoHandler := connect.NewUnaryHandler(
+		AuthServiceBuildInfoProcedure,
+		svc.BuildInfo,
+		connect.WithSchema(authServiceBuildInfoMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	authServiceUserInfoHandler := connect.NewUnaryHandler(
+		AuthServiceUserInfoProcedure,
+		svc.UserInfo,
+		connect.WithSchema(authServiceUserInfoMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	authServiceSendPhoneCodeHandler := connect.NewUnaryHandler(
+		AuthServiceSendPhoneCodeProcedure,
+		svc.SendPhoneCode,
+		connect.WithSchema(authServiceSendPhoneCodeMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	authServiceVerifyPhoneHandler := connect.NewUnaryHandler(
+		AuthServiceVerifyPhoneProcedure,
+		svc.VerifyPhone,
+		connect.WithSchema(authServiceVerifyPhoneMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	authServiceGrantRoleHandler := connect.NewUnaryHandler(
+		AuthServiceGrantRoleProcedure,
+		svc.GrantRole,
+		connect.WithSchema(authServiceGrantRoleMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	authServiceRevokeRoleHandler := connect.NewUnaryHandler(
+		AuthServiceRevokeRoleProcedure,
+		svc.RevokeRole,
+		connect.WithSchema(authServiceRevokeRoleMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	authServiceWatchSecurityEventsHandler := connect.NewServerStreamHandler(
+		AuthServiceWatchSecurityEventsProcedure,
+		svc.WatchSecurityEvents,
+		connect.WithSchema(authServiceWatchSecurityEventsMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	authServiceWatchAllSecurityEventsHandler := connect.NewServerStreamHandler(
+		AuthServiceWatchAllSecurityEventsProcedure,
+		svc.WatchAllSecurityEvents,
+		connect.WithSchema(authServiceWatchAllSecurityEventsMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	return "/auth.v1.AuthService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case AuthServiceRegisterUserProcedure:
+			authServiceRegisterUserHandler.ServeHTTP(w, r)
+		case AuthServiceLoginUserProcedure:
+			authServiceLoginUserHandler.ServeHTTP(w, r)
+		case AuthServiceRefreshProcedure:
+			authServiceRefreshHandler.ServeHTTP(w, r)
+		case AuthServiceGetUserProcedure:
+			authServiceGetUserHandler.ServeHTTP(w, r)
+		case AuthServiceBuildInfoProcedure:
+			authServiceBuildInfoHandler.ServeHTTP(w, r)
+		case AuthServiceUserInfoProcedure:
+			authServiceUserInfoHandler.ServeHTTP(w, r)
+		case AuthServiceSendPhoneCodeProcedure:
+			authServiceSendPhoneCodeHandler.ServeHTTP(w, r)
+		case AuthServiceVerifyPhoneProcedure:
+			authServiceVerifyPhoneHandler.ServeHTTP(w, r)
+		case AuthServiceGrantRoleProcedure:
+			authServiceGrantRoleHandler.ServeHTTP(w, r)
+		case AuthServiceRevokeRoleProcedure:
+			authServiceRevokeRoleHandler.ServeHTTP(w, r)
+		case AuthServiceWatchSecurityEventsProcedure:
+			authServiceWatchSecurityEventsHandler.ServeHTTP(w, r)
+		case AuthServiceWatchAllSecurityEventsProcedure:
+			authServiceWatchAllSecurityEventsHandler.ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// UnimplementedAuthServiceHandler returns CodeUnimplemented from all methods.
+type UnimplementedAuthServiceHandler struct{}
+
+func (UnimplementedAuthServiceHandler) RegisterUser(context.Context, *connect.Request[auth.RegisterUserRequest]) (*connect.Response[auth.RegisterUserResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("auth.v1.AuthService.RegisterUser is not implemented"))
+}
+
+func (UnimplementedAuthServiceHandler) LoginUser(context.Context, *connect.Request[auth.LoginUserRequest]) (*connect.Response[auth.LoginUserResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("auth.v1.AuthService.LoginUser is not implemented"))
+}
+
+func (UnimplementedAuthServiceHandler) Refresh(context.Context, *connect.Request[auth.RefreshRequest]) (*connect.Response[auth.RefreshResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("auth.v1.AuthService.Refresh is not implemented"))
+}
+
+func (UnimplementedAuthServiceHandler) GetUser(context.Context, *connect.Request[auth.GetUserRequest]) (*connect.Response[auth.GetUserResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("auth.v1.AuthService.GetUser is not implemented"))
+}
+
+func (UnimplementedAuthServiceHandler) BuildInfo(context.Context, *connect.Request[auth.BuildInfoRequest]) (*connect.Response[auth.BuildInfoResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("auth.v1.AuthService.BuildInfo is not implemented"))
+}
+
+func (UnimplementedAuthServiceHandler) UserInfo(context.Context, *connect.Request[auth.UserInfoRequest]) (*connect.Response[auth.UserInfoResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("auth.v1.AuthService.UserInfo is not implemented"))
+}
+
+func (UnimplementedAuthServiceHandler) SendPhoneCode(context.Context, *connect.Request[auth.SendPhoneCodeRequest]) (*connect.Response[auth.SendPhoneCodeResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("auth.v1.AuthService.SendPhoneCode is not implemented"))
+}
+
+func (UnimplementedAuthServiceHandler) VerifyPhone(context.Context, *connect.Request[auth.VerifyPhoneRequest]) (*connect.Response[auth.VerifyPhoneResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("auth.v1.AuthService.VerifyPhone is not implemented"))
+}
+
+func (UnimplementedAuthServiceHandler) GrantRole(context.Context, *connect.Request[auth.GrantRoleRequest]) (*connect.Response[auth.UserRolesResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("auth.v1.AuthService.GrantRole is not implemented"))
+}
+
+func (UnimplementedAuthServiceHandler) RevokeRole(context.Context, *connect.Request[auth.RevokeRoleRequest]) (*connect.Response[auth.UserRolesResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("auth.v1.AuthService.RevokeRole is not implemented"))
+}
+
+func (UnimplementedAuthServiceHandler) WatchSecurityEvents(context.Context, *connect.Request[auth.WatchSecurityEventsRequest], *connect.ServerStream[auth.SecurityEvent]) error {
+	return connect.NewError(connect.CodeUnimplemented, errors.New("auth.v1.AuthService.WatchSecurityEvents is not implemented"))
+}
+
+func (UnimplementedAuthServiceHandler) WatchAllSecurityEvents(context.Context, *connect.Request[auth.WatchAllSecurityEventsRequest], *connect.ServerStream[auth.SecurityEvent]) error {
+	return connect.NewError(connect.CodeUnimplemented, errors.New("auth.v1.AuthService.WatchAllSecurityEvents is not implemented"))
+}
@@ -0,0 +1,200 @@
+// Code generated by protoc-gen-connect-go. DO NOT EDIT.
+//
+// Source: relations.proto
+
+package authconnect
+
+import (
+	connect "connectrpc.com/connect"
+	context "context"
+	errors "errors"
+	auth "github.com/bogatyr285/auth-go/pkg/server/grpc/auth"
+	http "net/http"
+	strings "strings"
+)
+
+// This is a compile-time assertion to ensure that this generated file and the connect package are
+// compatible. If you get a compiler error that this constant is not defined, this code was
+// generated with a version of connect newer than the one compiled into your binary. You can fix the
+// problem by either regenerating this code with an older version of connect or updating the connect
+// version compiled into your binary.
+const _ = connect.IsAtLeastVersion1_13_0
+
+const (
+	// RelationServiceName is the fully-qualified name of the RelationService service.
+	RelationServiceName = "auth.v1.RelationService"
+)
+
+// These constants are the fully-qualified names of the RPCs defined in this package. They're
+// exposed at runtime as Spec.Procedure and as the final two segments of the HTTP route.
+//
+// Note that these are different from the fully-qualified method names used by
+// google.golang.org/protobuf/reflect/protoreflect. To convert from these constants to
+// reflection-formatted method names, remove the leading slash and convert the remaining slash to a
+// period.
+const (
+	// RelationServiceCheckProcedure is the fully-qualified name of the RelationService's Check RPC.
+	RelationServiceCheckProcedure = "/auth.v1.RelationService/Check"
+	// RelationServiceExpandProcedure is the fully-qualified name of the RelationService's Expand RPC.
+	RelationServiceExpandProcedure = "/auth.v1.RelationService/Expand"
+	// RelationServiceWriteProcedure is the fully-qualified name of the RelationService's Write RPC.
+	RelationServiceWriteProcedure = "/auth.v1.RelationService/Write"
+	// RelationServiceListObjectsProcedure is the fully-qualified name of the RelationService's
+	// ListObjects RPC.
+	RelationServiceListObjectsProcedure = "/auth.v1.RelationService/ListObjects"
+)
+
+// These variables are the protoreflect.Descriptor objects for the RPCs defined in this package.
+var (
+	relationServiceServiceDescriptor           = auth.File_relations_proto.Services().ByName("RelationService")
+	relationServiceCheckMethodDescriptor       = relationServiceServiceDescriptor.Methods().ByName("Check")
+	relationServiceExpandMethodDescriptor      = relationServiceServiceDescriptor.Methods().ByName("Expand")
+	relationServiceWriteMethodDescriptor       = relationServiceServiceDescriptor.Methods().ByName("Write")
+	relationServiceListObjectsMethodDescriptor = relationServiceServiceDescriptor.Methods().ByName("ListObjects")
+)
+
+// RelationServiceClient is a client for the auth.v1.RelationService service.
+type RelationServiceClient interface {
+	Check(context.Context, *connect.Request[auth.CheckRequest]) (*connect.Response[auth.CheckResponse], error)
+	Expand(context.Context, *connect.Request[auth.ExpandRequest]) (*connect.Response[auth.ExpandResponse], error)
+	Write(context.Context, *connect.Request[auth.WriteRequest]) (*connect.Response[auth.WriteResponse], error)
+	ListObjects(context.Context, *connect.Request[auth.ListObjectsRequest]) (*connect.Response[auth.ListObjectsResponse], error)
+}
+
+// NewRelationServiceClient constructs a client for the auth.v1.RelationService service. By default,
+// it uses the Connect protocol with the binary Protobuf Codec, asks for gzipped responses, and
+// sends uncompressed requests. To use the gRPC or gRPC-Web protocols, supply the connect.WithGRPC()
+// or connect.WithGRPCWeb() options.
+//
+// The URL supplied here should be the base URL for the Connect or gRPC server (for example,
+// http://api.acme.com or https://acme.com/grpc).
+func NewRelationServiceClient(httpClient connect.HTTPClient, baseURL string, opts ...connect.ClientOption) RelationServiceClient {
+	baseURL = strings.TrimRight(baseURL, "/")
+	return &relationServiceClient{
+		check: connect.NewClient[auth.CheckRequest, auth.CheckResponse](
+			httpClient,
+			baseURL+RelationServiceCheckProcedure,
+			connect.WithSchema(relationServiceCheckMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		expand: connect.NewClient[auth.ExpandRequest, auth.ExpandResponse](
+			httpClient,
+			baseURL+RelationServiceExpandProcedure,
+			connect.WithSchema(relationServiceExpandMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		write: connect.NewClient[auth.WriteRequest, auth.WriteResponse](
+			httpClient,
+			baseURL+RelationServiceWriteProcedure,
+			connect.WithSchema(relationServiceWriteMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		listObjects: connect.NewClient[auth.ListObjectsRequest, auth.ListObjectsResponse](
+			httpClient,
+			baseURL+RelationServiceListObjectsProcedure,
+			connect.WithSchema(relationServiceListObjectsMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+	}
+}
+
+// relationServiceClient implements RelationServiceClient.
+type relationServiceClient struct {
+	check       *connect.Client[auth.CheckRequest, auth.CheckResponse]
+	expand      *connect.Client[auth.ExpandRequest, auth.ExpandResponse]
+	write       *connect.Client[auth.WriteRequest, auth.WriteResponse]
+	listObjects *connect.Client[auth.ListObjectsRequest, auth.ListObjectsResponse]
+}
+
+// Check calls auth.v1.RelationService.Check.
+func (c *relationServiceClient) Check(ctx context.Context, req *connect.Request[auth.CheckRequest]) (*connect.Response[auth.CheckResponse], error) {
+	return c.check.CallUnary(ctx, req)
+}
+
+// Expand calls auth.v1.RelationService.Expand.
+func (c *relationServiceClient) Expand(ctx context.Context, req *connect.Request[auth.ExpandRequest]) (*connect.Response[auth.ExpandResponse], error) {
+	return c.expand.CallUnary(ctx, req)
+}
+
+// Write calls auth.v1.RelationService.Write.
+func (c *relationServiceClient) Write(ctx context.Context, req *connect.Request[auth.WriteRequest]) (*connect.Response[auth.WriteResponse], error) {
+	return c.write.CallUnary(ctx, req)
+}
+
+// ListObjects calls auth.v1.RelationService.ListObjects.
+func (c *relationServiceClient) ListObjects(ctx context.Context, req *connect.Request[auth.ListObjectsRequest]) (*connect.Response[auth.ListObjectsResponse], error) {
+	return c.listObjects.CallUnary(ctx, req)
+}
+
+// RelationServiceHandler is an implementation of the auth.v1.RelationService service.
+type RelationServiceHandler interface {
+	Check(context.Context, *connect.Request[auth.CheckRequest]) (*connect.Response[auth.CheckResponse], error)
+	Expand(context.Context, *connect.Request[auth.ExpandRequest]) (*connect.Response[auth.ExpandResponse], error)
+	Write(context.Context, *connect.Request[auth.WriteRequest]) (*connect.Response[auth.WriteResponse], error)
+	ListObjects(context.Context, *connect.Request[auth.ListObjectsRequest]) (*connect.Response[auth.ListObjectsResponse], error)
+}
+
+// NewRelationServiceHandler builds an HTTP handler from the service implementation. It returns the
+// path on which to mount the handler and the handler itself.
+//
+// By default, handlers support the Connect, gRPC, and gRPC-Web protocols with the binary Protobuf
+// and JSON codecs. They also support gzip compression.
+func NewRelationServiceHandler(svc RelationServiceHandler, opts ...connect.HandlerOption) (string, http.Handler) {
+	relationServiceCheckHandler := connect.NewUnaryHandler(
+		RelationServiceCheckProcedure,
+		svc.Check,
+		connect.WithSchema(relationServiceCheckMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	relationServiceExpandHandler := connect.NewUnaryHandler(
+		RelationServiceExpandProcedure,
+		svc.Expand,
+		connect.WithSchema(relationServiceExpandMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	relationServiceWriteHandler := connect.NewUnaryHandler(
+		RelationServiceWriteProcedure,
+		svc.Write,
+		connect.WithSchema(relationServiceWriteMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	relationServiceListObjectsHandler := connect.NewUnaryHandler(
+		RelationServiceListObjectsProcedure,
+		svc.ListObjects,
+		connect.WithSchema(relationServiceListObjectsMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	return "/auth.v1.RelationService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case RelationServiceCheckProcedure:
+			relationServiceCheckHandler.ServeHTTP(w, r)
+		case RelationServiceExpandProcedure:
+			relationServiceExpandHandler.ServeHTTP(w, r)
+		case RelationServiceWriteProcedure:
+			relationServiceWriteHandler.ServeHTTP(w, r)
+		case RelationServiceListObjectsProcedure:
+			relationServiceListObjectsHandler.ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// UnimplementedRelationServiceHandler returns CodeUnimplemented from all methods.
+type UnimplementedRelationServiceHandler struct{}
+
+func (UnimplementedRelationServiceHandler) Check(context.Context, *connect.Request[auth.CheckRequest]) (*connect.Response[auth.CheckResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("auth.v1.RelationService.Check is not implemented"))
+}
+
+func (UnimplementedRelationServiceHandler) Expand(context.Context, *connect.Request[auth.ExpandRequest]) (*connect.Response[auth.ExpandResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("auth.v1.RelationService.Expand is not implemented"))
+}
+
+func (UnimplementedRelationServiceHandler) Write(context.Context, *connect.Request[auth.WriteRequest]) (*connect.Response[auth.WriteResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("auth.v1.RelationService.Write is not implemented"))
+}
+
+func (UnimplementedRelationServiceHandler) ListObjects(context.Context, *connect.Request[auth.ListObjectsRequest]) (*connect.Response[auth.ListObjectsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("auth.v1.RelationService.ListObjects is not implemented"))
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+)
+
+// HTTPServer - Service serving http.Server, over TLS when its TLSConfig is set
+type HTTPServer struct {
+	srv             *http.Server
+	shutdownTimeout time.Duration
+	listener        net.Listener
+	log             *slog.Logger
+}
+
+func NewHTTPServer(srv *http.Server, shutdownTimeout time.Duration, log *slog.Logger) *HTTPServer {
+	return &HTTPServer{
+		srv:             srv,
+		shutdownTimeout: shutdownTimeout,
+		log:             log.With(slog.String("addr", srv.Addr)),
+	}
+}
+
+// Init - binds the address, so busy ports fail before anything is served
+func (s *HTTPServer) Init(ctx context.Context) error {
+	l, err := net.Listen("tcp", s.srv.Addr)
+	if err != nil {
+		return err
+	}
+	s.listener = l
+
+	return nil
+}
+
+// Addr - address the server listens on, known once it is initialized
+func (s *HTTPServer) Addr() net.Addr {
+	return s.listener.Addr()
+}
+
+func (s *HTTPServer) Run(ctx context.Context) error {
+	s.log.Info("http server listening", slog.Bool("tls", s.srv.TLSConfig != nil))
+
+	var err error
+	if s.srv.TLSConfig != nil {
+		// certificates come from TLSConfig
+		err = s.srv.ServeTLS(s.listener, "", "")
+	} else {
+		err = s.srv.Serve(s.listener)
+	}
+
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+
+	return err
+}
+
+// Stop - waits for requests in flight up to the shutdown timeout, then closes connections
+func (s *HTTPServer) Stop() {
+	ctx, cancel := context.WithTimeout(context.Background(), s.shutdownTimeout)
+	defer cancel()
+
+	if err := s.srv.Shutdown(ctx); err != nil {
+		s.log.Error("http server shutdown", slog.Any("err", err))
+		s.srv.Close()
+	}
+
+	// the listener is not tracked by the server when Run wasn't called
+	if s.listener != nil {
+		s.listener.Close()
+	}
+	s.log.Info("http server stopped")
+}
@@ -33,21 +33,26 @@ func (s *Manager) AddService(service ...Service) {
 	s.services = append(s.services, service...)
 }
 
+// Run - initializes all services, then runs them until the context is done, a signal
+// arrives or one of them fails. Services are stopped in reverse order, so proxies are
+// stopped before the servers behind them, and Run returns once all of them returned
 func (s *Manager) Run(ctx context.Context) error {
-	var wg sync.WaitGroup
-	errChan := make(chan error, len(s.services))
-
 	s.log.Info("going to start services")
-	for _, service := range s.services {
-		err := service.Init(ctx)
-		if err != nil {
+	for i, service := range s.services {
+		if err := service.Init(ctx); err != nil {
 			s.log.ErrorContext(
 				ctx,
 				"service initialization failed",
 				slog.String("err_msg", err.Error()),
 			)
-			continue
+			s.stop(s.services[:i])
+			return err
 		}
+	}
+
+	var wg sync.WaitGroup
+	errChan := make(chan error, len(s.services))
+	for _, service := range s.services {
 		wg.Add(1)
 		go func(svc Service) {
 			defer wg.Done()
@@ -59,28 +64,29 @@ func (s *Manager) Run(ctx context.Context) error {
 
 	c := make(chan os.Signal, 1)
 	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(c)
 
 	go func() {
 		wg.Wait()
 		close(errChan)
 	}()
 
+	var err error
 	select {
 	case <-c:
-		s.stop()
 	case <-ctx.Done():
-		s.stop()
-	case err := <-errChan:
-		s.stop()
-		return err
+	case err = <-errChan:
 	}
 
-	return nil
+	s.stop(s.services)
+	wg.Wait()
+
+	return err
 }
 
-func (s *Manager) stop() {
+func (s *Manager) stop(services []Service) {
 	s.log.Info("going to stop")
-	for _, service := range s.services {
-		service.Stop()
+	for i := len(services) - 1; i >= 0; i-- {
+		services[i].Stop()
 	}
 }
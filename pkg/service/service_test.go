@@ -0,0 +1,112 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bogatyr285/auth-go/pkg/service"
+	"github.com/stretchr/testify/require"
+)
+
+// events - calls of services in order
+type events struct {
+	mu   sync.Mutex
+	list []string
+}
+
+func (e *events) add(event string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.list = append(e.list, event)
+}
+
+func (e *events) get() []string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]string(nil), e.list...)
+}
+
+// fakeService - runs until stopped, or fails Init or Run with the errors
+type fakeService struct {
+	name    string
+	events  *events
+	initErr error
+	runErr  error
+	stopped chan struct{}
+}
+
+func newFakeService(name string, e *events) *fakeService {
+	return &fakeService{name: name, events: e, stopped: make(chan struct{})}
+}
+
+func (s *fakeService) Init(context.Context) error {
+	s.events.add("init " + s.name)
+	return s.initErr
+}
+
+func (s *fakeService) Run(context.Context) error {
+	if s.runErr != nil {
+		return s.runErr
+	}
+
+	<-s.stopped
+	// returns a bit later than Stop, like servers draining connections
+	time.Sleep(10 * time.Millisecond)
+	s.events.add("returned " + s.name)
+
+	return nil
+}
+
+func (s *fakeService) Stop() {
+	s.events.add("stop " + s.name)
+	close(s.stopped)
+}
+
+func newManager(services ...service.Service) service.Services {
+	m := service.NewManager(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	m.AddService(services...)
+	return m
+}
+
+func TestManagerStopsInReverseOrder(t *testing.T) {
+	e := &events{}
+	a, b := newFakeService("a", e), newFakeService("b", e)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- newManager(a, b).Run(ctx) }()
+
+	require.Eventually(t, func() bool { return len(e.get()) == 2 }, time.Second, time.Millisecond)
+	cancel()
+
+	require.NoError(t, <-done)
+	events := e.get()
+	require.Equal(t, []string{"init a", "init b", "stop b", "stop a"}, events[:4])
+	// Run returns once every service returned
+	require.ElementsMatch(t, []string{"returned a", "returned b"}, events[4:])
+}
+
+func TestManagerInitFailure(t *testing.T) {
+	e := &events{}
+	a, b, c := newFakeService("a", e), newFakeService("b", e), newFakeService("c", e)
+	b.initErr = errors.New("address in use")
+
+	err := newManager(a, b, c).Run(context.Background())
+	require.ErrorIs(t, err, b.initErr)
+	require.Equal(t, []string{"init a", "init b", "stop a"}, e.get())
+}
+
+func TestManagerRunFailure(t *testing.T) {
+	e := &events{}
+	a, b := newFakeService("a", e), newFakeService("b", e)
+	b.runErr = errors.New("listener closed")
+
+	err := newManager(a, b).Run(context.Background())
+	require.ErrorIs(t, err, b.runErr)
+	require.Equal(t, []string{"init a", "init b", "stop b", "stop a", "returned a"}, e.get())
+}
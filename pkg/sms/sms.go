@@ -0,0 +1,23 @@
+package sms
+
+import (
+	"context"
+	"log/slog"
+)
+
+// LogSender - writes messages to log instead of sending them, for local usage
+type LogSender struct {
+	logger *slog.Logger
+}
+
+func NewLogSender(logger *slog.Logger) LogSender {
+	return LogSender{logger: logger.With("module", "log-sms")}
+}
+
+func (s LogSender) SendSMS(ctx context.Context, phone, text string) error {
+	s.logger.InfoContext(ctx, "sms",
+		slog.String("phone", phone),
+		slog.String("text", text),
+	)
+	return nil
+}